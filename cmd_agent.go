@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/hub"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// runAgent implements `csm agent --push <hub-url>`: it discovers local
+// sessions and posts them to a `csm hub` every interval.
+func runAgent(args []string) {
+	hostname, _ := os.Hostname()
+
+	fs := newFlagSet("agent", "agent --push <hub-url> [flags]")
+	pushURL := fs.String("push", "", "Hub URL to push sessions to, e.g. http://hub:9847")
+	interval := fs.Duration("interval", 10*time.Second, "How often to push")
+	token := fs.String("token", os.Getenv("CSM_HUB_TOKEN"), "Shared secret expected by the hub (default $CSM_HUB_TOKEN)")
+	name := fs.String("name", hostname, "Host name shown on the hub")
+	fs.Parse(args)
+	if *pushURL == "" || *name == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	loadConfig()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	fmt.Printf("Pushing sessions as %q to %s every %s\n", *name, *pushURL, *interval)
+
+	// Only report changes in push health, so a down hub doesn't flood the log.
+	var lastErr string
+	push := func() {
+		sessions, err := session.Discover()
+		if err == nil {
+			err = hub.Push(ctx, *pushURL, *token, hub.Report{Host: *name, Sessions: sessions})
+		}
+		switch {
+		case err != nil && err.Error() != lastErr:
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Push failed: %v\n", err)
+			}
+			lastErr = err.Error()
+		case err == nil && lastErr != "":
+			fmt.Println("Push succeeded again")
+			lastErr = ""
+		}
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		push()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/export"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/ui"
+)
+
+// runAudit implements `csm audit`, a security report of the Bash commands
+// Claude ran with the sandbox disabled or with permission checks bypassed.
+func runAudit(args []string) {
+	fs := newFlagSet("audit", "audit [--days N] [--project X] [--format table|csv|json|md] [--output file]")
+	days := fs.Int("days", 30, "Only report commands from the last N days")
+	project := fs.String("project", "", "Only report projects whose name contains this")
+	report := addReportFlags(fs)
+	fs.Parse(args)
+	exporting := report.exporting()
+	loadConfig()
+
+	entries, err := session.Audit(time.Now().AddDate(0, 0, -*days))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *project != "" {
+		q := strings.ToLower(*project)
+		kept := entries[:0]
+		for _, e := range entries {
+			if strings.Contains(strings.ToLower(e.Project), q) {
+				kept = append(kept, e)
+			}
+		}
+		entries = kept
+	}
+
+	if exporting {
+		if entries == nil {
+			entries = []session.AuditEntry{}
+		}
+		t := export.Table{
+			Columns: []string{"timestamp", "project", "session_id", "reason", "command", "description", "log_file"},
+			Data:    entries,
+		}
+		for _, e := range entries {
+			t.Rows = append(t.Rows, []string{e.Timestamp.Format(time.RFC3339), e.Project, e.SessionID, e.Reason, e.Command, e.Description, e.LogFile})
+		}
+		report.write(t)
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("No unsandboxed or permission-bypassing commands in the last %d days.\n", *days)
+		return
+	}
+	fmt.Printf("%-16s  %-18s  %-24s  %s\n", "TIME", "REASON", "PROJECT", "COMMAND")
+	for _, e := range entries {
+		// Multi-line commands are shown on one line; exports keep them
+		// intact. Both come from logs, so they are sanitized for the terminal.
+		command := ui.Sanitize(strings.ReplaceAll(strings.TrimSpace(e.Command), "\n", " ⏎ "))
+		fmt.Printf("%-16s  %-18s  %-24s  %s\n", ui.FormatDateTime(e.Timestamp), e.Reason, ui.Sanitize(e.Project), command)
+	}
+	fmt.Printf("\n%d commands.\n", len(entries))
+}
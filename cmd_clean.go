@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/logfiles"
+)
+
+// runClean implements `csm clean`, which deletes empty and unparseable
+// session logs after confirmation.
+func runClean(args []string) {
+	fs := newFlagSet("clean", "clean [--dry-run] [--yes]")
+	dryRun := fs.Bool("dry-run", false, "Only list the junk logs")
+	yes := fs.Bool("yes", false, "Don't ask for confirmation")
+	minAge := fs.Duration("min-age", time.Hour, "Leave logs written more recently than this alone")
+	fs.Parse(args)
+	loadConfig()
+
+	junk := logfiles.Junk(walkProfiles(), *minAge, time.Now())
+	if len(junk) == 0 {
+		fmt.Println("No empty or corrupt logs found.")
+		return
+	}
+
+	logs := make([]logfiles.Log, len(junk))
+	for i, j := range junk {
+		logs[i] = j.Log
+		fmt.Printf("%-7s  %9s  %s\n", j.Reason, logfiles.FormatSize(j.Size), j.Path)
+	}
+	size := logfiles.TotalSize(logs)
+	fmt.Printf("\n%d junk logs, %s.\n", len(junk), logfiles.FormatSize(size))
+	if *dryRun {
+		return
+	}
+	if !*yes && !confirm(fmt.Sprintf("Delete these %d logs?", len(junk))) {
+		fmt.Println("Cancelled.")
+		return
+	}
+
+	deleted, err := logfiles.Delete(logs)
+	fmt.Printf("Deleted %d logs.\n", len(deleted))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error deleting logs: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/digest"
+	"github.com/itk-dev/claude-sessions-monitor/internal/events"
+	"github.com/itk-dev/claude-sessions-monitor/internal/otlp"
+	"github.com/itk-dev/claude-sessions-monitor/internal/search"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/state"
+	"github.com/itk-dev/claude-sessions-monitor/internal/wakatime"
+	"github.com/itk-dev/claude-sessions-monitor/internal/watcher"
+)
+
+// runDaemon implements `csm daemon`: it keeps discovering sessions and
+// publishes each snapshot to a state file and, optionally, a Unix socket.
+func runDaemon(args []string) {
+	defaultPath, _ := state.DefaultPath()
+
+	fs := newFlagSet("daemon", "daemon [flags]")
+	interval := fs.Duration("interval", 2*time.Second, "How often to refresh the state")
+	statePath := fs.String("state-file", defaultPath, "JSON state file to keep up to date (empty to disable)")
+	socketPath := fs.String("socket", "", "Also serve the state on this Unix socket, e.g. ~/.claude-monitor/csm.sock")
+	searchIndex := fs.Bool("search-index", false, "Also keep the word index for `csm grep --indexed` up to date")
+	wakaTime := fs.Bool("wakatime", false, "Send WakaTime heartbeats while sessions are working (API key from the wakatime config or ~/.wakatime.cfg)")
+	sendDigest := fs.Bool("digest", false, "Email yesterday's digest every day at digest.at (see `csm digest`)")
+	otlpEndpoint := fs.String("otlp", "", "Export sessions and turns as OpenTelemetry spans to this OTLP/HTTP `endpoint`, e.g. http://localhost:4318 (overrides the otlp config)")
+	statsdAddr := addStatsDFlag(fs)
+	fs.Parse(args)
+	cfg := loadConfig()
+	if *otlpEndpoint != "" {
+		cfg.OTLP.Endpoint = *otlpEndpoint
+	}
+	if *statePath == "" && *socketPath == "" && !*searchIndex && !*wakaTime && !*sendDigest && cfg.OTLP.Endpoint == "" {
+		fmt.Fprintf(os.Stderr, "Error: nothing to publish; set --state-file, --socket, --search-index, --wakatime, --digest or --otlp\n")
+		os.Exit(2)
+	}
+	setupStatsD(cfg, *statsdAddr, *interval)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	var mu sync.Mutex
+	current := state.Build(nil, time.Now())
+	snapshot := func() state.State {
+		mu.Lock()
+		defer mu.Unlock()
+		return current
+	}
+
+	if *socketPath != "" {
+		ln, err := listenUnix(*socketPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer ln.Close()
+		go state.Serve(ln, snapshot)
+		fmt.Printf("Serving state on %s\n", ln.Addr())
+	}
+	if *statePath != "" {
+		fmt.Printf("Writing state to %s every %s\n", *statePath, *interval)
+	}
+	if *searchIndex {
+		go keepSearchIndex(ctx)
+		fmt.Printf("Updating the search index every %s\n", searchIndexInterval)
+	}
+	var heartbeats chan []wakatime.Heartbeat
+	var tracker *wakatime.Tracker
+	if *wakaTime {
+		client, err := newWakaTimeClient(cfg.WakaTime)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: wakatime: %v\n", err)
+			os.Exit(1)
+		}
+		heartbeats = make(chan []wakatime.Heartbeat, 16)
+		tracker = wakatime.NewTracker()
+		go sendHeartbeats(ctx, client, heartbeats)
+		fmt.Printf("Sending WakaTime heartbeats for working sessions\n")
+	}
+	if *sendDigest {
+		dc := cfg.Digest
+		long, err := digestLongSession(dc)
+		if err == nil && (len(dc.To) == 0 || dc.SMTP.Host == "") {
+			err = fmt.Errorf("set digest.to and digest.smtp in ~/.claude-monitor/config.json")
+		}
+		if err == nil && dc.At != "" {
+			_, err = digest.NextRun(dc.At, time.Now())
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: digest: %v\n", err)
+			os.Exit(1)
+		}
+		go sendDigests(ctx, dc, long)
+		fmt.Printf("Emailing the daily digest to %s\n", strings.Join(dc.To, ", "))
+	}
+	var spans chan []otlp.Span
+	var recorder *otlp.Recorder
+	var transitions *events.Tracker
+	if oc := cfg.OTLP; oc.Endpoint != "" {
+		exporter := otlp.NewExporter(oc.Endpoint, oc.Headers, oc.ServiceName, version)
+		spans = make(chan []otlp.Span, 16)
+		recorder = otlp.NewRecorder()
+		transitions = events.NewTracker()
+		done := make(chan struct{})
+		go func() {
+			exportSpans(ctx, exporter, spans)
+			close(done)
+		}()
+		// Sessions still running when the daemon stops end their spans then,
+		// sent with any spans not exported yet.
+		defer func() {
+			<-done
+			var pending []otlp.Span
+			for len(spans) > 0 {
+				pending = append(pending, <-spans...)
+			}
+			if err := exporter.Export(append(pending, recorder.Flush(time.Now())...)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: otlp: %v\n", err)
+			}
+		}()
+		fmt.Printf("Exporting session spans to %s\n", oc.Endpoint)
+	}
+
+	var lastErr string
+	refresh := func() {
+		sessions, err := session.Discover()
+		if err == nil && tracker != nil {
+			if due := tracker.Due(sessions, time.Now()); len(due) > 0 {
+				select {
+				case heartbeats <- due:
+				default: // the API is slow; WakaTime fills short gaps anyway
+				}
+			}
+		}
+		if err == nil && recorder != nil {
+			if finished := recorder.Record(transitions.Update(sessions, time.Now()), sessions); len(finished) > 0 {
+				select {
+				case spans <- finished:
+				default:
+					fmt.Fprintf(os.Stderr, "Warning: otlp: collector too slow, dropped %d spans\n", len(finished))
+				}
+			}
+		}
+		if err == nil {
+			st := state.Build(sessions, time.Now())
+			mu.Lock()
+			current = st
+			mu.Unlock()
+			if *statePath != "" {
+				err = state.Write(*statePath, st)
+			}
+		}
+		// Report each distinct failure once rather than every tick.
+		switch {
+		case err == nil:
+			lastErr = ""
+		case err.Error() != lastErr:
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			lastErr = err.Error()
+		}
+	}
+
+	// Sessions are picked up as soon as their logs are created rather than
+	// on the next tick.
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	changes := watcher.Changes(ctx, watcher.DefaultProbe)
+	for {
+		refresh()
+		select {
+		case <-ctx.Done():
+			return
+		case <-changes:
+			session.InvalidateCaches()
+		case <-ticker.C:
+		}
+	}
+}
+
+// searchIndexInterval is how often the daemon indexes new log lines.
+const searchIndexInterval = time.Minute
+
+// keepSearchIndex updates the search index until ctx is done. The index stays
+// in memory between updates and is only written when it changed.
+func keepSearchIndex(ctx context.Context) {
+	path, err := search.DefaultIndexPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: search index: %v\n", err)
+		return
+	}
+	ix := search.LoadIndex(path)
+	ticker := time.NewTicker(searchIndexInterval)
+	defer ticker.Stop()
+	for {
+		if profiles, err := session.Profiles(); err == nil && ix.Update(search.Files(profiles, time.Time{}, "")) {
+			if err := ix.Save(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: search index: %v\n", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newWakaTimeClient returns a WakaTime client with the API key and URL from
+// the config, falling back to ~/.wakatime.cfg.
+func newWakaTimeClient(wc config.WakaTimeConfig) (*wakatime.Client, error) {
+	if wc.APIKey == "" {
+		key, url, err := wakatime.ReadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("no API key: set wakatime.api_key or install WakaTime (%v)", err)
+		}
+		wc.APIKey = key
+		if wc.APIURL == "" {
+			wc.APIURL = url
+		}
+	}
+	return wakatime.NewClient(wc.APIURL, wc.APIKey, "csm/"+version), nil
+}
+
+// sendHeartbeats posts the heartbeats it receives until ctx is done,
+// reporting each distinct failure once.
+func sendHeartbeats(ctx context.Context, client *wakatime.Client, heartbeats <-chan []wakatime.Heartbeat) {
+	var lastErr string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case hb := <-heartbeats:
+			err := client.Send(hb)
+			switch {
+			case err == nil:
+				lastErr = ""
+			case err.Error() != lastErr:
+				fmt.Fprintf(os.Stderr, "Warning: wakatime: %v\n", err)
+				lastErr = err.Error()
+			}
+		}
+	}
+}
+
+// exportSpans sends the spans it receives until ctx is done, reporting each
+// distinct failure once.
+func exportSpans(ctx context.Context, exporter *otlp.Exporter, spans <-chan []otlp.Span) {
+	var lastErr string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case batch := <-spans:
+			err := exporter.Export(batch)
+			switch {
+			case err == nil:
+				lastErr = ""
+			case err.Error() != lastErr:
+				fmt.Fprintf(os.Stderr, "Warning: otlp: %v\n", err)
+				lastErr = err.Error()
+			}
+		}
+	}
+}
+
+// listenUnix listens on a Unix socket, replacing a stale socket file left by
+// a previous daemon but refusing to steal one that is still answering.
+func listenUnix(path string) (net.Listener, error) {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another csm daemon is already serving %s", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/digest"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// defaultDigestAt is when `csm daemon --digest` sends yesterday's digest
+// unless digest.at says otherwise.
+const defaultDigestAt = "08:00"
+
+// runDigest implements `csm digest`, which prints or emails the summary of a
+// day's sessions, yesterday's by default. Run from cron with --send, or let
+// `csm daemon --digest` send it.
+func runDigest(args []string) {
+	fs := newFlagSet("digest", "digest [--date YYYY-MM-DD] [--html] [--send]")
+	date := fs.String("date", "", "Summarise this day instead of yesterday")
+	asHTML := fs.Bool("html", false, "Print HTML instead of Markdown")
+	send := fs.Bool("send", false, "Email the digest with the digest settings of the config instead of printing it")
+	fs.Parse(args)
+	cfg := loadConfig()
+
+	now := time.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, now.Location())
+	if *date != "" {
+		t, err := time.ParseInLocation("2006-01-02", *date, now.Location())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --date %q, want YYYY-MM-DD\n", *date)
+			os.Exit(2)
+		}
+		day = t
+	}
+	long, err := digestLongSession(cfg.Digest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	d, err := buildDigest(day, long)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering history: %v\n", err)
+		os.Exit(1)
+	}
+	switch {
+	case *send:
+		err = digest.Send(cfg.Digest, d)
+	case *asHTML:
+		err = digest.WriteHTML(os.Stdout, d)
+	default:
+		err = digest.WriteMarkdown(os.Stdout, d)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// buildDigest summarises the day starting at midnight day.
+func buildDigest(day time.Time, long time.Duration) (digest.Digest, error) {
+	end := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location())
+	history, err := session.DiscoverHistory(int(time.Since(day).Hours()/24) + 1)
+	if err != nil {
+		return digest.Digest{}, err
+	}
+	return digest.Build(day, history, session.ComputeUsageBetween(day, end), long), nil
+}
+
+// digestLongSession parses digest.long_session.
+func digestLongSession(dc config.DigestConfig) (time.Duration, error) {
+	if dc.LongSession == "" {
+		return digest.DefaultLongSession, nil
+	}
+	d, err := time.ParseDuration(dc.LongSession)
+	if err != nil {
+		return 0, fmt.Errorf("digest.long_session: %v", err)
+	}
+	return d, nil
+}
+
+// sendDigests emails yesterday's digest every day at digest.at until ctx is
+// done. Failures are reported and retried the next day.
+func sendDigests(ctx context.Context, dc config.DigestConfig, long time.Duration) {
+	at := dc.At
+	if at == "" {
+		at = defaultDigestAt
+	}
+	for {
+		next, err := digest.NextRun(at, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: digest.at: %v\n", err)
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		day := time.Date(next.Year(), next.Month(), next.Day()-1, 0, 0, 0, 0, next.Location())
+		d, err := buildDigest(day, long)
+		if err == nil {
+			err = digest.Send(dc, d)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: digest: %v\n", err)
+		}
+	}
+}
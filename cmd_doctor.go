@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/ui"
+)
+
+// runDoctor implements `csm doctor`: it checks the environment csm depends
+// on and prints each result with a hint for what fails. It exits 1 if any
+// check fails.
+func runDoctor(args []string) {
+	fs := newFlagSet("doctor", "doctor [--strict]")
+	strict := fs.Bool("strict", false, "Parse every session log and list each one with lines that fail to parse")
+	fs.Parse(args)
+
+	checks := []session.Check{configCheck()}
+	loadConfig()
+	checks = append(checks, session.CheckEnvironment(*strict)...)
+	checks = append(checks, terminalChecks()...)
+
+	color := term.IsTerminal(int(os.Stdout.Fd()))
+	failed := false
+	for _, c := range checks {
+		mark, markColor := "✓", ui.Green
+		switch c.Result {
+		case session.CheckWarn:
+			mark, markColor = "!", ui.Yellow
+		case session.CheckFail:
+			mark, markColor = "✗", ui.Red
+			failed = true
+		}
+		if color {
+			mark = markColor + mark + ui.Reset
+		}
+		fmt.Printf("%s %-26s %s\n", mark, c.Name, c.Detail)
+		// Extra lines quote log content, such as samples of unparsable
+		// lines, so they are sanitized and kept to one terminal line.
+		for _, line := range c.Extra {
+			fmt.Printf("    %s\n", ui.Truncate(ui.Sanitize(line), maxExtraWidth))
+		}
+		if c.Hint != "" {
+			fmt.Printf("  → %s\n", c.Hint)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// maxExtraWidth is the longest extra line doctor prints, in runes.
+const maxExtraWidth = 160
+
+// configCheck checks that the config file, if there is one, parses.
+func configCheck() session.Check {
+	c := session.Check{Name: "Config file"}
+	path, _ := config.Path()
+	if _, err := config.Load(); err != nil {
+		c.Result, c.Detail = session.CheckFail, err.Error()
+		c.Hint = "Fix the JSON in " + path + "; until then csm ignores it"
+		return c
+	}
+	if _, err := os.Stat(path); err != nil {
+		c.Detail = "none (defaults)"
+		return c
+	}
+	c.Detail = path
+	return c
+}
+
+// terminalChecks checks what the live view needs from the terminal.
+func terminalChecks() []session.Check {
+	tty := session.Check{Name: "Terminal", Detail: "interactive"}
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		tty.Result, tty.Detail = session.CheckWarn, "output is not a terminal"
+		tty.Hint = "The live view needs one; `csm list --json` works anywhere"
+		return []session.Check{tty}
+	}
+	tty.Detail = fmt.Sprintf("%d×%d", width, height)
+	if width < 50 {
+		tty.Result = session.CheckWarn
+		tty.Hint = "Below 50 columns sessions are shown as stacked cards"
+	}
+
+	termType := session.Check{Name: "TERM", Detail: os.Getenv("TERM")}
+	if termType.Detail == "" || termType.Detail == "dumb" {
+		termType.Result, termType.Detail = session.CheckWarn, fmt.Sprintf("%q", termType.Detail)
+		termType.Hint = "Set TERM (e.g. xterm-256color); the live view uses colors and cursor movement"
+	}
+
+	locale := os.Getenv("LC_ALL")
+	if locale == "" {
+		locale = os.Getenv("LC_CTYPE")
+	}
+	if locale == "" {
+		locale = os.Getenv("LANG")
+	}
+	utf8 := session.Check{Name: "UTF-8", Detail: locale}
+	if l := strings.ToLower(locale); !strings.Contains(l, "utf-8") && !strings.Contains(l, "utf8") {
+		utf8.Result, utf8.Detail = session.CheckWarn, fmt.Sprintf("locale %q", locale)
+		utf8.Hint = "Use a UTF-8 locale (e.g. LANG=en_US.UTF-8); status symbols and badges are Unicode"
+	}
+	return []session.Check{tty, termType, utf8}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/logfiles"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// runDu implements `csm du`, a per-project disk usage report of the session
+// logs, largest first.
+func runDu(args []string) {
+	fs := newFlagSet("du", "du [--top N]")
+	top := fs.Int("top", 0, "Only show the N largest projects (0 shows all)")
+	fs.Parse(args)
+	loadConfig()
+
+	logs := walkProfiles()
+	if len(logs) == 0 {
+		fmt.Println("No session logs found.")
+		return
+	}
+	usage := logfiles.Usage(logs)
+
+	profileNames := make(map[string]string)
+	if session.MultiProfile() {
+		profiles, _ := session.Profiles()
+		for _, p := range profiles {
+			profileNames[p.Dir] = p.Name
+		}
+	}
+
+	fmt.Printf("%9s %6s %9s  %s\n", "SIZE", "LOGS", "LARGEST", "PROJECT")
+	for i, u := range usage {
+		if *top > 0 && i == *top {
+			fmt.Printf("%9s %6s %9s  ... and %d more projects\n", "", "", "", len(usage)-i)
+			break
+		}
+		name := session.ProjectName(filepath.Join(u.Root, "projects", u.Project))
+		if p := profileNames[u.Root]; p != "" {
+			name = "[" + p + "] " + name
+		}
+		fmt.Printf("%9s %6d %9s  %s\n", logfiles.FormatSize(u.Size), u.Logs, logfiles.FormatSize(u.Largest.Size), name)
+	}
+	fmt.Printf("%9s %6d %9s  total (%d projects)\n", logfiles.FormatSize(logfiles.TotalSize(logs)), len(logs), "", len(usage))
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/events"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/watcher"
+)
+
+// runEvents implements `csm events`: it watches sessions and prints one JSON
+// object per state transition (newline-delimited JSON), e.g. for jq. With
+// notifications on, events also carry whether they notified or why not.
+func runEvents(args []string) {
+	fs := newFlagSet("events", "events [flags]")
+	interval := fs.Duration("interval", 2*time.Second, "How often to check for changes")
+	notifyEnabled := addNotifyFlag(fs)
+	untilIdle := addUntilIdleFlag(fs)
+	fs.Parse(args)
+	notifier := setupNotifier(loadConfig(), *notifyEnabled)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	tracker := events.NewTracker()
+	enc := json.NewEncoder(os.Stdout)
+	watcher.New(*interval).Watch(ctx, func(sessions []session.Session) {
+		evs := tracker.Update(sessions, time.Now())
+		if notifier != nil {
+			evs = notifier.Process(evs)
+		}
+		for _, e := range evs {
+			enc.Encode(e)
+			if *untilIdle && e.Kind == events.AllIdle {
+				cancel()
+			}
+		}
+	})
+}
+
+// addUntilIdleFlag registers --until-idle, which stops the command once every
+// session that was working has finished.
+func addUntilIdleFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("until-idle", false, "Exit once no session is working any more (after at least one was)")
+}
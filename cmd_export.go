@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/export"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/transcript"
+)
+
+// historyFormats are the formats `csm export` writes the session history
+// in, rather than one session's transcript.
+var historyFormats = []string{export.ICS, export.Toggl, export.Timewarrior}
+
+// runExport implements `csm export <session-id>`, which writes a session's
+// conversation as a Markdown document for sharing, and `csm export --format
+// ics|toggl|timew`, which writes the session history for a calendar or a
+// time tracker.
+func runExport(args []string) {
+	fs := newFlagSet("export", "export <session-id> [--format md] [--output file]\n       csm export --format ics|toggl|timew [--days N] [--output file]")
+	format := fs.String("format", "md", "Output format: md (a session's transcript); ics, toggl or timew (the history as calendar events, a Toggl CSV import or timew track commands)")
+	output := fs.String("output", "", "Write to this file instead of stdout")
+	days := fs.Int("days", 30, "Number of days of history to export (ics, toggl, timew)")
+	fs.Parse(args)
+	// Allow flags after the session id too: csm export 3f2a --output s.md
+	id := fs.Arg(0)
+	if id != "" {
+		fs.Parse(fs.Args()[1:])
+	}
+	history := slices.Contains(historyFormats, *format)
+	switch {
+	case history && id == "" && fs.NArg() == 0:
+		exportHistory(*format, *days, *output, loadConfig())
+		return
+	case !history && *format != export.Markdown:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (want md, %s)\n", *format, strings.Join(historyFormats, ", "))
+		os.Exit(2)
+	case id == "" || fs.NArg() > 0 || history:
+		fs.Usage()
+		os.Exit(2)
+	}
+	loadConfig()
+
+	logFile, err := session.FindLogFile(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	t, err := transcript.Read(logFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", logFile, err)
+		os.Exit(1)
+	}
+
+	w, err := export.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer w.Close()
+	project := session.ProjectName(filepath.Dir(logFile))
+	if err := transcript.WriteMarkdown(w, t, project); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// exportHistory writes the sessions of the last days in one of the
+// historyFormats. Calendar events are titled by project and described by the
+// first prompt; time entries are tagged as configured in time_tracking.
+func exportHistory(format string, days int, output string, cfg *config.Config) {
+	sessions, err := session.DiscoverHistory(days)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering history: %v\n", err)
+		os.Exit(1)
+	}
+	sessions = session.ChainHistory(sessions)
+	tt := cfg.TimeTracking
+	if format == export.Toggl && tt.Email == "" {
+		fmt.Fprintf(os.Stderr, "Error: Toggl's import needs your email; set time_tracking.email in ~/.claude-monitor/config.json\n")
+		os.Exit(1)
+	}
+
+	w, err := export.Create(output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	switch format {
+	case export.ICS:
+		var events []export.Event
+		for _, s := range sessions {
+			events = append(events, export.Event{
+				UID:         strings.TrimSuffix(filepath.Base(s.LogFile), ".jsonl") + "@csm",
+				Start:       s.StartTime,
+				End:         s.EndTime,
+				Summary:     s.Project,
+				Description: s.FirstPrompt,
+			})
+		}
+		err = export.WriteICS(w, events, time.Now())
+	default:
+		// Time trackers reject entries without a duration.
+		var entries []export.TimeEntry
+		for _, s := range sessions {
+			if !s.EndTime.After(s.StartTime) {
+				continue
+			}
+			entries = append(entries, export.TimeEntry{
+				Start:       s.StartTime,
+				End:         s.EndTime,
+				Project:     s.Project,
+				Description: s.FirstPrompt,
+				Tags:        tt.ProjectTags(s.Project),
+			})
+		}
+		if format == export.Toggl {
+			err = export.WriteToggl(w, entries, tt.Email)
+		} else {
+			err = export.WriteTimewarrior(w, entries)
+		}
+	}
+	if err != nil {
+		w.Close()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := w.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
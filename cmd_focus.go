@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// runFocus implements `csm focus <project>`: it brings the terminal window
+// (or tmux pane) of the project's running session to the front.
+func runFocus(args []string) {
+	fs := newFlagSet("focus", "focus <project>")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	loadConfig()
+
+	sessions, err := session.Discover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering sessions: %v\n", err)
+		os.Exit(1)
+	}
+	var running []session.Session
+	for _, s := range sessions {
+		if s.GhostPID != 0 && !s.IsGhost {
+			running = append(running, s)
+		}
+	}
+	s, err := session.FindLatestSession(running, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := session.Focus(s); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
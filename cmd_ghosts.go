@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/ui"
+)
+
+// runGhosts implements `csm ghosts`, which lists ghost (orphaned) Claude
+// processes without terminating them, or with --log the processes csm has
+// terminated.
+func runGhosts(args []string) {
+	fs := newFlagSet("ghosts", "ghosts [--log]")
+	showLog := fs.Bool("log", false, "List the Claude processes csm has terminated, oldest first")
+	fs.Parse(args)
+	loadConfig()
+
+	if *showLog {
+		listKillLog()
+		return
+	}
+
+	ghosts, err := session.FindGhostProcesses()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding ghost processes: %v\n", err)
+		os.Exit(1)
+	}
+	if len(ghosts) == 0 {
+		fmt.Println("No ghost processes found.")
+		return
+	}
+	fmt.Printf("Found %d ghost process(es):\n\n", len(ghosts))
+	for _, g := range ghosts {
+		fmt.Printf("  PID %d - %s (inactive for %s)\n", g.PID, g.Project, session.FormatAge(g.Age))
+	}
+	fmt.Println("\nRun `csm kill` to terminate them.")
+}
+
+// runKill implements `csm kill`, which terminates ghost Claude processes.
+func runKill(args []string) {
+	fs := newFlagSet("kill", "kill")
+	fs.Parse(args)
+	setupStatsD(loadConfig(), "", 0)
+
+	handleKillGhosts()
+}
+
+// handleKillGhosts finds and terminates ghost Claude processes
+func handleKillGhosts() {
+	ghosts, err := session.FindGhostProcesses()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding ghost processes: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(ghosts) == 0 {
+		fmt.Println("No ghost processes found.")
+		return
+	}
+
+	fmt.Printf("Found %d ghost process(es):\n\n", len(ghosts))
+	for _, g := range ghosts {
+		fmt.Printf("  PID %d - %s (inactive for %s)\n", g.PID, g.Project, session.FormatAge(g.Age))
+	}
+	fmt.Println()
+
+	killed, err := session.KillGhostProcesses()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error killing ghost processes: %v\n", err)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	for _, g := range killed {
+		r := session.KillRecord{Time: now, PID: g.PID, Project: g.Project, SessionID: g.SessionID, Idle: g.Age, Via: "csm kill"}
+		if err := session.RecordKill(r); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not record the kill of PID %d: %v\n", g.PID, err)
+		}
+	}
+
+	if len(killed) == 0 {
+		fmt.Println("No processes were terminated (they may have already exited).")
+	} else {
+		fmt.Printf("Terminated %d ghost process(es).\n", len(killed))
+	}
+	if metrics != nil {
+		metrics.Count("ghosts.killed", int64(len(killed)))
+	}
+}
+
+// listKillLog prints the kill log: when each process was terminated, for
+// which project, how long its session had been idle and what terminated it.
+func listKillLog() {
+	records, err := session.LoadKillLog()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading kill log: %v\n", err)
+		os.Exit(1)
+	}
+	if len(records) == 0 {
+		fmt.Println("csm has not terminated any processes.")
+		return
+	}
+	fmt.Printf("%-16s %7s %6s  %-10s %s\n", "TERMINATED", "PID", "IDLE", "VIA", "PROJECT")
+	for _, r := range records {
+		fmt.Printf("%-16s %7d %6s  %-10s %s\n", ui.FormatDateTime(r.Time), r.PID, session.FormatAge(r.Idle), r.Via, r.Project)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/search"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/ui"
+)
+
+// runGrep implements `csm grep`, a full-text search over the prompts and
+// replies in session transcripts.
+func runGrep(args []string) {
+	fs := newFlagSet("grep", "grep <pattern> [--days N] [--project X] [-i] [--indexed] [--json]")
+	days := fs.Int("days", 30, "Only search sessions active in the last N days")
+	project := fs.String("project", "", "Only search projects whose name contains this")
+	ignoreCase := fs.Bool("i", false, "Case-insensitive match")
+	jsonOutput := fs.Bool("json", false, "Print matches as JSON lines")
+	indexed := fs.Bool("indexed", false, "Search the word index instead of scanning logs: the pattern is words that must all appear (any case), results are ranked")
+	limit := fs.Int("limit", 50, "With --indexed, show at most this many results")
+	fs.Parse(args)
+	// Allow flags after the pattern too: csm grep migration --days 90
+	pattern := fs.Arg(0)
+	if pattern != "" {
+		fs.Parse(fs.Args()[1:])
+	}
+	if pattern == "" || fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	loadConfig()
+	if *indexed {
+		grepIndexed(pattern, *days, *project, *limit, *jsonOutput)
+		return
+	}
+
+	if *ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid pattern: %v\n", err)
+		os.Exit(2)
+	}
+
+	profiles, err := session.Profiles()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	files := search.Files(profiles, time.Now().AddDate(0, 0, -*days), *project)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	printMatches := matchPrinter(*jsonOutput)
+	found := 0
+	search.Search(ctx, files, re, func(matches []search.Match) {
+		found += len(matches)
+		printMatches(matches)
+	})
+
+	if found == 0 {
+		os.Exit(1)
+	}
+}
+
+// grepIndexed answers `csm grep --indexed` from the word index, bringing it
+// up to date first (cheap when `csm daemon --search-index` keeps it fresh).
+func grepIndexed(query string, days int, project string, limit int, jsonOutput bool) {
+	ix, err := updateSearchIndex(true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: search index not saved: %v\n", err)
+	}
+	matches := ix.Query(query, time.Now().AddDate(0, 0, -days), project, limit)
+	matchPrinter(jsonOutput)(matches)
+	if len(matches) == 0 {
+		os.Exit(1)
+	}
+}
+
+// updateSearchIndex loads the search index, indexes what was written to the
+// logs since its last update and saves it. With verbose set, a first full
+// build is announced on stderr since it can take a while.
+func updateSearchIndex(verbose bool) (*search.Index, error) {
+	path, err := search.DefaultIndexPath()
+	if err != nil {
+		return search.NewIndex(), err
+	}
+	ix := search.LoadIndex(path)
+	if verbose && len(ix.Files) == 0 {
+		fmt.Fprintln(os.Stderr, "Building the search index; later searches only read new log lines...")
+	}
+	profiles, err := session.Profiles()
+	if err != nil {
+		return ix, err
+	}
+	if !ix.Update(search.Files(profiles, time.Time{}, "")) {
+		return ix, nil
+	}
+	return ix, ix.Save(path)
+}
+
+// matchPrinter returns a function printing matches as JSON lines or as
+// "project  time  role  snippet" lines, with the match highlighted on a
+// terminal.
+func matchPrinter(jsonOutput bool) func([]search.Match) {
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		return func(matches []search.Match) {
+			for _, m := range matches {
+				enc.Encode(m)
+			}
+		}
+	}
+	color := term.IsTerminal(int(os.Stdout.Fd()))
+	return func(matches []search.Match) {
+		for _, m := range matches {
+			// Sanitize the parts around the match separately, so its
+			// offsets still hold.
+			before, match, after := ui.Sanitize(m.Snippet[:m.Start]), ui.Sanitize(m.Snippet[m.Start:m.End]), ui.Sanitize(m.Snippet[m.End:])
+			snippet := before + match + after
+			if color {
+				snippet = before + ui.Bold + ui.Yellow + match + ui.Reset + after
+			}
+			fmt.Printf("%s  %s  %-9s  %s\n", ui.Sanitize(m.Project), ui.FormatDateTime(m.Timestamp), m.Role, snippet)
+		}
+	}
+}
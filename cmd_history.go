@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/export"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// runHistory implements `csm history`, which prints recently active sessions.
+func runHistory(args []string) {
+	fs := newFlagSet("history", "history [--days N] [--format table|csv|json|md] [--output file]")
+	days := fs.Int("days", 7, "Number of days of history to show")
+	report := addReportFlags(fs)
+	fs.Parse(args)
+	exporting := report.exporting()
+
+	loadConfig()
+	if !exporting {
+		listHistory(*days)
+		return
+	}
+
+	sessions, err := session.DiscoverHistory(*days)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering history: %v\n", err)
+		os.Exit(1)
+	}
+	sessions = session.ChainHistory(sessions)
+	if sessions == nil {
+		sessions = []session.HistorySession{}
+	}
+	t := export.Table{
+		Columns: []string{"start_time", "end_time", "duration_minutes", "project", "git_branch", "messages", "first_prompt", "log_file"},
+		Data:    sessions,
+	}
+	for _, s := range sessions {
+		t.Rows = append(t.Rows, []string{s.StartTime.Format(time.RFC3339), s.EndTime.Format(time.RFC3339),
+			strconv.Itoa(int(s.Duration.Minutes())), s.Project, s.GitBranch, strconv.Itoa(s.MessageCount), s.FirstPrompt, s.LogFile})
+	}
+	report.write(t)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/hub"
+	"github.com/itk-dev/claude-sessions-monitor/internal/web"
+)
+
+// runHub implements `csm hub`: a web dashboard and API serving the sessions
+// pushed by `csm agent` instances on other machines.
+func runHub(args []string) {
+	fs := newFlagSet("hub", "hub [flags]")
+	webPort := fs.Int("port", defaultWebPort, "Port for the hub dashboard and push endpoint")
+	listen := fs.String("listen", "127.0.0.1", "Interface to listen on, e.g. 0.0.0.0 for agents on other machines (needs --token)")
+	token := fs.String("token", os.Getenv("CSM_HUB_TOKEN"), "Shared secret agents must send (default $CSM_HUB_TOKEN)")
+	expiry := fs.Duration("expiry", time.Minute, "Drop hosts that have not reported for this long")
+	fs.Parse(args)
+
+	if *token == "" && !hub.Loopback(*listen) {
+		fmt.Fprintf(os.Stderr, "Error: --listen %s accepts connections from other machines; set --token (or $CSM_HUB_TOKEN)\n", *listen)
+		os.Exit(2)
+	}
+
+	store := hub.NewStore(*expiry)
+	srv := web.NewServer(*webPort)
+	srv.SetHost(*listen)
+	srv.SetSource(store.Sessions)
+	srv.RemoteOnly()
+	srv.SetAuth(func(h http.Handler) http.Handler { return hub.RequireToken(*token, h) })
+	srv.Handle(hub.PushPath, store.PushHandler(*token))
+	serveWeb(srv, "csm hub")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/ui"
+)
+
+// runLimits implements `csm limits`, which projects this week's token use
+// forward against the weekly usage limit.
+func runLimits(args []string) {
+	fs := newFlagSet("limits", "limits [--json]")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	fs.Parse(args)
+	cfg := loadConfig()
+
+	// The usage API knows when the week resets and how much of it is used.
+	var quota *session.QuotaBucket
+	if q := session.FetchAPIQuota(); q.Available && q.SevenDay != nil {
+		quota = q.SevenDay
+	}
+
+	now := time.Now()
+	var start, reset time.Time
+	switch {
+	case cfg.Limits.Reset != "":
+		var err error
+		start, reset, err = session.WeekBounds(cfg.Limits.Reset, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: limits: %v\n", err)
+			os.Exit(1)
+		}
+	case quota != nil && quota.ResetsAt != nil && quota.ResetsAt.After(now):
+		reset = *quota.ResetsAt
+		start = reset.AddDate(0, 0, -7)
+	default:
+		start, reset, _ = session.WeekBounds("Mon", now)
+	}
+
+	week := session.ComputeWeek(start, reset, cfg.Limits.WeeklyTokens)
+	source := "config"
+	if cfg.Limits.WeeklyTokens == 0 {
+		source = ""
+		if quota != nil && quota.Utilization > 0 && week.Used > 0 {
+			week = week.WithLimit(int(float64(week.Used) * 100 / quota.Utilization))
+			source = fmt.Sprintf("estimated: the usage API reports %.0f%% used", quota.Utilization)
+		}
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(week); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering JSON: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	ui.RenderLimits(week, source)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// runList implements `csm list`, which prints the current sessions once.
+func runList(args []string) {
+	fs := newFlagSet("list", "list [flags]")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	oneline := fs.Bool("oneline", false, "Print a one-line summary, e.g. \"2 working, 1 needs input (org/api)\"")
+	columns := addColumnsFlag(fs)
+	remoteHosts := addRemoteFlag(fs)
+	checkBudget := addCheckBudgetFlag(fs)
+	addOnlyNeedsInputFlag(fs)
+	addActiveWithinFlag(fs)
+	addShowHeadlessFlag(fs)
+	fs.Parse(args)
+
+	if *jsonOutput && *oneline {
+		fmt.Fprintf(os.Stderr, "Error: --json and --oneline are mutually exclusive\n")
+		os.Exit(2)
+	}
+	if activeWithin < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --active-within must be positive\n")
+		os.Exit(2)
+	}
+
+	cfg := loadConfig()
+	setupRemote(*remoteHosts)
+	applyColumns(cfg, *columns)
+	applyHighlights(cfg)
+	applyProjectLinks(cfg)
+	sessions := listSessions(*jsonOutput, *oneline)
+	if *checkBudget {
+		exitIfOverBudget(sessions)
+	}
+}
+
+// addCheckBudgetFlag registers --check-budget, shared by `csm list` and the
+// legacy -l flag.
+func addCheckBudgetFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("check-budget", false, "Exit with status 1 if a listed session or today's usage is over the configured budget")
+}
+
+// exitIfOverBudget reports the listed sessions and the day's usage that are
+// over budget on stderr, and exits 1 if there are any.
+func exitIfOverBudget(sessions []session.Session) {
+	b := session.CurrentBudget()
+	if b.IsZero() {
+		fmt.Fprintf(os.Stderr, "Error: --check-budget needs a budget in the config file\n")
+		os.Exit(2)
+	}
+	over := false
+	for _, s := range sessions {
+		if s.OverBudget {
+			fmt.Fprintf(os.Stderr, "Over budget: %s (%d tokens, $%.2f)\n", s.Project, s.Tokens, s.Cost)
+			over = true
+		}
+	}
+	if b.DailyTokens > 0 || b.DailyCost > 0 {
+		if today := usageToday(); session.OverDailyBudget(today) {
+			fmt.Fprintf(os.Stderr, "Over daily budget: %d tokens, $%.2f today\n", today.TotalTokens, today.Cost)
+			over = true
+		}
+	}
+	if over {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/export"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/ui"
+)
+
+// runModels implements `csm models`, the tokens, estimated cost and sessions
+// of each model family over the last days.
+func runModels(args []string) {
+	fs := newFlagSet("models", "models [--days N] [--format table|csv|json|md] [--output file]")
+	days := fs.Int("days", 7, "Number of days to total, today included")
+	report := addReportFlags(fs)
+	fs.Parse(args)
+	exporting := report.exporting()
+	loadConfig()
+
+	if *days < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --days must be at least 1\n")
+		os.Exit(2)
+	}
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day()-(*days-1), 0, 0, 0, 0, now.Location())
+	models := session.ComputeModelUsage(since)
+
+	if exporting {
+		if models == nil {
+			models = []session.ModelUsage{}
+		}
+		t := export.Table{
+			Columns: []string{"model", "total_tokens", "input_tokens", "output_tokens", "cache_tokens", "cost", "sessions"},
+			Data:    models,
+		}
+		for _, m := range models {
+			t.Rows = append(t.Rows, []string{m.Model, strconv.Itoa(m.Tokens), strconv.Itoa(m.Input), strconv.Itoa(m.Output),
+				strconv.Itoa(m.Cache), fmt.Sprintf("%.2f", m.Cost), strconv.Itoa(m.Sessions)})
+		}
+		report.write(t)
+		return
+	}
+	ui.RenderModels(models, *days)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/logfiles"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// runPrune implements `csm prune`, which deletes session logs older than a
+// given age, optionally archiving them first.
+func runPrune(args []string) {
+	fs := newFlagSet("prune", "prune [--older-than 60d] [--archive file.tar.zst] [--dry-run]")
+	olderThan := fs.String("older-than", "60d", "Prune logs last written longer ago than this (e.g. 60d, 2w, 36h)")
+	archive := fs.String("archive", "", "Archive the logs to this `file` (.tar.zst, .tar.gz or .tar) before deleting them")
+	dryRun := fs.Bool("dry-run", false, "List what would be pruned and the space reclaimed, without touching anything")
+	yes := fs.Bool("yes", false, "Don't ask for confirmation")
+	fs.Parse(args)
+
+	age, err := logfiles.ParseAge(*olderThan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --older-than: %v\n", err)
+		os.Exit(2)
+	}
+	loadConfig()
+
+	logs := walkProfiles()
+	old := logfiles.OlderThan(logs, time.Now().Add(-age))
+	if len(old) == 0 {
+		fmt.Printf("No logs older than %s.\n", *olderThan)
+		return
+	}
+
+	size := logfiles.TotalSize(old)
+	if *dryRun {
+		for _, l := range old {
+			fmt.Printf("%s  %9s  %s\n", l.ModTime.Format("2006-01-02"), logfiles.FormatSize(l.Size), l.Path)
+		}
+		fmt.Printf("\n%d logs older than %s, %s would be reclaimed.\n", len(old), *olderThan, logfiles.FormatSize(size))
+		return
+	}
+
+	action := "Delete"
+	if *archive != "" {
+		action = "Archive to " + *archive + " and delete"
+	}
+	if !*yes && !confirm(fmt.Sprintf("%s %d logs older than %s (%s)?", action, len(old), *olderThan, logfiles.FormatSize(size))) {
+		fmt.Println("Cancelled.")
+		return
+	}
+
+	if *archive != "" {
+		if err := logfiles.Archive(*archive, old); err != nil {
+			fmt.Fprintf(os.Stderr, "Error archiving logs: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Archived %d logs to %s.\n", len(old), *archive)
+	}
+
+	deleted, err := logfiles.Delete(old)
+	fmt.Printf("Deleted %d logs, reclaimed %s.\n", len(deleted), logfiles.FormatSize(logfiles.TotalSize(deleted)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error deleting logs: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// walkProfiles returns the logs of every monitored profile. Profiles whose
+// projects directory can't be read are reported and skipped.
+func walkProfiles() []logfiles.Log {
+	profiles, err := session.Profiles()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var logs []logfiles.Log
+	for _, p := range profiles {
+		found, err := logfiles.Walk(p.Dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		logs = append(logs, found...)
+	}
+	return logs
+}
+
+// confirm asks a yes/no question on the terminal; anything but y/yes is no.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
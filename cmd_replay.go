@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/ui"
+)
+
+// runReplay implements `csm replay <session-id>`, which plays a session's log
+// back in time order, showing how its status and context changed, to
+// post-mortem a run that went wrong.
+func runReplay(args []string) {
+	fs := newFlagSet("replay", "replay <session-id> [--speed N] [--step]")
+	speed := fs.Float64("speed", 10, "Playback speed, as a multiple of real time")
+	maxGap := fs.Duration("max-gap", 2*time.Second, "Longest pause between entries during playback, however long the real gap")
+	step := fs.Bool("step", false, "Start paused; space steps one entry at a time")
+	fs.Parse(args)
+	// Allow flags after the session id too: csm replay 3f2a --speed 60
+	id := fs.Arg(0)
+	if id != "" {
+		fs.Parse(fs.Args()[1:])
+	}
+	if id == "" || fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *speed <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --speed must be positive\n")
+		os.Exit(2)
+	}
+	loadConfig()
+
+	logFile, err := session.FindLogFile(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	frames, err := session.LoadReplay(logFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", logFile, err)
+		os.Exit(1)
+	}
+	if len(frames) == 0 {
+		fmt.Println("Nothing to replay: the log has no timestamped entries.")
+		return
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	winchCh := make(chan os.Signal, 1)
+	signal.Notify(winchCh, syscall.SIGWINCH)
+	defer signal.Stop(winchCh)
+
+	if err := ui.SetupRawInput(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error setting up keyboard input: %v\n", err)
+		os.Exit(1)
+	}
+	keyCh := make(chan rune, 1)
+	done := make(chan struct{})
+	go ui.ReadKey(keyCh, done)
+
+	ui.HideCursor()
+	defer func() {
+		close(done)
+		ui.CleanupRawInput()
+		ui.ShowCursor()
+		ui.ClearScreen()
+	}()
+
+	view := ui.ReplayView{
+		Project:   session.ProjectName(filepath.Dir(logFile)),
+		SessionID: strings.TrimSuffix(filepath.Base(logFile), ".jsonl"),
+		Frames:    frames,
+		Speed:     *speed,
+		Paused:    *step,
+	}
+
+	// The timer fires when playback reaches the next frame. Gaps are scaled
+	// by the speed and capped so idle stretches don't stall the replay.
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	schedule := func() {
+		timer.Stop()
+		if view.Paused || view.Pos == len(frames)-1 {
+			return
+		}
+		gap := time.Duration(float64(frames[view.Pos+1].Time.Sub(frames[view.Pos].Time)) / view.Speed)
+		timer.Reset(min(max(gap, 0), *maxGap))
+	}
+	move := func(delta int) {
+		view.Paused = true
+		view.Pos = min(max(view.Pos+delta, 0), len(frames)-1)
+	}
+
+	ui.ClearScreen()
+	ui.RenderReplay(view)
+	schedule()
+	for {
+		select {
+		case <-sigCh:
+			return
+		case <-winchCh:
+			ui.ClearScreen()
+		case <-timer.C:
+			view.Pos++
+			if view.Pos == len(frames)-1 {
+				view.Paused = true
+			}
+		case key := <-keyCh:
+			switch key {
+			case ' ':
+				if view.Paused {
+					move(1)
+				} else {
+					view.Paused = true
+				}
+			case ui.KeyRight:
+				move(1)
+			case ui.KeyLeft:
+				move(-1)
+			case 'p', 'P', '\r':
+				if view.Pos == len(frames)-1 {
+					view.Pos = 0
+				}
+				view.Paused = false
+			case '+', '=':
+				view.Speed *= 2
+			case '-':
+				view.Speed = max(view.Speed/2, 0.25)
+			case 'q', 'Q', 3: // 3 is Ctrl+C
+				return
+			}
+		}
+		ui.RenderReplay(view)
+		schedule()
+	}
+}
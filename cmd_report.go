@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/export"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// runReport implements `csm report`, a usage review of the last days as a
+// self-contained HTML page for people who don't live in a terminal.
+func runReport(args []string) {
+	fs := newFlagSet("report", "report [--days N] [--format html] [--output file]")
+	days := fs.Int("days", 30, "Number of days to cover, today included")
+	format := fs.String("format", export.HTML, "Output format: html")
+	output := fs.String("output", "", "Write the report to this `file` instead of stdout")
+	fs.Parse(args)
+	if *format != export.HTML {
+		fmt.Fprintf(os.Stderr, "Error: --format must be html\n")
+		os.Exit(2)
+	}
+	if *days < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --days must be at least 1\n")
+		os.Exit(2)
+	}
+	loadConfig()
+
+	history, err := session.DiscoverHistory(*days)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering history: %v\n", err)
+		os.Exit(1)
+	}
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day()-(*days-1), 0, 0, 0, 0, now.Location())
+	r := buildReport(from, now, history, session.ComputeUsageSince(from))
+
+	w, err := export.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := export.WriteHTMLReport(w, r); err != nil {
+		w.Close()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := w.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// buildReport totals the days from midnight from until now. Active time is
+// each session's span clipped to the day it falls in, as in the live view's
+// header; tokens and cost come from usage.
+func buildReport(from, now time.Time, history []session.HistorySession, usage *session.UsageStats) export.Report {
+	r := export.Report{
+		Title:     "Claude usage",
+		From:      from,
+		To:        time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+		Generated: now,
+		Tokens:    usage.TotalTokens,
+		Cost:      usage.Cost,
+	}
+	for day := from; !day.After(r.To); day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location()) {
+		r.Days = append(r.Days, export.ReportDay{Day: day})
+	}
+
+	projects := make(map[string]*export.ReportProject)
+	project := func(name string) *export.ReportProject {
+		p, ok := projects[name]
+		if !ok {
+			p = &export.ReportProject{Name: name}
+			projects[name] = p
+		}
+		return p
+	}
+	for _, s := range history {
+		if s.EndTime.Before(from) {
+			continue
+		}
+		var active time.Duration
+		for i := range r.Days {
+			d := &r.Days[i]
+			end := time.Date(d.Day.Year(), d.Day.Month(), d.Day.Day()+1, 0, 0, 0, 0, d.Day.Location())
+			start, stop := s.StartTime, s.EndTime
+			if start.Before(d.Day) {
+				start = d.Day
+			}
+			if stop.After(end) {
+				stop = end
+			}
+			if stop.Before(start) || !s.StartTime.Before(end) {
+				continue
+			}
+			d.Active += stop.Sub(start)
+			d.Sessions++
+			active += stop.Sub(start)
+		}
+		p := project(s.Project)
+		p.Active += active
+		p.Sessions++
+		r.Active += active
+		r.Sessions++
+	}
+	for _, su := range usage.Sessions {
+		p := project(su.Project)
+		p.Tokens += su.TotalTokens
+		p.Cost += su.Cost
+	}
+
+	for _, p := range projects {
+		r.Projects = append(r.Projects, *p)
+	}
+	sort.Slice(r.Projects, func(i, j int) bool {
+		a, b := r.Projects[i], r.Projects[j]
+		if a.Tokens != b.Tokens {
+			return a.Tokens > b.Tokens
+		}
+		if a.Active != b.Active {
+			return a.Active > b.Active
+		}
+		return a.Name < b.Name
+	})
+	return r
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// runResume implements `csm resume [--exec] <project>`: it prints the
+// `claude --resume` command for the project's most recent session, or with
+// --exec replaces csm with that claude process.
+func runResume(args []string) {
+	fs := newFlagSet("resume", "resume [--exec] <project>")
+	execClaude := fs.Bool("exec", false, "Run claude --resume directly instead of printing the command")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	loadConfig()
+
+	sessions, err := session.Discover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering sessions: %v\n", err)
+		os.Exit(1)
+	}
+	s, err := session.FindLatestSession(sessions, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !*execClaude {
+		fmt.Println(session.ResumeCommand(s))
+		return
+	}
+
+	claude, err := exec.LookPath("claude")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: claude not found in PATH\n")
+		os.Exit(1)
+	}
+	if s.CWD != "" {
+		if err := os.Chdir(s.CWD); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	err = syscall.Exec(claude, []string{"claude", "--resume", s.SessionID}, os.Environ())
+	fmt.Fprintf(os.Stderr, "Error running claude: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/ui"
+)
+
+// runTop implements `csm top`, a live view ranking sessions by how fast they
+// are burning tokens, to find the culprit when the rate limit is draining.
+func runTop(args []string) {
+	fs := newFlagSet("top", "top [flags]")
+	interval := fs.Duration("interval", 2*time.Second, "Refresh interval")
+	window := fs.Duration("window", 5*time.Minute, "Window the burn rate is averaged over")
+	sortBy := fs.String("sort", ui.TopByTokens, "Sort by `tokens` per minute or cost per hour")
+	fs.Parse(args)
+
+	if *sortBy != ui.TopByTokens && *sortBy != ui.TopByCost {
+		fmt.Fprintf(os.Stderr, "Error: --sort must be %q or %q\n", ui.TopByTokens, ui.TopByCost)
+		os.Exit(2)
+	}
+	if *window <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --window must be positive\n")
+		os.Exit(2)
+	}
+
+	loadConfig()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	winchCh := make(chan os.Signal, 1)
+	signal.Notify(winchCh, syscall.SIGWINCH)
+	defer signal.Stop(winchCh)
+
+	if err := ui.SetupRawInput(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error setting up keyboard input: %v\n", err)
+		os.Exit(1)
+	}
+	keyCh := make(chan rune, 1)
+	done := make(chan struct{})
+	go ui.ReadKey(keyCh, done)
+
+	ui.HideCursor()
+	defer func() {
+		close(done)
+		ui.CleanupRawInput()
+		ui.ShowCursor()
+		ui.ClearScreen()
+	}()
+
+	meter := session.NewRateMeter(*window)
+	render := func() {
+		sessions, _ := session.Discover()
+		now := time.Now()
+		rows := make([]ui.TopRow, 0, len(sessions))
+		seen := make(map[string]bool, len(sessions))
+		for _, s := range sessions {
+			rows = append(rows, ui.TopRow{Session: s, Rate: meter.Rate(s.LogFile, now)})
+			seen[s.LogFile] = true
+		}
+		meter.Forget(seen)
+		ui.SortTop(rows, *sortBy)
+		ui.RenderTop(rows, *sortBy)
+	}
+
+	ui.ClearScreen()
+	render()
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sigCh:
+			return
+		case <-winchCh:
+			ui.ClearScreen()
+			render()
+		case key := <-keyCh:
+			switch key {
+			case 't', 'T':
+				*sortBy = ui.TopByTokens
+				render()
+			case 'c', 'C':
+				*sortBy = ui.TopByCost
+				render()
+			case 'q', 'Q', 3: // 3 is Ctrl+C
+				return
+			}
+		case <-ticker.C:
+			render()
+		}
+	}
+}
@@ -0,0 +1,1010 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"os/user"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/events"
+	"github.com/itk-dev/claude-sessions-monitor/internal/notify"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/ui"
+	"github.com/itk-dev/claude-sessions-monitor/internal/update"
+	"github.com/itk-dev/claude-sessions-monitor/internal/watcher"
+	"github.com/itk-dev/claude-sessions-monitor/internal/web"
+)
+
+// runWatch implements `csm watch`, the interactive live view. A bare `csm`
+// invocation runs the same view via the legacy flags. `csm watch <project>`
+// follows a single session in a focused view instead.
+func runWatch(args []string) {
+	fs := newFlagSet("watch", "watch [<project>] [flags]")
+	interval := fs.Duration("interval", 2*time.Second, "Refresh interval for live view")
+	webMode := fs.Bool("web", false, "Also start the web dashboard server")
+	webPort := fs.Int("port", defaultWebPort, "Port for web dashboard")
+	columns := addColumnsFlag(fs)
+	remoteHosts := addRemoteFlag(fs)
+	statsdAddr := addStatsDFlag(fs)
+	notifyEnabled := addNotifyFlag(fs)
+	addOnlyNeedsInputFlag(fs)
+	addShowHeadlessFlag(fs)
+	addNoMouseFlag(fs)
+	untilIdle := addUntilIdleFlag(fs)
+	fs.Parse(args)
+	// Allow flags after the project too: csm watch api --interval 1s
+	project := fs.Arg(0)
+	if project != "" {
+		fs.Parse(fs.Args()[1:])
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg := loadConfig()
+	if project != "" {
+		runProjectView(project, *interval)
+		return
+	}
+	setupRemote(*remoteHosts)
+	setupStatsD(cfg, *statsdAddr, *interval)
+	applyColumns(cfg, *columns)
+	applyHighlights(cfg)
+	applyProjectLinks(cfg)
+	runLiveView(cfg, *interval, *webMode, *webPort, setupNotifier(cfg, *notifyEnabled), *untilIdle)
+}
+
+// noMouse is set by --no-mouse, which leaves the mouse to the terminal (for
+// selecting text) instead of the live view.
+var noMouse bool
+
+// addNoMouseFlag registers --no-mouse for the live view.
+func addNoMouseFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&noMouse, "no-mouse", false, "Leave the mouse to the terminal; by default clicks select rows and sort by a column, and the wheel scrolls")
+}
+
+// ViewMode represents the current display mode
+type ViewMode int
+
+const (
+	ViewModeLive ViewMode = iota
+	ViewModeHistory
+	ViewModeUsage
+	ViewModeChanges
+)
+
+// flashDuration is how long an action's feedback message stays in the live view footer.
+const flashDuration = 5 * time.Second
+
+func runLiveView(cfg *config.Config, interval time.Duration, webEnabled bool, webPort int, notifier *notify.Notifier, untilIdle bool) {
+	// Set up signal handling for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	// Re-layout as soon as the terminal is resized rather than on the next tick.
+	winchCh := make(chan os.Signal, 1)
+	signal.Notify(winchCh, syscall.SIGWINCH)
+	defer signal.Stop(winchCh)
+
+	// Start web server in background if requested
+	var webURL string
+	var webBrowseURL string
+	if webEnabled {
+		if web.ProbeCSMServer(webPort) {
+			webBrowseURL = fmt.Sprintf("http://localhost:%d", webPort)
+			webURL = webBrowseURL + " (existing server)"
+		} else {
+			srv := web.NewServer(webPort)
+			srv.SetSource(discoverSessions)
+			webErrCh, err := srv.Start(ctx)
+			if err != nil {
+				cancel()
+				fmt.Fprintf(os.Stderr, "Web server error: %v\n", err)
+				os.Exit(1)
+			}
+			go func() {
+				if err := <-webErrCh; err != nil {
+					fmt.Fprintf(os.Stderr, "\nWeb server error: %v\n", err)
+				}
+			}()
+			webBrowseURL = "http://" + srv.Addr()
+			webURL = webBrowseURL
+		}
+	}
+
+	// Poll remote hosts in the background; renders use the latest results
+	if remotePoller != nil {
+		go remotePoller.Run(ctx, remotePollInterval)
+	}
+
+	// Set up keyboard input
+	if err := ui.SetupRawInput(); err != nil {
+		cancel()
+		fmt.Fprintf(os.Stderr, "Error setting up keyboard input: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Start keyboard reader
+	keyCh := make(chan rune, 1)
+	done := make(chan struct{})
+	go ui.ReadKey(keyCh, done)
+
+	// Track current view mode
+	viewMode := ViewModeLive
+	historyDays := 7
+
+	// Claude status: fetch on-demand (user interaction), use cached on ticker
+	var lastClaudeStatus *session.ClaudeStatus
+	refreshClaudeStatus := func() {
+		lastClaudeStatus = session.FetchClaudeStatus()
+	}
+
+	// Hide cursor and ensure cleanup on exit
+	ui.HideCursor()
+	if !noMouse {
+		ui.EnableMouse()
+	}
+	defer func() {
+		close(done)
+		if !noMouse {
+			ui.DisableMouse()
+		}
+		ui.CleanupRawInput()
+		ui.ShowCursor()
+		ui.ResetTerminalTitle()
+		if cfg.TerminalProgress {
+			ui.ResetTerminalProgress()
+		}
+		if cfg.ITerm2 {
+			ui.ResetITerm2()
+		}
+		ui.ClearScreen()
+		fmt.Println("Goodbye!")
+	}()
+
+	// Throttle history view refreshes (data changes infrequently)
+	var lastHistoryRender time.Time
+
+	// The split view shows today's finished sessions under the live table.
+	// Like the history view, that list is only re-read every 30 seconds.
+	var splitView bool
+	var today []session.HistorySession
+	var todayAt time.Time
+	finishedToday := func(active []session.Session) []session.HistorySession {
+		if time.Since(todayAt) >= 30*time.Second {
+			today, _ = session.DiscoverHistory(1)
+			todayAt = time.Now()
+		}
+		var out []session.HistorySession
+		for _, h := range today {
+			if session.GetDateGroup(h.EndTime) != "Today" ||
+				slices.ContainsFunc(active, func(s session.Session) bool { return s.LogFile == h.LogFile }) {
+				continue
+			}
+			out = append(out, h)
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].EndTime.After(out[j].EndTime) })
+		return out
+	}
+
+	// Row selection in the live view is tracked by log file so it follows the
+	// session when rows re-sort between refreshes.
+	var rows []session.Session
+	var selected string
+	var scroll int // rows scrolled off the top with the mouse wheel
+	var perf bool  // the hidden perf overlay (P)
+	var flash string
+	var flashAt time.Time
+
+	// killTarget is the row awaiting a y/N answer after `x`; while set, the
+	// next key answers the prompt instead of acting normally.
+	var killTarget *session.Session
+
+	setFlash := func(msg string) {
+		flash = msg
+		flashAt = time.Now()
+	}
+
+	// selectedSession returns the currently selected live row, if any.
+	selectedSession := func() (session.Session, bool) {
+		for _, s := range rows {
+			if s.LogFile == selected {
+				return s, true
+			}
+		}
+		return session.Session{}, false
+	}
+
+	// moveSelection moves the selection by delta rows, clamped to the table.
+	moveSelection := func(delta int) {
+		if len(rows) == 0 {
+			return
+		}
+		idx := 0
+		for i, s := range rows {
+			if s.LogFile == selected {
+				idx = i + delta
+				break
+			}
+		}
+		if idx < 0 {
+			idx = 0
+		}
+		if idx >= len(rows) {
+			idx = len(rows) - 1
+		}
+		selected = rows[idx].LogFile
+		scroll = min(scroll, idx)
+	}
+
+	// click acts on a mouse event in the live view: a click on a row selects
+	// it, one on a column header sorts by that column (again to reverse),
+	// and the wheel scrolls the table.
+	click := func(m ui.Mouse) {
+		switch m.Button {
+		case ui.MouseWheelUp:
+			scroll = max(scroll-1, 0)
+		case ui.MouseWheelDown:
+			scroll = min(scroll+1, max(len(rows)-1, 0))
+		case ui.MouseLeft:
+			if logFile := ui.RowAt(m.X, m.Y); logFile != "" {
+				selected = logFile
+			} else if key := ui.HeaderAt(m.X, m.Y); key != "" {
+				current, reverse := ui.LiveSort()
+				ui.SetLiveSort(key, key == current && !reverse)
+			}
+		}
+	}
+
+	identity := headerIdentity(cfg)
+
+	// Look for a newer release in the background (at most once a day).
+	var updateHint string
+	updateCh := make(chan string, 1)
+	if cfg.UpdateCheckEnabled() {
+		go func() { updateCh <- update.Available(ctx, version) }()
+	}
+
+	// The bottom status bar's token and quota figures need a log scan and an
+	// API call, so they are refreshed in the background once a minute. The
+	// same scan checks the daily budget.
+	var statusBar *ui.StatusBar
+	var todayUsage *session.UsageStats
+	statusBarCh := make(chan statusUpdate, 1)
+	go func() {
+		ticker := time.NewTicker(usageMetricsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case statusBarCh <- gatherStatusBar():
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// The tracker follows every refresh, whichever view is showing: it feeds
+	// notifications and stamps each session with when it entered its status.
+	// It sees every session, before --only-needs-input filtering.
+	// recent keeps the latest status transitions for the changes panel.
+	tracker := events.NewTracker()
+	recent := events.NewRecent(events.DefaultRecent)
+	track := func(sessions []session.Session) []session.Session {
+		evs := tracker.Update(sessions, time.Now())
+		recent.Add(evs)
+		if notifier != nil {
+			notifier.Process(evs)
+		}
+		if untilIdle && slices.ContainsFunc(evs, func(e events.Event) bool { return e.Kind == events.AllIdle }) {
+			cancel()
+		}
+		// Discover's cached result is shared with the web server's
+		// goroutines, so StatusSince is set on a copy of it, whatever
+		// filtering does.
+		sessions = slices.Clone(filterSessions(sessions))
+		for i := range sessions {
+			if since, ok := tracker.Since(sessions[i]); ok {
+				sessions[i].StatusSince = since
+			}
+		}
+		return sessions
+	}
+	trackInBackground := func() {
+		if sessions, err := discoverSessions(); err == nil {
+			track(sessions)
+		}
+	}
+
+	// Render function that respects current mode
+	render := func() {
+		switch viewMode {
+		case ViewModeHistory:
+			ui.ClearScreen()
+			sessions, _ := session.DiscoverHistory(historyDays)
+			ui.RenderHistory(session.ChainHistory(sessions), historyDays, true)
+		case ViewModeUsage:
+			ui.ClearScreen()
+			usage := session.ComputeUsage()
+			apiQuota := session.FetchAPIQuota()
+			ui.RenderUsage(usage, apiQuota, true)
+		case ViewModeChanges:
+			ui.RenderChanges(recent.Events())
+		default:
+			start := time.Now()
+			all, _ := discoverSessions()
+			discovered := time.Since(start)
+			sessions := track(all)
+			rows = ui.LiveRows(sessions)
+			scroll = min(scroll, max(len(rows)-1, 0))
+			if _, ok := selectedSession(); !ok && len(rows) > 0 {
+				selected = rows[0].LogFile
+			}
+			if flash != "" && time.Since(flashAt) > flashDuration {
+				flash = ""
+			}
+			message := flash
+			if killTarget != nil {
+				message = fmt.Sprintf("Terminate PID %d (%s)? y/N", killTarget.GhostPID, killTarget.Project)
+			}
+			if message == "" && remotePoller != nil {
+				if errs := remotePoller.Errors(); len(errs) > 0 {
+					message = "remote " + errs[0].Error()
+				}
+			}
+			var totals *ui.TodayTotals
+			if todayUsage != nil {
+				totals = todayTotals(todayUsage, all, time.Now())
+			}
+			var finished []session.HistorySession
+			if splitView {
+				finished = finishedToday(rows)
+			}
+			ui.RenderLive(sessions, ui.LiveOptions{
+				WebURL:       webURL,
+				ClaudeStatus: lastClaudeStatus,
+				Selected:     selected,
+				Message:      message,
+				Notify:       notifier != nil,
+				UpdateHint:   updateHint,
+				NeedsInput:   onlyNeedsInput,
+				Split:        splitView,
+				Today:        finished,
+				StatusBar:    statusBar,
+				TodayTotals:  totals,
+				Hidden:       countRunning(all) - len(rows),
+				Identity:     identity,
+				Tmux:         os.Getenv("TMUX") != "",
+				Progress:     cfg.TerminalProgress,
+				ITerm2:       cfg.ITerm2,
+				Scroll:       scroll,
+				Perf:         perf,
+			})
+			slog.Debug("live refresh", "discover", discovered.String(), "duration", time.Since(start).String())
+		}
+	}
+
+	// Initial render
+	refreshClaudeStatus()
+	render()
+
+	// Main loop with both watcher and keyboard input. New projects and
+	// sessions are shown as soon as their logs are created.
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	changes := watcher.Changes(ctx, watcher.DefaultProbe)
+
+	for {
+		select {
+		case <-sigCh:
+			cancel()
+			return
+		case <-ctx.Done():
+			return
+		case <-winchCh:
+			ui.ClearScreen()
+			render()
+			if viewMode == ViewModeHistory {
+				lastHistoryRender = time.Now()
+			}
+		case u := <-statusBarCh:
+			if u.bar.OverBudget && (statusBar == nil || !statusBar.OverBudget) && notifier != nil {
+				notifier.Process([]events.Event{events.DailyBudgetExceeded(time.Now(), u.today)})
+			}
+			statusBar = u.bar
+			todayUsage = u.today
+			if viewMode == ViewModeLive {
+				render()
+			}
+		case updateHint = <-updateCh:
+			if updateHint != "" && viewMode == ViewModeLive {
+				render()
+			}
+		case key := <-keyCh:
+			if m, ok := ui.MouseEvent(key); ok {
+				if viewMode == ViewModeLive && killTarget == nil {
+					click(m)
+					render()
+				}
+				continue
+			}
+			if killTarget != nil {
+				if key == 'y' || key == 'Y' {
+					setFlash(killSession(*killTarget))
+				} else {
+					setFlash("Cancelled")
+				}
+				killTarget = nil
+				render()
+				continue
+			}
+			switch key {
+			case 'h', 'H':
+				if viewMode != ViewModeHistory {
+					viewMode = ViewModeHistory
+					render()
+					lastHistoryRender = time.Now()
+				}
+			case 'l':
+				if viewMode != ViewModeLive {
+					viewMode = ViewModeLive
+					refreshClaudeStatus()
+					render()
+				}
+			case 's', 'S':
+				if viewMode == ViewModeLive {
+					splitView = !splitView
+					todayAt = time.Time{}
+					render()
+				}
+			case 'u', 'U':
+				if viewMode != ViewModeUsage {
+					viewMode = ViewModeUsage
+					render()
+				}
+			case 'r', 'R':
+				if viewMode == ViewModeUsage {
+					render()
+				}
+			case 'P':
+				if viewMode == ViewModeLive {
+					perf = !perf
+					render()
+				}
+			case 'e', 'E':
+				if viewMode != ViewModeChanges {
+					viewMode = ViewModeChanges
+					ui.ClearScreen()
+					render()
+				}
+			case 'w', 'W':
+				if webBrowseURL != "" {
+					openBrowser(webBrowseURL)
+				}
+			case 'j', ui.KeyDown:
+				if viewMode == ViewModeLive {
+					moveSelection(1)
+					render()
+				}
+			case 'k', ui.KeyUp:
+				if viewMode == ViewModeLive {
+					moveSelection(-1)
+					render()
+				}
+			case 'o', 'O':
+				if viewMode != ViewModeLive {
+					continue
+				}
+				if s, ok := selectedSession(); ok {
+					if err := openProject(cfg, s); err != nil {
+						setFlash(err.Error())
+					}
+					render()
+				}
+			case 'L':
+				if viewMode != ViewModeLive {
+					continue
+				}
+				if s, ok := selectedSession(); ok {
+					if err := openLog(s); err != nil {
+						setFlash(err.Error())
+					}
+					render()
+				}
+			case 'x', 'X':
+				if viewMode != ViewModeLive {
+					continue
+				}
+				if s, ok := selectedSession(); ok {
+					switch {
+					case s.Host != "" || s.GhostPID == 0:
+						setFlash("No local process to terminate for " + s.Project)
+					case !s.IsGhost && s.Status != session.StatusInactive:
+						setFlash(fmt.Sprintf("%s is %s; only ghost or inactive sessions can be terminated", s.Project, s.Status))
+					default:
+						killTarget = &s
+					}
+					render()
+				}
+			case 'f', 'F':
+				if viewMode != ViewModeLive {
+					continue
+				}
+				if s, ok := selectedSession(); ok {
+					if err := session.Focus(s); err != nil {
+						setFlash(err.Error())
+					}
+					render()
+				}
+			case 't':
+				if viewMode != ViewModeLive {
+					continue
+				}
+				if s, ok := selectedSession(); ok {
+					if err := jumpToSession(s); err != nil {
+						setFlash(err.Error())
+					}
+					render()
+				}
+			case 'T':
+				if viewMode != ViewModeLive {
+					continue
+				}
+				if s, ok := longestWaiting(rows); ok {
+					selected = s.LogFile
+					if err := jumpToSession(s); err != nil {
+						setFlash(err.Error())
+					}
+				} else {
+					setFlash("No session in tmux needs input")
+				}
+				render()
+			case 'a', 'A', 'd', 'D':
+				if viewMode != ViewModeLive {
+					continue
+				}
+				if s, ok := selectedSession(); ok {
+					setFlash(answerSession(s, key == 'a' || key == 'A'))
+					render()
+				}
+			case 'm', 'M':
+				if viewMode != ViewModeLive || notifier == nil {
+					continue
+				}
+				if s, ok := selectedSession(); ok {
+					if notifier.ToggleMute(s.Project) {
+						setFlash("Muted notifications for " + s.Project)
+					} else {
+						setFlash("Unmuted notifications for " + s.Project)
+					}
+					render()
+				}
+			case 3: // Ctrl+C
+				cancel()
+				return
+			}
+		case <-changes:
+			session.InvalidateCaches()
+			if viewMode == ViewModeLive {
+				render()
+			}
+			if viewMode == ViewModeChanges {
+				trackInBackground()
+				render()
+			}
+		case <-ticker.C:
+			if viewMode != ViewModeLive {
+				trackInBackground()
+			}
+			if viewMode == ViewModeUsage {
+				continue
+			}
+			if viewMode == ViewModeChanges {
+				render()
+				continue
+			}
+			if viewMode == ViewModeHistory && time.Since(lastHistoryRender) < 30*time.Second {
+				continue
+			}
+			render()
+			if viewMode == ViewModeHistory {
+				lastHistoryRender = time.Now()
+			}
+		}
+	}
+}
+
+// headerIdentity returns what the live header shows to identify this
+// machine: the configured label, or user@hostname by default.
+func headerIdentity(cfg *config.Config) string {
+	if cfg.Identity == "off" {
+		return ""
+	}
+	if cfg.Identity != "" {
+		return cfg.Identity
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	host, _, _ = strings.Cut(host, ".")
+	if u, err := user.Current(); err == nil {
+		return u.Username + "@" + host
+	}
+	return host
+}
+
+// statusUpdate is a refreshed status bar and the usage it was computed from.
+type statusUpdate struct {
+	bar   *ui.StatusBar
+	today *session.UsageStats
+}
+
+// gatherStatusBar collects today's token total and the 5-hour quota for the
+// live view's status bar.
+func gatherStatusBar() statusUpdate {
+	today := usageToday()
+	bar := &ui.StatusBar{TodayTokens: today.TotalTokens, OverBudget: session.OverDailyBudget(today)}
+	if q := session.FetchAPIQuota(); q.Available && q.FiveHour != nil {
+		bar.WindowPercent = q.FiveHour.Utilization
+		bar.HasWindow = true
+	}
+	return statusUpdate{bar: bar, today: today}
+}
+
+// usageToday totals token use across all sessions since local midnight.
+func usageToday() *session.UsageStats {
+	now := time.Now()
+	return session.ComputeUsageSince(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+}
+
+// todayTotals sums today's usage for the live view's header. Active time is
+// each session's span clipped to today; a session counts as done once it no
+// longer has a running Claude process.
+func todayTotals(usage *session.UsageStats, sessions []session.Session, now time.Time) *ui.TodayTotals {
+	t := &ui.TodayTotals{
+		Input:  usage.InputTokens + usage.CacheTokens,
+		Output: usage.OutputTokens,
+		Cost:   usage.Cost,
+	}
+	for _, s := range usage.Sessions {
+		start, end := s.StartTime, s.EndTime
+		if start.Before(usage.WindowStart) {
+			start = usage.WindowStart
+		}
+		if end.After(now) {
+			end = now
+		}
+		if end.After(start) {
+			t.Active += end.Sub(start)
+		}
+		if !slices.ContainsFunc(sessions, func(r session.Session) bool {
+			return r.LogFile == s.LogFile && r.Status != session.StatusInactive
+		}) {
+			t.Completed++
+		}
+	}
+	return t
+}
+
+// countRunning counts the sessions that are not Inactive.
+func countRunning(sessions []session.Session) int {
+	n := 0
+	for _, s := range sessions {
+		if s.Status != session.StatusInactive {
+			n++
+		}
+	}
+	return n
+}
+
+// killSession terminates the session's Claude process and describes the
+// outcome for the live view's message line.
+func killSession(s session.Session) string {
+	if err := session.TerminateProcess(s.GhostPID); err != nil {
+		return fmt.Sprintf("Could not terminate PID %d: %v", s.GhostPID, err)
+	}
+	if metrics != nil {
+		metrics.Count("ghosts.killed", 1)
+	}
+	r := session.KillRecord{Time: time.Now(), PID: s.GhostPID, Project: s.Project, SessionID: s.SessionID,
+		Idle: time.Since(s.LastActivity), Via: "live view"}
+	if err := session.RecordKill(r); err != nil {
+		return fmt.Sprintf("Terminated PID %d (%s); could not record it: %v", s.GhostPID, s.Project, err)
+	}
+	return fmt.Sprintf("Terminated PID %d (%s)", s.GhostPID, s.Project)
+}
+
+// answerSession approves or denies the request a session waits on and
+// describes the outcome for the message line.
+func answerSession(s session.Session, approve bool) string {
+	if err := session.AnswerPermission(s, approve); err != nil {
+		return err.Error()
+	}
+	verb := "Denied"
+	if approve {
+		verb = "Approved"
+	}
+	request := s.PendingRequest
+	if request == "" {
+		request = "the request"
+	}
+	return fmt.Sprintf("%s %s (%s)", verb, request, s.Project)
+}
+
+// jumpToSession switches the tmux client csm runs in to the session's pane.
+func jumpToSession(s session.Session) error {
+	if s.Tmux == nil {
+		return fmt.Errorf("%s is not running in tmux", s.Project)
+	}
+	if os.Getenv("TMUX") == "" {
+		return fmt.Errorf("csm is not running inside tmux; %s is in tmux pane %s:%s", s.Project, s.Tmux.Session, s.Tmux.Label())
+	}
+	return session.JumpToTmuxPane(*s.Tmux)
+}
+
+// longestWaiting returns the tmux session that has needed input the longest.
+func longestWaiting(sessions []session.Session) (session.Session, bool) {
+	var found session.Session
+	ok := false
+	for _, s := range sessions {
+		if s.Status != session.StatusNeedsInput || s.Tmux == nil {
+			continue
+		}
+		if !ok || s.StatusSince.Before(found.StatusSince) {
+			found, ok = s, true
+		}
+	}
+	return found, ok
+}
+
+// openProject opens the session's project directory with the configured
+// open_command, falling back to $VISUAL / $EDITOR and then the system opener.
+func openProject(cfg *config.Config, s session.Session) error {
+	if s.Host != "" {
+		return fmt.Errorf("%s runs on %s; open it there", s.Project, s.Host)
+	}
+	dir := s.CWD
+	if dir == "" {
+		return fmt.Errorf("project directory unknown for %s (no cwd in log yet)", s.Project)
+	}
+
+	command := cfg.OpenCommand
+	if command == "" {
+		command = os.Getenv("VISUAL")
+	}
+	if command == "" {
+		command = os.Getenv("EDITOR")
+	}
+	if command == "" {
+		openBrowser(dir)
+		return nil
+	}
+
+	cmd, err := commandWithPath(command, dir)
+	if err != nil {
+		return err
+	}
+	if err := ui.RunInTerminal(cmd); err != nil {
+		return fmt.Errorf("open %s: %v", dir, err)
+	}
+	return nil
+}
+
+// openLog shows the session's JSONL log in $PAGER (default: less).
+func openLog(s session.Session) error {
+	if s.Host != "" {
+		return fmt.Errorf("%s runs on %s; its log is not available locally", s.Project, s.Host)
+	}
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	cmd, err := commandWithPath(pager, s.LogFile)
+	if err != nil {
+		return err
+	}
+	if err := ui.RunInTerminal(cmd); err != nil {
+		return fmt.Errorf("view log: %v", err)
+	}
+	return nil
+}
+
+// commandWithPath builds a command from a user-supplied command line such as
+// "code -w" or "zed {path}". A {path} placeholder is substituted; otherwise the
+// path is appended as the final argument. Arguments are split on whitespace;
+// no shell is involved, so the path is never interpreted.
+func commandWithPath(command, path string) (*exec.Cmd, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	substituted := false
+	for i, f := range fields {
+		if strings.Contains(f, "{path}") {
+			fields[i] = strings.ReplaceAll(f, "{path}", path)
+			substituted = true
+		}
+	}
+	if !substituted {
+		fields = append(fields, path)
+	}
+	return exec.Command(fields[0], fields[1:]...), nil
+}
+
+// openBrowser opens the given URL in the default browser
+func openBrowser(url string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "linux":
+		cmd = exec.Command("xdg-open", url)
+	default:
+		return
+	}
+	cmd.Start()
+}
+
+// runProjectView is `csm watch <project>`: a focused, live view of the
+// project's latest session with its recent messages, tool calls, context
+// trend and turn lengths. When the project starts a new session (after
+// /clear, say) the view moves to it.
+func runProjectView(project string, interval time.Duration) {
+	// Running sessions win over finished ones; only local logs can be read.
+	find := func() (session.Session, error) {
+		all, err := session.Discover()
+		if err != nil {
+			return session.Session{}, err
+		}
+		var running []session.Session
+		for _, s := range all {
+			if s.GhostPID != 0 && !s.IsGhost {
+				running = append(running, s)
+			}
+		}
+		if s, err := session.FindLatestSession(running, project); err == nil {
+			return s, nil
+		}
+		return session.FindLatestSession(all, project)
+	}
+	current, err := find()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	winchCh := make(chan os.Signal, 1)
+	signal.Notify(winchCh, syscall.SIGWINCH)
+	defer signal.Stop(winchCh)
+
+	if err := ui.SetupRawInput(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error setting up keyboard input: %v\n", err)
+		os.Exit(1)
+	}
+	keyCh := make(chan rune, 1)
+	done := make(chan struct{})
+	go ui.ReadKey(keyCh, done)
+
+	ui.HideCursor()
+	defer func() {
+		close(done)
+		ui.CleanupRawInput()
+		ui.ShowCursor()
+		ui.ResetTerminalTitle()
+		ui.ClearScreen()
+	}()
+
+	// The log is only re-read when it changed since the last refresh.
+	var detail session.Detail
+	var loaded struct {
+		file string
+		size int64
+		mod  time.Time
+	}
+	var flash string
+	var flashAt time.Time
+	var diffScroll int
+	tracker := events.NewTracker()
+
+	refresh := func() {
+		if s, err := find(); err == nil {
+			current = s
+		}
+		tracker.Update([]session.Session{current}, time.Now())
+		if since, ok := tracker.Since(current); ok {
+			current.StatusSince = since
+		}
+		info, err := os.Stat(current.LogFile)
+		if err != nil || (loaded.file == current.LogFile && loaded.size == info.Size() && loaded.mod.Equal(info.ModTime())) {
+			return
+		}
+		if d, err := session.LoadDetail(current.LogFile); err == nil {
+			// A new pending change is shown from its top.
+			if d.Pending == nil || detail.Pending == nil || d.Pending.FilePath != detail.Pending.FilePath {
+				diffScroll = 0
+			}
+			detail = d
+			loaded.file, loaded.size, loaded.mod = current.LogFile, info.Size(), info.ModTime()
+		}
+	}
+	view := func() ui.DetailView {
+		return ui.DetailView{
+			Session:    current,
+			Detail:     detail,
+			Message:    flash,
+			Tmux:       os.Getenv("TMUX") != "",
+			DiffScroll: diffScroll,
+		}
+	}
+	render := func() {
+		if flash != "" && time.Since(flashAt) > flashDuration {
+			flash = ""
+		}
+		ui.SetTerminalTitle(fmt.Sprintf("CSM: %s %s", current.Project, strings.ToLower(string(current.Status))))
+		ui.RenderDetail(view())
+	}
+	setFlash := func(err error) {
+		if err != nil {
+			flash, flashAt = err.Error(), time.Now()
+		}
+	}
+
+	ui.ClearScreen()
+	refresh()
+	render()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sigCh:
+			return
+		case <-winchCh:
+			ui.ClearScreen()
+			render()
+		case <-ticker.C:
+			refresh()
+			render()
+		case key := <-keyCh:
+			switch key {
+			case 'q', 'Q', 3: // 3 is Ctrl+C
+				return
+			case 'f', 'F':
+				setFlash(session.Focus(current))
+				render()
+			case 't', 'T':
+				setFlash(jumpToSession(current))
+				render()
+			case 'a', 'A', 'd', 'D':
+				flash, flashAt = answerSession(current, key == 'a' || key == 'A'), time.Now()
+				render()
+			case 'j', ui.KeyDown:
+				diffScroll = min(diffScroll+1, view().MaxDiffScroll())
+				render()
+			case 'k', ui.KeyUp:
+				diffScroll = max(diffScroll-1, 0)
+				render()
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/web"
+)
+
+// runWeb implements `csm web`, the headless web dashboard server.
+func runWeb(args []string) {
+	fs := newFlagSet("web", "web [flags]")
+	webPort := fs.Int("port", defaultWebPort, "Port for web dashboard")
+	statsdAddr := addStatsDFlag(fs)
+	addShowHeadlessFlag(fs)
+	fs.Parse(args)
+	setupStatsD(loadConfig(), *statsdAddr, 2*time.Second) // the dashboard's SSE refresh rate
+
+	runWebOnly(*webPort)
+}
+
+// runWebOnly starts the web dashboard server without the terminal UI.
+// This is used by the macOS menu bar app and other headless integrations.
+func runWebOnly(webPort int) {
+	if web.ProbeCSMServer(webPort) {
+		fmt.Printf("csm web dashboard is already running at http://localhost:%d\n", webPort)
+		os.Exit(0)
+	}
+
+	srv := web.NewServer(webPort)
+	srv.SetSource(discoverSessions)
+	serveWeb(srv, "Web dashboard")
+}
+
+// serveWeb runs srv until SIGINT/SIGTERM, exiting on a server error.
+func serveWeb(srv *web.Server, what string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	webErrCh, err := srv.Start(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Web server error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s running at http://%s\n", what, srv.Addr())
+
+	select {
+	case <-sigCh:
+		cancel()
+	case err := <-webErrCh:
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Web server error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/i18n"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/ui"
+)
+
+// defaultWebPort is the port the web dashboard listens on unless --port is given.
+const defaultWebPort = 9847
+
+// command is a csm subcommand. Each command parses its own flags from args.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string)
+}
+
+// commands is keyed by name. It is filled in init because the help command
+// refers back to the table.
+var commands map[string]command
+
+func init() {
+	commands = make(map[string]command)
+	for _, c := range []command{
+		{"watch", "Live view of running sessions (default)", runWatch},
+		{"list", "List sessions once and exit", runList},
+		{"history", "Show session history", runHistory},
+		{"top", "Rank sessions by token burn rate, live", runTop},
+		{"limits", "Project this week's token use against the weekly limit", runLimits},
+		{"models", "Show tokens, cost and sessions per model", runModels},
+		{"digest", "Print or email a summary of yesterday's sessions", runDigest},
+		{"report", "Write a usage review of the last days as an HTML page", runReport},
+		{"grep", "Search the text of session transcripts", runGrep},
+		{"export", "Write a session transcript as Markdown, or the history as a calendar", runExport},
+		{"replay", "Play back a session's log, showing status and context over time", runReplay},
+		{"audit", "List unsandboxed and permission-bypassing commands", runAudit},
+		{"ghosts", "List ghost (orphaned) Claude processes", runGhosts},
+		{"kill", "Terminate ghost Claude processes", runKill},
+		{"du", "Show disk usage of session logs per project", runDu},
+		{"clean", "Delete empty and corrupt session logs", runClean},
+		{"prune", "Delete (and optionally archive) old session logs", runPrune},
+		{"web", "Run the web dashboard without the terminal UI", runWeb},
+		{"events", "Print session state transitions as JSON lines", runEvents},
+		{"daemon", "Keep a machine-readable state file up to date", runDaemon},
+		{"agent", "Push local sessions to a csm hub", runAgent},
+		{"hub", "Serve sessions pushed by agents on other machines", runHub},
+		{"resume", "Print or run `claude --resume` for a project", runResume},
+		{"focus", "Bring a project's terminal window to the front", runFocus},
+		{"doctor", "Check the environment csm depends on", runDoctor},
+		{"version", "Show version", runVersion},
+		{"help", "Show this help", runHelp},
+	} {
+		commands[c.name] = c
+	}
+}
+
+// usage prints the top-level help: the command list followed by the legacy flags.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: csm [command] [flags]\n\nCommands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-10s %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintf(out, "\nRun 'csm <command> -h' for command flags.\n")
+	fmt.Fprintf(out, "\nWithout a command, csm opens the live view. Legacy flags:\n")
+	flag.PrintDefaults()
+}
+
+// newFlagSet returns a FlagSet for a subcommand whose usage line reads
+// "csm <synopsis>".
+func newFlagSet(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: csm %s\n\n", synopsis)
+		fs.PrintDefaults()
+	}
+	addClaudeDirFlag(fs)
+	addLangFlag(fs)
+	addDebugFlag(fs)
+	return fs
+}
+
+// claudeDirs collects every --claude-dir value; see loadConfig.
+var claudeDirs []string
+
+// addClaudeDirFlag registers --claude-dir, which every command accepts. It may
+// be repeated to monitor several profiles.
+func addClaudeDirFlag(fs *flag.FlagSet) {
+	fs.Func("claude-dir", "Claude config directory, as `[name=]dir`; repeat to monitor several profiles (default $CLAUDE_CONFIG_DIR or ~/.claude)", func(dir string) error {
+		claudeDirs = append(claudeDirs, dir)
+		return nil
+	})
+}
+
+// lang is the --lang value; see applyLanguage.
+var lang string
+
+// addLangFlag registers --lang, which every command accepts.
+func addLangFlag(fs *flag.FlagSet) {
+	fs.StringVar(&lang, "lang", "", "Language of the terminal views ("+strings.Join(i18n.Languages(), ", ")+", or auto to follow $LANG); default the language config key, else en")
+}
+
+// addColumnsFlag registers the --columns flag shared by the session views.
+func addColumnsFlag(fs *flag.FlagSet) *string {
+	return fs.String("columns", "", "Comma-separated optional columns to show ("+strings.Join(ui.OptionalColumnNames(), ", ")+")")
+}
+
+// loadConfig reads the user config, selects the Claude profiles to monitor
+// (--claude-dir flags, else the claude_dirs config key) and installs the
+// configured status rules, minimum Claude Code version, context mode and
+// budget. A broken config file is reported and ignored rather than stopping
+// csm.
+func loadConfig() *config.Config {
+	setupDebugLog()
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring config: %v\n", err)
+	}
+
+	dirs := claudeDirs
+	if len(dirs) == 0 {
+		dirs = cfg.ClaudeDirs
+	}
+	var profiles []session.Profile
+	for _, dir := range dirs {
+		profiles = append(profiles, session.ParseProfile(dir))
+	}
+	session.SetProfiles(profiles)
+
+	var rules []session.StatusRule
+	for _, r := range cfg.StatusRules {
+		rule, err := session.NewStatusRule(r.Match, r.Status, r.From)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		rules = append(rules, rule)
+	}
+	session.SetStatusRules(rules)
+	if err := session.SetMinVersion(cfg.MinVersion); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	switch cfg.ContextMode {
+	case "", "window":
+	case "compact":
+		session.SetContextToCompact(true)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: context_mode %q must be window or compact\n", cfg.ContextMode)
+		os.Exit(1)
+	}
+	session.SetBudget(session.Budget{
+		SessionTokens: cfg.Budget.SessionTokens,
+		SessionCost:   cfg.Budget.SessionCost,
+		DailyTokens:   cfg.Budget.DailyTokens,
+		DailyCost:     cfg.Budget.DailyCost,
+	})
+	applyTimeFormat(cfg.Time)
+	applyLanguage(cfg.Language)
+	return cfg
+}
+
+// applyLanguage switches the terminal views to --lang, falling back to the
+// language config key.
+func applyLanguage(configured string) {
+	code := lang
+	if code == "" {
+		code = configured
+	}
+	if err := i18n.SetLanguage(code); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// applyTimeFormat installs the time display settings. The timezone replaces
+// time.Local so every local time, in whichever package, follows it.
+func applyTimeFormat(tc config.TimeConfig) {
+	if tc.Style != "" && tc.Style != "relative" && tc.Style != "absolute" {
+		fmt.Fprintf(os.Stderr, "Error: time style %q must be relative or absolute\n", tc.Style)
+		os.Exit(1)
+	}
+	if tc.Clock != "" && tc.Clock != "24h" && tc.Clock != "12h" {
+		fmt.Fprintf(os.Stderr, "Error: time clock %q must be 24h or 12h\n", tc.Clock)
+		os.Exit(1)
+	}
+	if tc.Timezone != "" {
+		loc, err := time.LoadLocation(tc.Timezone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: time zone: %v\n", err)
+			os.Exit(1)
+		}
+		time.Local = loc
+	}
+	ui.SetTimeFormat(tc.Style == "absolute", tc.Clock == "12h")
+}
+
+// applyColumns enables the optional columns from --columns, falling back to
+// the config file. The profile and host columns are added whenever several
+// profiles or remote hosts are monitored, and configured plugin columns are
+// always shown.
+func applyColumns(cfg *config.Config, columns string) {
+	setupPlugins(cfg)
+	columnNames := cfg.Columns
+	if columns != "" {
+		columnNames = strings.Split(columns, ",")
+	}
+	columnNames = withPluginColumns(columnNames)
+	if session.MultiProfile() && !slices.Contains(columnNames, "profile") {
+		columnNames = append([]string{"profile"}, columnNames...)
+	}
+	if remotePoller != nil && !slices.Contains(columnNames, "host") {
+		columnNames = append([]string{"host"}, columnNames...)
+	}
+	if err := ui.SetColumns(columnNames); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// applyHighlights installs the keyword highlights from the config. Invalid
+// rules stop csm so a typo doesn't silently do nothing.
+func applyHighlights(cfg *config.Config) {
+	var hs []ui.Highlight
+	for _, h := range cfg.Highlights {
+		re, err := regexp.Compile(h.Match)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: highlight %q: %v\n", h.Match, err)
+			os.Exit(1)
+		}
+		color, err := ui.ParseColor(h.Color)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: highlight %q: %v\n", h.Match, err)
+			os.Exit(1)
+		}
+		hs = append(hs, ui.Highlight{Pattern: re, Color: color})
+	}
+	ui.SetHighlights(hs)
+}
+
+// applyProjectLinks makes project names hyperlinks when stdout is a terminal,
+// so piped output stays free of escape sequences.
+func applyProjectLinks(cfg *config.Config) {
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		ui.SetProjectLinks(cfg.ProjectLink)
+	}
+}
+
+func printVersion() {
+	fmt.Printf("csm version %s\n", version)
+}
+
+func runVersion(args []string) {
+	fs := newFlagSet("version", "version")
+	fs.Parse(args)
+
+	printVersion()
+}
+
+func runHelp(args []string) {
+	if len(args) > 0 {
+		if cmd, ok := commands[args[0]]; ok {
+			cmd.run([]string{"-h"})
+			return
+		}
+	}
+	runLegacy([]string{"-h"})
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+)
+
+// debugFile is where --debug writes structured logs; empty when off.
+var debugFile string
+
+// debugFlag is --debug: a bare --debug logs to the default file next to the
+// config, --debug=file to the given one. It is a boolean flag, so the file
+// must be joined with "="; in "--debug file" the file is an argument.
+type debugFlag struct{}
+
+func (debugFlag) String() string   { return debugFile }
+func (debugFlag) IsBoolFlag() bool { return true }
+
+func (debugFlag) Set(v string) error {
+	switch v {
+	case "false":
+		debugFile = ""
+	case "true":
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		debugFile = filepath.Join(filepath.Dir(path), "debug.log")
+	default:
+		debugFile = v
+	}
+	return nil
+}
+
+// addDebugFlag registers --debug, which every command accepts.
+func addDebugFlag(fs *flag.FlagSet) {
+	fs.Var(debugFlag{}, "debug", "Append debug logs (discovery timings, parse errors, process matching) to a file: --debug=FILE, or --debug alone for ~/.claude-monitor/debug.log")
+}
+
+// setupDebugLog sends slog debug records to the --debug file as JSON lines.
+// Without --debug the default logger drops them, so the TUI stays clean.
+func setupDebugLog() {
+	if debugFile == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(debugFile), 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: debug log: %v\n", err)
+		os.Exit(1)
+	}
+	f, err := os.OpenFile(debugFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: debug log: %v\n", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	slog.Debug("csm started", "version", version, "args", os.Args[1:])
+}
@@ -2,6 +2,7 @@ module github.com/itk-dev/claude-sessions-monitor
 
 go 1.25.6
 
-require golang.org/x/term v0.39.0
-
-require golang.org/x/sys v0.40.0 // indirect
+require (
+	golang.org/x/sys v0.40.0
+	golang.org/x/term v0.39.0
+)
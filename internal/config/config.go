@@ -0,0 +1,274 @@
+// Package config loads csm's optional user configuration file.
+//
+// The file lives at ~/.claude-monitor/config.json, next to the origin cache.
+// Every setting is optional: a missing file is not an error and simply yields
+// the zero Config, so csm works out of the box without any configuration.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Config holds all user-configurable settings.
+type Config struct {
+	// OpenCommand is the command used by the live view's "o" hotkey to open
+	// a project directory, e.g. "code" or "zed {path}". A {path} placeholder
+	// is replaced with the directory; otherwise the directory is appended as
+	// the last argument. Falls back to $VISUAL / $EDITOR when empty.
+	OpenCommand string `json:"open_command,omitempty"`
+
+	// Columns lists optional live-table columns to show (see --columns).
+	Columns []string `json:"columns,omitempty"`
+
+	// ClaudeDirs lists Claude config directories to monitor together, each
+	// either a path or "name=path" (see --claude-dir). Sessions are tagged
+	// with their profile name when more than one is listed.
+	ClaudeDirs []string `json:"claude_dirs,omitempty"`
+
+	// StatsD enables StatsD / DogStatsD metrics when Addr is set.
+	StatsD StatsDConfig `json:"statsd,omitempty"`
+
+	// Notifications configures desktop notifications (see --notify).
+	Notifications NotifyConfig `json:"notifications,omitempty"`
+
+	// PluginColumns adds live-table columns whose values come from external
+	// commands (see PluginColumn).
+	PluginColumns []PluginColumn `json:"plugin_columns,omitempty"`
+
+	// StatusRules refine the detected status of running sessions by matching
+	// the last assistant message (see StatusRule).
+	StatusRules []StatusRule `json:"status_rules,omitempty"`
+
+	// Highlights color keywords in the last-message line (see Highlight).
+	Highlights []Highlight `json:"highlights,omitempty"`
+
+	// Identity is shown in the live header to tell dashboards on different
+	// machines apart. Empty means user@hostname; "off" hides it.
+	Identity string `json:"identity,omitempty"`
+
+	// ProjectLink turns project names in the live view into terminal
+	// hyperlinks (OSC 8). It is a URL template with {path}, {project} and
+	// {branch} placeholders, e.g. "https://github.com/{project}/tree/{branch}".
+	// Empty links to the project directory as a file:// URL; "off" disables.
+	ProjectLink string `json:"project_link,omitempty"`
+
+	// TerminalProgress makes the live view drive the terminal's progress
+	// indicator (OSC 9;4, shown on the tab or taskbar icon by Windows
+	// Terminal, ConEmu, Ghostty and others). Off by default: terminals that
+	// don't know the sequence may show it as a notification.
+	TerminalProgress bool `json:"terminal_progress,omitempty"`
+
+	// ITerm2 makes the live view set the iTerm2 badge to the status summary
+	// and color the tab: yellow when a session needs input, green when all
+	// are working.
+	ITerm2 bool `json:"iterm2,omitempty"`
+
+	// MinVersion is the oldest Claude Code version considered current, e.g.
+	// "2.0.0". Sessions running an older one are flagged. Empty disables.
+	MinVersion string `json:"min_version,omitempty"`
+
+	// ContextMode sets what context percentages are relative to: "window"
+	// (default), the model's whole context window, or "compact", the point
+	// where Claude Code auto-compacts, as its status line shows. Thresholds
+	// and notifications follow the same percentage.
+	ContextMode string `json:"context_mode,omitempty"`
+
+	// Budget caps token use and estimated cost per session and per day (see
+	// BudgetConfig).
+	Budget BudgetConfig `json:"budget,omitempty"`
+
+	// Limits describes the plan's weekly usage limit for `csm limits` (see
+	// LimitsConfig).
+	Limits LimitsConfig `json:"limits,omitempty"`
+
+	// Time sets how times are displayed (see TimeConfig).
+	Time TimeConfig `json:"time,omitempty"`
+
+	// Language is the language of the terminal views, e.g. "da"; "auto"
+	// follows $LANG. Default English; --lang overrides it.
+	Language string `json:"language,omitempty"`
+
+	// OTLP sends sessions as OpenTelemetry traces from `csm daemon` when
+	// Endpoint is set (see OTLPConfig).
+	OTLP OTLPConfig `json:"otlp,omitempty"`
+
+	// WakaTime configures the heartbeats of `csm daemon --wakatime` (see
+	// WakaTimeConfig).
+	WakaTime WakaTimeConfig `json:"wakatime,omitempty"`
+
+	// TimeTracking maps sessions onto time-tracker entries for `csm export
+	// --format toggl|timew` (see TimeTrackingConfig).
+	TimeTracking TimeTrackingConfig `json:"time_tracking,omitempty"`
+
+	// Digest configures the daily summary emailed by `csm digest --send`
+	// and `csm daemon --digest` (see DigestConfig).
+	Digest DigestConfig `json:"digest,omitempty"`
+
+	// UpdateCheck lets the live view look for a newer release once a day.
+	// Defaults to true; set false to opt out.
+	UpdateCheck *bool `json:"update_check,omitempty"`
+}
+
+// PluginColumn is an extra column filled by a command. The command runs via
+// sh with one session as JSON on stdin; the first line it prints is the cell.
+type PluginColumn struct {
+	Name     string `json:"name"`               // key for --columns; also the default header
+	Header   string `json:"header,omitempty"`   // column header; default upper-cased name
+	Command  string `json:"command"`            // shell command, e.g. "~/bin/ci-status"
+	Width    int    `json:"width,omitempty"`    // cell width; default 12
+	Timeout  string `json:"timeout,omitempty"`  // per run; default "2s"
+	Interval string `json:"interval,omitempty"` // refresh per session; default "30s"
+}
+
+// StatusRule sets Status when the last assistant message matches the Match
+// regexp, optionally only for sessions currently detected as one of From.
+type StatusRule struct {
+	Match  string   `json:"match"`          // Go regexp, e.g. "(?i)waiting for your review"
+	Status string   `json:"status"`         // e.g. "needs_input"
+	From   []string `json:"from,omitempty"` // e.g. ["waiting"]
+}
+
+// Highlight colors the parts of the last message matching the Match regexp.
+type Highlight struct {
+	Match string `json:"match"` // Go regexp, e.g. "(?i)error|failed"
+	Color string `json:"color"` // red, yellow, green, blue, cyan, gray or bold
+}
+
+// UpdateCheckEnabled reports whether the daily release check may run.
+func (c *Config) UpdateCheckEnabled() bool {
+	return c.UpdateCheck == nil || *c.UpdateCheck
+}
+
+// BudgetConfig sets token and cost limits. Sessions over the session budget
+// are shown in red, going over either budget notifies, and `csm list
+// --check-budget` fails. Costs are list-price estimates in USD; zero or
+// omitted fields are unlimited.
+type BudgetConfig struct {
+	SessionTokens int     `json:"session_tokens,omitempty"` // e.g. 5000000
+	SessionCost   float64 `json:"session_cost,omitempty"`   // e.g. 20.0
+	DailyTokens   int     `json:"daily_tokens,omitempty"`   // since local midnight, all sessions
+	DailyCost     float64 `json:"daily_cost,omitempty"`
+}
+
+// LimitsConfig describes a plan's weekly usage limit. Without it, `csm
+// limits` takes the week, and a limit estimated from the reported
+// utilization, from the usage API when signed in; otherwise weeks start on
+// Monday and the limit is unknown.
+type LimitsConfig struct {
+	WeeklyTokens int    `json:"weekly_tokens,omitempty"` // tokens per week, counted like the usage view (cache included)
+	Reset        string `json:"reset,omitempty"`         // when the week starts over, e.g. "Thu 09:00" (local time)
+}
+
+// TimeConfig sets how times are displayed. The timezone applies everywhere
+// times are shown: the live view, history date groups and exports.
+type TimeConfig struct {
+	Style    string `json:"style,omitempty"`    // last activity as "relative" ("3m ago", default) or "absolute" ("14:32")
+	Clock    string `json:"clock,omitempty"`    // "24h" (default) or "12h"
+	Timezone string `json:"timezone,omitempty"` // IANA name, e.g. "America/New_York"; default the system timezone
+}
+
+// OTLPConfig configures the OpenTelemetry trace export (see --otlp).
+type OTLPConfig struct {
+	Endpoint    string            `json:"endpoint,omitempty"`     // OTLP/HTTP collector, e.g. "http://localhost:4318"
+	Headers     map[string]string `json:"headers,omitempty"`      // sent with every export, e.g. {"x-honeycomb-team": "..."}
+	ServiceName string            `json:"service_name,omitempty"` // resource service.name; default "csm"
+}
+
+// WakaTimeConfig overrides the API key and URL csm otherwise reads from
+// ~/.wakatime.cfg, like WakaTime's editor plugins.
+type WakaTimeConfig struct {
+	APIKey string `json:"api_key,omitempty"` // e.g. "waka_..."
+	APIURL string `json:"api_url,omitempty"` // default https://api.wakatime.com/api/v1; set for Wakapi and other compatible servers
+}
+
+// TimeTrackingConfig maps projects to time-tracker tags. Projects without
+// an entry are tagged with their own name.
+type TimeTrackingConfig struct {
+	Email string              `json:"email,omitempty"` // Toggl workspace member the entries belong to
+	Tags  map[string][]string `json:"tags,omitempty"`  // project name → tags, e.g. {"api": ["acme", "backend"]}
+}
+
+// ProjectTags returns the tags of a project's time entries.
+func (t TimeTrackingConfig) ProjectTags(project string) []string {
+	if tags, ok := t.Tags[project]; ok {
+		return tags
+	}
+	return []string{project}
+}
+
+// DigestConfig says where and when the daily digest is sent.
+type DigestConfig struct {
+	To          []string   `json:"to,omitempty"`           // recipients
+	From        string     `json:"from,omitempty"`         // sender; default the SMTP username
+	At          string     `json:"at,omitempty"`           // local time `csm daemon --digest` sends yesterday's digest; default "08:00"
+	LongSession string     `json:"long_session,omitempty"` // sessions at least this long are listed; default "1h"
+	SMTP        SMTPConfig `json:"smtp,omitempty"`
+}
+
+// SMTPConfig is the mail server the digest is sent through.
+type SMTPConfig struct {
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"` // default 587 (STARTTLS); 465 connects with TLS
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// NotifyConfig configures desktop notifications and when to hold them back.
+// Suppressed notifications are still reported by `csm events`.
+type NotifyConfig struct {
+	Enabled    bool              `json:"enabled,omitempty"`
+	QuietHours string            `json:"quiet_hours,omitempty"` // local time range, e.g. "22:00-08:00"
+	Cooldowns  map[string]string `json:"cooldowns,omitempty"`   // event type → minimum gap per session, e.g. {"context_threshold": "10m"}
+	Mute       []string          `json:"mute,omitempty"`        // project names that never notify
+	LongTurn   string            `json:"long_turn,omitempty"`   // notify when a turn this long finishes (default "2m", "0" disables)
+	AllIdle    bool              `json:"all_idle,omitempty"`    // notify once when no session is working any more
+}
+
+// StatsDConfig configures the optional StatsD sink (see --statsd).
+type StatsDConfig struct {
+	Addr   string   `json:"addr,omitempty"`   // agent address, e.g. "127.0.0.1:8125"
+	Prefix string   `json:"prefix,omitempty"` // metric name prefix; default "csm"
+	Tags   []string `json:"tags,omitempty"`   // extra DogStatsD tags, e.g. ["env:dev"]
+}
+
+// pathFn is overridable in tests.
+var pathFn = defaultPath
+
+func defaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".claude-monitor", "config.json"), nil
+}
+
+// Path returns the location of the config file.
+func Path() (string, error) {
+	return pathFn()
+}
+
+// Load reads the config file. A missing file yields an empty Config and no
+// error; a present but malformed file is reported so typos don't go unnoticed.
+func Load() (*Config, error) {
+	cfg := &Config{}
+	path, err := Path()
+	if err != nil {
+		return cfg, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return &Config{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func useConfigFile(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if content != "" {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pathFn = func() (string, error) { return path, nil }
+	t.Cleanup(func() { pathFn = defaultPath })
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	useConfigFile(t, "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.OpenCommand != "" {
+		t.Errorf("OpenCommand = %q, want empty", cfg.OpenCommand)
+	}
+}
+
+func TestLoadValidFile(t *testing.T) {
+	useConfigFile(t, `{"open_command": "zed {path}"}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.OpenCommand != "zed {path}" {
+		t.Errorf("OpenCommand = %q, want %q", cfg.OpenCommand, "zed {path}")
+	}
+}
+
+func TestLoadMalformedFile(t *testing.T) {
+	useConfigFile(t, `{"open_command": `)
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for malformed config")
+	}
+}
+
+func TestUpdateCheckEnabled(t *testing.T) {
+	for content, want := range map[string]bool{
+		`{}`:                      true,
+		`{"update_check": true}`:  true,
+		`{"update_check": false}`: false,
+	} {
+		useConfigFile(t, content)
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load(%s): %v", content, err)
+		}
+		if got := cfg.UpdateCheckEnabled(); got != want {
+			t.Errorf("UpdateCheckEnabled() for %s = %v, want %v", content, got, want)
+		}
+	}
+}
+
+func TestProjectTags(t *testing.T) {
+	useConfigFile(t, `{"time_tracking": {"tags": {"api": ["acme", "backend"], "notes": []}}}`)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	tt := cfg.TimeTracking
+	if got := tt.ProjectTags("api"); !slices.Equal(got, []string{"acme", "backend"}) {
+		t.Errorf("api tags = %q", got)
+	}
+	if got := tt.ProjectTags("web"); !slices.Equal(got, []string{"web"}) {
+		t.Errorf("unmapped project tags = %q, want its name", got)
+	}
+	if got := tt.ProjectTags("notes"); len(got) != 0 {
+		t.Errorf("notes tags = %q, want none", got)
+	}
+}
@@ -0,0 +1,232 @@
+// Package digest builds the daily summary of Claude sessions that `csm
+// digest` prints or emails: active time, tokens and cost per project, and the
+// day's notable long sessions.
+package digest
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// DefaultLongSession is how long a session must have run within the day to
+// be listed among the long sessions.
+const DefaultLongSession = time.Hour
+
+// maxLong caps the long sessions listed.
+const maxLong = 5
+
+// Project is one project's share of the day.
+type Project struct {
+	Name     string
+	Active   time.Duration
+	Sessions int
+	Tokens   int
+	Cost     float64
+}
+
+// Digest summarises one day.
+type Digest struct {
+	Day      time.Time // local midnight
+	Active   time.Duration
+	Sessions int
+	Tokens   int
+	Cost     float64
+	Projects []Project // most active first
+	// Long lists the sessions that ran at least the long-session threshold
+	// that day, longest first, with Duration clipped to the day.
+	Long []session.HistorySession
+}
+
+// Build summarises the day starting at midnight day from the history and
+// the usage of that day. Active time is each session's span clipped to the
+// day, as in the live view's header.
+func Build(day time.Time, history []session.HistorySession, usage *session.UsageStats, long time.Duration) Digest {
+	end := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location())
+	d := Digest{Day: day}
+	byName := make(map[string]*Project)
+	project := func(name string) *Project {
+		p, ok := byName[name]
+		if !ok {
+			p = &Project{Name: name}
+			byName[name] = p
+		}
+		return p
+	}
+
+	for _, s := range history {
+		start, stop := s.StartTime, s.EndTime
+		if start.Before(day) {
+			start = day
+		}
+		if stop.After(end) {
+			stop = end
+		}
+		if stop.Before(start) || !s.StartTime.Before(end) {
+			continue
+		}
+		p := project(s.Project)
+		p.Active += stop.Sub(start)
+		p.Sessions++
+		d.Active += stop.Sub(start)
+		d.Sessions++
+		if stop.Sub(start) >= long {
+			s.Duration = stop.Sub(start)
+			d.Long = append(d.Long, s)
+		}
+	}
+	if usage != nil {
+		for _, su := range usage.Sessions {
+			p := project(su.Project)
+			p.Tokens += su.TotalTokens
+			p.Cost += su.Cost
+		}
+		d.Tokens, d.Cost = usage.TotalTokens, usage.Cost
+	}
+
+	for _, p := range byName {
+		d.Projects = append(d.Projects, *p)
+	}
+	sort.Slice(d.Projects, func(i, j int) bool {
+		a, b := d.Projects[i], d.Projects[j]
+		if a.Active != b.Active {
+			return a.Active > b.Active
+		}
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		return a.Name < b.Name
+	})
+	sort.SliceStable(d.Long, func(i, j int) bool { return d.Long[i].Duration > d.Long[j].Duration })
+	if len(d.Long) > maxLong {
+		d.Long = d.Long[:maxLong]
+	}
+	return d
+}
+
+// Subject is the digest's email subject, e.g. "Claude sessions on Mon 15
+// Jun: 3h 12m, $4.20".
+func (d Digest) Subject() string {
+	return fmt.Sprintf("Claude sessions on %s: %s, $%.2f", d.Day.Format("Mon 2 Jan"), formatDuration(d.Active), d.Cost)
+}
+
+// WriteMarkdown writes the digest as Markdown.
+func WriteMarkdown(w io.Writer, d Digest) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Claude sessions on %s\n\n", d.Day.Format("Monday 2 January 2006"))
+	if d.Sessions == 0 && d.Tokens == 0 {
+		b.WriteString("No sessions.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	fmt.Fprintf(&b, "%s active in %d sessions · %s tokens · $%.2f\n\n", formatDuration(d.Active), d.Sessions, formatTokens(d.Tokens), d.Cost)
+
+	b.WriteString("## Projects\n\n")
+	b.WriteString("| Project | Active | Sessions | Tokens | Cost |\n")
+	b.WriteString("| --- | ---: | ---: | ---: | ---: |\n")
+	for _, p := range d.Projects {
+		fmt.Fprintf(&b, "| %s | %s | %d | %s | $%.2f |\n", markdownCell(p.Name), formatDuration(p.Active), p.Sessions, formatTokens(p.Tokens), p.Cost)
+	}
+
+	if len(d.Long) > 0 {
+		b.WriteString("\n## Long sessions\n\n")
+		for _, s := range d.Long {
+			fmt.Fprintf(&b, "- **%s** %s–%s (%s)", s.Project, s.StartTime.Format("15:04"), s.EndTime.Format("15:04"), formatDuration(s.Duration))
+			if prompt := firstLine(s.FirstPrompt); prompt != "" {
+				fmt.Fprintf(&b, ": %s", prompt)
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteHTML writes the digest as an HTML email body.
+func WriteHTML(w io.Writer, d Digest) error {
+	return htmlTemplate.Execute(w, d)
+}
+
+var htmlTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"duration":  formatDuration,
+	"tokens":    formatTokens,
+	"firstLine": firstLine,
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, Segoe UI, Helvetica, Arial, sans-serif; color: #222;">
+<h2>Claude sessions on {{.Day.Format "Monday 2 January 2006"}}</h2>
+{{- if and (eq .Sessions 0) (eq .Tokens 0)}}
+<p>No sessions.</p>
+{{- else}}
+<p>{{duration .Active}} active in {{.Sessions}} sessions · {{tokens .Tokens}} tokens · ${{printf "%.2f" .Cost}}</p>
+<table cellpadding="4" style="border-collapse: collapse;">
+<tr style="text-align: right; border-bottom: 1px solid #ccc;"><th style="text-align: left;">Project</th><th>Active</th><th>Sessions</th><th>Tokens</th><th>Cost</th></tr>
+{{- range .Projects}}
+<tr style="text-align: right;"><td style="text-align: left;">{{.Name}}</td><td>{{duration .Active}}</td><td>{{.Sessions}}</td><td>{{tokens .Tokens}}</td><td>${{printf "%.2f" .Cost}}</td></tr>
+{{- end}}
+</table>
+{{- if .Long}}
+<h3>Long sessions</h3>
+<ul>
+{{- range .Long}}
+<li><b>{{.Project}}</b> {{.StartTime.Format "15:04"}}–{{.EndTime.Format "15:04"}} ({{duration .Duration}}){{with firstLine .FirstPrompt}}: {{.}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// NextRun returns the first time at or after now that the local clock reads
+// at, "HH:MM".
+func NextRun(at string, now time.Time) (time.Time, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(at))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, want HH:MM", at)
+	}
+	next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+	if next.Before(now) {
+		next = time.Date(now.Year(), now.Month(), now.Day()+1, t.Hour(), t.Minute(), 0, 0, now.Location())
+	}
+	return next, nil
+}
+
+// formatDuration formats active time like "45m" or "3h 12m".
+func formatDuration(d time.Duration) string {
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	if h > 0 {
+		return fmt.Sprintf("%dh %dm", h, m)
+	}
+	return fmt.Sprintf("%dm", m)
+}
+
+// formatTokens formats a token count like "840", "84K" or "1.2M".
+func formatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.0fK", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// firstLine returns the first line of a prompt, shortened to 100 runes.
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	if r := []rune(s); len(r) > 100 {
+		s = string(r[:99]) + "…"
+	}
+	return s
+}
+
+// markdownCell escapes the pipes that would end a table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,122 @@
+package digest
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestBuild(t *testing.T) {
+	day := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	history := []session.HistorySession{
+		{Project: "api", StartTime: at(9, 0), EndTime: at(11, 30), FirstPrompt: "Add rate limiting\nto the API"},
+		{Project: "web", StartTime: at(-2, 0), EndTime: at(0, 20)}, // started the evening before
+		{Project: "api", StartTime: at(14, 0), EndTime: at(14, 40)},
+		{Project: "docs", StartTime: at(-5, 0), EndTime: at(-4, 0)}, // the day before
+	}
+	usage := &session.UsageStats{TotalTokens: 1_500_000, Cost: 4.2, Sessions: []session.SessionUsage{
+		{Project: "api", TotalTokens: 1_200_000, Cost: 3.5},
+		{Project: "web", TotalTokens: 300_000, Cost: 0.7},
+	}}
+
+	d := Build(day, history, usage, time.Hour)
+	if d.Active != 3*time.Hour+30*time.Minute || d.Sessions != 3 {
+		t.Errorf("active %s in %d sessions", d.Active, d.Sessions)
+	}
+	if len(d.Projects) != 2 || d.Projects[0].Name != "api" || d.Projects[0].Active != 3*time.Hour+10*time.Minute ||
+		d.Projects[0].Sessions != 2 || d.Projects[0].Cost != 3.5 || d.Projects[1].Active != 20*time.Minute {
+		t.Errorf("projects = %+v", d.Projects)
+	}
+	if len(d.Long) != 1 || d.Long[0].Duration != 150*time.Minute {
+		t.Errorf("long sessions = %+v", d.Long)
+	}
+	if got := d.Subject(); got != "Claude sessions on Mon 15 Jun: 3h 30m, $4.20" {
+		t.Errorf("Subject() = %q", got)
+	}
+
+	var md bytes.Buffer
+	WriteMarkdown(&md, d)
+	for _, want := range []string{"3h 30m active in 3 sessions · 1.5M tokens · $4.20", "| api | 3h 10m | 2 | 1.2M | $3.50 |",
+		"- **api** 09:00–11:30 (2h 30m): Add rate limiting\n"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Markdown missing %q:\n%s", want, md.String())
+		}
+	}
+}
+
+func TestWriteHTMLEscapes(t *testing.T) {
+	day := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	d := Build(day, []session.HistorySession{{Project: "api", StartTime: day.Add(time.Hour), EndTime: day.Add(3 * time.Hour), FirstPrompt: "fix <script> tag"}}, nil, time.Hour)
+	var html bytes.Buffer
+	if err := WriteHTML(&html, d); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), "fix &lt;script&gt; tag") {
+		t.Errorf("prompt not escaped:\n%s", html.String())
+	}
+}
+
+func TestMessage(t *testing.T) {
+	now := time.Date(2026, 6, 16, 8, 0, 0, 0, time.UTC)
+	raw, err := message("csm@example.com", []string{"a@example.com", "b@example.com"}, "Claude sessions · Mon", []byte("# text"), []byte("<p>html</p>"), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); subject != "Claude sessions · Mon" {
+		t.Errorf("Subject = %q", subject)
+	}
+	if to := msg.Header.Get("To"); to != "a@example.com, b@example.com" {
+		t.Errorf("To = %q", to)
+	}
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q", mediaType)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(p) // decodes the quoted-printable
+		parts = append(parts, p.Header.Get("Content-Type")+": "+string(body))
+	}
+	if len(parts) != 2 || parts[0] != "text/plain; charset=utf-8: # text" || parts[1] != "text/html; charset=utf-8: <p>html</p>" {
+		t.Errorf("parts = %q", parts)
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	now := time.Date(2026, 6, 15, 9, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		at   string
+		want time.Time
+	}{
+		{"08:00", time.Date(2026, 6, 16, 8, 0, 0, 0, time.UTC)},
+		{"18:15", time.Date(2026, 6, 15, 18, 15, 0, 0, time.UTC)},
+		{"09:30", now},
+	} {
+		if got, err := NextRun(tc.at, now); err != nil || !got.Equal(tc.want) {
+			t.Errorf("NextRun(%q) = %v, %v; want %v", tc.at, got, err, tc.want)
+		}
+	}
+	if _, err := NextRun("8am", now); err == nil {
+		t.Error("NextRun accepted 8am")
+	}
+}
@@ -0,0 +1,140 @@
+package digest
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+)
+
+// Send emails d to cfg.To through cfg.SMTP, with the Markdown as the plain
+// text part and the HTML as the alternative.
+func Send(cfg config.DigestConfig, d Digest) error {
+	if len(cfg.To) == 0 {
+		return errors.New("no recipients; set digest.to")
+	}
+	if cfg.SMTP.Host == "" {
+		return errors.New("no mail server; set digest.smtp.host")
+	}
+	from := cfg.From
+	if from == "" {
+		from = cfg.SMTP.Username
+	}
+	if from == "" {
+		return errors.New("no sender; set digest.from")
+	}
+
+	var text, html bytes.Buffer
+	if err := WriteMarkdown(&text, d); err != nil {
+		return err
+	}
+	if err := WriteHTML(&html, d); err != nil {
+		return err
+	}
+	msg, err := message(from, cfg.To, d.Subject(), text.Bytes(), html.Bytes(), time.Now())
+	if err != nil {
+		return err
+	}
+	return sendMail(cfg.SMTP, from, cfg.To, msg)
+}
+
+// message builds a multipart/alternative email.
+func message(from string, to []string, subject string, text, html []byte, now time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		data        []byte
+	}{{"text/plain; charset=utf-8", text}, {"text/html; charset=utf-8", html}} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(w)
+		if _, err := qw.Write(part.data); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// sendMail delivers msg. Port 465 speaks TLS from the start; other ports
+// upgrade with STARTTLS when the server offers it.
+func sendMail(cfg config.SMTPConfig, from string, to []string, msg []byte) error {
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	if port != 465 {
+		return smtp.SendMail(addr, auth, from, to, msg)
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 30 * time.Second}, "tcp", addr, &tls.Config{ServerName: cfg.Host})
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,210 @@
+// Package events turns successive session snapshots into discrete state
+// transitions: a session appearing, changing status, crossing a context
+// threshold, turning into a ghost, going over budget, or ending, and every
+// session having stopped working.
+//
+// The Tracker is shared by `csm events` (which prints every transition) and
+// the notification subsystem (which alerts on a subset of them).
+package events
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// Kind identifies a transition.
+type Kind string
+
+const (
+	Appeared         Kind = "session_appeared"
+	StatusChanged    Kind = "status_changed"
+	ContextThreshold Kind = "context_threshold"
+	GhostDetected    Kind = "ghost_detected"
+	Ended            Kind = "session_ended"
+	AllIdle          Kind = "all_idle"        // no session is Working any more; not tied to one session
+	BudgetExceeded   Kind = "budget_exceeded" // a session, or the day when Project is empty, went over budget
+)
+
+// DefaultThresholds are the context-usage percentages that emit a
+// ContextThreshold event when crossed upwards.
+var DefaultThresholds = []int{50, 80, 90}
+
+// Event is one transition. It marshals to a single flat JSON object so the
+// stream is easy to filter with jq.
+type Event struct {
+	Time           time.Time      `json:"time"`
+	Kind           Kind           `json:"type"`
+	Project        string         `json:"project"`
+	SessionID      string         `json:"session_id,omitempty"`
+	LogFile        string         `json:"log_file"`
+	Host           string         `json:"host,omitempty"`
+	Status         session.Status `json:"status,omitempty"`
+	PrevStatus     session.Status `json:"prev_status,omitempty"`
+	Elapsed        time.Duration  `json:"elapsed,omitempty"` // time spent in PrevStatus; for AllIdle, how long some session was working
+	ContextPercent float64        `json:"context_percent,omitempty"`
+	Threshold      int            `json:"threshold,omitempty"`
+	PID            int            `json:"pid,omitempty"`    // ghost process, for GhostDetected
+	Tokens         int            `json:"tokens,omitempty"` // usage so far, for BudgetExceeded
+	Cost           float64        `json:"cost,omitempty"`   // estimated USD, for BudgetExceeded
+
+	// Notified and Suppressed are filled in by the notification subsystem:
+	// Suppressed names the rule that held a notification back (e.g.
+	// "quiet_hours", "cooldown", "muted").
+	Notified   bool   `json:"notified,omitempty"`
+	Suppressed string `json:"suppressed,omitempty"`
+}
+
+type tracked struct {
+	s     session.Session
+	since time.Time // when s.Status was first seen
+}
+
+// Tracker remembers the previous snapshot and diffs each new one against it.
+// It is not safe for concurrent use.
+type Tracker struct {
+	thresholds []int
+	prev       map[string]tracked
+	busySince  time.Time // when some session started working; zero while none is
+}
+
+// NewTracker returns a Tracker using DefaultThresholds.
+func NewTracker() *Tracker {
+	return &Tracker{
+		thresholds: DefaultThresholds,
+		prev:       make(map[string]tracked),
+	}
+}
+
+// key identifies a session across snapshots. Remote sessions are qualified
+// by host since their log paths may collide with local ones.
+func key(s session.Session) string {
+	return s.Host + "\x00" + s.LogFile
+}
+
+// Seed records sessions as the baseline without emitting events, for
+// consumers that only care about changes from now on.
+func (t *Tracker) Seed(sessions []session.Session, now time.Time) {
+	t.Update(sessions, now)
+}
+
+// Since returns when the session entered its current status, as far as the
+// tracker has observed.
+func (t *Tracker) Since(s session.Session) (time.Time, bool) {
+	tr, ok := t.prev[key(s)]
+	if !ok || tr.s.Status != s.Status {
+		return time.Time{}, false
+	}
+	return tr.since, true
+}
+
+// Update diffs sessions against the previous snapshot and returns the
+// resulting events in a stable order (the order of sessions, then ended
+// sessions). Inactive sessions that were never seen active are recorded
+// silently, so the initial scan doesn't report long-finished sessions.
+func (t *Tracker) Update(sessions []session.Session, now time.Time) []Event {
+	var out []Event
+	next := make(map[string]tracked, len(sessions))
+
+	for _, s := range sessions {
+		k := key(s)
+		old, seen := t.prev[k]
+		since := now
+		if seen && old.s.Status == s.Status {
+			since = old.since
+		}
+		next[k] = tracked{s: s, since: since}
+
+		switch {
+		case !seen:
+			if s.Status != session.StatusInactive {
+				out = append(out, newEvent(now, Appeared, s))
+			}
+		case old.s.Status != s.Status:
+			kind := StatusChanged
+			if s.Status == session.StatusInactive {
+				kind = Ended
+			}
+			e := newEvent(now, kind, s)
+			e.PrevStatus = old.s.Status
+			e.Elapsed = now.Sub(old.since)
+			out = append(out, e)
+		}
+
+		if s.IsGhost && (!seen || !old.s.IsGhost) {
+			e := newEvent(now, GhostDetected, s)
+			e.PID = s.GhostPID
+			out = append(out, e)
+		}
+
+		// Like thresholds, only a session seen going over budget fires.
+		if seen && !old.s.OverBudget && s.OverBudget {
+			e := newEvent(now, BudgetExceeded, s)
+			e.Tokens, e.Cost = s.Tokens, s.Cost
+			out = append(out, e)
+		}
+
+		// Thresholds only fire on an observed rise, not for whatever usage a
+		// session already had when first seen.
+		for _, th := range t.thresholds {
+			if seen && old.s.ContextPercent < float64(th) && s.ContextPercent >= float64(th) {
+				e := newEvent(now, ContextThreshold, s)
+				e.Threshold = th
+				out = append(out, e)
+			}
+		}
+	}
+
+	// Sessions that vanished without going Inactive first (log deleted,
+	// remote host gone) also end.
+	var gone []string
+	for k, old := range t.prev {
+		if _, ok := next[k]; !ok && old.s.Status != session.StatusInactive {
+			gone = append(gone, k)
+		}
+	}
+	sort.Strings(gone)
+	for _, k := range gone {
+		old := t.prev[k]
+		e := newEvent(now, Ended, old.s)
+		e.Status = session.StatusInactive
+		e.PrevStatus = old.s.Status
+		e.Elapsed = now.Sub(old.since)
+		out = append(out, e)
+	}
+
+	// The whole batch finished: the last Working session moved on.
+	working := slices.ContainsFunc(sessions, func(s session.Session) bool { return s.Status == session.StatusWorking })
+	switch {
+	case working && t.busySince.IsZero():
+		t.busySince = now
+	case !working && !t.busySince.IsZero():
+		out = append(out, Event{Time: now, Kind: AllIdle, Elapsed: now.Sub(t.busySince)})
+		t.busySince = time.Time{}
+	}
+
+	t.prev = next
+	return out
+}
+
+func newEvent(now time.Time, kind Kind, s session.Session) Event {
+	return Event{
+		Time:           now,
+		Kind:           kind,
+		Project:        s.Project,
+		SessionID:      s.SessionID,
+		LogFile:        s.LogFile,
+		Host:           s.Host,
+		Status:         s.Status,
+		ContextPercent: s.ContextPercent,
+	}
+}
+
+// DailyBudgetExceeded is the BudgetExceeded event for the day's usage going
+// over the daily budget. The Tracker only sees sessions, so watchers that
+// total the day's usage emit it themselves.
+func DailyBudgetExceeded(now time.Time, u *session.UsageStats) Event {
+	return Event{Time: now, Kind: BudgetExceeded, Tokens: u.TotalTokens, Cost: u.Cost}
+}
@@ -0,0 +1,151 @@
+package events
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func kinds(evs []Event) []Kind {
+	var out []Kind
+	for _, e := range evs {
+		out = append(out, e.Kind)
+	}
+	return out
+}
+
+func TestTrackerLifecycle(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tr := NewTracker()
+
+	old := session.Session{Project: "org/old", LogFile: "/old.jsonl", Status: session.StatusInactive}
+	api := session.Session{Project: "org/api", LogFile: "/api.jsonl", Status: session.StatusWorking, ContextPercent: 40}
+
+	// Initial scan: active sessions appear, long-finished ones stay quiet.
+	evs := tr.Update([]session.Session{api, old}, t0)
+	if got := kinds(evs); !slices.Equal(got, []Kind{Appeared}) {
+		t.Fatalf("initial kinds = %v", got)
+	}
+
+	// Status change carries the previous status and how long it lasted.
+	api.Status = session.StatusWaiting
+	api.ContextPercent = 85
+	evs = tr.Update([]session.Session{api, old}, t0.Add(3*time.Minute))
+	if got := kinds(evs); !slices.Equal(got, []Kind{StatusChanged, ContextThreshold, ContextThreshold, AllIdle}) {
+		t.Fatalf("change kinds = %v", got)
+	}
+	if evs[0].PrevStatus != session.StatusWorking || evs[0].Elapsed != 3*time.Minute {
+		t.Errorf("status event = %+v", evs[0])
+	}
+	if evs[1].Threshold != 50 || evs[2].Threshold != 80 {
+		t.Errorf("thresholds = %d, %d", evs[1].Threshold, evs[2].Threshold)
+	}
+
+	// No change, no events; Since reports when the status began.
+	if evs = tr.Update([]session.Session{api, old}, t0.Add(4*time.Minute)); len(evs) != 0 {
+		t.Errorf("unchanged snapshot produced %v", kinds(evs))
+	}
+	if since, ok := tr.Since(api); !ok || !since.Equal(t0.Add(3*time.Minute)) {
+		t.Errorf("Since = %v, %v", since, ok)
+	}
+
+	// Becoming a ghost, then going inactive.
+	api.IsGhost = true
+	api.GhostPID = 4242
+	evs = tr.Update([]session.Session{api}, t0.Add(5*time.Minute))
+	if got := kinds(evs); !slices.Equal(got, []Kind{GhostDetected}) || evs[0].PID != 4242 {
+		t.Errorf("ghost events = %+v", evs)
+	}
+	api.Status = session.StatusInactive
+	evs = tr.Update([]session.Session{api}, t0.Add(6*time.Minute))
+	if got := kinds(evs); !slices.Equal(got, []Kind{Ended}) {
+		t.Errorf("inactive kinds = %v", got)
+	}
+}
+
+func TestTrackerVanishedSessionEnds(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tr := NewTracker()
+	web := session.Session{Project: "org/web", LogFile: "/web.jsonl", Status: session.StatusIdle, Host: "dev1"}
+	tr.Seed([]session.Session{web}, t0)
+
+	evs := tr.Update(nil, t0.Add(time.Minute))
+	if len(evs) != 1 || evs[0].Kind != Ended || evs[0].Host != "dev1" || evs[0].PrevStatus != session.StatusIdle {
+		t.Errorf("vanished events = %+v", evs)
+	}
+}
+
+func TestTrackerBudget(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tr := NewTracker()
+
+	// Already over budget when first seen: no event.
+	api := session.Session{Project: "org/api", LogFile: "/api.jsonl", Status: session.StatusWorking, OverBudget: true}
+	web := session.Session{Project: "org/web", LogFile: "/web.jsonl", Status: session.StatusWorking}
+	tr.Update([]session.Session{api, web}, t0)
+
+	web.OverBudget, web.Tokens, web.Cost = true, 2_000_000, 12.5
+	evs := tr.Update([]session.Session{api, web}, t0.Add(time.Minute))
+	if got := kinds(evs); !slices.Equal(got, []Kind{BudgetExceeded}) {
+		t.Fatalf("kinds = %v", got)
+	}
+	if evs[0].Project != "org/web" || evs[0].Tokens != 2_000_000 || evs[0].Cost != 12.5 {
+		t.Errorf("budget event = %+v", evs[0])
+	}
+	if evs = tr.Update([]session.Session{api, web}, t0.Add(2*time.Minute)); len(evs) != 0 {
+		t.Errorf("repeated budget event: %v", kinds(evs))
+	}
+}
+
+func TestTrackerAllIdle(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tr := NewTracker()
+
+	api := session.Session{Project: "org/api", LogFile: "/api.jsonl", Status: session.StatusWaiting}
+	web := session.Session{Project: "org/web", LogFile: "/web.jsonl", Status: session.StatusWaiting}
+	tr.Update([]session.Session{api, web}, t0)
+
+	// Nothing was working: no all-idle event.
+	if evs := tr.Update([]session.Session{api, web}, t0.Add(time.Minute)); len(evs) != 0 {
+		t.Fatalf("idle snapshot produced %v", kinds(evs))
+	}
+
+	api.Status, web.Status = session.StatusWorking, session.StatusWorking
+	tr.Update([]session.Session{api, web}, t0.Add(2*time.Minute))
+
+	// One finished, the other still works.
+	api.Status = session.StatusWaiting
+	if evs := tr.Update([]session.Session{api, web}, t0.Add(5*time.Minute)); slices.Contains(kinds(evs), AllIdle) {
+		t.Fatalf("all-idle while web is working: %v", kinds(evs))
+	}
+
+	// Both done: one event, timed from when the first started working.
+	web.Status = session.StatusNeedsInput
+	evs := tr.Update([]session.Session{api, web}, t0.Add(9*time.Minute))
+	if got := kinds(evs); !slices.Equal(got, []Kind{StatusChanged, AllIdle}) {
+		t.Fatalf("kinds = %v", got)
+	}
+	if evs[1].Elapsed != 7*time.Minute {
+		t.Errorf("all-idle elapsed = %v, want 7m", evs[1].Elapsed)
+	}
+	if evs = tr.Update([]session.Session{api, web}, t0.Add(10*time.Minute)); len(evs) != 0 {
+		t.Errorf("repeated all-idle: %v", kinds(evs))
+	}
+}
+
+func TestRecent(t *testing.T) {
+	r := NewRecent(3)
+	r.Add([]Event{{Kind: Appeared, Project: "a"}, {Kind: StatusChanged, Project: "b"}})
+	r.Add([]Event{{Kind: ContextThreshold, Project: "c"}, {Kind: StatusChanged, Project: "d"}, {Kind: AllIdle}})
+	r.Add([]Event{{Kind: Ended, Project: "e"}, {Kind: StatusChanged, Project: "f"}})
+
+	var got []string
+	for _, e := range r.Events() {
+		got = append(got, e.Project)
+	}
+	if !slices.Equal(got, []string{"f", "e", "d"}) {
+		t.Errorf("Events = %v, want the last three transitions, newest first", got)
+	}
+}
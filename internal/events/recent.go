@@ -0,0 +1,39 @@
+package events
+
+// DefaultRecent is how many status transitions the live view keeps.
+const DefaultRecent = 50
+
+// Recent is a rolling log of the latest status transitions (StatusChanged
+// and Ended events), for looking back at what happened across sessions. It
+// is kept in memory only and is not safe for concurrent use.
+type Recent struct {
+	size   int
+	events []Event // oldest first
+}
+
+// NewRecent returns a Recent keeping the last size transitions.
+func NewRecent(size int) *Recent {
+	return &Recent{size: size}
+}
+
+// Add records the status transitions among evs, dropping the oldest beyond
+// the size.
+func (r *Recent) Add(evs []Event) {
+	for _, e := range evs {
+		if e.Kind == StatusChanged || e.Kind == Ended {
+			r.events = append(r.events, e)
+		}
+	}
+	if n := len(r.events) - r.size; n > 0 {
+		r.events = append(r.events[:0], r.events[n:]...)
+	}
+}
+
+// Events returns the recorded transitions, newest first.
+func (r *Recent) Events() []Event {
+	out := make([]Event, len(r.events))
+	for i, e := range r.events {
+		out[len(out)-1-i] = e
+	}
+	return out
+}
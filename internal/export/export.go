@@ -0,0 +1,124 @@
+// Package export writes csm's reports (history, audit, usage) in formats
+// other tools read: CSV for spreadsheets, JSON for scripts and Markdown for
+// notes and tickets. Commands build a Table and leave the format to --format.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// The formats Write supports.
+const (
+	CSV      = "csv"
+	JSON     = "json"
+	Markdown = "md"
+)
+
+// Formats lists the formats Write supports, for flag help and errors.
+var Formats = []string{CSV, JSON, Markdown}
+
+// Table is a report to export. CSV and Markdown write Columns and Rows. JSON
+// writes Data when set, so values keep their types and field names, and
+// otherwise one object per row keyed by column.
+type Table struct {
+	Columns []string
+	Rows    [][]string
+	Data    any
+}
+
+// Supported reports whether Write knows format.
+func Supported(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes t to w in format.
+func Write(w io.Writer, format string, t Table) error {
+	switch format {
+	case CSV:
+		return WriteCSV(w, t)
+	case JSON:
+		if t.Data != nil {
+			return WriteJSON(w, t.Data)
+		}
+		rows := make([]map[string]string, 0, len(t.Rows))
+		for _, r := range t.Rows {
+			obj := make(map[string]string, len(t.Columns))
+			for i, c := range t.Columns {
+				if i < len(r) {
+					obj[c] = r[i]
+				}
+			}
+			rows = append(rows, obj)
+		}
+		return WriteJSON(w, rows)
+	case Markdown:
+		return WriteMarkdown(w, t)
+	}
+	return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(Formats, ", "))
+}
+
+// WriteCSV writes the header and rows of t as CSV.
+func WriteCSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns); err != nil {
+		return err
+	}
+	return cw.WriteAll(t.Rows)
+}
+
+// WriteJSON writes v as indented JSON.
+func WriteJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// WriteMarkdown writes t as a GitHub-flavored Markdown table. Pipes in cells
+// are escaped and line breaks become <br> so every row stays on one line.
+func WriteMarkdown(w io.Writer, t Table) error {
+	var b strings.Builder
+	writeMarkdownRow(&b, t.Columns)
+	b.WriteString("|")
+	for range t.Columns {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+	for _, r := range t.Rows {
+		writeMarkdownRow(&b, r)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var markdownCell = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	b.WriteString("|")
+	for _, c := range cells {
+		b.WriteString(" " + markdownCell.Replace(c) + " |")
+	}
+	b.WriteString("\n")
+}
+
+// Create opens the destination of --output: stdout for "" or "-", else the
+// file at path, created or truncated. Closing stdout is a no-op.
+func Create(path string) (io.WriteCloser, error) {
+	if path == "" || path == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,153 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	table := Table{
+		Columns: []string{"project", "command"},
+		Rows: [][]string{
+			{"api", "ls | wc -l"},
+			{"web, app", "make\ntest"},
+		},
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{CSV, "project,command\napi,ls | wc -l\n\"web, app\",\"make\ntest\"\n"},
+		{Markdown, "| project | command |\n| --- | --- |\n| api | ls \\| wc -l |\n| web, app | make<br>test |\n"},
+		{JSON, "[\n  {\n    \"command\": \"ls | wc -l\",\n    \"project\": \"api\"\n  },\n  {\n    \"command\": \"make\\ntest\",\n    \"project\": \"web, app\"\n  }\n]\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := Write(&b, tt.format, table); err != nil {
+			t.Fatalf("Write(%s): %v", tt.format, err)
+		}
+		if b.String() != tt.want {
+			t.Errorf("Write(%s) =\n%s\nwant\n%s", tt.format, b.String(), tt.want)
+		}
+	}
+
+	// JSON prefers the typed data over the rows.
+	var b strings.Builder
+	Write(&b, JSON, Table{Columns: table.Columns, Rows: table.Rows, Data: []int{1, 2}})
+	if got := b.String(); got != "[\n  1,\n  2\n]\n" {
+		t.Errorf("Write(json) with Data = %q", got)
+	}
+
+	if err := Write(&b, "xml", table); err == nil || !Supported(CSV) || Supported("xml") {
+		t.Errorf("xml accepted: %v", err)
+	}
+}
+
+func TestWriteJSONEmpty(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, JSON, Table{Columns: []string{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "[]\n" {
+		t.Errorf("empty table as JSON = %q, want []", b.String())
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	start := time.Date(2026, 6, 1, 9, 30, 0, 0, time.UTC)
+	events := []Event{
+		{UID: "3f2a@csm", Start: start, End: start.Add(90 * time.Minute), Summary: "api", Description: "fix the migration; then, run it\nagain"},
+		{UID: "9b1c@csm", Start: start, End: start, Summary: "web"},
+	}
+	var b strings.Builder
+	if err := WriteICS(&b, events, start); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:3f2a@csm\r\nDTSTAMP:20260601T093000Z\r\nDTSTART:20260601T093000Z\r\nDTEND:20260601T110000Z\r\nSUMMARY:api\r\n",
+		`DESCRIPTION:fix the migration\; then\, run it\nagain` + "\r\n",
+		"DTEND:20260601T093100Z\r\nSUMMARY:web\r\nEND:VEVENT\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "BEGIN:VEVENT") != 2 {
+		t.Errorf("want 2 events:\n%s", out)
+	}
+}
+
+func TestFoldICS(t *testing.T) {
+	long := "DESCRIPTION:" + strings.Repeat("é", 40)
+	folded := foldICS(long)
+	for _, line := range strings.Split(folded, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+	}
+	if got := strings.ReplaceAll(folded, "\r\n ", ""); got != long {
+		t.Errorf("unfolded = %q, want %q", got, long)
+	}
+}
+
+func TestTimeTrackers(t *testing.T) {
+	start := time.Date(2026, 6, 1, 9, 30, 0, 0, time.Local)
+	entries := []TimeEntry{
+		{Start: start, End: start.Add(92*time.Minute + 5*time.Second), Project: "api", Description: "fix the migration\nand test it", Tags: []string{"acme", "back end"}},
+	}
+
+	var b strings.Builder
+	if err := WriteToggl(&b, entries, "me@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	want := "Email,Project,Description,Start date,Start time,Duration,Tags\n" +
+		"me@example.com,api,\"fix the migration\nand test it\",2026-06-01,09:30:00,01:32:05,\"acme,back end\"\n"
+	if b.String() != want {
+		t.Errorf("WriteToggl =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := WriteTimewarrior(&b, entries); err != nil {
+		t.Fatal(err)
+	}
+	want = "#!/bin/sh\n\n# api: fix the migration\ntimew track " + start.UTC().Format(timewTimeLayout) + " - " +
+		start.Add(92*time.Minute+5*time.Second).UTC().Format(timewTimeLayout) + " acme 'back end' :quiet\n"
+	if b.String() != want {
+		t.Errorf("WriteTimewarrior =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	day := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	r := Report{
+		Title: "Claude usage", From: day, To: day.AddDate(0, 0, 1), Generated: day.AddDate(0, 0, 1),
+		Active: 3 * time.Hour, Sessions: 3, Tokens: 1_500_000, Cost: 4.2,
+		Days: []ReportDay{{Day: day, Active: time.Hour, Sessions: 1}, {Day: day.AddDate(0, 0, 1), Active: 2 * time.Hour, Sessions: 2}},
+		Projects: []ReportProject{
+			{Name: "api", Active: 2 * time.Hour, Sessions: 2, Tokens: 1_200_000, Cost: 3.5},
+			{Name: "<web>", Active: time.Hour, Sessions: 1, Tokens: 300_000, Cost: 0.7},
+		},
+	}
+	var b strings.Builder
+	if err := WriteHTMLReport(&b, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<div style="height: 50.0%" title="Mon 1 Jun: 1h 0m">`,
+		`<div style="height: 100.0%" title="Tue 2 Jun: 2h 0m">`,
+		`<td>api</td><td>2h 0m</td><td>2</td><td>1.2M</td><td>$3.50</td><td class="bar"><div class="hbar" style="width: 100.0%">`,
+		`<td>&lt;web&gt;</td>`,
+		`style="width: 25.0%"`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("report missing %s", want)
+		}
+	}
+	if strings.Contains(b.String(), "<script") || strings.Contains(b.String(), "src=") {
+		t.Error("report is not self-contained")
+	}
+}
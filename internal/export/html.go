@@ -0,0 +1,158 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// HTML is the self-contained usage report page of `csm report`.
+const HTML = "html"
+
+// Report is a usage review over a range of days.
+type Report struct {
+	Title     string
+	From, To  time.Time // first and last day, local midnight
+	Generated time.Time
+	Active    time.Duration
+	Sessions  int
+	Tokens    int
+	Cost      float64
+	Days      []ReportDay     // every day of the range, oldest first
+	Projects  []ReportProject // most tokens first
+}
+
+// ReportDay is the time spent in sessions on one day.
+type ReportDay struct {
+	Day      time.Time
+	Active   time.Duration
+	Sessions int
+}
+
+// ReportProject is one project's share of the range.
+type ReportProject struct {
+	Name     string
+	Active   time.Duration
+	Sessions int
+	Tokens   int
+	Cost     float64
+}
+
+// WriteHTMLReport writes r as a single HTML page with its styles inline and
+// bar charts drawn in CSS, so it can be mailed or shared as one file.
+func WriteHTMLReport(w io.Writer, r Report) error {
+	var maxActive time.Duration
+	maxTokens := 0
+	for _, d := range r.Days {
+		maxActive = max(maxActive, d.Active)
+	}
+	for _, p := range r.Projects {
+		maxTokens = max(maxTokens, p.Tokens)
+	}
+	return reportTemplate.Execute(w, struct {
+		Report
+		MaxActive time.Duration
+		MaxTokens int
+	}{r, maxActive, maxTokens})
+}
+
+// percent is v of total as a CSS percentage, at least a sliver for v > 0 so
+// small bars stay visible.
+func percent(v, total float64) string {
+	if total <= 0 || v <= 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", max(v/total*100, 0.5))
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": formatDuration,
+	"tokens":   formatTokens,
+	"durationPercent": func(d, total time.Duration) string {
+		return percent(float64(d), float64(total))
+	},
+	"tokensPercent": func(n, total int) string {
+		return percent(float64(n), float64(total))
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #222; max-width: 960px; margin: 2em auto; padding: 0 1em; }
+h1 { margin-bottom: 0.2em; }
+.muted { color: #777; }
+.totals { display: flex; gap: 2em; margin: 1.5em 0; }
+.totals div { font-size: 1.6em; font-weight: 600; }
+.totals span { display: block; font-size: 0.55em; font-weight: normal; color: #777; }
+.days { display: flex; align-items: flex-end; gap: 2px; height: 160px; border-bottom: 1px solid #ccc; }
+.days div { flex: 1; background: #d97757; min-width: 2px; }
+.axis { display: flex; justify-content: space-between; font-size: 0.8em; color: #777; }
+.hbar { background: #6a9bcc; height: 0.9em; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0 2em; }
+th, td { padding: 0.3em 0.6em; border-bottom: 1px solid #eee; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+td.bar { width: 35%; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="muted">{{.From.Format "Mon 2 Jan 2006"}} – {{.To.Format "Mon 2 Jan 2006"}} · generated {{.Generated.Format "2006-01-02 15:04"}}</p>
+<div class="totals">
+<div>{{duration .Active}}<span>active</span></div>
+<div>{{.Sessions}}<span>sessions</span></div>
+<div>{{tokens .Tokens}}<span>tokens</span></div>
+<div>${{printf "%.2f" .Cost}}<span>estimated cost</span></div>
+</div>
+
+<h2>Active time per day</h2>
+<div class="days">
+{{- range .Days}}
+<div style="height: {{durationPercent .Active $.MaxActive}}" title="{{.Day.Format "Mon 2 Jan"}}: {{duration .Active}}"></div>
+{{- end}}
+</div>
+<div class="axis"><span>{{.From.Format "2 Jan"}}</span><span>{{.To.Format "2 Jan"}}</span></div>
+
+<h2>Tokens per project</h2>
+<table>
+<tr><th>Project</th><th>Active</th><th>Sessions</th><th>Tokens</th><th>Cost</th><th></th></tr>
+{{- range .Projects}}
+<tr><td>{{.Name}}</td><td>{{duration .Active}}</td><td>{{.Sessions}}</td><td>{{tokens .Tokens}}</td><td>${{printf "%.2f" .Cost}}</td><td class="bar"><div class="hbar" style="width: {{tokensPercent .Tokens $.MaxTokens}}"></div></td></tr>
+{{- else}}
+<tr><td colspan="6" class="muted">No sessions.</td></tr>
+{{- end}}
+</table>
+
+<h2>Days</h2>
+<table>
+<tr><th>Day</th><th>Active</th><th>Sessions</th></tr>
+{{- range .Days}}
+<tr><td>{{.Day.Format "Mon 2 Jan"}}</td><td>{{duration .Active}}</td><td>{{.Sessions}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// formatDuration formats active time like "45m" or "3h 12m".
+func formatDuration(d time.Duration) string {
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	if h > 0 {
+		return fmt.Sprintf("%dh %dm", h, m)
+	}
+	return fmt.Sprintf("%dm", m)
+}
+
+// formatTokens formats a token count like "840", "84K" or "1.2M".
+func formatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.0fK", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d", n)
+}
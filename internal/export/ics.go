@@ -0,0 +1,78 @@
+package export
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+// ICS is the iCalendar format, for calendars rather than tables; see WriteICS.
+const ICS = "ics"
+
+// Event is one calendar entry of an iCalendar export.
+type Event struct {
+	UID         string // stable across exports, so re-imports update the event
+	Start, End  time.Time
+	Summary     string
+	Description string
+}
+
+// icsTimeLayout is an iCalendar UTC date-time.
+const icsTimeLayout = "20060102T150405Z"
+
+// WriteICS writes events as an iCalendar (RFC 5545) calendar, one VEVENT
+// each. stamp is the DTSTAMP of every event, normally the time of export.
+func WriteICS(w io.Writer, events []Event, stamp time.Time) error {
+	var b strings.Builder
+	line := func(s string) { b.WriteString(foldICS(s) + "\r\n") }
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//itk-dev//claude-sessions-monitor//EN")
+	line("CALSCALE:GREGORIAN")
+	for _, e := range events {
+		end := e.End
+		if !end.After(e.Start) {
+			end = e.Start.Add(time.Minute) // zero-length events are hidden by most calendars
+		}
+		line("BEGIN:VEVENT")
+		line("UID:" + escapeICS(e.UID))
+		line("DTSTAMP:" + stamp.UTC().Format(icsTimeLayout))
+		line("DTSTART:" + e.Start.UTC().Format(icsTimeLayout))
+		line("DTEND:" + end.UTC().Format(icsTimeLayout))
+		line("SUMMARY:" + escapeICS(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeICS(e.Description))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var icsText = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escapeICS escapes a TEXT property value.
+func escapeICS(s string) string {
+	return icsText.Replace(s)
+}
+
+// foldICS splits a content line longer than 75 octets into continuation
+// lines starting with a space, without cutting a UTF-8 character in two.
+func foldICS(s string) string {
+	const limit = 75
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > limit {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// The time-tracker formats: Toggl Track's CSV import and a script of
+// Timewarrior `timew track` commands.
+const (
+	Toggl       = "toggl"
+	Timewarrior = "timew"
+)
+
+// TimeEntry is a span of tracked time for a time tracker.
+type TimeEntry struct {
+	Start, End  time.Time
+	Project     string
+	Description string
+	Tags        []string
+}
+
+// WriteToggl writes entries in Toggl Track's CSV import format. Toggl
+// requires the email of the workspace member the entries belong to. Dates
+// and times are local.
+func WriteToggl(w io.Writer, entries []TimeEntry, email string) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Email", "Project", "Description", "Start date", "Start time", "Duration", "Tags"})
+	for _, e := range entries {
+		start := e.Start.Local()
+		d := e.End.Sub(e.Start).Round(time.Second)
+		cw.Write([]string{
+			email, e.Project, e.Description,
+			start.Format("2006-01-02"), start.Format("15:04:05"),
+			fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60),
+			strings.Join(e.Tags, ","),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// timewTimeLayout is a UTC ISO 8601 time as `timew track` accepts it.
+const timewTimeLayout = "2006-01-02T15:04:05Z"
+
+// WriteTimewarrior writes entries as a shell script of `timew track`
+// commands, each preceded by a comment with the description's first line.
+func WriteTimewarrior(w io.Writer, entries []TimeEntry) error {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	for _, e := range entries {
+		comment := e.Project
+		if desc, _, _ := strings.Cut(e.Description, "\n"); desc != "" {
+			comment += ": " + desc
+		}
+		fmt.Fprintf(&b, "\n# %s\n", comment)
+		fmt.Fprintf(&b, "timew track %s - %s", e.Start.UTC().Format(timewTimeLayout), e.End.UTC().Format(timewTimeLayout))
+		for _, tag := range e.Tags {
+			b.WriteString(" " + shellQuote(tag))
+		}
+		b.WriteString(" :quiet\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// shellQuote quotes s for sh when it holds anything but letters, digits
+// and a few safe punctuation marks.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.:/@+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
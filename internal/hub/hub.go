@@ -0,0 +1,208 @@
+// Package hub aggregates sessions from several machines. Agents (`csm agent`)
+// periodically push their local sessions to a hub (`csm hub`), which serves
+// the merged set through the regular web dashboard and API.
+package hub
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// PushPath is the hub endpoint agents POST to.
+const PushPath = "/api/push"
+
+// maxPushBytes caps a single push body.
+const maxPushBytes = 8 << 20
+
+// Report is the body of a push: one agent's current sessions.
+type Report struct {
+	Host     string            `json:"host"`
+	Sessions []session.Session `json:"sessions"`
+}
+
+type hostReport struct {
+	sessions []session.Session
+	at       time.Time
+}
+
+// Store holds the latest report from each agent. Hosts that stop reporting
+// for longer than the expiry are dropped, so a dead agent's sessions don't
+// linger on the dashboard.
+type Store struct {
+	expiry time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]hostReport
+	now   func() time.Time // overridable in tests
+}
+
+// NewStore returns an empty Store that forgets hosts silent for expiry.
+func NewStore(expiry time.Duration) *Store {
+	return &Store{
+		expiry: expiry,
+		hosts:  make(map[string]hostReport),
+		now:    time.Now,
+	}
+}
+
+// Update replaces host's sessions with the given ones.
+func (st *Store) Update(host string, sessions []session.Session) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.hosts[host] = hostReport{sessions: sessions, at: st.now()}
+}
+
+// Sessions returns the sessions of every live host, tagged with their host
+// and sorted like local discovery. It matches web.Source.
+func (st *Store) Sessions() ([]session.Session, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := st.now()
+	all := []session.Session{}
+	for host, r := range st.hosts {
+		if now.Sub(r.at) > st.expiry {
+			delete(st.hosts, host)
+			continue
+		}
+		for _, s := range r.sessions {
+			s.Host = host
+			all = append(all, s)
+		}
+	}
+	session.SortSessions(all)
+	return all, nil
+}
+
+// Hosts returns the names of hosts that have reported within the expiry.
+func (st *Store) Hosts() []string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	var hosts []string
+	now := st.now()
+	for host, r := range st.hosts {
+		if now.Sub(r.at) <= st.expiry {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// TokenCookie carries the hub token for the dashboard, which can't send an
+// Authorization header from EventSource.
+const TokenCookie = "csm_hub_token"
+
+// Loopback reports whether host, as given to --listen, only accepts
+// connections from this machine.
+func Loopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// RequireToken serves next only to requests carrying token, as
+// "Authorization: Bearer <token>", the TokenCookie cookie, or a ?token=
+// query parameter, which also sets the cookie so the dashboard opened from
+// http://hub:9847/?token=... keeps working. An empty token lets every
+// request through.
+func RequireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	valid := func(got string) bool {
+		return got != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query().Get("token"); valid(q) {
+			http.SetCookie(w, &http.Cookie{Name: TokenCookie, Value: q, Path: "/", HttpOnly: true, SameSite: http.SameSiteStrictMode})
+			next.ServeHTTP(w, r)
+			return
+		}
+		if c, err := r.Cookie(TokenCookie); err == nil && valid(c.Value) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") && valid(strings.TrimPrefix(auth, "Bearer ")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// PushHandler accepts agent reports. When token is non-empty, requests must
+// carry it as "Authorization: Bearer <token>".
+func (st *Store) PushHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if token != "" {
+			got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		var report Report
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxPushBytes)).Decode(&report); err != nil {
+			http.Error(w, "invalid report", http.StatusBadRequest)
+			return
+		}
+		if report.Host == "" {
+			http.Error(w, "host is required", http.StatusBadRequest)
+			return
+		}
+
+		st.Update(report.Host, report.Sessions)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// Push sends one report to the hub at hubURL (e.g. "http://hub:9847").
+func Push(ctx context.Context, hubURL, token string, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(hubURL, "/")+PushPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("hub returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
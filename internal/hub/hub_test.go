@@ -0,0 +1,110 @@
+package hub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestStoreExpiresSilentHosts(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	st := NewStore(time.Minute)
+	st.now = func() time.Time { return now }
+
+	st.Update("old", []session.Session{{Project: "org/old", Status: session.StatusIdle}})
+	now = now.Add(50 * time.Second)
+	st.Update("new", []session.Session{{Project: "org/new", Status: session.StatusWorking}})
+
+	got, _ := st.Sessions()
+	if len(got) != 2 || got[0].Host != "new" || got[1].Host != "old" {
+		t.Fatalf("Sessions() = %+v, want new (working) then old (idle)", got)
+	}
+
+	now = now.Add(20 * time.Second)
+	got, _ = st.Sessions()
+	if len(got) != 1 || got[0].Project != "org/new" {
+		t.Errorf("Sessions() after expiry = %+v, want only org/new", got)
+	}
+	if hosts := st.Hosts(); len(hosts) != 1 || hosts[0] != "new" {
+		t.Errorf("Hosts() = %v, want [new]", hosts)
+	}
+}
+
+func TestPushRoundTrip(t *testing.T) {
+	st := NewStore(time.Minute)
+	srv := httptest.NewServer(st.PushHandler("s3cret"))
+	defer srv.Close()
+
+	report := Report{Host: "build1", Sessions: []session.Session{{Project: "org/api", Status: session.StatusWorking}}}
+
+	if err := Push(context.Background(), srv.URL, "wrong", report); err == nil {
+		t.Error("Push with a wrong token succeeded")
+	}
+	if err := Push(context.Background(), srv.URL, "s3cret", report); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	got, _ := st.Sessions()
+	if len(got) != 1 || got[0].Host != "build1" || got[0].Project != "org/api" {
+		t.Errorf("Sessions() = %+v", got)
+	}
+}
+
+func TestPushRequiresHost(t *testing.T) {
+	st := NewStore(time.Minute)
+	srv := httptest.NewServer(st.PushHandler(""))
+	defer srv.Close()
+
+	if err := Push(context.Background(), srv.URL, "", Report{}); err == nil {
+		t.Error("Push without a host succeeded")
+	}
+}
+
+func TestRequireToken(t *testing.T) {
+	h := RequireToken("s3cret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	get := func(target string, prep func(*http.Request)) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		if prep != nil {
+			prep(r)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	for name, w := range map[string]*httptest.ResponseRecorder{
+		"no token":    get("/api/sessions", nil),
+		"wrong query": get("/?token=nope", nil),
+		"wrong bearer": get("/api/events", func(r *http.Request) {
+			r.Header.Set("Authorization", "Bearer nope")
+		}),
+	} {
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status %d, want 401", name, w.Code)
+		}
+	}
+
+	w := get("/?token=s3cret", nil)
+	if w.Code != http.StatusOK || len(w.Result().Cookies()) != 1 {
+		t.Fatalf("query token: status %d, cookies %v", w.Code, w.Result().Cookies())
+	}
+	cookie := w.Result().Cookies()[0]
+	if w := get("/api/events", func(r *http.Request) { r.AddCookie(cookie) }); w.Code != http.StatusOK {
+		t.Errorf("cookie token: status %d", w.Code)
+	}
+	if w := get("/api/sessions", func(r *http.Request) { r.Header.Set("Authorization", "Bearer s3cret") }); w.Code != http.StatusOK {
+		t.Errorf("bearer token: status %d", w.Code)
+	}
+}
+
+func TestLoopback(t *testing.T) {
+	for host, want := range map[string]bool{"127.0.0.1": true, "localhost": true, "::1": true, "0.0.0.0": false, "": false, "192.168.1.5": false} {
+		if got := Loopback(host); got != want {
+			t.Errorf("Loopback(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
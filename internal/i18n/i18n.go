@@ -0,0 +1,99 @@
+// Package i18n translates the user-facing strings of the terminal views.
+//
+// Messages are looked up by their English text, gettext style, in a catalog
+// per language embedded from locales/<lang>.json. A message missing from the
+// catalog is shown in English, so a partial translation is still usable and
+// English needs no catalog at all. Adding a language is adding its JSON file.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	lang    = "en"
+	catalog map[string]string // English message → translation; nil for English
+)
+
+// Languages returns the available language codes, English first.
+func Languages() []string {
+	langs := []string{"en"}
+	entries, _ := locales.ReadDir("locales")
+	for _, e := range entries {
+		langs = append(langs, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	return langs
+}
+
+// SetLanguage switches the views to lang, a code from Languages. Locale
+// names like "da_DK.UTF-8" or "da-DK" select their language; "auto" takes
+// it from $LC_ALL, $LC_MESSAGES or $LANG, falling back to English when
+// those name no available language. "" is English.
+func SetLanguage(code string) error {
+	if code == "auto" {
+		code = "en"
+		for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+			if v := os.Getenv(env); v != "" {
+				if slices.Contains(Languages(), baseLanguage(v)) {
+					code = v
+				}
+				break
+			}
+		}
+	}
+	code = baseLanguage(code)
+	if code == "" || code == "en" {
+		lang, catalog = "en", nil
+		return nil
+	}
+	data, err := locales.ReadFile(path.Join("locales", code+".json"))
+	if err != nil {
+		return fmt.Errorf("unknown language %q (available: %s)", code, strings.Join(Languages(), ", "))
+	}
+	var c map[string]string
+	if err := json.Unmarshal(data, &c); err != nil {
+		return fmt.Errorf("language %s: %w", code, err)
+	}
+	lang, catalog = code, c
+	return nil
+}
+
+// Language returns the code of the current language.
+func Language() string {
+	return lang
+}
+
+// baseLanguage reduces a locale name to its language code:
+// "da_DK.UTF-8" → "da". "C" and "POSIX" are English.
+func baseLanguage(locale string) string {
+	code, _, _ := strings.Cut(locale, ".")
+	code, _, _ = strings.Cut(code, "_")
+	code, _, _ = strings.Cut(code, "-")
+	code = strings.ToLower(code)
+	if code == "c" || code == "posix" {
+		return "en"
+	}
+	return code
+}
+
+// T translates msg into the current language and, when args are given,
+// formats it with fmt.Sprintf. Translations keep the verbs of the English
+// message in the same order.
+func T(msg string, args ...any) string {
+	if s, ok := catalog[msg]; ok && s != "" {
+		msg = s
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestT(t *testing.T) {
+	defer SetLanguage("en")
+
+	if got := T("%d working", 3); got != "3 working" {
+		t.Errorf("English T = %q", got)
+	}
+	for _, code := range []string{"da", "da_DK.UTF-8", "da-DK"} {
+		if err := SetLanguage(code); err != nil || Language() != "da" {
+			t.Fatalf("SetLanguage(%q) = %v, language %s", code, err, Language())
+		}
+	}
+	if got := T("%d working", 3); got != "3 arbejder" {
+		t.Errorf("Danish T = %q", got)
+	}
+	if got := T("not in any catalog"); got != "not in any catalog" {
+		t.Errorf("untranslated T = %q, want the English text", got)
+	}
+
+	if err := SetLanguage("xx"); err == nil || Language() != "da" {
+		t.Errorf("SetLanguage(xx) = %v, language %s", err, Language())
+	}
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "fr_FR.UTF-8")
+	if err := SetLanguage("auto"); err != nil || Language() != "en" {
+		t.Errorf("auto with LANG=fr = %v, language %s", err, Language())
+	}
+	t.Setenv("LANG", "da_DK.UTF-8")
+	if SetLanguage("auto"); Language() != "da" {
+		t.Errorf("auto with LANG=da: language %s", Language())
+	}
+}
+
+// Translations are passed the English message's arguments, so their verbs
+// must match.
+func TestCatalogVerbs(t *testing.T) {
+	verb := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	for _, code := range Languages()[1:] {
+		data, err := locales.ReadFile(path.Join("locales", code+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var c map[string]string
+		if err := json.Unmarshal(data, &c); err != nil {
+			t.Fatalf("%s: %v", code, err)
+		}
+		for msg, tr := range c {
+			if !slices.Equal(verb.FindAllString(msg, -1), verb.FindAllString(tr, -1)) {
+				t.Errorf("%s: %q has other verbs than %q", code, tr, msg)
+			}
+		}
+	}
+}
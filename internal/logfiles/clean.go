@@ -0,0 +1,69 @@
+package logfiles
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// Reasons a log is junk.
+const (
+	JunkEmpty   = "empty"
+	JunkCorrupt = "corrupt"
+)
+
+// JunkLog is a log with nothing usable in it.
+type JunkLog struct {
+	Log
+	Reason string // JunkEmpty or JunkCorrupt
+}
+
+// Junk returns the logs that are empty or in which no line parses as JSON.
+// Logs written within minAge of now are left alone: a session that just
+// started has an empty log, and the live view relies on seeing it.
+func Junk(logs []Log, minAge time.Duration, now time.Time) []JunkLog {
+	var junk []JunkLog
+	for _, l := range logs {
+		if now.Sub(l.ModTime) < minAge {
+			continue
+		}
+		if reason := junkReason(l.Path); reason != "" {
+			junk = append(junk, JunkLog{Log: l, Reason: reason})
+		}
+	}
+	return junk
+}
+
+// junkReason reads a log until its first valid line and says why it is junk,
+// or returns "" if it isn't (or can't be read).
+func junkReason(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	lines := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if json.Valid(line) {
+			return ""
+		}
+		lines++
+	}
+	if scanner.Err() != nil {
+		// An over-long line isn't proof of corruption.
+		return ""
+	}
+	if lines == 0 {
+		return JunkEmpty
+	}
+	return JunkCorrupt
+}
@@ -0,0 +1,146 @@
+// Package logfiles does housekeeping on the session logs Claude Code keeps
+// under <claude dir>/projects: measuring them, and finding, archiving and
+// deleting old ones.
+package logfiles
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Log is a JSONL session log on disk.
+type Log struct {
+	Path    string    // Absolute path
+	Root    string    // The Claude config dir the log lives under
+	Project string    // Encoded project directory name
+	Size    int64     // Bytes
+	ModTime time.Time // Last write
+}
+
+// Walk returns every .jsonl log under root/projects, including subagent logs
+// in nested directories.
+func Walk(root string) ([]Log, error) {
+	projects := filepath.Join(root, "projects")
+	var logs []Log
+	err := filepath.WalkDir(projects, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == projects {
+				return err
+			}
+			return nil // skip unreadable subtrees
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".jsonl") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(projects, path)
+		project, _, _ := strings.Cut(rel, string(filepath.Separator))
+		logs = append(logs, Log{
+			Path:    path,
+			Root:    root,
+			Project: project,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	return logs, err
+}
+
+// ParseAge parses an age such as "60d", "2w" or any time.ParseDuration
+// string ("36h").
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 60d, 2w or 36h)", s)
+	}
+	return d, nil
+}
+
+// FormatSize formats a byte count for humans: "512 B", "3.4 MB", "12.0 GB".
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// removeEmptyDirs removes directories left empty under root/projects after
+// their logs were deleted, deepest first, keeping projects itself.
+func removeEmptyDirs(root string, deleted []Log) {
+	projects := filepath.Join(root, "projects")
+	seen := make(map[string]bool)
+	for _, l := range deleted {
+		for dir := filepath.Dir(l.Path); dir != projects && strings.HasPrefix(dir, projects); dir = filepath.Dir(dir) {
+			if seen[dir] {
+				break
+			}
+			seen[dir] = true
+			if os.Remove(dir) != nil {
+				break // not empty (or gone): its parents aren't either
+			}
+		}
+	}
+}
+
+// ProjectUsage is the disk usage of one project directory's logs.
+type ProjectUsage struct {
+	Root    string // Claude config dir
+	Project string // Encoded project directory name
+	Logs    int
+	Size    int64
+	Largest Log
+}
+
+// Usage totals logs per project, largest project first.
+func Usage(logs []Log) []ProjectUsage {
+	type key struct{ root, project string }
+	byProject := make(map[key]*ProjectUsage)
+	var order []key
+	for _, l := range logs {
+		k := key{l.Root, l.Project}
+		u, ok := byProject[k]
+		if !ok {
+			u = &ProjectUsage{Root: l.Root, Project: l.Project}
+			byProject[k] = u
+			order = append(order, k)
+		}
+		u.Logs++
+		u.Size += l.Size
+		if l.Size > u.Largest.Size || u.Largest.Path == "" {
+			u.Largest = l
+		}
+	}
+
+	usage := make([]ProjectUsage, 0, len(order))
+	for _, k := range order {
+		usage = append(usage, *byProject[k])
+	}
+	sort.SliceStable(usage, func(i, j int) bool { return usage[i].Size > usage[j].Size })
+	return usage
+}
@@ -0,0 +1,182 @@
+package logfiles
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"60d", 60 * 24 * time.Hour, true},
+		{"2w", 14 * 24 * time.Hour, true},
+		{"36h", 36 * time.Hour, true},
+		{"xd", 0, false},
+		{"-3d", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseAge(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		512:             "512 B",
+		2048:            "2.0 KB",
+		5 * 1024 * 1024: "5.0 MB",
+		3 << 30:         "3.0 GB",
+	}
+	for n, want := range tests {
+		if got := FormatSize(n); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+// writeLog creates a log under root/projects with the given age.
+func writeLog(t *testing.T, root, rel string, age time.Duration) string {
+	t.Helper()
+	path := filepath.Join(root, "projects", rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now().Add(-age)
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPrune(t *testing.T) {
+	root := filepath.Join(t.TempDir(), ".claude")
+	day := 24 * time.Hour
+	oldLog := writeLog(t, root, "-home-me-api/old.jsonl", 90*day)
+	oldAgent := writeLog(t, root, "-home-me-api/old/subagents/agent-1.jsonl", 80*day)
+	newLog := writeLog(t, root, "-home-me-api/new.jsonl", day)
+
+	logs, err := Walk(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 3 || logs[0].Project != "-home-me-api" {
+		t.Fatalf("Walk = %+v", logs)
+	}
+
+	old := OlderThan(logs, time.Now().Add(-60*day))
+	if len(old) != 2 || old[0].Path != oldLog {
+		t.Fatalf("OlderThan = %+v, want the two old logs, oldest first", old)
+	}
+
+	archive := filepath.Join(t.TempDir(), "logs.tar.gz")
+	if err := Archive(archive, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := Archive(archive, old); err == nil {
+		t.Error("Archive overwrote an existing file")
+	}
+	names := tarNames(t, archive)
+	if len(names) != 2 || names[0] != ".claude/projects/-home-me-api/old.jsonl" {
+		t.Errorf("archive entries = %v", names)
+	}
+
+	deleted, err := Delete(old)
+	if err != nil || len(deleted) != 2 {
+		t.Fatalf("Delete = %d, %v", len(deleted), err)
+	}
+	for _, p := range []string{oldLog, oldAgent, filepath.Dir(oldAgent)} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", p)
+		}
+	}
+	if _, err := os.Stat(newLog); err != nil {
+		t.Errorf("recent log removed: %v", err)
+	}
+}
+
+func tarNames(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, hdr.Name)
+	}
+	return names
+}
+
+func TestUsage(t *testing.T) {
+	logs := []Log{
+		{Root: "/c", Project: "-a", Path: "/c/projects/-a/1.jsonl", Size: 10},
+		{Root: "/c", Project: "-b", Path: "/c/projects/-b/1.jsonl", Size: 50},
+		{Root: "/c", Project: "-a", Path: "/c/projects/-a/2.jsonl", Size: 30},
+		{Root: "/w", Project: "-a", Path: "/w/projects/-a/1.jsonl", Size: 5},
+	}
+	got := Usage(logs)
+	if len(got) != 3 {
+		t.Fatalf("Usage returned %d projects, want 3 (same name in two roots stays apart)", len(got))
+	}
+	if got[0].Project != "-b" || got[1].Project != "-a" || got[1].Root != "/c" {
+		t.Errorf("order = %+v", got)
+	}
+	if got[1].Logs != 2 || got[1].Size != 40 || got[1].Largest.Path != "/c/projects/-a/2.jsonl" {
+		t.Errorf("project -a = %+v", got[1])
+	}
+}
+
+func TestJunk(t *testing.T) {
+	root := t.TempDir()
+	day := 24 * time.Hour
+	write := func(name, content string, age time.Duration) Log {
+		path := writeLog(t, root, name, age)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		at := time.Now().Add(-age)
+		os.Chtimes(path, at, at)
+		return Log{Path: path, ModTime: at}
+	}
+	logs := []Log{
+		write("-a/empty.jsonl", "", day),
+		write("-a/blank.jsonl", "\n\n", day),
+		write("-a/corrupt.jsonl", "{\"type\":\nnot json\n", day),
+		write("-a/partly.jsonl", "garbage\n{\"type\":\"user\"}\n", day),
+		write("-a/fresh.jsonl", "", time.Minute),
+	}
+
+	got := Junk(logs, time.Hour, time.Now())
+	want := map[string]string{"empty.jsonl": JunkEmpty, "blank.jsonl": JunkEmpty, "corrupt.jsonl": JunkCorrupt}
+	if len(got) != len(want) {
+		t.Fatalf("Junk = %+v, want %v", got, want)
+	}
+	for _, j := range got {
+		if want[filepath.Base(j.Path)] != j.Reason {
+			t.Errorf("%s: reason %q, want %q", filepath.Base(j.Path), j.Reason, want[filepath.Base(j.Path)])
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package logfiles
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OlderThan returns the logs last written before cutoff, oldest first.
+func OlderThan(logs []Log, cutoff time.Time) []Log {
+	var old []Log
+	for _, l := range logs {
+		if l.ModTime.Before(cutoff) {
+			old = append(old, l)
+		}
+	}
+	sort.Slice(old, func(i, j int) bool { return old[i].ModTime.Before(old[j].ModTime) })
+	return old
+}
+
+// TotalSize sums the size of logs.
+func TotalSize(logs []Log) int64 {
+	var n int64
+	for _, l := range logs {
+		n += l.Size
+	}
+	return n
+}
+
+// Archive writes logs to a new tar archive at path, compressed according to
+// its extension: .tar.zst/.tzst (needs the zstd command), .tar.gz/.tgz, or
+// plain .tar. Entries are named after the Claude config dir, e.g.
+// ".claude/projects/-home-me-api/<id>.jsonl". An existing file is never
+// overwritten.
+func Archive(path string, logs []Log) (err error) {
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	w, finish, err := compressor(path, out)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(w)
+	for _, l := range logs {
+		if err := addToTar(tw, l); err != nil {
+			finish()
+			return fmt.Errorf("archive %s: %w", l.Path, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		finish()
+		return err
+	}
+	return finish()
+}
+
+// compressor wraps out in the compression the archive name asks for. finish
+// flushes the compressed stream and must be called once writing is done.
+func compressor(path string, out *os.File) (io.Writer, func() error, error) {
+	name := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.HasSuffix(name, ".tar.zst"), strings.HasSuffix(name, ".tzst"):
+		zstd, err := exec.LookPath("zstd")
+		if err != nil {
+			return nil, nil, errors.New("zstd not found in PATH; use a .tar.gz archive instead")
+		}
+		cmd := exec.Command(zstd, "-q", "-c")
+		cmd.Stdout = out
+		cmd.Stderr = os.Stderr
+		in, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, nil, err
+		}
+		return in, func() error {
+			in.Close()
+			return cmd.Wait()
+		}, nil
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		gz := gzip.NewWriter(out)
+		return gz, gz.Close, nil
+	case strings.HasSuffix(name, ".tar"):
+		return out, func() error { return nil }, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported archive %q: use .tar.zst, .tar.gz or .tar", filepath.Base(path))
+}
+
+func addToTar(tw *tar.Writer, l Log) error {
+	f, err := os.Open(l.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(l.Root), l.Path)
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = filepath.ToSlash(rel)
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	// Copy exactly the header's size in case the file grew meanwhile.
+	_, err = io.CopyN(tw, f, hdr.Size)
+	return err
+}
+
+// Delete removes logs and any directories they leave empty, returning the
+// logs actually deleted and the first error met.
+func Delete(logs []Log) ([]Log, error) {
+	var deleted []Log
+	var firstErr error
+	for _, l := range logs {
+		if err := os.Remove(l.Path); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		deleted = append(deleted, l)
+	}
+	roots := make(map[string][]Log)
+	for _, l := range deleted {
+		roots[l.Root] = append(roots[l.Root], l)
+	}
+	for root, ls := range roots {
+		removeEmptyDirs(root, ls)
+	}
+	return deleted, firstErr
+}
@@ -0,0 +1,257 @@
+// Package notify sends desktop notifications for session events and decides
+// when to hold them back: quiet hours, per-event-type cooldowns, and muted
+// projects.
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/events"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// Suppression reasons recorded in events.Event.Suppressed.
+const (
+	ReasonQuietHours = "quiet_hours"
+	ReasonCooldown   = "cooldown"
+	ReasonMuted      = "muted"
+)
+
+// defaultCooldown applies to event types without a configured cooldown.
+const defaultCooldown = time.Minute
+
+// defaultLongTurn is how long Claude must have been working for its return
+// to Waiting to notify.
+const defaultLongTurn = 2 * time.Minute
+
+// Notification is one desktop notification.
+type Notification struct {
+	Title string
+	Body  string
+}
+
+// Message returns the notification for e, or false when e is not one that
+// alerts.
+func (n *Notifier) Message(e events.Event) (Notification, bool) {
+	switch {
+	case e.Kind == events.StatusChanged && e.Status == session.StatusNeedsInput:
+		return Notification{Title: e.Project, Body: "Needs your input"}, true
+	case e.Kind == events.StatusChanged && e.PrevStatus == session.StatusWorking && e.Status == session.StatusWaiting &&
+		n.longTurn > 0 && e.Elapsed >= n.longTurn:
+		return Notification{Title: e.Project, Body: fmt.Sprintf("%s finished its task (%s)", e.Project, formatTurn(e.Elapsed))}, true
+	case e.Kind == events.ContextThreshold:
+		return Notification{Title: e.Project, Body: fmt.Sprintf("Context at %d%%", e.Threshold)}, true
+	case e.Kind == events.GhostDetected:
+		return Notification{Title: e.Project, Body: fmt.Sprintf("Ghost process detected (PID %d)", e.PID)}, true
+	case e.Kind == events.BudgetExceeded && e.Project == "":
+		return Notification{Title: "csm", Body: fmt.Sprintf("Daily budget exceeded (%s)", formatSpend(e))}, true
+	case e.Kind == events.BudgetExceeded:
+		return Notification{Title: e.Project, Body: fmt.Sprintf("Session budget exceeded (%s)", formatSpend(e))}, true
+	case e.Kind == events.AllIdle && n.allIdle:
+		return Notification{Title: "csm", Body: fmt.Sprintf("All sessions finished (%s)", formatTurn(e.Elapsed))}, true
+	}
+	return Notification{}, false
+}
+
+// Notifier turns events into desktop notifications, applying the
+// suppression rules. It is not safe for concurrent use.
+type Notifier struct {
+	longTurn  time.Duration
+	allIdle   bool
+	quiet     *QuietHours
+	cooldowns map[events.Kind]time.Duration
+	muted     map[string]bool
+	last      map[string]time.Time // kind + session → last notification
+
+	send func(Notification) error // overridable in tests
+	now  func() time.Time
+}
+
+// New builds a Notifier from the notifications config.
+func New(cfg config.NotifyConfig) (*Notifier, error) {
+	n := &Notifier{
+		longTurn:  defaultLongTurn,
+		allIdle:   cfg.AllIdle,
+		cooldowns: make(map[events.Kind]time.Duration),
+		muted:     make(map[string]bool),
+		last:      make(map[string]time.Time),
+		send:      Send,
+		now:       time.Now,
+	}
+	if cfg.LongTurn != "" {
+		d, err := time.ParseDuration(cfg.LongTurn)
+		if err != nil {
+			return nil, fmt.Errorf("notifications long_turn: %w", err)
+		}
+		n.longTurn = d
+	}
+	if cfg.QuietHours != "" {
+		q, err := ParseQuietHours(cfg.QuietHours)
+		if err != nil {
+			return nil, err
+		}
+		n.quiet = &q
+	}
+	for kind, d := range cfg.Cooldowns {
+		dur, err := time.ParseDuration(d)
+		if err != nil {
+			return nil, fmt.Errorf("notifications cooldown for %s: %w", kind, err)
+		}
+		n.cooldowns[events.Kind(kind)] = dur
+	}
+	for _, p := range cfg.Mute {
+		n.muted[p] = true
+	}
+	return n, nil
+}
+
+// ToggleMute mutes or unmutes project and reports whether it is now muted.
+func (n *Notifier) ToggleMute(project string) bool {
+	n.muted[project] = !n.muted[project]
+	return n.muted[project]
+}
+
+// Muted lists the currently muted projects, sorted.
+func (n *Notifier) Muted() []string {
+	var out []string
+	for p, on := range n.muted {
+		if on {
+			out = append(out, p)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Process sends notifications for evs and records the outcome on each event
+// (Notified, or the Suppressed reason), returning the annotated events.
+// Events that never alert are returned unchanged.
+func (n *Notifier) Process(evs []events.Event) []events.Event {
+	now := n.now()
+	for i := range evs {
+		msg, ok := n.Message(evs[i])
+		if !ok {
+			continue
+		}
+		if reason := n.suppress(evs[i], now); reason != "" {
+			evs[i].Suppressed = reason
+			continue
+		}
+		if err := n.send(msg); err != nil {
+			continue
+		}
+		evs[i].Notified = true
+		n.last[cooldownKey(evs[i])] = now
+	}
+	return evs
+}
+
+// suppress returns why e must not notify at now, or "".
+func (n *Notifier) suppress(e events.Event, now time.Time) string {
+	if n.muted[e.Project] {
+		return ReasonMuted
+	}
+	if n.quiet != nil && n.quiet.Contains(now) {
+		return ReasonQuietHours
+	}
+	cooldown, ok := n.cooldowns[e.Kind]
+	if !ok {
+		cooldown = defaultCooldown
+	}
+	if last, ok := n.last[cooldownKey(e)]; ok && now.Sub(last) < cooldown {
+		return ReasonCooldown
+	}
+	return ""
+}
+
+func cooldownKey(e events.Event) string {
+	return string(e.Kind) + "\x00" + e.Host + "\x00" + e.LogFile
+}
+
+// formatTurn formats a turn length like "45s", "4m 32s", or "1h 5m".
+func formatTurn(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm %ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// formatSpend describes a BudgetExceeded event's usage as "1.2M tokens,
+// $4.50".
+func formatSpend(e events.Event) string {
+	tokens := fmt.Sprintf("%d", e.Tokens)
+	switch {
+	case e.Tokens >= 1_000_000:
+		tokens = fmt.Sprintf("%.1fM", float64(e.Tokens)/1e6)
+	case e.Tokens >= 1_000:
+		tokens = fmt.Sprintf("%.1fk", float64(e.Tokens)/1e3)
+	}
+	return fmt.Sprintf("%s tokens, $%.2f", tokens, e.Cost)
+}
+
+// QuietHours is a daily local-time window, possibly spanning midnight.
+type QuietHours struct {
+	start, end int // minutes since midnight
+}
+
+// ParseQuietHours parses "HH:MM-HH:MM", e.g. "22:00-08:00".
+func ParseQuietHours(s string) (QuietHours, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("quiet hours %q: want HH:MM-HH:MM", s)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("quiet hours %q: %w", s, err)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("quiet hours %q: %w", s, err)
+	}
+	return QuietHours{
+		start: start.Hour()*60 + start.Minute(),
+		end:   end.Hour()*60 + end.Minute(),
+	}, nil
+}
+
+// Contains reports whether t's local time of day falls in the window.
+func (q QuietHours) Contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	if q.start <= q.end {
+		return m >= q.start && m < q.end
+	}
+	return m >= q.start || m < q.end
+}
+
+// Send shows a desktop notification via osascript (macOS) or notify-send
+// (Linux).
+func Send(n Notification) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(n.Body), appleScriptString(n.Title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux":
+		cmd = exec.Command("notify-send", "--app-name=csm", "--", n.Title, n.Body)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	return cmd.Run()
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/events"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestQuietHours(t *testing.T) {
+	day := func(h, m int) time.Time { return time.Date(2026, 1, 1, h, m, 0, 0, time.Local) }
+
+	overnight, err := ParseQuietHours("22:00-08:00")
+	if err != nil {
+		t.Fatalf("ParseQuietHours: %v", err)
+	}
+	daytime, _ := ParseQuietHours("12:00 - 13:30")
+
+	tests := []struct {
+		q    QuietHours
+		at   time.Time
+		want bool
+	}{
+		{overnight, day(23, 15), true},
+		{overnight, day(3, 0), true},
+		{overnight, day(8, 0), false},
+		{overnight, day(21, 59), false},
+		{daytime, day(12, 45), true},
+		{daytime, day(13, 30), false},
+	}
+	for _, tt := range tests {
+		if got := tt.q.Contains(tt.at); got != tt.want {
+			t.Errorf("%+v.Contains(%s) = %v, want %v", tt.q, tt.at.Format("15:04"), got, tt.want)
+		}
+	}
+
+	if _, err := ParseQuietHours("10pm-8am"); err == nil {
+		t.Error("ParseQuietHours accepted 10pm-8am")
+	}
+}
+
+func newTestNotifier(t *testing.T, cfg config.NotifyConfig, now *time.Time) (*Notifier, *[]Notification) {
+	t.Helper()
+	n, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var sent []Notification
+	n.send = func(msg Notification) error {
+		sent = append(sent, msg)
+		return nil
+	}
+	n.now = func() time.Time { return *now }
+	return n, &sent
+}
+
+func needsInput(project string) events.Event {
+	return events.Event{Kind: events.StatusChanged, Status: session.StatusNeedsInput, Project: project, LogFile: "/" + project + ".jsonl"}
+}
+
+func TestNotifierSuppression(t *testing.T) {
+	now := time.Date(2026, 1, 1, 14, 0, 0, 0, time.Local)
+	n, sent := newTestNotifier(t, config.NotifyConfig{
+		QuietHours: "22:00-08:00",
+		Cooldowns:  map[string]string{"status_changed": "5m"},
+		Mute:       []string{"org/noisy"},
+	}, &now)
+
+	evs := n.Process([]events.Event{
+		needsInput("org/api"),
+		needsInput("org/noisy"),
+		{Kind: events.Appeared, Project: "org/api"},
+	})
+	if !evs[0].Notified || evs[1].Suppressed != ReasonMuted || evs[2].Notified || evs[2].Suppressed != "" {
+		t.Errorf("first batch = %+v", evs)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if evs = n.Process([]events.Event{needsInput("org/api")}); evs[0].Suppressed != ReasonCooldown {
+		t.Errorf("within cooldown = %+v", evs[0])
+	}
+
+	now = now.Add(4 * time.Minute)
+	if evs = n.Process([]events.Event{needsInput("org/api")}); !evs[0].Notified {
+		t.Errorf("after cooldown = %+v", evs[0])
+	}
+
+	now = time.Date(2026, 1, 1, 23, 0, 0, 0, time.Local)
+	if evs = n.Process([]events.Event{needsInput("org/web")}); evs[0].Suppressed != ReasonQuietHours {
+		t.Errorf("quiet hours = %+v", evs[0])
+	}
+
+	if len(*sent) != 2 {
+		t.Errorf("sent %d notifications, want 2: %+v", len(*sent), *sent)
+	}
+
+	if !n.ToggleMute("org/api") || n.ToggleMute("org/api") {
+		t.Error("ToggleMute did not toggle")
+	}
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	if _, err := New(config.NotifyConfig{Cooldowns: map[string]string{"ghost_detected": "soon"}}); err == nil {
+		t.Error("New accepted an invalid cooldown")
+	}
+	if _, err := New(config.NotifyConfig{QuietHours: "22"}); err == nil {
+		t.Error("New accepted invalid quiet hours")
+	}
+}
+
+func TestLongTurnFinished(t *testing.T) {
+	now := time.Date(2026, 1, 1, 14, 0, 0, 0, time.Local)
+	n, sent := newTestNotifier(t, config.NotifyConfig{LongTurn: "2m"}, &now)
+
+	finished := func(project string, elapsed time.Duration) events.Event {
+		return events.Event{Kind: events.StatusChanged, PrevStatus: session.StatusWorking, Status: session.StatusWaiting,
+			Project: project, LogFile: "/" + project + ".jsonl", Elapsed: elapsed}
+	}
+	evs := n.Process([]events.Event{finished("org/api", 4*time.Minute+32*time.Second), finished("org/web", 30*time.Second)})
+	if !evs[0].Notified || evs[1].Notified {
+		t.Errorf("long turn events = %+v", evs)
+	}
+	if len(*sent) != 1 || (*sent)[0].Body != "org/api finished its task (4m 32s)" {
+		t.Errorf("sent = %+v", *sent)
+	}
+
+	off, _ := newTestNotifier(t, config.NotifyConfig{LongTurn: "0"}, &now)
+	if _, ok := off.Message(finished("org/api", time.Hour)); ok {
+		t.Error("long_turn 0 still notified")
+	}
+}
+
+func TestAllIdle(t *testing.T) {
+	now := time.Date(2026, 1, 1, 14, 0, 0, 0, time.Local)
+	idle := events.Event{Kind: events.AllIdle, Elapsed: 12 * time.Minute}
+
+	off, _ := newTestNotifier(t, config.NotifyConfig{}, &now)
+	if _, ok := off.Message(idle); ok {
+		t.Error("all-idle notified without all_idle")
+	}
+
+	n, sent := newTestNotifier(t, config.NotifyConfig{AllIdle: true}, &now)
+	if evs := n.Process([]events.Event{idle}); !evs[0].Notified {
+		t.Errorf("all-idle event = %+v", evs[0])
+	}
+	if len(*sent) != 1 || (*sent)[0].Body != "All sessions finished (12m 0s)" {
+		t.Errorf("sent = %+v", *sent)
+	}
+}
+
+func TestBudgetMessages(t *testing.T) {
+	now := time.Date(2026, 1, 1, 14, 0, 0, 0, time.Local)
+	n, _ := newTestNotifier(t, config.NotifyConfig{}, &now)
+
+	msg, ok := n.Message(events.Event{Kind: events.BudgetExceeded, Project: "org/api", Tokens: 1_250_000, Cost: 4.5})
+	if !ok || msg.Title != "org/api" || msg.Body != "Session budget exceeded (1.2M tokens, $4.50)" {
+		t.Errorf("session budget message = %+v, %v", msg, ok)
+	}
+	msg, ok = n.Message(events.DailyBudgetExceeded(now, &session.UsageStats{TotalTokens: 800, Cost: 0.01}))
+	if !ok || msg.Title != "csm" || msg.Body != "Daily budget exceeded (800 tokens, $0.01)" {
+		t.Errorf("daily budget message = %+v, %v", msg, ok)
+	}
+}
+
+func TestFormatTurn(t *testing.T) {
+	tests := map[time.Duration]string{
+		45 * time.Second:                          "45s",
+		4*time.Minute + 32*time.Second:            "4m 32s",
+		time.Hour + 5*time.Minute + 9*time.Second: "1h 5m",
+		2*time.Minute + 400*time.Millisecond:      "2m 0s",
+	}
+	for d, want := range tests {
+		if got := formatTurn(d); got != want {
+			t.Errorf("formatTurn(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+// Package otlp exports sessions as OpenTelemetry traces over OTLP/HTTP, so
+// Claude activity can be lined up with a team's other traces.
+//
+// Each session is a trace: a root span from the session appearing to it
+// ending, with its status transitions, context thresholds, ghost and budget
+// events as span events, and one child span per turn (a stretch of
+// Working). Spans are sent as OTLP JSON, which every OTLP/HTTP collector
+// accepts, so no OpenTelemetry SDK is needed.
+package otlp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/events"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// Span is a finished span, laid out as OTLP JSON.
+type Span struct {
+	TraceID      string      `json:"traceId"`
+	SpanID       string      `json:"spanId"`
+	ParentSpanID string      `json:"parentSpanId,omitempty"`
+	Name         string      `json:"name"`
+	Kind         int         `json:"kind"`
+	Start        nanos       `json:"startTimeUnixNano"`
+	End          nanos       `json:"endTimeUnixNano"`
+	Attributes   []Attribute `json:"attributes,omitempty"`
+	Events       []SpanEvent `json:"events,omitempty"`
+}
+
+// SpanEvent is a point in time within a span.
+type SpanEvent struct {
+	Time       nanos       `json:"timeUnixNano"`
+	Name       string      `json:"name"`
+	Attributes []Attribute `json:"attributes,omitempty"`
+}
+
+// Attribute is a key and a string, integer or float value.
+type Attribute struct {
+	Key   string `json:"key"`
+	Value Value  `json:"value"`
+}
+
+// Value holds one of the OTLP AnyValue kinds csm uses. OTLP JSON encodes
+// 64-bit integers as strings.
+type Value struct {
+	String *string  `json:"stringValue,omitempty"`
+	Int    *string  `json:"intValue,omitempty"`
+	Double *float64 `json:"doubleValue,omitempty"`
+}
+
+// nanos is a time as OTLP JSON wants it: Unix nanoseconds in a string.
+type nanos time.Time
+
+func (n nanos) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(time.Time(n).UnixNano(), 10))
+}
+
+func str(key, v string) Attribute { return Attribute{Key: key, Value: Value{String: &v}} }
+
+func integer(key string, v int) Attribute {
+	s := strconv.Itoa(v)
+	return Attribute{Key: key, Value: Value{Int: &s}}
+}
+
+func double(key string, v float64) Attribute { return Attribute{Key: key, Value: Value{Double: &v}} }
+
+// spanKindInternal is OTLP's SPAN_KIND_INTERNAL.
+const spanKindInternal = 1
+
+// openSession is a session whose span is still in progress.
+type openSession struct {
+	span  Span
+	last  session.Session
+	turn  *Span
+	turnS session.Session // the session when the turn started, for token deltas
+}
+
+// Recorder turns the events of an events.Tracker into spans. It is not safe
+// for concurrent use.
+type Recorder struct {
+	open map[string]*openSession // by host and log file
+}
+
+// NewRecorder returns a Recorder with no sessions in progress.
+func NewRecorder() *Recorder {
+	return &Recorder{open: make(map[string]*openSession)}
+}
+
+func key(host, logFile string) string { return host + "\x00" + logFile }
+
+// Record applies a batch of events from events.Tracker.Update for the
+// snapshot sessions, and returns the spans that finished: turns that
+// stopped working and sessions that ended.
+func (r *Recorder) Record(evs []events.Event, sessions []session.Session) []Span {
+	current := make(map[string]session.Session, len(sessions))
+	for _, s := range sessions {
+		current[key(s.Host, s.LogFile)] = s
+	}
+
+	var done []Span
+	for _, e := range evs {
+		if e.LogFile == "" { // AllIdle and the daily budget aren't about one session
+			continue
+		}
+		k := key(e.Host, e.LogFile)
+		s, ok := current[k]
+		o := r.open[k]
+		if !ok && o != nil {
+			s = o.last // vanished; describe it as last seen
+		}
+
+		if o == nil {
+			if e.Kind == events.Ended {
+				continue
+			}
+			o = r.start(s, e.Time)
+			r.open[k] = o
+		}
+		o.last = s
+
+		switch e.Kind {
+		case events.Appeared:
+			if s.Status == session.StatusWorking {
+				o.startTurn(s, e.Time)
+			}
+			continue
+		case events.StatusChanged, events.Ended:
+			o.span.Events = append(o.span.Events, SpanEvent{
+				Time: nanos(e.Time),
+				Name: "status " + string(e.Status),
+				Attributes: []Attribute{
+					str("claude.status", string(e.Status)),
+					str("claude.prev_status", string(e.PrevStatus)),
+					integer("claude.prev_status_ms", int(e.Elapsed.Milliseconds())),
+				},
+			})
+			if e.PrevStatus == session.StatusWorking && o.turn != nil {
+				done = append(done, o.endTurn(s, e.Time))
+			}
+			if e.Kind == events.Ended {
+				done = append(done, o.end(e.Time))
+				delete(r.open, k)
+				continue
+			}
+			if e.Status == session.StatusWorking {
+				o.startTurn(s, e.Time)
+			}
+		case events.ContextThreshold:
+			o.span.Events = append(o.span.Events, SpanEvent{Time: nanos(e.Time), Name: "context threshold",
+				Attributes: []Attribute{integer("claude.context.threshold", e.Threshold), double("claude.context.percent", e.ContextPercent)}})
+		case events.GhostDetected:
+			o.span.Events = append(o.span.Events, SpanEvent{Time: nanos(e.Time), Name: "ghost detected",
+				Attributes: []Attribute{integer("process.pid", e.PID)}})
+		case events.BudgetExceeded:
+			o.span.Events = append(o.span.Events, SpanEvent{Time: nanos(e.Time), Name: "budget exceeded",
+				Attributes: []Attribute{integer("claude.tokens", e.Tokens), double("claude.cost_usd", e.Cost)}})
+		}
+	}
+	return done
+}
+
+// Flush ends every span in progress at now, for a clean shutdown.
+func (r *Recorder) Flush(now time.Time) []Span {
+	var done []Span
+	keys := make([]string, 0, len(r.open))
+	for k := range r.open {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		o := r.open[k]
+		if o.turn != nil {
+			done = append(done, o.endTurn(o.last, now))
+		}
+		done = append(done, o.end(now))
+	}
+	r.open = make(map[string]*openSession)
+	return done
+}
+
+func (r *Recorder) start(s session.Session, now time.Time) *openSession {
+	start := now
+	if !s.StartedAt.IsZero() && s.StartedAt.Before(now) {
+		start = s.StartedAt
+	}
+	return &openSession{span: Span{
+		TraceID: traceID(s),
+		SpanID:  newSpanID(),
+		Name:    "session " + s.Project,
+		Kind:    spanKindInternal,
+		Start:   nanos(start),
+	}}
+}
+
+func (o *openSession) startTurn(s session.Session, now time.Time) {
+	o.turn = &Span{
+		TraceID:      o.span.TraceID,
+		SpanID:       newSpanID(),
+		ParentSpanID: o.span.SpanID,
+		Name:         "turn",
+		Kind:         spanKindInternal,
+		Start:        nanos(now),
+	}
+	o.turnS = s
+}
+
+func (o *openSession) endTurn(s session.Session, now time.Time) Span {
+	t := *o.turn
+	o.turn = nil
+	t.End = nanos(now)
+	t.Attributes = []Attribute{
+		str("claude.project", s.Project),
+		integer("claude.tokens", max(s.Tokens-o.turnS.Tokens, 0)),
+		integer("gen_ai.usage.output_tokens", max(s.OutputTokens-o.turnS.OutputTokens, 0)),
+		double("claude.cost_usd", max(s.Cost-o.turnS.Cost, 0)),
+	}
+	if s.Model != "" {
+		t.Attributes = append(t.Attributes, str("gen_ai.response.model", s.Model))
+	}
+	return t
+}
+
+func (o *openSession) end(now time.Time) Span {
+	s := o.last
+	sp := o.span
+	sp.End = nanos(now)
+	sp.Attributes = []Attribute{
+		str("claude.project", s.Project),
+		str("claude.session_id", s.SessionID),
+		integer("claude.tokens", s.Tokens),
+		integer("gen_ai.usage.output_tokens", s.OutputTokens),
+		double("claude.cost_usd", s.Cost),
+	}
+	for _, a := range []struct{ key, v string }{
+		{"claude.project_path", s.CWD},
+		{"claude.git_branch", s.GitBranch},
+		{"gen_ai.response.model", s.Model},
+		{"claude.version", s.Version},
+		{"host.name", s.Host},
+	} {
+		if a.v != "" {
+			sp.Attributes = append(sp.Attributes, str(a.key, a.v))
+		}
+	}
+	return sp
+}
+
+// traceID is the session's UUID when it has one, so traces can be found by
+// session id, else a hash of its log file.
+func traceID(s session.Session) string {
+	id := strings.ReplaceAll(s.SessionID, "-", "")
+	if b, err := hex.DecodeString(id); err == nil && len(b) == 16 {
+		return strings.ToLower(id)
+	}
+	sum := sha256.Sum256([]byte(s.Host + "\x00" + s.LogFile))
+	return hex.EncodeToString(sum[:16])
+}
+
+func newSpanID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Exporter posts spans to an OTLP/HTTP collector.
+type Exporter struct {
+	url      string
+	headers  map[string]string
+	resource []Attribute
+	version  string
+	http     *http.Client
+}
+
+// NewExporter returns an exporter for the collector at endpoint, e.g.
+// "http://localhost:4318"; "/v1/traces" is appended unless the endpoint
+// already names it. headers are sent with each request, e.g. an API key.
+func NewExporter(endpoint string, headers map[string]string, serviceName, version string) *Exporter {
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/traces") {
+		url += "/v1/traces"
+	}
+	if serviceName == "" {
+		serviceName = "csm"
+	}
+	return &Exporter{
+		url:      url,
+		headers:  headers,
+		resource: []Attribute{str("service.name", serviceName), str("service.version", version)},
+		version:  version,
+		http:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Export sends spans in one request.
+func (x *Exporter) Export(spans []Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(x.request(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", x.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range x.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := x.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// request wraps spans in an ExportTraceServiceRequest.
+func (x *Exporter) request(spans []Span) any {
+	type scope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+	type scopeSpans struct {
+		Scope scope  `json:"scope"`
+		Spans []Span `json:"spans"`
+	}
+	type resource struct {
+		Attributes []Attribute `json:"attributes"`
+	}
+	type resourceSpans struct {
+		Resource   resource     `json:"resource"`
+		ScopeSpans []scopeSpans `json:"scopeSpans"`
+	}
+	return struct {
+		ResourceSpans []resourceSpans `json:"resourceSpans"`
+	}{[]resourceSpans{{
+		Resource:   resource{Attributes: x.resource},
+		ScopeSpans: []scopeSpans{{Scope: scope{Name: "csm", Version: x.version}, Spans: spans}},
+	}}}
+}
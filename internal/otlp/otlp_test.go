@@ -0,0 +1,108 @@
+package otlp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/events"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func attr(attrs []Attribute, key string) string {
+	for _, a := range attrs {
+		if a.Key == key {
+			switch {
+			case a.Value.String != nil:
+				return *a.Value.String
+			case a.Value.Int != nil:
+				return *a.Value.Int
+			}
+		}
+	}
+	return ""
+}
+
+func TestRecorder(t *testing.T) {
+	t0 := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	s := session.Session{
+		Project: "api", LogFile: "/p/3f2a.jsonl", SessionID: "3f2a9c1e-0000-4000-8000-00000000abcd",
+		Status: session.StatusWorking, Tokens: 1000, OutputTokens: 100, StartedAt: t0.Add(-time.Minute),
+	}
+	tracker, rec := events.NewTracker(), NewRecorder()
+	step := func(at time.Time, s session.Session) []Span {
+		sessions := []session.Session{s}
+		return rec.Record(tracker.Update(sessions, at), sessions)
+	}
+
+	if spans := step(t0, s); len(spans) != 0 {
+		t.Fatalf("spans on appearing: %+v", spans)
+	}
+	s.Status, s.Tokens, s.OutputTokens = session.StatusWaiting, 5000, 700
+	turns := step(t0.Add(30*time.Second), s)
+	if len(turns) != 1 {
+		t.Fatalf("want the finished turn, got %+v", turns)
+	}
+	turn := turns[0]
+	if turn.Name != "turn" || turn.TraceID != "3f2a9c1e00004000800000000000abcd" || attr(turn.Attributes, "claude.tokens") != "4000" ||
+		attr(turn.Attributes, "gen_ai.usage.output_tokens") != "600" {
+		t.Errorf("turn = %+v", turn)
+	}
+
+	s.Status = session.StatusInactive
+	ended := step(t0.Add(time.Minute), s)
+	if len(ended) != 1 {
+		t.Fatalf("want the session span, got %+v", ended)
+	}
+	root := ended[0]
+	if root.SpanID != turn.ParentSpanID || root.TraceID != turn.TraceID || root.ParentSpanID != "" {
+		t.Errorf("turn %s/%s is not a child of session %s/%s", turn.TraceID, turn.ParentSpanID, root.TraceID, root.SpanID)
+	}
+	if time.Time(root.Start) != s.StartedAt || time.Time(root.End) != t0.Add(time.Minute) {
+		t.Errorf("session span %v → %v", time.Time(root.Start), time.Time(root.End))
+	}
+	if len(root.Events) != 2 || root.Events[0].Name != "status Waiting" || root.Events[1].Name != "status Inactive" {
+		t.Errorf("session events = %+v", root.Events)
+	}
+	if attr(root.Attributes, "claude.session_id") != s.SessionID || attr(root.Attributes, "claude.tokens") != "5000" {
+		t.Errorf("session attributes = %+v", root.Attributes)
+	}
+
+	// A session still running when csm stops is flushed.
+	s2 := session.Session{Project: "web", LogFile: "/p/web.jsonl", Status: session.StatusWorking}
+	step(t0.Add(2*time.Minute), s2)
+	if spans := rec.Flush(t0.Add(3 * time.Minute)); len(spans) != 2 || spans[0].Name != "turn" || spans[1].Name != "session web" {
+		t.Errorf("Flush = %+v", spans)
+	}
+}
+
+func TestExport(t *testing.T) {
+	var path, key string
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, key = r.URL.Path, r.Header.Get("x-api-key")
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &body)
+	}))
+	defer srv.Close()
+
+	x := NewExporter(srv.URL, map[string]string{"x-api-key": "secret"}, "", "1.0.0")
+	start := time.Unix(1780000000, 5)
+	err := x.Export([]Span{{TraceID: "ab", SpanID: "cd", Name: "turn", Start: nanos(start), End: nanos(start), Attributes: []Attribute{integer("claude.tokens", 42)}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/v1/traces" || key != "secret" {
+		t.Errorf("posted to %q with key %q", path, key)
+	}
+	out, _ := json.Marshal(body)
+	for _, want := range []string{`"service.name"`, `"stringValue":"csm"`, `"startTimeUnixNano":"1780000000000000005"`, `"intValue":"42"`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("request missing %s: %s", want, out)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package plugin runs user-configured commands that supply extra live-table
+// columns. Each command gets one session as JSON on stdin and prints the cell
+// value as a single line, so org-specific data (ticket status, CI state, ...)
+// can be shown without forking csm.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+const (
+	defaultWidth    = 12
+	defaultTimeout  = 2 * time.Second
+	defaultInterval = 30 * time.Second
+)
+
+// Column is a plugin column whose values come from an external command.
+// Values are cached per session and refreshed in the background, so a slow
+// command never stalls rendering; a cell shows its previous value (or
+// nothing) until the command answers.
+type Column struct {
+	Name     string
+	Header   string
+	Width    int
+	command  string
+	timeout  time.Duration
+	interval time.Duration
+
+	mu      sync.Mutex
+	values  map[string]cell
+	pending map[string]bool
+}
+
+type cell struct {
+	text string
+	at   time.Time
+}
+
+// New builds a Column from its config entry, applying defaults.
+func New(c config.PluginColumn) (*Column, error) {
+	if c.Name == "" || c.Command == "" {
+		return nil, fmt.Errorf("plugin column needs a name and a command")
+	}
+	col := &Column{
+		Name:     c.Name,
+		Header:   c.Header,
+		Width:    c.Width,
+		command:  c.Command,
+		timeout:  defaultTimeout,
+		interval: defaultInterval,
+		values:   make(map[string]cell),
+		pending:  make(map[string]bool),
+	}
+	if col.Header == "" {
+		col.Header = strings.ToUpper(c.Name)
+	}
+	if col.Width <= 0 {
+		col.Width = defaultWidth
+	}
+	var err error
+	if c.Timeout != "" {
+		if col.timeout, err = time.ParseDuration(c.Timeout); err != nil {
+			return nil, fmt.Errorf("plugin column %s timeout: %w", c.Name, err)
+		}
+	}
+	if c.Interval != "" {
+		if col.interval, err = time.ParseDuration(c.Interval); err != nil {
+			return nil, fmt.Errorf("plugin column %s interval: %w", c.Name, err)
+		}
+	}
+	return col, nil
+}
+
+func sessionKey(s session.Session) string {
+	return s.Host + "\x00" + s.LogFile
+}
+
+// Value returns the cached cell for s and starts a background refresh when
+// the cache is missing or older than the column's interval.
+func (c *Column) Value(s session.Session) string {
+	k := sessionKey(s)
+	c.mu.Lock()
+	v, ok := c.values[k]
+	stale := !ok || time.Since(v.at) >= c.interval
+	if stale && !c.pending[k] {
+		c.pending[k] = true
+		go c.Refresh(s)
+	}
+	c.mu.Unlock()
+	return v.text
+}
+
+// Refresh runs the command for s and caches its output. Errors are cached as
+// "?" so a broken plugin is visible without being retried every frame.
+func (c *Column) Refresh(s session.Session) {
+	text, err := run(c.command, s, c.timeout)
+	if err != nil {
+		text = "?"
+	}
+	c.mu.Lock()
+	c.values[sessionKey(s)] = cell{text: text, at: time.Now()}
+	delete(c.pending, sessionKey(s))
+	c.mu.Unlock()
+}
+
+// pipeWaitDelay is how long run waits for a timed-out command's children to
+// close its output.
+const pipeWaitDelay = 100 * time.Millisecond
+
+// run executes command through the shell with s as JSON on stdin and returns
+// the first line of its output.
+func run(command string, s session.Session, timeout time.Duration) (string, error) {
+	input, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(input)
+	// The timeout kills only sh. A pipeline it started (curl … | jq) can
+	// still hold stdout open, so stop waiting for the output soon after.
+	cmd.WaitDelay = pipeWaitDelay
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(string(out), "\n")
+	return strings.TrimSpace(line), nil
+}
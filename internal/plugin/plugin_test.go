@@ -0,0 +1,62 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestRunReceivesSessionJSON(t *testing.T) {
+	s := session.Session{Project: "org/api", LogFile: "/a.jsonl"}
+	got, err := run(`grep -o '"project":"[^"]*"'; echo second line`, s, time.Second)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got != `"project":"org/api"` {
+		t.Errorf("run = %q, want the first output line", got)
+	}
+}
+
+func TestColumnCachesValues(t *testing.T) {
+	col, err := New(config.PluginColumn{Name: "ci", Command: "echo green"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if col.Header != "CI" || col.Width != defaultWidth {
+		t.Errorf("defaults: header %q width %d", col.Header, col.Width)
+	}
+
+	s := session.Session{LogFile: "/a.jsonl"}
+	col.Refresh(s)
+	if got := col.Value(s); got != "green" {
+		t.Errorf("Value = %q, want green", got)
+	}
+
+	broken, _ := New(config.PluginColumn{Name: "t", Command: "exit 3"})
+	broken.Refresh(s)
+	if got := broken.Value(s); got != "?" {
+		t.Errorf("Value for a failing command = %q, want ?", got)
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	if _, err := New(config.PluginColumn{Name: "ci"}); err == nil {
+		t.Error("New accepted a column without a command")
+	}
+	if _, err := New(config.PluginColumn{Name: "ci", Command: "true", Timeout: "fast"}); err == nil {
+		t.Error("New accepted an invalid timeout")
+	}
+}
+
+func TestRunTimesOutPipelines(t *testing.T) {
+	// sleep keeps the pipe open after sh is killed.
+	start := time.Now()
+	if _, err := run("sleep 3 | cat", session.Session{}, 100*time.Millisecond); err == nil {
+		t.Error("timed-out command reported no error")
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("run took %v, want it to stop soon after the timeout", took)
+	}
+}
@@ -0,0 +1,156 @@
+// Package remote collects Claude sessions from other machines by running
+// `csm -l -json` on them over SSH.
+//
+// The legacy flag form is used on purpose so that hosts running an older csm
+// (before subcommands) still answer.
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// fetchTimeout bounds a single SSH round trip, including connection setup.
+const fetchTimeout = 15 * time.Second
+
+// remoteCommand is run on each host. It is a single string so the remote
+// login shell resolves csm on its own PATH.
+const remoteCommand = "csm -l -json"
+
+// ParseHosts splits a --remote value ("user@host,host2") into host names.
+func ParseHosts(spec string) []string {
+	var hosts []string
+	for _, h := range strings.Split(spec, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
+
+// Fetch runs csm on host over SSH and returns its sessions tagged with Host.
+// SSH runs in batch mode, so hosts must be reachable without a password
+// prompt (keys or an agent).
+func Fetch(ctx context.Context, host string) ([]session.Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "ssh", "-o", "BatchMode=yes", "-o", "ConnectTimeout=5", "--", host, remoteCommand)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("%s: %s", host, firstLine(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("%s: %w", host, err)
+	}
+	return parseSessions(host, out)
+}
+
+// parseSessions decodes `csm -l -json` output and tags each session with host.
+func parseSessions(host string, data []byte) ([]session.Session, error) {
+	var sessions []session.Session
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("%s: unexpected csm output: %w", host, err)
+	}
+	for i := range sessions {
+		sessions[i].Host = host
+	}
+	return sessions, nil
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// Poller keeps the latest sessions from a set of hosts. Each host keeps its
+// last good result when a later fetch fails, so a flaky link doesn't make
+// sessions blink in and out of the live view.
+type Poller struct {
+	hosts []string
+
+	mu       sync.Mutex
+	sessions map[string][]session.Session
+	errs     map[string]error
+}
+
+// NewPoller returns a Poller for hosts. Nothing is fetched until Refresh or Run.
+func NewPoller(hosts []string) *Poller {
+	return &Poller{
+		hosts:    hosts,
+		sessions: make(map[string][]session.Session),
+		errs:     make(map[string]error),
+	}
+}
+
+// Refresh fetches every host in parallel and waits for all of them.
+func (p *Poller) Refresh(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, host := range p.hosts {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			sessions, err := Fetch(ctx, host)
+			p.record(host, sessions, err)
+		}(host)
+	}
+	wg.Wait()
+}
+
+// record stores one fetch result, keeping the previous sessions on error.
+func (p *Poller) record(host string, sessions []session.Session, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.errs[host] = err
+	if err == nil {
+		p.sessions[host] = sessions
+	}
+}
+
+// Run refreshes every interval until ctx is cancelled.
+func (p *Poller) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		p.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sessions returns the latest sessions from all hosts, in host order.
+func (p *Poller) Sessions() []session.Session {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var all []session.Session
+	for _, host := range p.hosts {
+		all = append(all, p.sessions[host]...)
+	}
+	return all
+}
+
+// Errors returns the error from each host's most recent fetch, if it failed.
+func (p *Poller) Errors() []error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var errs []error
+	for _, host := range p.hosts {
+		if err := p.errs[host]; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
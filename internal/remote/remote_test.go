@@ -0,0 +1,51 @@
+package remote
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestParseHosts(t *testing.T) {
+	got := ParseHosts(" me@dev1 ,dev2,, ")
+	want := []string{"me@dev1", "dev2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseHosts = %v, want %v", got, want)
+	}
+}
+
+func TestParseSessions(t *testing.T) {
+	data := []byte(`[{"project":"org/api","status":"Working","log_file":"/home/me/.claude/projects/x/a.jsonl"}]`)
+	sessions, err := parseSessions("dev1", data)
+	if err != nil {
+		t.Fatalf("parseSessions: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(sessions))
+	}
+	s := sessions[0]
+	if s.Host != "dev1" || s.Project != "org/api" || s.Status != session.StatusWorking {
+		t.Errorf("parseSessions = %+v", s)
+	}
+
+	if _, err := parseSessions("dev1", []byte("csm: command not found")); err == nil {
+		t.Error("parseSessions accepted non-JSON output")
+	}
+}
+
+func TestPollerKeepsLastGoodResult(t *testing.T) {
+	p := NewPoller([]string{"dev1", "dev2"})
+	p.record("dev1", []session.Session{{Project: "org/api", Host: "dev1"}}, nil)
+	p.record("dev2", []session.Session{{Project: "org/web", Host: "dev2"}}, nil)
+	p.record("dev1", nil, errors.New("dev1: connection refused"))
+
+	got := p.Sessions()
+	if len(got) != 2 || got[0].Project != "org/api" || got[1].Project != "org/web" {
+		t.Errorf("Sessions() = %+v, want last good result from both hosts in host order", got)
+	}
+	if errs := p.Errors(); len(errs) != 1 {
+		t.Errorf("Errors() = %v, want one error", errs)
+	}
+}
@@ -0,0 +1,410 @@
+package search
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// indexVersion is bumped whenever the on-disk format changes; an index
+// written by another version is rebuilt from scratch.
+const indexVersion = 2
+
+// Index is an inverted word index over session transcripts, kept on disk so
+// repeated searches across months of history don't re-read every log. It is
+// updated incrementally: each log is read from where the last update stopped.
+// Messages are stored by where they start in their log, not by their text,
+// which keeps the index a fraction of the size of the logs; a query re-reads
+// only the messages it returns, for their snippets.
+type Index struct {
+	Version  int
+	Logs     []Log // indexed logs, by Doc.Log
+	Docs     []Doc
+	Files    map[string]*IndexedFile
+	Postings map[string][]Posting
+	Deleted  int // Docs dropped since the last compaction
+}
+
+// Log is an indexed session log.
+type Log struct {
+	Path    string
+	Project string
+}
+
+// Doc is one indexed message: the line at Offset in Logs[Log].
+type Doc struct {
+	Log       int32
+	Offset    int64
+	Role      string
+	Timestamp time.Time
+	Deleted   bool
+}
+
+// IndexedFile records how far a log has been indexed and which docs came
+// from it.
+type IndexedFile struct {
+	Log    int32
+	Offset int64
+	Docs   []int32
+}
+
+// Posting says a doc contains a word Count times.
+type Posting struct {
+	Doc   int32
+	Count uint16
+}
+
+// DefaultIndexPath returns ~/.claude-monitor/search-index.gob.
+func DefaultIndexPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".claude-monitor", "search-index.gob"), nil
+}
+
+// NewIndex returns an empty index.
+func NewIndex() *Index {
+	return &Index{
+		Version:  indexVersion,
+		Files:    make(map[string]*IndexedFile),
+		Postings: make(map[string][]Posting),
+	}
+}
+
+// LoadIndex reads the index at path. A missing, unreadable or outdated index
+// yields an empty one, which the next Update fills.
+func LoadIndex(path string) *Index {
+	f, err := os.Open(path)
+	if err != nil {
+		return NewIndex()
+	}
+	defer f.Close()
+
+	var ix Index
+	if err := gob.NewDecoder(bufio.NewReader(f)).Decode(&ix); err != nil || ix.Version != indexVersion {
+		return NewIndex()
+	}
+	if ix.Files == nil {
+		ix.Files = make(map[string]*IndexedFile)
+	}
+	if ix.Postings == nil {
+		ix.Postings = make(map[string][]Posting)
+	}
+	return &ix
+}
+
+// Save atomically replaces the index file at path.
+func (ix *Index) Save(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op if rename succeeded
+	w := bufio.NewWriter(tmp)
+	if err := gob.NewEncoder(w).Encode(ix); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Update indexes what was written to files since the last update and drops
+// logs that no longer exist. It reports whether the index changed and so
+// needs saving.
+func (ix *Index) Update(files []File) bool {
+	changed := false
+	for path := range ix.Files {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			ix.dropFile(path)
+			changed = true
+		}
+	}
+
+	for _, f := range files {
+		if ix.updateFile(f) {
+			changed = true
+		}
+	}
+	if ix.Deleted > len(ix.Docs)/2 {
+		ix.compact()
+	}
+	return changed
+}
+
+// updateFile indexes the complete lines appended to a log since its last
+// update, reporting whether it read anything. A log that shrank is
+// re-indexed from the start.
+func (ix *Index) updateFile(f File) bool {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	state := ix.Files[f.Path]
+	if state != nil && info.Size() < state.Offset {
+		ix.dropFile(f.Path)
+		state = nil
+	}
+	if state == nil {
+		state = &IndexedFile{Log: int32(len(ix.Logs))}
+		ix.Logs = append(ix.Logs, Log{Path: f.Path, Project: f.Project})
+		ix.Files[f.Path] = state
+	}
+	if info.Size() == state.Offset {
+		return false
+	}
+	if _, err := file.Seek(state.Offset, io.SeekStart); err != nil {
+		return false
+	}
+
+	start := state.Offset
+	reader := bufio.NewReaderSize(file, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break // EOF, or a partial line left for the next update
+		}
+		offset := state.Offset
+		state.Offset += int64(len(line))
+
+		var entry session.LogEntry
+		if json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		text := messageText(entry)
+		if text == "" {
+			continue
+		}
+		id := ix.addDoc(Doc{
+			Log:       state.Log,
+			Offset:    offset,
+			Role:      entry.Type,
+			Timestamp: entry.Timestamp,
+		}, text)
+		state.Docs = append(state.Docs, id)
+	}
+	return state.Offset != start
+}
+
+// addDoc adds a message with the given text.
+func (ix *Index) addDoc(d Doc, text string) int32 {
+	id := int32(len(ix.Docs))
+	ix.Docs = append(ix.Docs, d)
+	counts := make(map[string]int)
+	for _, w := range tokenize(text) {
+		counts[w]++
+	}
+	for w, n := range counts {
+		ix.Postings[w] = append(ix.Postings[w], Posting{Doc: id, Count: uint16(min(n, math.MaxUint16))})
+	}
+	return id
+}
+
+// dropFile marks a log's docs deleted; compact reclaims them.
+func (ix *Index) dropFile(path string) {
+	state := ix.Files[path]
+	if state == nil {
+		return
+	}
+	for _, id := range state.Docs {
+		ix.Docs[id].Deleted = true
+	}
+	ix.Deleted += len(state.Docs)
+	delete(ix.Files, path)
+}
+
+// compact rebuilds the index without deleted docs and the logs they came
+// from, renumbering what is left.
+func (ix *Index) compact() {
+	logs := ix.Logs
+	ix.Logs = nil
+	relog := make(map[int32]int32)
+	for _, state := range ix.Files {
+		relog[state.Log] = int32(len(ix.Logs))
+		ix.Logs = append(ix.Logs, logs[state.Log])
+		state.Log = relog[state.Log]
+	}
+
+	old := ix.Docs
+	ix.Docs = nil
+	renum := make(map[int32]int32)
+	for i, d := range old {
+		if !d.Deleted {
+			d.Log = relog[d.Log]
+			renum[int32(i)] = int32(len(ix.Docs))
+			ix.Docs = append(ix.Docs, d)
+		}
+	}
+	for _, state := range ix.Files {
+		for i, id := range state.Docs {
+			state.Docs[i] = renum[id]
+		}
+	}
+	for w, postings := range ix.Postings {
+		kept := postings[:0]
+		for _, p := range postings {
+			if id, ok := renum[p.Doc]; ok {
+				kept = append(kept, Posting{Doc: id, Count: p.Count})
+			}
+		}
+		if len(kept) == 0 {
+			delete(ix.Postings, w)
+		} else {
+			ix.Postings[w] = kept
+		}
+	}
+	ix.Deleted = 0
+}
+
+// Query returns up to limit messages containing every word of q, best match
+// first: words rare across the index and repeated in a message weigh most,
+// ties go to the newer message. Messages before since, or in projects whose
+// name doesn't contain project, are skipped.
+func (ix *Index) Query(q string, since time.Time, project string, limit int) []Match {
+	words := tokenize(q)
+	if len(words) == 0 {
+		return nil
+	}
+	project = strings.ToLower(project)
+
+	scores := make(map[int32]float64)
+	for i, w := range words {
+		postings := ix.Postings[w]
+		idf := math.Log(1 + float64(len(ix.Docs))/float64(len(postings)+1))
+		next := make(map[int32]float64)
+		for _, p := range postings {
+			if _, ok := scores[p.Doc]; i > 0 && !ok {
+				continue
+			}
+			next[p.Doc] = scores[p.Doc] + (1+math.Log(float64(p.Count)))*idf
+		}
+		scores = next
+	}
+
+	type hit struct {
+		doc   int32
+		score float64
+	}
+	var hits []hit
+	for id, score := range scores {
+		d := ix.Docs[id]
+		if d.Deleted || d.Timestamp.Before(since) {
+			continue
+		}
+		if project != "" && !strings.Contains(strings.ToLower(ix.Logs[d.Log].Project), project) {
+			continue
+		}
+		hits = append(hits, hit{id, score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return ix.Docs[hits[i].doc].Timestamp.After(ix.Docs[hits[j].doc].Timestamp)
+	})
+
+	// Only the messages returned are read back from their logs. One whose
+	// log was rewritten since it was indexed is skipped.
+	first := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(words[0]))
+	logs := make(map[int32]*os.File)
+	defer func() {
+		for _, f := range logs {
+			f.Close()
+		}
+	}()
+	var matches []Match
+	for _, h := range hits {
+		if limit > 0 && len(matches) == limit {
+			break
+		}
+		d := ix.Docs[h.doc]
+		log := ix.Logs[d.Log]
+		f, ok := logs[d.Log]
+		if !ok {
+			f, _ = os.Open(log.Path)
+			logs[d.Log] = f
+		}
+		text := readMessage(f, d.Offset)
+		if text == "" {
+			continue
+		}
+		loc := first.FindStringIndex(text)
+		if loc == nil {
+			loc = []int{0, 0}
+		}
+		snippet, start, end := Snippet(text, loc)
+		matches = append(matches, Match{
+			LogFile:   log.Path,
+			Project:   log.Project,
+			SessionID: strings.TrimSuffix(filepath.Base(log.Path), ".jsonl"),
+			Timestamp: d.Timestamp,
+			Role:      d.Role,
+			Snippet:   snippet,
+			Start:     start,
+			End:       end,
+		})
+	}
+	return matches
+}
+
+// readMessage returns the text of the message on the log line starting at
+// offset, or "" when f is nil or the line no longer holds a message.
+func readMessage(f *os.File, offset int64) string {
+	if f == nil {
+		return ""
+	}
+	line, err := bufio.NewReader(io.NewSectionReader(f, offset, math.MaxInt64-offset)).ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return ""
+	}
+	var entry session.LogEntry
+	if json.Unmarshal(line, &entry) != nil {
+		return ""
+	}
+	return messageText(entry)
+}
+
+// tokenize splits text into lowercase words of at least two letters or
+// digits; very long runs (hashes, base64) are dropped.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := fields[:0]
+	for _, f := range fields {
+		if n := len([]rune(f)); n >= 2 && n <= 40 {
+			words = append(words, f)
+		}
+	}
+	return words
+}
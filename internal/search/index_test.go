@@ -0,0 +1,103 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIndex(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "s1.jsonl")
+	msg := func(ts, role, text string) string {
+		return `{"type":"` + role + `","timestamp":"` + ts + `","message":{"role":"` + role + `","content":[{"type":"text","text":"` + text + `"}]}}` + "\n"
+	}
+	write := func(content string, flag int) {
+		f, err := os.OpenFile(logPath, flag|os.O_WRONLY|os.O_CREATE, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(content)
+		f.Close()
+	}
+	write(msg("2026-03-01T10:00:00Z", "user", "write the migration script")+
+		msg("2026-03-01T10:01:00Z", "assistant", "The migration script migrates the migration table"), os.O_TRUNC)
+
+	ix := NewIndex()
+	files := []File{{Path: logPath, Project: "org/api"}}
+	if !ix.Update(files) {
+		t.Fatal("first Update reported no change")
+	}
+	if ix.Update(files) {
+		t.Error("Update without new lines reported a change")
+	}
+
+	got := ix.Query("Migration script", time.Time{}, "", 10)
+	if len(got) != 2 {
+		t.Fatalf("Query = %+v, want both messages", got)
+	}
+	if got[0].Role != "assistant" {
+		t.Errorf("ranking: first hit %q, want the message repeating the word", got[0].Role)
+	}
+	if s := got[0].Snippet; s[got[0].Start:got[0].End] != "migration" {
+		t.Errorf("snippet highlight = %q", s[got[0].Start:got[0].End])
+	}
+	if got := ix.Query("migration unicorn", time.Time{}, "", 10); len(got) != 0 {
+		t.Errorf("all words must match, got %+v", got)
+	}
+	if got := ix.Query("migration", time.Date(2026, 3, 1, 10, 0, 30, 0, time.UTC), "", 10); len(got) != 1 {
+		t.Errorf("since filter: got %d hits, want 1", len(got))
+	}
+	if got := ix.Query("migration", time.Time{}, "web", 10); len(got) != 0 {
+		t.Errorf("project filter: got %+v", got)
+	}
+
+	// Appended lines are indexed incrementally; the index survives a reload.
+	write(msg("2026-03-02T09:00:00Z", "user", "now the rollback"), os.O_APPEND)
+	ix.Update(files)
+	path := filepath.Join(dir, "index.gob")
+	if err := ix.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded := LoadIndex(path)
+	if got := loaded.Query("rollback", time.Time{}, "", 10); len(got) != 1 {
+		t.Errorf("reloaded index: rollback hits = %d, want 1", len(got))
+	}
+
+	// A deleted log drops out of the index.
+	os.Remove(logPath)
+	loaded.Update(nil)
+	if got := loaded.Query("migration", time.Time{}, "", 10); len(got) != 0 {
+		t.Errorf("deleted log still found: %+v", got)
+	}
+}
+
+func TestIndexCompact(t *testing.T) {
+	dir := t.TempDir()
+	msg := func(text string) string {
+		return `{"type":"user","timestamp":"2026-03-01T10:00:00Z","message":{"role":"user","content":"` + text + `"}}` + "\n"
+	}
+	old, kept := filepath.Join(dir, "old.jsonl"), filepath.Join(dir, "kept.jsonl")
+	os.WriteFile(old, []byte(msg("deploy the api")+msg("deploy again")+msg("deploy once more")), 0o644)
+	os.WriteFile(kept, []byte(msg("ship it")+msg("deploy the web app")), 0o644)
+
+	ix := NewIndex()
+	ix.Update([]File{{Path: old, Project: "org/api"}, {Path: kept, Project: "org/web"}})
+	os.Remove(old)
+	ix.Update(nil)
+	if len(ix.Docs) != 2 || len(ix.Logs) != 1 || ix.Deleted != 0 {
+		t.Fatalf("after compaction: %d docs, %d logs, %d deleted", len(ix.Docs), len(ix.Logs), ix.Deleted)
+	}
+	got := ix.Query("deploy", time.Time{}, "", 10)
+	if len(got) != 1 || got[0].Project != "org/web" || got[0].Snippet != "deploy the web app" {
+		t.Errorf("Query after compaction = %+v", got)
+	}
+
+	// Snippets are read back from the log; a message no longer where it
+	// was indexed is left out rather than shown wrong.
+	os.WriteFile(kept, []byte(msg("ship it")+`{"type":"summary"}`+"\n"), 0o644)
+	if got := ix.Query("deploy", time.Time{}, "", 10); len(got) != 0 {
+		t.Errorf("rewritten log still matched: %+v", got)
+	}
+}
@@ -0,0 +1,221 @@
+// Package search finds text in session transcripts: the prompts the user
+// typed and the text Claude wrote back, ignoring tool calls and results.
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// Match is a message whose text matches the pattern.
+type Match struct {
+	LogFile   string    `json:"log_file"`
+	Project   string    `json:"project"`
+	SessionID string    `json:"session_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Role      string    `json:"role"`    // "user" or "assistant"
+	Snippet   string    `json:"snippet"` // The match with some context, on one line
+	Start     int       `json:"-"`       // Byte offsets of the match in Snippet
+	End       int       `json:"-"`
+}
+
+// File is a session log to search.
+type File struct {
+	Path    string
+	Project string
+}
+
+// snippetContext is how many bytes of text a snippet keeps on each side of
+// the match.
+const snippetContext = 40
+
+// Files lists the main session logs (not subagent logs) of the profiles
+// written since the given time, in projects whose name contains project
+// (case-insensitively; "" matches all).
+func Files(profiles []session.Profile, since time.Time, project string) []File {
+	project = strings.ToLower(project)
+	var files []File
+	for _, p := range profiles {
+		dirs, _ := filepath.Glob(filepath.Join(p.Dir, "projects", "*"))
+		for _, dir := range dirs {
+			logs, _ := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+			if len(logs) == 0 {
+				continue
+			}
+			name := session.ProjectName(dir)
+			if project != "" && !strings.Contains(strings.ToLower(name), project) {
+				continue
+			}
+			for _, l := range logs {
+				if strings.HasPrefix(filepath.Base(l), "agent-") {
+					continue
+				}
+				if info, err := os.Stat(l); err != nil || info.ModTime().Before(since) {
+					continue
+				}
+				files = append(files, File{Path: l, Project: name})
+			}
+		}
+	}
+	return files
+}
+
+// Search scans files in parallel and calls emit with each file's matches, in
+// the order they appear in the file, as soon as that file is done. emit is
+// never called concurrently. Search returns early when ctx is cancelled.
+func Search(ctx context.Context, files []File, re *regexp.Regexp, emit func([]Match)) {
+	work := make(chan File)
+	results := make(chan []Match)
+
+	var wg sync.WaitGroup
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range work {
+				matches := searchFile(f, re)
+				select {
+				case results <- matches:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(work)
+		for _, f := range files {
+			select {
+			case work <- f:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for matches := range results {
+		if len(matches) > 0 {
+			emit(matches)
+		}
+	}
+}
+
+// searchFile streams one log and returns its matching messages, at most one
+// match per message.
+func searchFile(f File, re *regexp.Regexp) []Match {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	sessionID := strings.TrimSuffix(filepath.Base(f.Path), ".jsonl")
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var matches []Match
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		// Only user and assistant messages carry conversation text.
+		if !bytes.Contains(line, []byte(`"type":"user"`)) && !bytes.Contains(line, []byte(`"type":"assistant"`)) {
+			continue
+		}
+		var entry session.LogEntry
+		if json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		text := messageText(entry)
+		if text == "" {
+			continue
+		}
+		loc := re.FindStringIndex(text)
+		if loc == nil {
+			continue
+		}
+		snippet, start, end := Snippet(text, loc)
+		matches = append(matches, Match{
+			LogFile:   f.Path,
+			Project:   f.Project,
+			SessionID: sessionID,
+			Timestamp: entry.Timestamp,
+			Role:      entry.Type,
+			Snippet:   snippet,
+			Start:     start,
+			End:       end,
+		})
+	}
+	return matches
+}
+
+// messageText joins the text parts of a user or assistant message. Tool
+// results come back as user messages and are skipped.
+func messageText(entry session.LogEntry) string {
+	if (entry.Type != "user" && entry.Type != "assistant") || entry.Message == nil {
+		return ""
+	}
+	var parts []string
+	for _, c := range entry.Message.Content {
+		switch c.Type {
+		case "tool_result":
+			return ""
+		case "text":
+			parts = append(parts, c.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Snippet cuts the text around loc (a match's byte offsets) down to
+// snippetContext bytes each side, on one line, and returns it with the match
+// offsets within the snippet.
+func Snippet(text string, loc []int) (string, int, int) {
+	from := max(loc[0]-snippetContext, 0)
+	to := min(loc[1]+snippetContext, len(text))
+	// Don't cut runes in half.
+	for from > 0 && !isRuneStart(text[from]) {
+		from--
+	}
+	for to < len(text) && !isRuneStart(text[to]) {
+		to++
+	}
+
+	prefix, suffix := "", ""
+	if from > 0 {
+		prefix = "…"
+	}
+	if to < len(text) {
+		suffix = "…"
+	}
+	flat := func(s string) string { return strings.Join(strings.Fields(s), " ") }
+	before := flat(text[from:loc[0]])
+	if before != "" && isSpace(text[loc[0]-1]) {
+		before += " "
+	}
+	match := flat(text[loc[0]:loc[1]])
+	after := flat(text[loc[1]:to])
+	if after != "" && isSpace(text[loc[1]]) {
+		after = " " + after
+	}
+
+	start := len(prefix) + len(before)
+	return prefix + before + match + after + suffix, start, start + len(match)
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
+
+func isSpace(b byte) bool { return b == ' ' || b == '\n' || b == '\t' || b == '\r' }
@@ -0,0 +1,69 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("a", 60) + " the\nmigration script " + strings.Repeat("b", 60)
+	loc := regexp.MustCompile("migration").FindStringIndex(text)
+
+	got, start, end := Snippet(text, loc)
+	if got[start:end] != "migration" {
+		t.Errorf("offsets point at %q", got[start:end])
+	}
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("snippet not marked as cut: %q", got)
+	}
+	if strings.Contains(got, "\n") || !strings.Contains(got, "the migration script") {
+		t.Errorf("snippet = %q, want one line keeping the spacing around the match", got)
+	}
+
+	short, _, _ := Snippet("migration", []int{0, 9})
+	if short != "migration" {
+		t.Errorf("short snippet = %q", short)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "projects", "-home-me-repos-api")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	log := `{"type":"user","timestamp":"2026-03-02T10:00:00Z","cwd":"/home/me/repos/api","message":{"role":"user","content":"fix the Migration script"}}
+{"type":"assistant","timestamp":"2026-03-02T10:00:05Z","message":{"role":"assistant","content":[{"type":"text","text":"Looking at the migration now."},{"type":"tool_use","name":"Bash","input":{"command":"cat migration.sql"}}]}}
+{"type":"user","timestamp":"2026-03-02T10:00:06Z","message":{"role":"user","content":[{"type":"tool_result","content":"migration.sql contents"}]}}
+`
+	if err := os.WriteFile(filepath.Join(dir, "s1.jsonl"), []byte(log), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	profiles := []session.Profile{{Name: "default", Dir: root}}
+
+	if files := Files(profiles, time.Time{}, "web"); len(files) != 0 {
+		t.Errorf("project filter kept %v", files)
+	}
+	files := Files(profiles, time.Time{}, "API")
+	if len(files) != 1 || files[0].Project != "api" {
+		t.Fatalf("Files = %+v", files)
+	}
+
+	var got []Match
+	Search(context.Background(), files, regexp.MustCompile("(?i)migration"), func(m []Match) {
+		got = append(got, m...)
+	})
+	if len(got) != 2 {
+		t.Fatalf("got %d matches, want 2 (tool calls and results are skipped): %+v", len(got), got)
+	}
+	if got[0].Role != "user" || got[1].Role != "assistant" || got[0].SessionID != "s1" {
+		t.Errorf("matches = %+v", got)
+	}
+}
@@ -0,0 +1,101 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// apiErrorBody is one level of the nested error objects Claude logs, from
+// the HTTP wrapper ({"status":529,"error":{...}}) down to the API's own
+// {"type":"overloaded_error","message":"Overloaded"}.
+type apiErrorBody struct {
+	Status  int             `json:"status"`
+	Type    string          `json:"type"`
+	Message string          `json:"message"`
+	Error   json.RawMessage `json:"error"`
+}
+
+// apiError reports whether entry records a failed API request, an
+// api_error retry notice or a reply that gave up, and describes it as
+// "529 overloaded_error: Overloaded (retry 3/10)".
+func apiError(entry LogEntry) (string, bool) {
+	switch {
+	case entry.Type == "system" && entry.Subtype == "api_error":
+		desc := describeAPIError(entry.Error)
+		if desc == "" {
+			desc = "API error"
+		}
+		if entry.MaxRetries > 0 {
+			desc += fmt.Sprintf(" (retry %d/%d)", entry.RetryAttempt, entry.MaxRetries)
+		}
+		return desc, true
+	case entry.Type == "assistant" && entry.IsAPIErrorMessage:
+		text := ""
+		if entry.Message != nil {
+			for _, c := range entry.Message.Content {
+				if c.Type == "text" {
+					text = strings.TrimSpace(c.Text)
+					break
+				}
+			}
+		}
+		// "API Error: 529 {"type":"error",...}": replace the JSON with its gist.
+		if i := strings.Index(text, "{"); i >= 0 {
+			if desc := describeAPIError(json.RawMessage(text[i:])); desc != "" {
+				prefix := strings.TrimSpace(strings.TrimPrefix(text[:i], "API Error:"))
+				text = strings.TrimSpace(prefix + " " + desc)
+			}
+		}
+		if text == "" {
+			text = "API error"
+		}
+		return text, true
+	}
+	return "", false
+}
+
+// describeAPIError condenses a logged error value to "status type: message",
+// taking the innermost type and message. Returns "" if nothing is found.
+func describeAPIError(raw json.RawMessage) string {
+	status, typ, msg := 0, "", ""
+	for depth := 0; len(raw) > 0 && depth < 5; depth++ {
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			if s != "" {
+				msg = s
+			}
+			break
+		}
+		var b apiErrorBody
+		if json.Unmarshal(raw, &b) != nil {
+			break
+		}
+		if status == 0 {
+			status = b.Status
+		}
+		if b.Type != "" && b.Type != "error" {
+			typ = b.Type
+		}
+		if b.Message != "" {
+			msg = b.Message
+		}
+		raw = b.Error
+	}
+
+	var parts []string
+	if status != 0 {
+		parts = append(parts, fmt.Sprint(status))
+	}
+	if typ != "" {
+		parts = append(parts, typ)
+	}
+	desc := strings.Join(parts, " ")
+	if msg != "" {
+		if desc != "" {
+			desc += ": "
+		}
+		desc += strings.Join(strings.Fields(msg), " ")
+	}
+	return desc
+}
@@ -0,0 +1,54 @@
+package session
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+		ok   bool
+	}{
+		{
+			"retry notice",
+			`{"type":"system","subtype":"api_error","level":"error","error":{"status":529,"headers":{},"error":{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}},"retryInMs":1086.5,"retryAttempt":3,"maxRetries":10}`,
+			"529 overloaded_error: Overloaded (retry 3/10)",
+			true,
+		},
+		{
+			"connection error",
+			`{"type":"system","subtype":"api_error","error":{"message":"Connection error.","cause":{"code":"ECONNRESET"}},"retryAttempt":1,"maxRetries":10}`,
+			"Connection error. (retry 1/10)",
+			true,
+		},
+		{
+			"reply that gave up",
+			`{"type":"assistant","isApiErrorMessage":true,"message":{"role":"assistant","model":"<synthetic>","content":[{"type":"text","text":"API Error: 429 {\"type\":\"error\",\"error\":{\"type\":\"rate_limit_error\",\"message\":\"Rate limited\"}}"}]}}`,
+			"429 rate_limit_error: Rate limited",
+			true,
+		},
+		{
+			"plain error text",
+			`{"type":"assistant","isApiErrorMessage":true,"message":{"role":"assistant","content":[{"type":"text","text":"API Error: Request timed out."}]}}`,
+			"API Error: Request timed out.",
+			true,
+		},
+		{"other system entry", `{"type":"system","subtype":"turn_duration","durationMs":1000}`, "", false},
+		{"normal reply", `{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"{\"a\":1}"}]}}`, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entry LogEntry
+			if err := json.Unmarshal([]byte(tt.line), &entry); err != nil {
+				t.Fatal(err)
+			}
+			got, ok := apiError(entry)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("apiError() = %q, %v; want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reasons a command shows up in the audit.
+const (
+	AuditSandboxDisabled   = "sandbox-disabled"   // run with dangerouslyDisableSandbox
+	AuditBypassPermissions = "bypass-permissions" // run while permission checks were off
+)
+
+// AuditEntry is a Bash command Claude ran outside the usual safety net.
+type AuditEntry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Project     string    `json:"project"`
+	SessionID   string    `json:"session_id"`
+	LogFile     string    `json:"log_file"`
+	Reason      string    `json:"reason"`
+	Command     string    `json:"command"`
+	Description string    `json:"description,omitempty"`
+}
+
+// Audit lists the unsandboxed and permission-bypassing Bash commands in the
+// logs of every profile, subagent logs included, oldest first. Only logs
+// written since the given time are read, and only commands from then on are
+// kept.
+func Audit(since time.Time) ([]AuditEntry, error) {
+	profiles, err := Profiles()
+	if err != nil {
+		return nil, err
+	}
+	var all []AuditEntry
+	for _, p := range profiles {
+		projects := filepath.Join(p.Dir, "projects")
+		dirs, _ := os.ReadDir(projects)
+		for _, d := range dirs {
+			if !d.IsDir() {
+				continue
+			}
+			projectDir := filepath.Join(projects, d.Name())
+			project := ""
+			filepath.WalkDir(projectDir, func(path string, e fs.DirEntry, err error) error {
+				if err != nil || e.IsDir() || !strings.HasSuffix(path, ".jsonl") {
+					return nil
+				}
+				if info, err := e.Info(); err != nil || info.ModTime().Before(since) {
+					return nil
+				}
+				if project == "" {
+					project = ProjectName(projectDir)
+				}
+				entries, _ := auditLog(path, project)
+				for _, a := range entries {
+					if !a.Timestamp.Before(since) {
+						all = append(all, a)
+					}
+				}
+				return nil
+			})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Timestamp.Before(all[j].Timestamp) })
+	return all, nil
+}
+
+// auditLog returns the audited commands of one log. The permission mode is
+// recorded on user entries and applies to the calls that follow.
+func auditLog(logFile, project string) ([]AuditEntry, error) {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sessionID := strings.TrimSuffix(filepath.Base(logFile), ".jsonl")
+	var found []AuditEntry
+	mode := ""
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !bytes.Contains(line, []byte(`"Bash"`)) && !bytes.Contains(line, []byte(`"permissionMode"`)) {
+			continue
+		}
+		var entry LogEntry
+		if json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		if entry.PermissionMode != "" {
+			mode = entry.PermissionMode
+		}
+		if entry.Type != "assistant" || entry.Message == nil {
+			continue
+		}
+		for _, c := range entry.Message.Content {
+			input, ok := bashInput(c)
+			if !ok {
+				continue
+			}
+			reason := ""
+			switch {
+			case input.DangerouslyDisableSandbox:
+				reason = AuditSandboxDisabled
+			case mode == PermissionBypass:
+				reason = AuditBypassPermissions
+			default:
+				continue
+			}
+			found = append(found, AuditEntry{
+				Timestamp:   entry.Timestamp,
+				Project:     project,
+				SessionID:   sessionID,
+				LogFile:     logFile,
+				Reason:      reason,
+				Command:     input.Command,
+				Description: input.Description,
+			})
+		}
+	}
+	return found, scanner.Err()
+}
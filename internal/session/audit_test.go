@@ -0,0 +1,58 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "projects", "-home-me-repos-api")
+	if err := os.MkdirAll(filepath.Join(dir, "s1", "subagents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	mainLog := `{"type":"user","cwd":"/home/me/repos/api","permissionMode":"default","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"clean up"}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:05Z","message":{"role":"assistant","content":[{"type":"tool_use","name":"Bash","input":{"command":"ls"}}]}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:10Z","message":{"role":"assistant","content":[{"type":"tool_use","name":"Bash","input":{"command":"rm -rf /tmp/cache","description":"Clear cache","dangerouslyDisableSandbox":true}}]}}
+{"type":"user","permissionMode":"bypassPermissions","timestamp":"2026-01-02T11:00:00Z","message":{"role":"user","content":"go on"}}
+{"type":"assistant","timestamp":"2026-01-02T11:00:05Z","message":{"role":"assistant","content":[{"type":"tool_use","name":"Bash","input":{"command":"git push --force"}},{"type":"tool_use","name":"Read","input":{"file_path":"/etc/passwd"}}]}}
+`
+	agent := `{"type":"assistant","timestamp":"2026-01-02T10:30:00Z","message":{"role":"assistant","content":[{"type":"tool_use","name":"Bash","input":{"command":"curl example.com","dangerouslyDisableSandbox":true}}]}}
+`
+	if err := os.WriteFile(filepath.Join(dir, "s1.jsonl"), []byte(mainLog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "s1", "subagents", "agent-a1.jsonl"), []byte(agent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	SetProfiles([]Profile{{Name: "default", Dir: root}})
+	t.Cleanup(func() { SetProfiles(nil) })
+
+	got, err := Audit(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct{ command, reason, session string }{
+		{"rm -rf /tmp/cache", AuditSandboxDisabled, "s1"},
+		{"curl example.com", AuditSandboxDisabled, "agent-a1"},
+		{"git push --force", AuditBypassPermissions, "s1"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Command != w.command || got[i].Reason != w.reason || got[i].SessionID != w.session || got[i].Project != "api" {
+			t.Errorf("entry %d = %+v, want %+v in project api", i, got[i], w)
+		}
+	}
+	if got[0].Description != "Clear cache" {
+		t.Errorf("description = %q", got[0].Description)
+	}
+
+	recent, _ := Audit(time.Date(2026, 1, 2, 10, 45, 0, 0, time.UTC))
+	if len(recent) != 1 || recent[0].Command != "git push --force" {
+		t.Errorf("Audit(since) = %+v, want only the later command", recent)
+	}
+}
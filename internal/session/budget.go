@@ -0,0 +1,44 @@
+package session
+
+// Budget caps the tokens and estimated cost one session, or all sessions in
+// a day, may use. Zero fields are unlimited.
+type Budget struct {
+	SessionTokens int
+	SessionCost   float64
+	DailyTokens   int
+	DailyCost     float64
+}
+
+// IsZero reports whether no limit is set.
+func (b Budget) IsZero() bool {
+	return b == Budget{}
+}
+
+var budget Budget
+
+// SetBudget sets the budget sessions are checked against; sessions over it
+// are flagged OverBudget.
+func SetBudget(b Budget) {
+	budget = b
+}
+
+// CurrentBudget returns the budget set with SetBudget.
+func CurrentBudget() Budget {
+	return budget
+}
+
+// overSessionBudget reports whether a session's usage exceeds the session
+// budget.
+func overSessionBudget(tokens int, cost float64) bool {
+	return exceeds(tokens, cost, budget.SessionTokens, budget.SessionCost)
+}
+
+// OverDailyBudget reports whether usage, normally since local midnight,
+// exceeds the daily budget.
+func OverDailyBudget(u *UsageStats) bool {
+	return u != nil && exceeds(u.TotalTokens, u.Cost, budget.DailyTokens, budget.DailyCost)
+}
+
+func exceeds(tokens int, cost float64, maxTokens int, maxCost float64) bool {
+	return (maxTokens > 0 && tokens > maxTokens) || (maxCost > 0 && cost > maxCost)
+}
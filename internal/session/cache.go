@@ -1,6 +1,8 @@
 package session
 
 import (
+	"maps"
+	"os"
 	"sync"
 	"time"
 )
@@ -14,8 +16,13 @@ import (
 // Three caches, all package-level so the speedup is transparent to callers:
 //
 //  1. parseCache      — parsed log contents keyed by (path, modTime, size).
-//     Skips the full-file re-parse when a log is unchanged.
-//  2. processScanCache — the `ps`/`lsof` running-process scan, TTL-cached.
+//     Skips the full-file re-parse when a log is unchanged. The opening
+//     lines of dormant logs are kept alongside it in headCache, each
+//     project's sessions-index.json in indexCache, and the message uuids
+//     ChainHistory links history logs by in uuidCache.
+//  2. processScanCache — the `ps`/`lsof` running-process scan, TTL-cached;
+//     between scans each process's cwd and open logs are reused, so only
+//     new processes are inspected.
 //  3. resultCache      — the whole Discover() result, TTL-cached, so bursts of
 //     concurrent callers within one tick collapse to a single scan.
 //
@@ -66,6 +73,18 @@ func cachedParseLogFile(logFile string, modTime time.Time, size int64, keep int)
 	return pl, nil
 }
 
+// peekParseCache returns the cached parse of logFile if it is current, without
+// parsing on a miss.
+func peekParseCache(logFile string, modTime time.Time, size int64) (parsedLog, bool) {
+	parseCacheMu.Lock()
+	defer parseCacheMu.Unlock()
+	c, ok := parseCache[logFile]
+	if !ok || c.size != size || !c.modTime.Equal(modTime) {
+		return parsedLog{}, false
+	}
+	return c.log, true
+}
+
 // pruneParseCache drops cached parses for log files not in liveFiles. Without it
 // the cache would grow unbounded over a long-running server's lifetime, as every
 // session's log path lingers forever after the session ends or its file is
@@ -79,6 +98,152 @@ func pruneParseCache(liveFiles map[string]struct{}) {
 			delete(parseCache, path)
 		}
 	}
+	for path := range headCache {
+		if _, ok := liveFiles[path]; !ok {
+			delete(headCache, path)
+		}
+	}
+	for path := range lastKnown {
+		if _, ok := liveFiles[path]; !ok {
+			delete(lastKnown, path)
+		}
+	}
+}
+
+// lastKnown is each log's session as Discover last returned it, what a log
+// or project that stops answering is listed as (see unreadableSession).
+// Unlike the result cache it survives InvalidateCaches. It is guarded by
+// parseCacheMu and pruned with parseCache.
+var lastKnown = map[string]Session{}
+
+// cachedHead is the opening of a dormant log (see readLogHead). Dormant logs
+// rarely change, so it is read once rather than on every refresh.
+type cachedHead struct {
+	modTime    time.Time
+	size       int64
+	cwd        string
+	entrypoint string
+}
+
+// headCache is guarded by parseCacheMu and pruned with parseCache.
+var headCache = map[string]cachedHead{}
+
+// cachedLogHead returns readLogHead(logFile), reusing the last read while the
+// file's (modTime, size) is unchanged.
+func cachedLogHead(logFile string, modTime time.Time, size int64) (cwd, entrypoint string) {
+	parseCacheMu.Lock()
+	if c, ok := headCache[logFile]; ok && c.size == size && c.modTime.Equal(modTime) {
+		parseCacheMu.Unlock()
+		return c.cwd, c.entrypoint
+	}
+	parseCacheMu.Unlock()
+
+	cwd, entrypoint = readLogHead(logFile)
+
+	parseCacheMu.Lock()
+	headCache[logFile] = cachedHead{modTime: modTime, size: size, cwd: cwd, entrypoint: entrypoint}
+	parseCacheMu.Unlock()
+	return cwd, entrypoint
+}
+
+// cachedIndex is a project directory's sessions-index.json as last read.
+type cachedIndex struct {
+	modTime time.Time
+	size    int64
+	paths   map[string]string // session ID → project path
+	first   string            // the first project path listed
+}
+
+// indexCache is guarded by parseCacheMu. It holds one entry per project
+// directory, so it needs no pruning.
+var indexCache = map[string]cachedIndex{}
+
+// cachedSessionIndex returns the project paths recorded in indexFile,
+// re-reading it only when its (modTime, size) changed. A missing or
+// unreadable index gives the zero cachedIndex.
+func cachedSessionIndex(indexFile string) cachedIndex {
+	info, err := os.Stat(indexFile)
+	if err != nil {
+		return cachedIndex{}
+	}
+	parseCacheMu.Lock()
+	if c, ok := indexCache[indexFile]; ok && c.size == info.Size() && c.modTime.Equal(info.ModTime()) {
+		parseCacheMu.Unlock()
+		return c
+	}
+	parseCacheMu.Unlock()
+
+	c := cachedIndex{modTime: info.ModTime(), size: info.Size(), paths: map[string]string{}}
+	entries, _ := parseSessionIndex(indexFile)
+	for _, e := range entries {
+		if e.ProjectPath == "" {
+			continue
+		}
+		if e.SessionID != "" {
+			c.paths[e.SessionID] = e.ProjectPath
+		}
+		if c.first == "" {
+			c.first = e.ProjectPath
+		}
+	}
+
+	parseCacheMu.Lock()
+	indexCache[indexFile] = c
+	parseCacheMu.Unlock()
+	return c
+}
+
+// cachedUUIDs is the message uuids of a history log (see logUUIDs).
+type cachedUUIDs struct {
+	modTime time.Time
+	size    int64
+	ids     []string
+	used    time.Time
+}
+
+// uuidCache is guarded by parseCacheMu. History logs aren't in Discover's
+// working set, so it is pruned by age instead (see pruneUUIDCache).
+var uuidCache = map[string]cachedUUIDs{}
+
+// uuidCacheIdle is how long a log's uuids stay cached without being asked
+// for, past any history view's refresh interval.
+const uuidCacheIdle = 10 * time.Minute
+
+// cachedLogUUIDs returns logUUIDs(logFile), re-reading the log only when its
+// (modTime, size) changed. ChainHistory runs on every refresh of the history
+// view, and would otherwise read each log in the window in full every time.
+func cachedLogUUIDs(logFile string) []string {
+	info, err := os.Stat(logFile)
+	if err != nil {
+		return nil
+	}
+	now := time.Now()
+	parseCacheMu.Lock()
+	if c, ok := uuidCache[logFile]; ok && c.size == info.Size() && c.modTime.Equal(info.ModTime()) {
+		c.used = now
+		uuidCache[logFile] = c
+		parseCacheMu.Unlock()
+		return c.ids
+	}
+	parseCacheMu.Unlock()
+
+	ids := logUUIDs(logFile)
+
+	parseCacheMu.Lock()
+	uuidCache[logFile] = cachedUUIDs{modTime: info.ModTime(), size: info.Size(), ids: ids, used: now}
+	parseCacheMu.Unlock()
+	return ids
+}
+
+// pruneUUIDCache drops the uuids of logs not asked for within uuidCacheIdle.
+func pruneUUIDCache() {
+	parseCacheMu.Lock()
+	defer parseCacheMu.Unlock()
+	for path, c := range uuidCache {
+		if time.Since(c.used) > uuidCacheIdle {
+			delete(uuidCache, path)
+		}
+	}
 }
 
 // --- 2. Process-scan cache ---------------------------------------------------
@@ -86,12 +251,22 @@ func pruneParseCache(liveFiles map[string]struct{}) {
 var (
 	processScanMu   sync.Mutex
 	processScanAt   time.Time
-	processScanDirs map[string][]int
+	processScanDirs map[string][]runningProcess
+
+	// processFiles is the last scan's result per PID and processFilesScans
+	// how many scans have reused it. Guarded by processScanMu.
+	processFiles      map[int]runningProcess
+	processFilesScans int
 )
 
+// processFilesRescan is how many process scans may reuse a process's cwd and
+// open logs before every process is inspected again. The cwd doesn't change,
+// but a process opens a new log after /clear.
+var processFilesRescan = 15
+
 // cachedRunningClaudeDirs wraps getRunningClaudeDirs with a short TTL so the
 // expensive `ps`/`lsof` subprocess spawns don't run on every refresh.
-func cachedRunningClaudeDirs() map[string][]int {
+func cachedRunningClaudeDirs() map[string][]runningProcess {
 	processScanMu.Lock()
 	defer processScanMu.Unlock()
 
@@ -104,6 +279,32 @@ func cachedRunningClaudeDirs() map[string][]int {
 	return processScanDirs
 }
 
+// cachedProcessFiles wraps getProcessFiles so that only PIDs not seen in the
+// previous scan are inspected (an lsof or /proc walk), except every
+// processFilesRescan scans, when all are. PIDs that are gone are forgotten.
+// The caller holds processScanMu.
+func cachedProcessFiles(pids []int) map[int]runningProcess {
+	processFilesScans++
+	if processFiles == nil || processFilesScans >= processFilesRescan {
+		processFiles = getProcessFiles(pids)
+		processFilesScans = 0
+		return processFiles
+	}
+
+	next := make(map[int]runningProcess, len(pids))
+	var unknown []int
+	for _, pid := range pids {
+		if p, ok := processFiles[pid]; ok {
+			next[pid] = p
+		} else {
+			unknown = append(unknown, pid)
+		}
+	}
+	maps.Copy(next, getProcessFiles(unknown))
+	processFiles = next
+	return next
+}
+
 // --- 3. Discover result cache ------------------------------------------------
 
 var (
@@ -123,10 +324,32 @@ func cachedResult() ([]Session, bool) {
 	return nil, false
 }
 
-// storeResult memoizes a fresh Discover() result.
+// storeResult memoizes a fresh Discover() result, and records each session
+// in lastKnown.
 func storeResult(sessions []Session) {
 	resultMu.Lock()
 	result = sessions
 	resultAt = time.Now()
 	resultMu.Unlock()
+
+	parseCacheMu.Lock()
+	for _, s := range sessions {
+		lastKnown[s.LogFile] = s
+	}
+	parseCacheMu.Unlock()
+}
+
+// InvalidateCaches drops the cached Discover() result and running-process
+// scan, so the next Discover() sees a project or session created since, and
+// the Claude process that created it. Parsed logs stay cached; they are
+// keyed by modification time already. So do the last known sessions, which
+// logs that stop answering are listed as.
+func InvalidateCaches() {
+	resultMu.Lock()
+	result = nil
+	resultMu.Unlock()
+
+	processScanMu.Lock()
+	processScanDirs = nil
+	processScanMu.Unlock()
 }
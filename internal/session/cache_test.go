@@ -1,8 +1,12 @@
 package session
 
 import (
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 )
@@ -133,6 +137,174 @@ func TestParseLogFile_ExtractsAllFields(t *testing.T) {
 	}
 }
 
+// Test: model switches are found across the whole file, even before the kept
+// entries, and subagent and synthetic replies don't count as switches.
+func TestParseLogFile_ModelSwitches(t *testing.T) {
+	log := `{"type":"assistant","timestamp":"2026-01-02T10:00:00Z","message":{"role":"assistant","model":"claude-opus-4-6","content":[{"type":"text","text":"a"}]}}
+{"type":"assistant","isSidechain":true,"timestamp":"2026-01-02T10:01:00Z","message":{"role":"assistant","model":"claude-haiku-4-5","content":[{"type":"text","text":"b"}]}}
+{"type":"assistant","timestamp":"2026-01-02T10:02:00Z","message":{"role":"assistant","model":"<synthetic>","content":[{"type":"text","text":"c"}]}}
+{"type":"assistant","timestamp":"2026-01-02T10:03:00Z","message":{"role":"assistant","model":"claude-sonnet-4-5","content":[{"type":"text","text":"d"}]}}
+{"type":"user","timestamp":"2026-01-02T10:04:00Z","message":{"role":"user","content":"e"}}
+{"type":"user","timestamp":"2026-01-02T10:05:00Z","message":{"role":"user","content":"f"}}
+`
+	path, _, _ := writeLog(t, t.TempDir(), "s.jsonl", log)
+	pl, err := parseLogFile(path, 2)
+	if err != nil {
+		t.Fatalf("parseLogFile: %v", err)
+	}
+	want := []ModelSwitch{{From: "claude-opus-4-6", To: "claude-sonnet-4-5", At: time.Date(2026, 1, 2, 10, 3, 0, 0, time.UTC)}}
+	if len(pl.modelSwitches) != 1 || pl.modelSwitches[0] != want[0] {
+		t.Errorf("modelSwitches = %+v, want %+v", pl.modelSwitches, want)
+	}
+}
+
+// Test: API errors are counted since the last successful reply, so a session
+// that recovered stops reporting them.
+func TestParseLogFile_APIErrors(t *testing.T) {
+	errLine := `{"type":"system","subtype":"api_error","timestamp":"2026-01-02T10:00:00Z","error":{"status":529,"error":{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}},"retryAttempt":%d,"maxRetries":10}` + "\n"
+	reply := `{"type":"assistant","timestamp":"2026-01-02T10:01:00Z","message":{"role":"assistant","model":"claude-opus-4-6","content":[{"type":"text","text":"ok"}]}}` + "\n"
+
+	dir := t.TempDir()
+	path, _, _ := writeLog(t, dir, "stuck.jsonl", fmt.Sprintf(errLine, 1)+reply+fmt.Sprintf(errLine, 1)+fmt.Sprintf(errLine, 2))
+	pl, err := parseLogFile(path, 100)
+	if err != nil {
+		t.Fatalf("parseLogFile: %v", err)
+	}
+	if pl.apiErrors != 2 || pl.lastAPIError != "529 overloaded_error: Overloaded (retry 2/10)" {
+		t.Errorf("apiErrors = %d, lastAPIError = %q", pl.apiErrors, pl.lastAPIError)
+	}
+
+	path, _, _ = writeLog(t, dir, "recovered.jsonl", fmt.Sprintf(errLine, 1)+reply)
+	if pl, _ = parseLogFile(path, 100); pl.apiErrors != 0 || pl.lastAPIError != "" {
+		t.Errorf("after a good reply: apiErrors = %d, lastAPIError = %q", pl.apiErrors, pl.lastAPIError)
+	}
+}
+
+// Test: the permission mode is the latest one logged by the main
+// conversation; subagent entries don't change it.
+func TestParseLogFile_PermissionMode(t *testing.T) {
+	log := `{"type":"user","permissionMode":"default","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"a"}}
+{"type":"user","permissionMode":"bypassPermissions","timestamp":"2026-01-02T10:01:00Z","message":{"role":"user","content":"b"}}
+{"type":"user","isSidechain":true,"permissionMode":"default","timestamp":"2026-01-02T10:02:00Z","message":{"role":"user","content":"c"}}
+{"type":"assistant","timestamp":"2026-01-02T10:03:00Z","message":{"role":"assistant","content":[{"type":"text","text":"d"}]}}
+`
+	path, _, _ := writeLog(t, t.TempDir(), "s.jsonl", log)
+	pl, err := parseLogFile(path, 1)
+	if err != nil {
+		t.Fatalf("parseLogFile: %v", err)
+	}
+	if pl.permissionMode != PermissionBypass {
+		t.Errorf("permissionMode = %q, want %q", pl.permissionMode, PermissionBypass)
+	}
+}
+
+// Test: token use and cost cover the whole file, subagents included, and
+// are checked against the session budget.
+func TestParseLogFile_Tokens(t *testing.T) {
+	log := `{"type":"assistant","timestamp":"2026-01-02T10:00:00Z","message":{"role":"assistant","model":"claude-sonnet-4-5","content":[{"type":"text","text":"a"}],"usage":{"input_tokens":1000,"output_tokens":2000,"cache_creation_input_tokens":0,"cache_read_input_tokens":100000}}}
+{"type":"assistant","isSidechain":true,"timestamp":"2026-01-02T10:01:00Z","message":{"role":"assistant","model":"claude-haiku-4-5","content":[{"type":"text","text":"b"}],"usage":{"input_tokens":500,"output_tokens":500}}}
+{"type":"user","timestamp":"2026-01-02T10:02:00Z","message":{"role":"user","content":"c"}}
+`
+	path, _, _ := writeLog(t, t.TempDir(), "s.jsonl", log)
+	pl, err := parseLogFile(path, 1)
+	if err != nil {
+		t.Fatalf("parseLogFile: %v", err)
+	}
+	// Sonnet: 1000×$3 + 2000×$15 + 100000×$0.30; Haiku: 500×$1 + 500×$5.
+	if pl.tokens != 104000 || math.Abs(pl.cost-0.066) > 1e-9 {
+		t.Errorf("tokens = %d, cost = %v", pl.tokens, pl.cost)
+	}
+
+	defer SetBudget(Budget{})
+	SetBudget(Budget{SessionCost: 0.05})
+	if !overSessionBudget(pl.tokens, pl.cost) {
+		t.Error("$0.066 should exceed a $0.05 session budget")
+	}
+	SetBudget(Budget{SessionTokens: 200000, DailyTokens: 1})
+	if overSessionBudget(pl.tokens, pl.cost) {
+		t.Error("104k tokens should be within a 200k session budget")
+	}
+}
+
+// Test: output tokens count each streamed reply once, and the last reply's
+// output is its final size.
+func TestParseLogFile_OutputTokens(t *testing.T) {
+	log := `{"type":"assistant","timestamp":"2026-01-02T10:00:00Z","message":{"id":"m1","role":"assistant","content":[{"type":"text","text":"a"}],"usage":{"input_tokens":10,"output_tokens":300}}}
+{"type":"assistant","isSidechain":true,"timestamp":"2026-01-02T10:00:30Z","message":{"id":"s1","role":"assistant","content":[{"type":"text","text":"sub"}],"usage":{"input_tokens":10,"output_tokens":50}}}
+{"type":"assistant","timestamp":"2026-01-02T10:01:00Z","message":{"id":"m2","role":"assistant","content":[{"type":"text","text":"b"}],"usage":{"input_tokens":10,"output_tokens":40}}}
+{"type":"assistant","timestamp":"2026-01-02T10:01:05Z","message":{"id":"m2","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Write","input":{}}],"usage":{"input_tokens":10,"output_tokens":1200}}}
+`
+	path, _, _ := writeLog(t, t.TempDir(), "s.jsonl", log)
+	pl, err := parseLogFile(path, 1)
+	if err != nil {
+		t.Fatalf("parseLogFile: %v", err)
+	}
+	if pl.outputTokens != 1550 || pl.lastOutput != 1200 {
+		t.Errorf("outputTokens = %d, lastOutput = %d; want 1550, 1200", pl.outputTokens, pl.lastOutput)
+	}
+}
+
+// Test: a dormant log (no process, untouched for a while) is listed from its
+// stat and first lines without a full parse; once parsed it keeps its details.
+func TestParseSession_Dormant(t *testing.T) {
+	path, _, _ := writeLog(t, t.TempDir(), "s.jsonl", sampleLog)
+	old := time.Now().Add(-2 * dormantAge)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := parseSession("-Users-me-Projects-org-proj", path, nil)
+	if err != nil {
+		t.Fatalf("parseSession: %v", err)
+	}
+	if _, parsed := peekParseCache(path, old, int64(len(sampleLog))); parsed {
+		t.Error("dormant log was parsed")
+	}
+	if s.Status != StatusInactive || s.Project != "org/proj" || !s.LastActivity.Equal(old) || s.LastMessage != "" {
+		t.Errorf("dormant session = %+v", s)
+	}
+
+	info, _ := os.Stat(path)
+	if _, err := cachedParseLogFile(path, info.ModTime(), info.Size(), recentEntries); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ = parseSession("-Users-me-Projects-org-proj", path, nil); s.LastMessage != "On it" {
+		t.Errorf("already parsed log lost its details: %+v", s)
+	}
+}
+
+// Test: a `claude -p` log is marked headless, whether it is parsed or dormant.
+func TestParseSession_Headless(t *testing.T) {
+	dir := t.TempDir()
+	headless := strings.ReplaceAll(sampleLog, `"type":"user",`, `"type":"user","entrypoint":"sdk-cli",`)
+	interactive := strings.ReplaceAll(sampleLog, `"type":"user",`, `"type":"user","entrypoint":"cli",`)
+	old := time.Now().Add(-2 * dormantAge)
+	for _, tc := range []struct {
+		name, log string
+		dormant   bool
+		want      bool
+	}{
+		{"headless", headless, false, true},
+		{"headless-dormant", headless, true, true},
+		{"interactive", interactive, false, false},
+		{"interactive-dormant", interactive, true, false},
+	} {
+		path, _, _ := writeLog(t, dir, tc.name+".jsonl", tc.log)
+		if tc.dormant {
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+		s, err := parseSession("-Users-me-Projects-org-proj", path, nil)
+		if err != nil {
+			t.Fatalf("%s: parseSession: %v", tc.name, err)
+		}
+		if s.Headless != tc.want {
+			t.Errorf("%s: Headless = %v, want %v", tc.name, s.Headless, tc.want)
+		}
+	}
+}
+
 // Test (c): on a cache HIT (file unchanged), status is still recomputed against
 // the current wall clock, so a session flips Working -> Waiting as time passes
 // without the file changing. Exercised through applyParsedLog, which parseSession
@@ -163,3 +335,57 @@ func TestApplyParsedLog_StatusRecomputedOverTime(t *testing.T) {
 		t.Errorf("stale: status = %q, want %q", stale.Status, StatusWaiting)
 	}
 }
+
+// Test: processes seen in the previous scan aren't inspected again until the
+// periodic rescan, and processes that are gone are forgotten.
+func TestCachedProcessFiles(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("inspects the test process through /proc")
+	}
+	t.Cleanup(func() { processFiles, processFilesScans = nil, 0 })
+	me := os.Getpid()
+	wd, _ := os.Getwd()
+
+	processFiles = map[int]runningProcess{me: {PID: me, CWD: "/cached"}, 1: {PID: 1, CWD: "/gone"}}
+	processFilesScans = 0
+	got := cachedProcessFiles([]int{me})
+	if got[me].CWD != "/cached" {
+		t.Errorf("known process was inspected again: %+v", got[me])
+	}
+	if _, ok := got[1]; ok {
+		t.Error("process that is gone was kept")
+	}
+
+	processFilesScans = processFilesRescan - 1
+	if got = cachedProcessFiles([]int{me}); got[me].CWD != wd {
+		t.Errorf("rescan cwd = %q, want %q", got[me].CWD, wd)
+	}
+}
+
+// Test: the sessions index gives a session its own entry's path only, and is
+// re-read once it changes.
+func TestIndexProjectPath(t *testing.T) {
+	dir := t.TempDir()
+	index := filepath.Join(dir, "sessions-index.json")
+	write := func(entries string, mtime time.Time) {
+		os.WriteFile(index, []byte(`{"version":1,"entries":[`+entries+`]}`), 0o644)
+		os.Chtimes(index, mtime, mtime)
+	}
+	t0 := time.Now().Add(-time.Hour)
+	write(`{"sessionId":"s1","projectPath":"/home/me/api"}`, t0)
+
+	if got := indexProjectPath(dir, "s1"); got != "/home/me/api" {
+		t.Errorf("s1 path = %q", got)
+	}
+	if got := indexProjectPath(dir, "s2"); got != "" {
+		t.Errorf("session without an entry got %q, want \"\"", got)
+	}
+	if got := ProjectName(dir); got != "api" {
+		t.Errorf("ProjectName = %q", got)
+	}
+
+	write(`{"sessionId":"s1","projectPath":"/home/me/api"},{"sessionId":"s2","projectPath":"/home/me/web"}`, t0.Add(time.Minute))
+	if got := indexProjectPath(dir, "s2"); got != "/home/me/web" {
+		t.Errorf("s2 path after the index changed = %q", got)
+	}
+}
@@ -0,0 +1,160 @@
+package session
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ChainHistory folds resumed sessions into the session they continue. A
+// resumed session is a new log that carries on earlier work: it references
+// (or copies) message uuids from an earlier log, or, failing that, repeats an
+// earlier session's first prompt in the same project. Each chain becomes one
+// item: the newest log's details, the earliest start, the summed message
+// count, a duration counting overlapping spans only once, and Chain set to
+// the number of logs folded in. The result is sorted newest first.
+func ChainHistory(sessions []HistorySession) []HistorySession {
+	if len(sessions) < 2 {
+		return sessions
+	}
+
+	// Oldest first, so every log can only continue one seen before it.
+	order := make([]int, len(sessions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sessions[order[a]].StartTime.Before(sessions[order[b]].StartTime)
+	})
+
+	parent := make([]int, len(sessions))
+	owner := make(map[string]int)      // message uuid -> first log holding it
+	prompts := make(map[[2]string]int) // project+first prompt -> latest log
+	for _, i := range order {
+		parent[i] = -1
+		s := sessions[i]
+
+		ids := cachedLogUUIDs(s.LogFile)
+		for _, id := range ids {
+			if o, ok := owner[id]; ok && o != i && sessions[o].Profile == s.Profile {
+				parent[i] = o
+				break
+			}
+		}
+		key := [2]string{s.Project, s.FirstPrompt}
+		chainable := chainablePrompt(s.FirstPrompt)
+		if parent[i] == -1 && chainable {
+			if o, ok := prompts[key]; ok && sessions[o].Profile == s.Profile {
+				parent[i] = o
+			}
+		}
+
+		for _, id := range ids {
+			if _, ok := owner[id]; !ok {
+				owner[id] = i
+			}
+		}
+		if chainable {
+			prompts[key] = i
+		}
+	}
+
+	// Group members under their root; order keeps each group oldest first.
+	groups := make(map[int][]int)
+	var roots []int
+	for _, i := range order {
+		root := i
+		for parent[root] != -1 {
+			root = parent[root]
+		}
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+
+	pruneUUIDCache()
+
+	chained := make([]HistorySession, 0, len(roots))
+	for _, root := range roots {
+		chained = append(chained, mergeChain(sessions, groups[root]))
+	}
+	sort.SliceStable(chained, func(i, j int) bool {
+		return chained[i].StartTime.After(chained[j].StartTime)
+	})
+	return chained
+}
+
+// chainablePrompt reports whether a first prompt is specific enough to link
+// sessions by: slash commands and command wrappers start many unrelated ones.
+func chainablePrompt(p string) bool {
+	return p != "" && !strings.HasPrefix(p, "/") && !strings.HasPrefix(p, "<")
+}
+
+// mergeChain combines a chain of logs, oldest first, into one item.
+func mergeChain(sessions []HistorySession, members []int) HistorySession {
+	if len(members) == 1 {
+		return sessions[members[0]]
+	}
+
+	merged := sessions[members[len(members)-1]]
+	merged.FirstPrompt = sessions[members[0]].FirstPrompt
+	merged.Chain = len(members)
+	merged.MessageCount = 0
+
+	var spanStart, spanEnd time.Time
+	var total time.Duration
+	for n, i := range members {
+		s := sessions[i]
+		merged.MessageCount += s.MessageCount
+		if s.StartTime.Before(merged.StartTime) {
+			merged.StartTime = s.StartTime
+		}
+		if s.EndTime.After(merged.EndTime) {
+			merged.EndTime = s.EndTime
+		}
+		if merged.GitBranch == "" {
+			merged.GitBranch = s.GitBranch
+		}
+
+		// Members are sorted by start, so overlapping spans merge in one pass.
+		switch {
+		case n == 0:
+			spanStart, spanEnd = s.StartTime, s.EndTime
+		case s.StartTime.After(spanEnd):
+			total += spanEnd.Sub(spanStart)
+			spanStart, spanEnd = s.StartTime, s.EndTime
+		case s.EndTime.After(spanEnd):
+			spanEnd = s.EndTime
+		}
+	}
+	merged.Duration = total + spanEnd.Sub(spanStart)
+	return merged
+}
+
+// logUUIDs returns the message uuids a log holds or points at (its own
+// uuids plus parentUuid/leafUuid references), in file order.
+func logUUIDs(logFile string) []string {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+
+	var ids []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, prefix := range []string{`"leafUuid":"`, `"parentUuid":"`, `"uuid":"`} {
+			if id := extractStringField(line, prefix); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestChainHistory(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	first := write("a.jsonl", `{"type":"user","uuid":"u1","parentUuid":null}
+{"type":"assistant","uuid":"u2","parentUuid":"u1"}
+`)
+	resumed := write("b.jsonl", `{"type":"summary","leafUuid":"u2"}
+{"type":"user","uuid":"u3","parentUuid":"u2"}
+`)
+	other := write("c.jsonl", `{"type":"user","uuid":"u9","parentUuid":null}
+`)
+	samePrompt := write("d.jsonl", `{"type":"user","uuid":"u10","parentUuid":null}
+`)
+
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	sessions := []HistorySession{
+		{Project: "org/api", LogFile: resumed, StartTime: base.Add(2 * time.Hour), EndTime: base.Add(3 * time.Hour), Duration: time.Hour, MessageCount: 4, FirstPrompt: "continue"},
+		{Project: "org/web", LogFile: other, StartTime: base.Add(time.Hour), EndTime: base.Add(90 * time.Minute), Duration: 30 * time.Minute, MessageCount: 1, FirstPrompt: "fix css"},
+		{Project: "org/api", LogFile: first, StartTime: base, EndTime: base.Add(time.Hour), Duration: time.Hour, MessageCount: 3, FirstPrompt: "add endpoint"},
+		{Project: "org/web", LogFile: samePrompt, StartTime: base.Add(4 * time.Hour), EndTime: base.Add(5 * time.Hour), Duration: time.Hour, MessageCount: 2, FirstPrompt: "fix css"},
+	}
+
+	got := ChainHistory(sessions)
+	if len(got) != 2 {
+		t.Fatalf("got %d items, want 2: %+v", len(got), got)
+	}
+
+	web, api := got[0], got[1]
+	if web.Project != "org/web" || web.Chain != 2 || web.LogFile != samePrompt {
+		t.Errorf("first prompt chain = %+v", web)
+	}
+	if web.Duration != 90*time.Minute {
+		t.Errorf("web duration = %v, want 1h30m", web.Duration)
+	}
+
+	if api.Chain != 2 || api.LogFile != resumed || api.FirstPrompt != "add endpoint" {
+		t.Errorf("uuid chain = %+v", api)
+	}
+	if !api.StartTime.Equal(base) || api.MessageCount != 7 || api.Duration != 2*time.Hour {
+		t.Errorf("api start/msgs/duration = %v/%d/%v", api.StartTime, api.MessageCount, api.Duration)
+	}
+}
+
+func TestMergeChainOverlap(t *testing.T) {
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	sessions := []HistorySession{
+		{StartTime: base, EndTime: base.Add(time.Hour)},
+		{StartTime: base.Add(30 * time.Minute), EndTime: base.Add(2 * time.Hour)},
+	}
+	if got := mergeChain(sessions, []int{0, 1}).Duration; got != 2*time.Hour {
+		t.Errorf("Duration = %v, want overlapping spans counted once (2h)", got)
+	}
+}
+
+func TestCachedLogUUIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.jsonl")
+	mtime := time.Now().Add(-time.Hour)
+	write := func(content string, mtime time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, mtime, mtime)
+	}
+	write(`{"type":"user","uuid":"u1"}`+"\n", mtime)
+	if got := cachedLogUUIDs(path); len(got) != 1 || got[0] != "u1" {
+		t.Fatalf("uuids = %v", got)
+	}
+
+	// Same size and mtime: the cached uuids are reused.
+	write(`{"type":"user","uuid":"u2"}`+"\n", mtime)
+	if got := cachedLogUUIDs(path); len(got) != 1 || got[0] != "u1" {
+		t.Errorf("unchanged log re-read: %v", got)
+	}
+
+	write(`{"type":"user","uuid":"u2"}`+"\n", mtime.Add(time.Minute))
+	if got := cachedLogUUIDs(path); len(got) != 1 || got[0] != "u2" {
+		t.Errorf("changed log not re-read: %v", got)
+	}
+}
@@ -0,0 +1,100 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// ConfigFiles is the configuration a session runs with: its Claude profile
+// and the settings and memory (CLAUDE.md) files that apply to it. Useful to
+// tell why one project's sessions behave differently from another's.
+type ConfigFiles struct {
+	ConfigDir string   `json:"config_dir"`         // Claude profile directory the log lives in
+	Settings  []string `json:"settings,omitempty"` // settings files that exist, lowest precedence first
+	Memory    []string `json:"memory,omitempty"`   // CLAUDE.md files in the order Claude loads them
+}
+
+// managedSettingsPath is where an administrator's settings live; they
+// override all others.
+func managedSettingsPath() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "/Library/Application Support/ClaudeCode/managed-settings.json"
+	case "windows":
+		return `C:\ProgramData\ClaudeCode\managed-settings.json`
+	}
+	return "/etc/claude-code/managed-settings.json"
+}
+
+// sessionConfigFiles lists the configuration of the session logged in
+// logFile, run in cwd. Claude reads the CLAUDE.md files of the profile and
+// of cwd and its parents at startup, and those of subdirectories (nested,
+// from the log) once it works in them.
+func sessionConfigFiles(logFile, cwd string, nested []string) ConfigFiles {
+	// <config dir>/projects/<project>/<session>.jsonl
+	c := ConfigFiles{ConfigDir: filepath.Dir(filepath.Dir(filepath.Dir(logFile)))}
+
+	settings := []string{filepath.Join(c.ConfigDir, "settings.json")}
+	memory := []string{filepath.Join(c.ConfigDir, "CLAUDE.md")}
+	if cwd != "" {
+		settings = append(settings,
+			filepath.Join(cwd, ".claude", "settings.json"),
+			filepath.Join(cwd, ".claude", "settings.local.json"))
+
+		var dirs []string
+		for dir := filepath.Clean(cwd); ; dir = filepath.Dir(dir) {
+			dirs = append(dirs, dir)
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+		for i := len(dirs) - 1; i >= 0; i-- {
+			memory = append(memory,
+				filepath.Join(dirs[i], "CLAUDE.md"),
+				filepath.Join(dirs[i], ".claude", "CLAUDE.md"),
+				filepath.Join(dirs[i], "CLAUDE.local.md"))
+		}
+	}
+	settings = append(settings, managedSettingsPath())
+
+	for _, path := range settings {
+		if fileExists(path) {
+			c.Settings = append(c.Settings, path)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, path := range append(memory, nested...) {
+		if !seen[path] && (fileExists(path) || slices.Contains(nested, path)) {
+			seen[path] = true
+			c.Memory = append(c.Memory, path)
+		}
+	}
+	return c
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// nestedMemoryPath returns the CLAUDE.md file a log line records Claude
+// loading on demand (a nested_memory attachment), or "".
+func nestedMemoryPath(line string) string {
+	if !strings.Contains(line, `"nested_memory"`) {
+		return ""
+	}
+	var entry struct {
+		Attachment *struct {
+			Type string `json:"type"`
+			Path string `json:"path"`
+		} `json:"attachment"`
+	}
+	if json.Unmarshal([]byte(line), &entry) != nil || entry.Attachment == nil || entry.Attachment.Type != "nested_memory" {
+		return ""
+	}
+	return entry.Attachment.Path
+}
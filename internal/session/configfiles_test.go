@@ -0,0 +1,57 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSessionConfigFiles(t *testing.T) {
+	root := t.TempDir()
+	configDir := filepath.Join(root, ".claude-work")
+	repo := filepath.Join(root, "repo")
+	cwd := filepath.Join(repo, "app")
+	for _, f := range []string{
+		filepath.Join(configDir, "settings.json"),
+		filepath.Join(configDir, "CLAUDE.md"),
+		filepath.Join(repo, "CLAUDE.md"),
+		filepath.Join(cwd, ".claude", "settings.local.json"),
+		filepath.Join(cwd, "CLAUDE.local.md"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(f), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logFile := filepath.Join(configDir, "projects", "-repo-app", "s.jsonl")
+	nested := filepath.Join(cwd, "src", "CLAUDE.md") // deleted since, still listed
+
+	c := sessionConfigFiles(logFile, cwd, []string{nested})
+	if c.ConfigDir != configDir {
+		t.Errorf("ConfigDir = %q, want %q", c.ConfigDir, configDir)
+	}
+	wantSettings := []string{filepath.Join(configDir, "settings.json"), filepath.Join(cwd, ".claude", "settings.local.json")}
+	if fileExists(managedSettingsPath()) {
+		wantSettings = append(wantSettings, managedSettingsPath())
+	}
+	if !slices.Equal(c.Settings, wantSettings) {
+		t.Errorf("Settings = %q, want %q", c.Settings, wantSettings)
+	}
+	wantMemory := []string{filepath.Join(configDir, "CLAUDE.md"), filepath.Join(repo, "CLAUDE.md"), filepath.Join(cwd, "CLAUDE.local.md"), nested}
+	if !slices.Equal(c.Memory, wantMemory) {
+		t.Errorf("Memory = %q, want %q", c.Memory, wantMemory)
+	}
+}
+
+func TestNestedMemoryPath(t *testing.T) {
+	line := `{"type":"attachment","attachment":{"type":"nested_memory","path":"/repo/src/CLAUDE.md","content":{"path":"/repo/src/CLAUDE.md","type":"Project"}}}`
+	if got := nestedMemoryPath(line); got != "/repo/src/CLAUDE.md" {
+		t.Errorf("nestedMemoryPath = %q", got)
+	}
+	if got := nestedMemoryPath(`{"type":"user","message":{"role":"user","content":"what is nested_memory?"}}`); got != "" {
+		t.Errorf("nestedMemoryPath of a prompt = %q, want nothing", got)
+	}
+}
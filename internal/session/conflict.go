@@ -0,0 +1,48 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// markConflicts sets Conflicts on every active session that shares its
+// working tree with other active sessions. Parallel sessions editing the same
+// checkout overwrite each other's changes; separate git worktrees of one
+// repository are fine and don't count.
+func markConflicts(sessions []Session) {
+	byTree := make(map[string][]int)
+	for i := range sessions {
+		s := &sessions[i]
+		s.Conflicts = 0
+		if s.Status == StatusInactive || s.CWD == "" {
+			continue
+		}
+		tree := worktreeRoot(s.CWD)
+		byTree[tree] = append(byTree[tree], i)
+	}
+	for _, idx := range byTree {
+		if len(idx) < 2 {
+			continue
+		}
+		for _, i := range idx {
+			sessions[i].Conflicts = len(idx) - 1
+		}
+	}
+}
+
+// worktreeRoot returns the top of the git working tree containing dir: the
+// nearest directory holding a .git entry (a directory for the main checkout,
+// a file for a linked worktree). Directories outside git are their own root.
+func worktreeRoot(dir string) string {
+	dir = filepath.Clean(dir)
+	for d := dir; ; {
+		if _, err := os.Lstat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
@@ -0,0 +1,38 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarkConflicts(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "app")
+	worktree := filepath.Join(root, "app-feature")
+	for _, d := range []string{filepath.Join(repo, ".git"), filepath.Join(repo, "web"), worktree} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A linked worktree has a .git file pointing back at the main repository.
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+repo+"/.git/worktrees/feature\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := []Session{
+		{Project: "app", CWD: repo, Status: StatusWorking},
+		{Project: "app/web", CWD: filepath.Join(repo, "web"), Status: StatusWaiting},
+		{Project: "app-feature", CWD: worktree, Status: StatusWorking},
+		{Project: "app (old)", CWD: repo, Status: StatusInactive},
+		{Project: "other", CWD: filepath.Join(root, "other"), Status: StatusWorking},
+	}
+	markConflicts(sessions)
+
+	want := []int{1, 1, 0, 0, 0}
+	for i, s := range sessions {
+		if s.Conflicts != want[i] {
+			t.Errorf("%s: Conflicts = %d, want %d", s.Project, s.Conflicts, want[i])
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+)
+
+// detailKeep is how many messages and tool calls a Detail keeps.
+const detailKeep = 50
+
+// Detail is the recent history of one session for the focused view of
+// `csm watch <project>`: what was said, which tools ran, how the context
+// grew and how long turns took. Subagent entries are left out.
+type Detail struct {
+	Messages []DetailMessage // latest prompts and replies, oldest first
+	Tools    []ToolCall      // latest tool calls, oldest first
+	Context  []float64       // context usage after each reply, oldest first
+	Turns    []time.Duration // length of each finished turn, oldest first
+	Config   ConfigFiles     // profile, settings and CLAUDE.md files in effect
+	Window   ContextBreakdown
+	// Pending is the change of the latest tool call when it is a file edit
+	// without a result yet: what a Needs Input session asks to write.
+	Pending *PendingChange
+}
+
+// ContextBreakdown splits the context of the latest reply by how its tokens
+// reached the model, which says how much of it is cached.
+type ContextBreakdown struct {
+	Fresh        int `json:"fresh"`         // input and output tokens not served from or written to the cache
+	CacheCreated int `json:"cache_created"` // written to the prompt cache
+	CacheRead    int `json:"cache_read"`    // read from the prompt cache
+	Limit        int `json:"limit"`         // the context limit the bar is measured against
+}
+
+// Total is the context size.
+func (b ContextBreakdown) Total() int {
+	return b.Fresh + b.CacheCreated + b.CacheRead
+}
+
+// contextBreakdown splits the usage of a reply from model; the zero value
+// when there is none.
+func contextBreakdown(u *Usage, model string) ContextBreakdown {
+	if u == nil {
+		return ContextBreakdown{}
+	}
+	return ContextBreakdown{
+		Fresh:        u.InputTokens + u.OutputTokens,
+		CacheCreated: u.CacheCreationInputTokens,
+		CacheRead:    u.CacheReadInputTokens,
+		Limit:        contextLimit(model),
+	}
+}
+
+// DetailMessage is a user prompt or the text of an assistant reply.
+type DetailMessage struct {
+	Time time.Time
+	Role string // "user" or "assistant"
+	Text string // whitespace collapsed to single spaces
+}
+
+// ToolCall is one tool use, summarized like a pending request.
+type ToolCall struct {
+	Time time.Time
+	Call string // e.g. "Bash: go test ./..."
+	Done bool   // its result has been logged
+}
+
+// LoadDetail reads a whole session log into a Detail.
+func LoadDetail(logFile string) (Detail, error) {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return Detail{}, err
+	}
+	defer file.Close()
+
+	var d Detail
+	pending := make(map[string]int) // tool_use id → index in d.Tools
+	var lastPrompt time.Time
+	lastReply := ""
+	cwd := ""
+	var nested []string
+	var lastUsage *Usage
+	lastModel := ""
+	var lastCall *ContentItem // the latest tool call of the current turn
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var entry LogEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.IsSidechain {
+			continue
+		}
+		if cwd == "" {
+			cwd = entry.CWD
+		}
+		if path := nestedMemoryPath(scanner.Text()); path != "" {
+			nested = append(nested, path)
+		}
+		if resetsContext(entry) {
+			lastUsage, lastModel = nil, ""
+		}
+		switch entry.Type {
+		case "user":
+			if entry.Message == nil {
+				continue
+			}
+			if isUserPrompt(&entry) {
+				lastCall = nil
+				lastPrompt = entry.Timestamp
+				d.Messages = append(d.Messages, DetailMessage{Time: entry.Timestamp, Role: "user", Text: contentText(entry.Message.Content)})
+			}
+			for _, c := range entry.Message.Content {
+				if i, ok := pending[c.ToolUseID]; ok && c.Type == "tool_result" {
+					d.Tools[i].Done = true
+					delete(pending, c.ToolUseID)
+				}
+			}
+		case "assistant":
+			if entry.Message == nil {
+				continue
+			}
+			if text := contentText(entry.Message.Content); text != "" {
+				d.Messages = append(d.Messages, DetailMessage{Time: entry.Timestamp, Role: "assistant", Text: text})
+			}
+			for _, c := range entry.Message.Content {
+				if c.Type != "tool_use" {
+					continue
+				}
+				if c.ID != "" {
+					pending[c.ID] = len(d.Tools)
+				}
+				lastCall = &c
+				d.Tools = append(d.Tools, ToolCall{Time: entry.Timestamp, Call: describeToolUse(c)})
+			}
+			// A streamed reply is logged as several entries of one message
+			// with the same usage; count it once.
+			if pct, _, _ := extractContextUsage([]LogEntry{entry}); pct > 0 {
+				lastUsage, lastModel = entry.Message.Usage, entry.Message.Model
+				if entry.Message.ID != "" && entry.Message.ID == lastReply && len(d.Context) > 0 {
+					d.Context[len(d.Context)-1] = pct
+				} else {
+					d.Context = append(d.Context, pct)
+				}
+				lastReply = entry.Message.ID
+			}
+		case "system":
+			if entry.Subtype == "turn_duration" {
+				d.Turns = append(d.Turns, turnDuration(entry, lastPrompt))
+			}
+		}
+	}
+
+	if lastCall != nil && !d.Tools[len(d.Tools)-1].Done {
+		d.Pending = pendingChange(*lastCall)
+	}
+	d.Config = sessionConfigFiles(logFile, cwd, nested)
+	d.Window = contextBreakdown(lastUsage, lastModel)
+
+	if len(d.Messages) > detailKeep {
+		d.Messages = d.Messages[len(d.Messages)-detailKeep:]
+	}
+	if len(d.Tools) > detailKeep {
+		d.Tools = d.Tools[len(d.Tools)-detailKeep:]
+	}
+	return d, scanner.Err()
+}
+
+// contentText joins the text items of a message, whitespace collapsed.
+func contentText(items []ContentItem) string {
+	var parts []string
+	for _, c := range items {
+		if c.Type == "text" {
+			if t := strings.Join(strings.Fields(c.Text), " "); t != "" {
+				parts = append(parts, t)
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,52 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLoadDetail(t *testing.T) {
+	log := `{"type":"user","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"run the   migration"}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:05Z","message":{"id":"m1","role":"assistant","model":"claude-sonnet-4-5","content":[{"type":"text","text":"Running it."}],"usage":{"input_tokens":10,"cache_read_input_tokens":19990,"output_tokens":0}}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:06Z","message":{"id":"m1","role":"assistant","model":"claude-sonnet-4-5","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"make migrate"}}],"usage":{"input_tokens":10,"cache_read_input_tokens":19990,"output_tokens":0}}}
+{"type":"assistant","isSidechain":true,"timestamp":"2026-01-02T10:00:07Z","message":{"role":"assistant","content":[{"type":"text","text":"subagent chatter"}]}}
+{"type":"user","timestamp":"2026-01-02T10:01:00Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+{"type":"assistant","timestamp":"2026-01-02T10:01:05Z","message":{"id":"m2","role":"assistant","model":"claude-sonnet-4-5","content":[{"type":"tool_use","id":"t2","name":"Read","input":{"file_path":"/repo/schema.sql"}}],"usage":{"input_tokens":10,"cache_read_input_tokens":39990,"output_tokens":0}}}
+{"type":"system","subtype":"turn_duration","timestamp":"2026-01-02T10:01:30Z","durationMs":90000}
+`
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	if err := os.WriteFile(path, []byte(log), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := LoadDetail(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var texts []string
+	for _, m := range d.Messages {
+		texts = append(texts, m.Role+": "+m.Text)
+	}
+	if want := []string{"user: run the migration", "assistant: Running it."}; !slices.Equal(texts, want) {
+		t.Errorf("messages = %q, want %q", texts, want)
+	}
+	want := []ToolCall{
+		{Time: time.Date(2026, 1, 2, 10, 0, 6, 0, time.UTC), Call: "Bash: make migrate", Done: true},
+		{Time: time.Date(2026, 1, 2, 10, 1, 5, 0, time.UTC), Call: "Read: /repo/schema.sql"},
+	}
+	if !slices.Equal(d.Tools, want) {
+		t.Errorf("tools = %+v, want %+v", d.Tools, want)
+	}
+	if !slices.Equal(d.Context, []float64{10, 20}) {
+		t.Errorf("context = %v, want one point per reply", d.Context)
+	}
+	if !slices.Equal(d.Turns, []time.Duration{90 * time.Second}) {
+		t.Errorf("turns = %v", d.Turns)
+	}
+	if want := (ContextBreakdown{Fresh: 10, CacheRead: 39990, Limit: 200000}); d.Window != want {
+		t.Errorf("window = %+v, want %+v", d.Window, want)
+	}
+}
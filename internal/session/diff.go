@@ -0,0 +1,245 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PendingChange is the file change an Edit, MultiEdit or Write call asks
+// permission for, as a unified diff against the file on disk.
+type PendingChange struct {
+	Tool     string
+	FilePath string
+	Diff     []DiffLine
+	Added    int
+	Removed  int
+	NewFile  bool // the file does not exist yet
+}
+
+// DiffLine is one line of a unified diff: a hunk header ("@@ -1,3 +1,4 @@")
+// or a context, removed or added line.
+type DiffLine struct {
+	Op   byte // '@', ' ', '-' or '+'
+	Text string
+}
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// maxDiffFile bounds the size of the file read to place an edit in context.
+const maxDiffFile = 4 << 20
+
+// maxDiffCells bounds the work of the line diff; changes beyond it are shown
+// as the old lines removed and the new ones added.
+const maxDiffCells = 4_000_000
+
+// editInput is the input of Edit, Write and MultiEdit tool calls.
+type editInput struct {
+	FilePath string      `json:"file_path"`
+	Content  *string     `json:"content"` // Write
+	Edits    []editInput `json:"edits"`   // MultiEdit
+	OldStr   string      `json:"old_string"`
+	NewStr   string      `json:"new_string"`
+	All      bool        `json:"replace_all"`
+}
+
+// pendingChange returns the change a file-editing tool call would make, or
+// nil for other calls. The edit is applied to the file as it is now so the
+// diff has line numbers and context; when its old text is no longer there,
+// the diff is of the old and new text alone.
+func pendingChange(c ContentItem) *PendingChange {
+	if c.Name != "Edit" && c.Name != "MultiEdit" && c.Name != "Write" {
+		return nil
+	}
+	var in editInput
+	if json.Unmarshal(c.Input, &in) != nil || in.FilePath == "" {
+		return nil
+	}
+	current, err := readForDiff(in.FilePath)
+	pc := &PendingChange{Tool: c.Name, FilePath: in.FilePath, NewFile: os.IsNotExist(err)}
+
+	var before, after string
+	switch {
+	case c.Name == "Write" && in.Content != nil:
+		before, after = current, *in.Content
+	case c.Name == "Write":
+		return nil
+	default:
+		edits := in.Edits
+		if c.Name == "Edit" {
+			edits = []editInput{in}
+		}
+		before, after = current, current
+		for _, e := range edits {
+			if err != nil || e.OldStr == "" || !strings.Contains(after, e.OldStr) {
+				before, after = joinEdits(edits)
+				break
+			}
+			if e.All {
+				after = strings.ReplaceAll(after, e.OldStr, e.NewStr)
+			} else {
+				after = strings.Replace(after, e.OldStr, e.NewStr, 1)
+			}
+		}
+	}
+	pc.Diff = unifiedDiff(splitLines(before), splitLines(after), diffContext)
+	for _, l := range pc.Diff {
+		switch l.Op {
+		case '+':
+			pc.Added++
+		case '-':
+			pc.Removed++
+		}
+	}
+	return pc
+}
+
+// readForDiff reads a file for diffing, refusing ones too large to show.
+func readForDiff(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > maxDiffFile {
+		return "", fmt.Errorf("%s is too large to diff", path)
+	}
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
+
+// joinEdits puts the old and new text of edits one after another, for when
+// they can't be placed in the file.
+func joinEdits(edits []editInput) (before, after string) {
+	var b, a []string
+	for _, e := range edits {
+		b = append(b, e.OldStr)
+		a = append(a, e.NewStr)
+	}
+	return strings.Join(b, "\n"), strings.Join(a, "\n")
+}
+
+// splitLines splits text into lines without their newlines.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// unifiedDiff diffs two texts by line, with context unchanged lines around
+// each hunk.
+func unifiedDiff(a, b []string, context int) []DiffLine {
+	ops := diffLines(a, b)
+
+	var out []DiffLine
+	for i := 0; i < len(ops); {
+		if ops[i].Op == ' ' {
+			i++
+			continue
+		}
+		// A hunk runs from context lines before this change to context
+		// lines after the last change closer than 2*context to the next.
+		start := max(i-context, 0)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].Op != ' ' {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
+			}
+		}
+		end = min(end+context, len(ops))
+
+		aStart, bStart := 1, 1
+		for _, op := range ops[:start] {
+			if op.Op != '+' {
+				aStart++
+			}
+			if op.Op != '-' {
+				bStart++
+			}
+		}
+		aLen, bLen := 0, 0
+		for _, op := range ops[start:end] {
+			if op.Op != '+' {
+				aLen++
+			}
+			if op.Op != '-' {
+				bLen++
+			}
+		}
+		if aLen == 0 {
+			aStart--
+		}
+		if bLen == 0 {
+			bStart--
+		}
+		out = append(out, DiffLine{Op: '@', Text: fmt.Sprintf("@@ -%d,%d +%d,%d @@", aStart, aLen, bStart, bLen)})
+		out = append(out, ops[start:end]...)
+		i = end
+	}
+	return out
+}
+
+// diffLines returns the lines of a and b as unchanged, removed and added,
+// from a longest common subsequence of the lines between their common prefix
+// and suffix.
+func diffLines(a, b []string) []DiffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []DiffLine
+	for _, l := range a[:prefix] {
+		ops = append(ops, DiffLine{' ', l})
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(ma)*len(mb) > maxDiffCells {
+		for _, l := range ma {
+			ops = append(ops, DiffLine{'-', l})
+		}
+		for _, l := range mb {
+			ops = append(ops, DiffLine{'+', l})
+		}
+	} else {
+		// lcs[i][j] is the length of the LCS of ma[i:] and mb[j:].
+		lcs := make([][]int, len(ma)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(mb)+1)
+		}
+		for i := len(ma) - 1; i >= 0; i-- {
+			for j := len(mb) - 1; j >= 0; j-- {
+				if ma[i] == mb[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(ma) || j < len(mb) {
+			switch {
+			case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+				ops = append(ops, DiffLine{' ', ma[i]})
+				i, j = i+1, j+1
+			case j < len(mb) && (i == len(ma) || lcs[i][j+1] > lcs[i+1][j]):
+				ops = append(ops, DiffLine{'+', mb[j]})
+				j++
+			default:
+				ops = append(ops, DiffLine{'-', ma[i]})
+				i++
+			}
+		}
+	}
+	for _, l := range a[len(a)-suffix:] {
+		ops = append(ops, DiffLine{' ', l})
+	}
+	return ops
+}
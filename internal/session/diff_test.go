@@ -0,0 +1,105 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// diffText renders a diff one line per DiffLine, as `diff -u` would.
+func diffText(lines []DiffLine) string {
+	var b strings.Builder
+	for _, l := range lines {
+		if l.Op == '@' {
+			b.WriteString(l.Text + "\n")
+		} else {
+			b.WriteString(string(l.Op) + l.Text + "\n")
+		}
+	}
+	return b.String()
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := splitLines("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n")
+	b := splitLines("1\n2\n3\nfour\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n17\n")
+	want := `@@ -1,7 +1,7 @@
+ 1
+ 2
+ 3
+-4
++four
+ 5
+ 6
+ 7
+@@ -14,3 +14,4 @@
+ 14
+ 15
+ 16
++17
+`
+	if got := diffText(unifiedDiff(a, b, 3)); got != want {
+		t.Errorf("unifiedDiff =\n%s\nwant\n%s", got, want)
+	}
+
+	// Changes closer than twice the context share a hunk.
+	b = splitLines("1\n2\n3\nfour\n5\n6\n7\n8\nnine\n10\n11\n12\n13\n14\n15\n16\n")
+	if got := diffText(unifiedDiff(a, b, 3)); strings.Count(got, "@@ -") != 1 || !strings.HasPrefix(got, "@@ -1,12 +1,12 @@\n") {
+		t.Errorf("nearby changes not merged:\n%s", got)
+	}
+
+	if got := diffText(unifiedDiff(nil, []string{"package main"}, 3)); got != "@@ -0,0 +1,1 @@\n+package main\n" {
+		t.Errorf("new file diff = %q", got)
+	}
+}
+
+func TestPendingChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	os.WriteFile(path, []byte("package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n"), 0o644)
+	call := func(name string, input map[string]any) ContentItem {
+		raw, _ := json.Marshal(input)
+		return ContentItem{Type: "tool_use", Name: name, Input: raw}
+	}
+
+	pc := pendingChange(call("Edit", map[string]any{"file_path": path, "old_string": `println("hi")`, "new_string": "println(\"hello\")\n\tos.Exit(0)"}))
+	want := "@@ -1,5 +1,6 @@\n package main\n \n func main() {\n-\tprintln(\"hi\")\n+\tprintln(\"hello\")\n+\tos.Exit(0)\n }\n"
+	if pc == nil || diffText(pc.Diff) != want || pc.Added != 2 || pc.Removed != 1 || pc.NewFile {
+		t.Fatalf("Edit change = %+v\n%s", pc, diffText(pc.Diff))
+	}
+
+	// Text that is no longer in the file is diffed on its own.
+	pc = pendingChange(call("MultiEdit", map[string]any{"file_path": path, "edits": []map[string]any{{"old_string": "gone", "new_string": "back"}}}))
+	if pc == nil || diffText(pc.Diff) != "@@ -1,1 +1,1 @@\n-gone\n+back\n" {
+		t.Errorf("MultiEdit fallback = %+v", pc)
+	}
+
+	pc = pendingChange(call("Write", map[string]any{"file_path": filepath.Join(dir, "new.go"), "content": "package new\n"}))
+	if pc == nil || !pc.NewFile || pc.Added != 1 || pc.Removed != 0 {
+		t.Errorf("Write change = %+v", pc)
+	}
+	if pendingChange(call("Bash", map[string]any{"command": "ls"})) != nil {
+		t.Error("Bash has a file change")
+	}
+
+	// LoadDetail shows the change while the edit has no result.
+	log := `{"type":"user","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"greet louder"}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:05Z","message":{"id":"m1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":` + jsonString(path) + `,"old_string":"\"hi\"","new_string":"\"HI\""}}]}}
+`
+	logPath := filepath.Join(dir, "s.jsonl")
+	os.WriteFile(logPath, []byte(log), 0o644)
+	if d, err := LoadDetail(logPath); err != nil || d.Pending == nil || d.Pending.Added != 1 {
+		t.Errorf("LoadDetail pending = %+v, %v", d.Pending, err)
+	}
+	os.WriteFile(logPath, []byte(log+`{"type":"user","timestamp":"2026-01-02T10:00:09Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+`), 0o644)
+	if d, _ := LoadDetail(logPath); d.Pending != nil {
+		t.Errorf("answered edit still pending: %+v", d.Pending)
+	}
+}
+
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
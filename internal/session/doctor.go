@@ -0,0 +1,248 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// CheckResult is the outcome of an environment check.
+type CheckResult int
+
+const (
+	CheckPass CheckResult = iota
+	CheckWarn             // works, but something is off
+	CheckFail             // csm can't work properly until it is fixed
+)
+
+// Check is one environment check run by `csm doctor`.
+type Check struct {
+	Name   string
+	Result CheckResult
+	Detail string   // what was found
+	Hint   string   // how to fix it; empty when passing
+	Extra  []string // further lines of detail, e.g. per-file results
+}
+
+// maxClockSkew is how far log timestamps may run ahead of the file times
+// before the clocks are reported as skewed.
+const maxClockSkew = time.Minute
+
+// recentLogCount is how many of the most recently written logs the log
+// format and clock checks look at.
+const recentLogCount = 5
+
+// CheckEnvironment checks what session discovery depends on: the projects
+// directory of each profile, its session indexes, whether recent logs parse,
+// whether log timestamps agree with the system clock, and the ps/lsof tools
+// used to find running Claude processes. With strict, every log is parsed
+// and each one with unparsable lines is listed with samples.
+func CheckEnvironment(strict bool) []Check {
+	var checks []Check
+	profiles, err := Profiles()
+	if err != nil {
+		return []Check{{Name: "Claude config directory", Result: CheckFail, Detail: err.Error(), Hint: "Set HOME, or pass --claude-dir"}}
+	}
+	for _, p := range profiles {
+		projects := filepath.Join(p.Dir, "projects")
+		label := ""
+		if len(profiles) > 1 {
+			label = " (" + p.Name + ")"
+		}
+		check := checkProjectsDir(projects)
+		check.Name += label
+		checks = append(checks, check)
+		if check.Result == CheckFail {
+			continue
+		}
+		logs := projectLogs(projects)
+		index := checkSessionIndexes(projects)
+		index.Name += label
+		format := checkLogFormat(logs, strict)
+		format.Name += label
+		skew := checkClockSkew(logs)
+		skew.Name += label
+		checks = append(checks, index, format, skew)
+	}
+	return append(checks, checkProcessTools()...)
+}
+
+// checkProjectsDir checks that the projects directory exists and is readable.
+func checkProjectsDir(dir string) Check {
+	c := Check{Name: "Projects directory"}
+	entries, err := os.ReadDir(dir)
+	switch {
+	case os.IsNotExist(err):
+		c.Result, c.Detail = CheckFail, dir+" does not exist"
+		c.Hint = "Start Claude Code once so it creates it, or point csm at your config with --claude-dir or CLAUDE_CONFIG_DIR"
+	case err != nil:
+		c.Result, c.Detail = CheckFail, err.Error()
+		c.Hint = "Run csm as the user that runs Claude Code, or fix the directory's permissions"
+	default:
+		n := 0
+		for _, e := range entries {
+			if e.IsDir() {
+				n++
+			}
+		}
+		c.Detail = fmt.Sprintf("%s (%d projects)", dir, n)
+	}
+	return c
+}
+
+// checkSessionIndexes checks that every sessions-index.json parses.
+func checkSessionIndexes(projects string) Check {
+	c := Check{Name: "Session indexes"}
+	files, _ := filepath.Glob(filepath.Join(projects, "*", "sessions-index.json"))
+	var bad []string
+	var firstErr error
+	for _, f := range files {
+		if _, err := parseSessionIndex(f); err != nil {
+			bad = append(bad, f)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if len(bad) == 0 {
+		c.Detail = fmt.Sprintf("%d parsed", len(files))
+		if len(files) == 0 {
+			c.Detail = "none"
+		}
+		return c
+	}
+	c.Result = CheckWarn
+	c.Detail = fmt.Sprintf("%d of %d unreadable, e.g. %s: %v", len(bad), len(files), bad[0], firstErr)
+	c.Hint = "History falls back to scanning the logs of those projects; Claude Code rewrites the index as sessions change"
+	return c
+}
+
+// checkClockSkew compares the last timestamp in the most recently written
+// logs with the files' modification times. Claude stamps entries as it
+// writes them, so a timestamp well after the write means the clock that
+// stamped it (or the one that set the file time) is off, which skews every
+// status that depends on how long ago something happened.
+func checkClockSkew(logs []logInfo) Check {
+	c := Check{Name: "Clock"}
+	var worst time.Duration
+	checked := 0
+	for _, l := range logs[:min(len(logs), recentLogCount)] {
+		_, _, end, _, _, _, _ := QuickSessionStats(l.path)
+		if end.IsZero() {
+			continue
+		}
+		checked++
+		worst = max(worst, end.Sub(l.modTime))
+	}
+	switch {
+	case checked == 0:
+		c.Detail = "no logs to compare"
+	case worst > maxClockSkew:
+		c.Result = CheckWarn
+		c.Detail = fmt.Sprintf("log timestamps run up to %s ahead of the system clock", worst.Round(time.Second))
+		c.Hint = "Sync the system clock (NTP); statuses are judged by how old the last log entry is"
+	default:
+		c.Detail = fmt.Sprintf("log timestamps match the system clock (%d recent logs)", checked)
+	}
+	return c
+}
+
+// logInfo is a session log and when it was last written.
+type logInfo struct {
+	path    string
+	modTime time.Time
+}
+
+// projectLogs lists the session logs in a projects directory, most recently
+// written first.
+func projectLogs(projects string) []logInfo {
+	paths, _ := filepath.Glob(filepath.Join(projects, "*", "*.jsonl"))
+	var logs []logInfo
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			logs = append(logs, logInfo{p, info.ModTime()})
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].modTime.After(logs[j].modTime) })
+	return logs
+}
+
+// checkLogFormat parses the most recent logs, or with strict all of them, and
+// warns about lines that don't parse: a sign Claude changed its log format
+// and statuses may be wrong.
+func checkLogFormat(logs []logInfo, strict bool) Check {
+	c := Check{Name: "Log format"}
+	if !strict {
+		logs = logs[:min(len(logs), recentLogCount)]
+	}
+	lines, skipped, bad := 0, 0, 0
+	for _, l := range logs {
+		stats, err := CheckLogFile(l.path)
+		if err != nil {
+			continue
+		}
+		lines += stats.Lines
+		if stats.Skipped == 0 {
+			continue
+		}
+		skipped += stats.Skipped
+		bad++
+		if strict || bad == 1 {
+			c.Extra = append(c.Extra, fmt.Sprintf("%s: %d of %d lines", stats.File, stats.Skipped, stats.Lines))
+			for _, s := range stats.Samples {
+				c.Extra = append(c.Extra, "  "+s)
+			}
+		}
+	}
+	if skipped == 0 {
+		c.Detail = fmt.Sprintf("%d lines in %d logs parsed", lines, len(logs))
+		return c
+	}
+	c.Result = CheckWarn
+	c.Detail = fmt.Sprintf("%d of %d lines in %d of %d logs failed to parse", skipped, lines, bad, len(logs))
+	c.Hint = "Claude may have changed its log format; update csm, or report it with the samples above"
+	if !strict {
+		c.Hint += " (csm doctor --strict checks every log)"
+	}
+	return c
+}
+
+// checkProcessTools checks for the tools that find running Claude processes
+// and their working directories. A missing tool with a working fallback
+// (/proc for ps on Linux, fuser for lsof on macOS) is only a warning.
+func checkProcessTools() []Check {
+	ps := toolCheck("ps", "Install procps (ps); without it no session is detected as running")
+	if runtime.GOOS == "linux" {
+		c := Check{Name: "/proc", Detail: "process working directories readable"}
+		if _, err := os.Readlink("/proc/self/cwd"); err != nil {
+			c.Result, c.Detail = CheckFail, err.Error()
+			c.Hint = "Mount /proc; csm reads /proc/<pid>/cwd to match processes to projects"
+		} else if ps.Result == CheckFail {
+			ps.Result, ps.Hint = CheckWarn, "csm lists processes from /proc instead; install procps (ps) for the process tree in the web dashboard"
+		}
+		return []Check{ps, c}
+	}
+	lsof := toolCheck("lsof", "Install lsof; csm uses it to match Claude processes to projects")
+	if lsof.Result == CheckFail {
+		if _, err := exec.LookPath("fuser"); err == nil {
+			lsof.Result, lsof.Hint = CheckWarn, "csm falls back to fuser, which only finds sessions while they hold their log open; install lsof"
+		}
+	}
+	return []Check{ps, lsof}
+}
+
+// toolCheck checks that a command is on PATH.
+func toolCheck(name, hint string) Check {
+	c := Check{Name: name}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		c.Result, c.Detail, c.Hint = CheckFail, "not found in PATH", hint
+		return c
+	}
+	c.Detail = path
+	return c
+}
@@ -0,0 +1,53 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckEnvironmentChecks(t *testing.T) {
+	projects := filepath.Join(t.TempDir(), "projects")
+	if c := checkProjectsDir(projects); c.Result != CheckFail || c.Hint == "" {
+		t.Errorf("missing dir: %+v, want a failure with a hint", c)
+	}
+
+	dir := filepath.Join(projects, "-home-me-app")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if c := checkProjectsDir(projects); c.Result != CheckPass || !strings.Contains(c.Detail, "1 projects") {
+		t.Errorf("existing dir: %+v", c)
+	}
+
+	os.WriteFile(filepath.Join(dir, "sessions-index.json"), []byte(`{"version":1,"entries":[`), 0o644)
+	if c := checkSessionIndexes(projects); c.Result != CheckWarn {
+		t.Errorf("truncated index: %+v, want a warning", c)
+	}
+
+	// The last entry is stamped ten minutes after the file was written.
+	log := filepath.Join(dir, "s1.jsonl")
+	written := time.Now().Add(-time.Hour)
+	stamp := written.Add(10 * time.Minute).UTC().Format(time.RFC3339Nano)
+	os.WriteFile(log, []byte(`{"type":"user","timestamp":"`+stamp+`","message":{"role":"user","content":"hi"}}`+"\n"), 0o644)
+	os.Chtimes(log, written, written)
+	if c := checkClockSkew(projectLogs(projects)); c.Result != CheckWarn || !strings.Contains(c.Detail, "10m0s ahead") {
+		t.Errorf("skewed clock: %+v, want a 10m warning", c)
+	}
+
+	os.Chtimes(log, written.Add(10*time.Minute), written.Add(10*time.Minute))
+	if c := checkClockSkew(projectLogs(projects)); c.Result != CheckPass {
+		t.Errorf("synced clock: %+v, want a pass", c)
+	}
+
+	// A line from a newer log format that no longer decodes.
+	f, _ := os.OpenFile(log, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString(`{"type":"assistant","timestamp":12}` + "\n")
+	f.Close()
+	c := checkLogFormat(projectLogs(projects), true)
+	if c.Result != CheckWarn || c.Detail != "1 of 2 lines in 1 of 1 logs failed to parse" || len(c.Extra) != 2 {
+		t.Errorf("log format: %+v", c)
+	}
+}
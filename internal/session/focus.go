@@ -0,0 +1,120 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Focus brings the terminal window or tab that hosts a session's Claude
+// process to the front. tmux panes are selected when csm runs inside tmux;
+// otherwise the session's terminal is driven through its own interface:
+// AppleScript for iTerm and Terminal.app, `wezterm cli` and `kitty @` remote
+// control for WezTerm and Kitty.
+func Focus(s Session) error {
+	if s.Host != "" {
+		return fmt.Errorf("%s runs on %s", s.Project, s.Host)
+	}
+	if s.GhostPID == 0 {
+		return fmt.Errorf("%s has no running Claude process", s.Project)
+	}
+	if s.Tmux != nil && os.Getenv("TMUX") != "" {
+		return JumpToTmuxPane(*s.Tmux)
+	}
+	argv, err := focusCommand(s.Origin.App, readProcessEnv(s.GhostPID), processTTY(s.GhostPID))
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.Project, err)
+	}
+	if out, err := exec.Command(argv[0], argv[1:]...).CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %s", argv[0], msg)
+		}
+		return fmt.Errorf("%s: %w", argv[0], err)
+	}
+	return nil
+}
+
+// focusCommand returns the command that focuses the window of a process
+// launched from app, given the process environment and its controlling
+// terminal (e.g. "/dev/ttys003").
+func focusCommand(app string, env map[string]string, tty string) ([]string, error) {
+	switch app {
+	case "iterm":
+		if tty == "" {
+			return nil, fmt.Errorf("terminal device unknown")
+		}
+		return []string{"osascript", "-e", fmt.Sprintf(itermFocusScript, tty)}, nil
+	case "terminal", "apple-terminal":
+		if tty == "" {
+			return nil, fmt.Errorf("terminal device unknown")
+		}
+		return []string{"osascript", "-e", fmt.Sprintf(terminalFocusScript, tty)}, nil
+	case "wezterm":
+		if env["WEZTERM_PANE"] == "" {
+			return nil, fmt.Errorf("WezTerm pane unknown")
+		}
+		return []string{"wezterm", "cli", "activate-pane", "--pane-id", env["WEZTERM_PANE"]}, nil
+	case "kitty":
+		if env["KITTY_WINDOW_ID"] == "" {
+			return nil, fmt.Errorf("Kitty window unknown")
+		}
+		argv := []string{"kitty", "@"}
+		if to := env["KITTY_LISTEN_ON"]; to != "" {
+			argv = append(argv, "--to", to)
+		}
+		return append(argv, "focus-window", "--match", "id:"+env["KITTY_WINDOW_ID"]), nil
+	case "":
+		return nil, fmt.Errorf("terminal unknown")
+	}
+	return nil, fmt.Errorf("focusing %s windows is not supported", newOrigin(app).Display)
+}
+
+// AppleScript that selects the iTerm session (and its tab and window) whose
+// tty is %[1]q.
+const itermFocusScript = `tell application "iTerm2"
+	repeat with w in windows
+		repeat with t in tabs of w
+			repeat with s in sessions of t
+				if tty of s is %[1]q then
+					select w
+					select t
+					select s
+					activate
+					return
+				end if
+			end repeat
+		end repeat
+	end repeat
+end tell
+error "no iTerm session on %[1]s"`
+
+// AppleScript that selects the Terminal.app tab whose tty is %[1]q and
+// raises its window.
+const terminalFocusScript = `tell application "Terminal"
+	repeat with w in windows
+		repeat with t in tabs of w
+			if tty of t is %[1]q then
+				set selected tab of w to t
+				set index of w to 1
+				activate
+				return
+			end if
+		end repeat
+	end repeat
+end tell
+error "no Terminal tab on %[1]s"`
+
+// processTTY returns the controlling terminal of pid as a device path, or ""
+// if it has none.
+func processTTY(pid int) string {
+	out, err := exec.Command("ps", "-o", "tty=", "-p", fmt.Sprint(pid)).Output()
+	if err != nil {
+		return ""
+	}
+	tty := strings.TrimSpace(string(out))
+	if tty == "" || tty == "?" || tty == "??" {
+		return ""
+	}
+	return "/dev/" + strings.TrimPrefix(tty, "/dev/")
+}
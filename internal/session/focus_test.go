@@ -0,0 +1,61 @@
+package session
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFocusCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		app     string
+		env     map[string]string
+		tty     string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "wezterm",
+			app:  "wezterm",
+			env:  map[string]string{"WEZTERM_PANE": "7"},
+			want: []string{"wezterm", "cli", "activate-pane", "--pane-id", "7"},
+		},
+		{
+			name: "kitty with socket",
+			app:  "kitty",
+			env:  map[string]string{"KITTY_WINDOW_ID": "3", "KITTY_LISTEN_ON": "unix:/tmp/kitty"},
+			want: []string{"kitty", "@", "--to", "unix:/tmp/kitty", "focus-window", "--match", "id:3"},
+		},
+		{name: "kitty without window id", app: "kitty", env: map[string]string{}, wantErr: "Kitty window unknown"},
+		{name: "iterm without tty", app: "iterm", wantErr: "terminal device unknown"},
+		{name: "unsupported", app: "alacritty", wantErr: "focusing Alacritty windows is not supported"},
+		{name: "unknown origin", wantErr: "terminal unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := focusCommand(tt.app, tt.env, tt.tty)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("focusCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// AppleScript terminals are matched by tty.
+	got, err := focusCommand("iterm", nil, "/dev/ttys003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0] != "osascript" || !strings.Contains(got[2], `if tty of s is "/dev/ttys003" then`) {
+		t.Errorf("iterm script = %q", got)
+	}
+}
@@ -21,6 +21,8 @@ type HistorySession struct {
 	FirstPrompt  string        `json:"first_prompt"`
 	LastMessage  string        `json:"last_message,omitempty"`
 	LogFile      string        `json:"log_file"`
+	Profile      string        `json:"profile,omitempty"`
+	Chain        int           `json:"chain,omitempty"` // Logs folded in by ChainHistory (resumed sessions), 0 if not chained
 }
 
 // SessionIndex represents the structure of sessions-index.json
@@ -46,7 +48,7 @@ type IndexEntry struct {
 // It merges sessions from sessions-index.json files with a direct scan
 // of .jsonl files so that projects without an index are also included.
 func DiscoverHistory(days int) ([]HistorySession, error) {
-	projectsDir, err := ClaudeProjectsDir()
+	profiles, err := Profiles()
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +58,31 @@ func DiscoverHistory(days int) ([]HistorySession, error) {
 	seen := make(map[string]bool)
 	var sessions []HistorySession
 
+	for _, profile := range profiles {
+		found, err := profileHistory(profile, cutoff, seen)
+		if err != nil {
+			if len(profiles) == 1 {
+				return nil, err
+			}
+			continue
+		}
+		sessions = append(sessions, found...)
+	}
+
+	// Sort by start time descending (newest first)
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartTime.After(sessions[j].StartTime)
+	})
+
+	return sessions, nil
+}
+
+// profileHistory collects sessions started after cutoff from one profile's
+// projects directory, skipping log files already in seen.
+func profileHistory(profile Profile, cutoff time.Time, seen map[string]bool) ([]HistorySession, error) {
+	projectsDir := filepath.Join(profile.Dir, "projects")
+	var sessions []HistorySession
+
 	// Phase 1: Collect sessions from sessions-index.json files (richest metadata)
 	pattern := filepath.Join(projectsDir, "*", "sessions-index.json")
 	indexFiles, err := filepath.Glob(pattern)
@@ -106,6 +133,7 @@ func DiscoverHistory(days int) ([]HistorySession, error) {
 				MessageCount: entry.MessageCount,
 				FirstPrompt:  entry.FirstPrompt,
 				LogFile:      entry.FullPath,
+				Profile:      profileTag(profile),
 			})
 			seen[entry.FullPath] = true
 		}
@@ -169,6 +197,9 @@ func DiscoverHistory(days int) ([]HistorySession, error) {
 
 			// Use cwd for accurate project naming when available
 			displayName := projectName
+			if sessionCwd == "" {
+				sessionCwd = indexProjectPath(projectDir, strings.TrimSuffix(f.Name(), ".jsonl"))
+			}
 			if sessionCwd != "" {
 				displayName = extractProjectName(sessionCwd)
 			}
@@ -182,16 +213,12 @@ func DiscoverHistory(days int) ([]HistorySession, error) {
 				Duration:     endTime.Sub(startTime),
 				MessageCount: msgCount,
 				LogFile:      logFile,
+				Profile:      profileTag(profile),
 			})
 			seen[logFile] = true
 		}
 	}
 
-	// Sort by start time descending (newest first)
-	sort.Slice(sessions, func(i, j int) bool {
-		return sessions[i].StartTime.After(sessions[j].StartTime)
-	})
-
 	return sessions, nil
 }
 
@@ -210,6 +237,59 @@ func parseSessionIndex(path string) ([]IndexEntry, error) {
 	return index.Entries, nil
 }
 
+// indexProjectPath returns the real project path a project directory's
+// sessions-index.json records for sessionID, or "" when it has no entry for
+// the session. It is the fallback for logs that don't carry a cwd yet.
+func indexProjectPath(projectDir, sessionID string) string {
+	return cachedSessionIndex(filepath.Join(projectDir, "sessions-index.json")).paths[sessionID]
+}
+
+// ProjectName returns a readable name for a projects/ subdirectory, from the
+// real path in its index or in one of its logs, falling back to decoding the
+// directory name.
+func ProjectName(projectDir string) string {
+	if path := cachedSessionIndex(filepath.Join(projectDir, "sessions-index.json")).first; path != "" {
+		return extractProjectName(path)
+	}
+	logs, _ := filepath.Glob(filepath.Join(projectDir, "*.jsonl"))
+	for _, logFile := range logs {
+		if cwd := headCwd(logFile); cwd != "" {
+			return extractProjectName(cwd)
+		}
+	}
+	return decodeProjectName(filepath.Base(projectDir))
+}
+
+// headCwd returns the first cwd recorded in the opening lines of a log,
+// without reading the rest of a possibly huge file.
+func headCwd(logFile string) string {
+	cwd, _ := readLogHead(logFile)
+	return cwd
+}
+
+// readLogHead returns the first cwd and entrypoint recorded in the opening
+// lines of a log.
+func readLogHead(logFile string) (cwd, entrypoint string) {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return "", ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for n := 0; n < 20 && (cwd == "" || entrypoint == "") && scanner.Scan(); n++ {
+		line := scanner.Text()
+		if cwd == "" {
+			cwd = extractStringField(line, `"cwd":"`)
+		}
+		if entrypoint == "" {
+			entrypoint = extractStringField(line, `"entrypoint":"`)
+		}
+	}
+	return cwd, entrypoint
+}
+
 // extractProjectName extracts a readable project name from a full path
 func extractProjectName(fullPath string) string {
 	// Try well-known directory markers (most specific first)
@@ -220,10 +300,13 @@ func extractProjectName(fullPath string) string {
 		}
 	}
 
-	// Detect /home/<user>/X pattern: skip the home directory prefix
-	if strings.HasPrefix(fullPath, "/home/") {
+	// Detect /home/<user>/X and /Users/<user>/X: skip the home directory prefix
+	for _, home := range []string{"/home/", "/Users/"} {
+		if !strings.HasPrefix(fullPath, home) {
+			continue
+		}
 		// /home/user/repos/myproject -> repos/myproject
-		rest := fullPath[len("/home/"):]
+		rest := fullPath[len(home):]
 		if slashIdx := strings.Index(rest, "/"); slashIdx != -1 {
 			afterUser := rest[slashIdx+1:]
 			if afterUser != "" {
@@ -396,6 +479,7 @@ func extractTimestampFromLine(line string) time.Time {
 // GetDateGroup returns a human-readable date group for a session
 func GetDateGroup(t time.Time) string {
 	now := time.Now()
+	t = t.In(now.Location())
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	sessionDate := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
 
@@ -0,0 +1,130 @@
+package session
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// HookRun is hook activity worth showing on a session: a hook that is still
+// running with nothing logged after it, or one that failed or blocked since
+// the last prompt. Without it a session stuck behind a hook just looks
+// Waiting.
+type HookRun struct {
+	Event   string    `json:"event,omitempty"`   // e.g. "PreToolUse", "Stop"
+	Name    string    `json:"name,omitempty"`    // e.g. "PreToolUse:Bash"
+	Command string    `json:"command,omitempty"` // the hook command, when logged
+	Running bool      `json:"running,omitempty"` // started and nothing logged since
+	Blocked bool      `json:"blocked,omitempty"` // stopped the tool call or the end of the turn
+	Failed  bool      `json:"failed,omitempty"`  // exited with an error or was cancelled
+	Error   string    `json:"error,omitempty"`   // first line of the hook's error or block reason
+	At      time.Time `json:"at"`
+}
+
+// Label names the hook for display: its name, else its event.
+func (h HookRun) Label() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	if h.Event != "" {
+		return h.Event
+	}
+	return "hook"
+}
+
+// hookLine holds the fields of the log entries that record hooks:
+//
+//   - progress entries whose data is a hook_progress, written when a hook starts
+//   - attachments of type hook_blocking_error, hook_non_blocking_error,
+//     hook_error_during_execution, hook_cancelled and hook_success
+//   - system/stop_hook_summary entries, with the errors of the Stop hooks and
+//     whether they prevented the turn from ending
+//
+// It is decoded separately from LogEntry so the progress data and
+// attachments of other entries aren't kept in memory.
+type hookLine struct {
+	Data *struct {
+		Type      string `json:"type"`
+		HookEvent string `json:"hookEvent"`
+		HookName  string `json:"hookName"`
+		Command   string `json:"command"`
+	} `json:"data"`
+	Attachment *struct {
+		Type          string `json:"type"`
+		HookEvent     string `json:"hookEvent"`
+		HookName      string `json:"hookName"`
+		Command       string `json:"command"`
+		Stderr        string `json:"stderr"`
+		BlockingError *struct {
+			BlockingError string `json:"blockingError"`
+			Command       string `json:"command"`
+		} `json:"blockingError"`
+	} `json:"attachment"`
+	HookErrors            []string `json:"hookErrors"`
+	PreventedContinuation bool     `json:"preventedContinuation"`
+	StopReason            string   `json:"stopReason"`
+}
+
+// hookActivity reports whether a log line records a hook and, if so, what it
+// says about the session: a *HookRun for a hook running, failing or blocking,
+// nil for a hook that succeeded.
+func hookActivity(line string, entry LogEntry) (*HookRun, bool) {
+	if !strings.Contains(line, "hook") {
+		return nil, false
+	}
+	var hl hookLine
+	if json.Unmarshal([]byte(line), &hl) != nil {
+		return nil, false
+	}
+	h := &HookRun{At: entry.Timestamp}
+	switch {
+	case entry.Type == "hook_progress" || (entry.Type == "progress" && hl.Data != nil && hl.Data.Type == "hook_progress"):
+		if hl.Data != nil {
+			h.Event, h.Name, h.Command = hl.Data.HookEvent, hl.Data.HookName, hl.Data.Command
+		}
+		h.Running = true
+	case entry.Type == "attachment" && hl.Attachment != nil && strings.HasPrefix(hl.Attachment.Type, "hook_"):
+		a := hl.Attachment
+		h.Event, h.Name, h.Command = a.HookEvent, a.HookName, a.Command
+		switch a.Type {
+		case "hook_blocking_error":
+			h.Blocked = true
+			if a.BlockingError != nil {
+				h.Error = hookError(a.BlockingError.BlockingError)
+				if h.Command == "" {
+					h.Command = a.BlockingError.Command
+				}
+			}
+		case "hook_non_blocking_error", "hook_error_during_execution":
+			h.Failed = true
+			h.Error = hookError(a.Stderr)
+		case "hook_cancelled":
+			h.Failed = true
+			h.Error = "cancelled"
+		case "hook_success":
+			return nil, true
+		default:
+			return nil, false
+		}
+	case entry.Type == "system" && entry.Subtype == "stop_hook_summary":
+		h.Event = "Stop"
+		switch {
+		case hl.PreventedContinuation:
+			h.Blocked = true
+			h.Error = hookError(hl.StopReason)
+		case len(hl.HookErrors) > 0:
+			h.Failed = true
+			h.Error = hookError(hl.HookErrors[0])
+		default:
+			return nil, true
+		}
+	default:
+		return nil, false
+	}
+	return h, true
+}
+
+// hookError condenses a hook's error output to its first line.
+func hookError(s string) string {
+	return truncateString(firstLine(s), 200)
+}
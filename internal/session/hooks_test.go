@@ -0,0 +1,57 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLogFileHook(t *testing.T) {
+	const (
+		prompt   = `{"type":"user","timestamp":"2026-06-01T10:00:00Z","message":{"role":"user","content":"run the tests"}}`
+		toolUse  = `{"type":"assistant","timestamp":"2026-06-01T10:00:05Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"make test"}}]}}`
+		progress = `{"type":"progress","timestamp":"2026-06-01T10:00:06Z","data":{"type":"hook_progress","hookEvent":"PreToolUse","hookName":"PreToolUse:Bash","command":"~/.claude/guard.sh"}}`
+		result   = `{"type":"user","timestamp":"2026-06-01T10:00:09Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`
+		blocked  = `{"type":"attachment","timestamp":"2026-06-01T10:00:07Z","attachment":{"type":"hook_blocking_error","hookName":"PreToolUse:Bash","hookEvent":"PreToolUse","blockingError":{"blockingError":"make is not allowed here\nuse just","command":"~/.claude/guard.sh"}}}`
+		failed   = `{"type":"attachment","timestamp":"2026-06-01T10:00:07Z","attachment":{"type":"hook_non_blocking_error","hookName":"PostToolUse:Edit","hookEvent":"PostToolUse","stderr":"prettier: not found","exitCode":127}}`
+		success  = `{"type":"attachment","timestamp":"2026-06-01T10:00:08Z","attachment":{"type":"hook_success","hookName":"PostToolUse:Edit","hookEvent":"PostToolUse","exitCode":0}}`
+		stop     = `{"type":"system","subtype":"stop_hook_summary","timestamp":"2026-06-01T10:00:10Z","hookCount":1,"hookErrors":["lint failed: 3 problems"],"preventedContinuation":false}`
+		reply    = `{"type":"assistant","timestamp":"2026-06-01T10:00:11Z","message":{"role":"assistant","content":[{"type":"text","text":"Done"}]}}`
+	)
+	tests := []struct {
+		name  string
+		lines []string
+		want  string // "" for no hook, else "running|blocked|failed Label: Error"
+	}{
+		{"still running", []string{prompt, toolUse, progress}, "running PreToolUse:Bash: "},
+		{"finished", []string{prompt, toolUse, progress, result}, ""},
+		{"blocked", []string{prompt, toolUse, progress, blocked, reply}, "blocked PreToolUse:Bash: make is not allowed here"},
+		{"failed", []string{prompt, toolUse, failed, result}, "failed PostToolUse:Edit: prettier: not found"},
+		{"failure then success", []string{prompt, failed, success, reply}, ""},
+		{"stop hook error", []string{prompt, reply, stop}, "failed Stop: lint failed: 3 problems"},
+		{"cleared by the next prompt", []string{prompt, toolUse, blocked, reply, prompt}, ""},
+		{"no hooks", []string{prompt, reply}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, _, _ := writeLog(t, t.TempDir(), "s.jsonl", strings.Join(tt.lines, "\n")+"\n")
+			pl, err := parseLogFile(path, recentEntries)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			if h := pl.hook; h != nil {
+				state := "failed"
+				switch {
+				case h.Running:
+					state = "running"
+				case h.Blocked:
+					state = "blocked"
+				}
+				got = state + " " + h.Label() + ": " + h.Error
+			}
+			if got != tt.want {
+				t.Errorf("hook = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
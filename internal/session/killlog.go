@@ -0,0 +1,85 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// KillRecord is one Claude process csm terminated, as kept in the kill log.
+type KillRecord struct {
+	Time      time.Time     `json:"time"`
+	PID       int           `json:"pid"`
+	Project   string        `json:"project"`
+	SessionID string        `json:"session_id,omitempty"`
+	Idle      time.Duration `json:"idle"` // time since the session's log last changed
+	Via       string        `json:"via"`  // what terminated it, e.g. "csm kill" or "live view"
+}
+
+// killLogPathFn is overridable in tests.
+var killLogPathFn = defaultKillLogPath
+
+func defaultKillLogPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".claude-monitor", "kills.jsonl"), nil
+}
+
+// RecordKill appends r to the kill log, one JSON object per line.
+func RecordKill(r KillRecord) error {
+	path, err := killLogPathFn()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadKillLog returns the recorded kills, oldest first. A missing log yields
+// none; lines that don't parse are skipped.
+func LoadKillLog() ([]KillRecord, error) {
+	path, err := killLogPathFn()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []KillRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r KillRecord
+		if json.Unmarshal(scanner.Bytes(), &r) == nil {
+			records = append(records, r)
+		}
+	}
+	return records, scanner.Err()
+}
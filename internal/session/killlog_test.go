@@ -0,0 +1,38 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestKillLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitor", "kills.jsonl")
+	killLogPathFn = func() (string, error) { return path, nil }
+	t.Cleanup(func() { killLogPathFn = defaultKillLogPath })
+
+	if records, err := LoadKillLog(); err != nil || records != nil {
+		t.Fatalf("empty log = %v, %v", records, err)
+	}
+
+	at := time.Date(2026, 1, 2, 14, 32, 0, 0, time.UTC)
+	first := KillRecord{Time: at, PID: 4242, Project: "org/api", SessionID: "abc", Idle: 3 * time.Hour, Via: "csm kill"}
+	second := KillRecord{Time: at.Add(time.Minute), PID: 4343, Project: "org/web", Idle: 2 * time.Hour, Via: "live view"}
+	for _, r := range []KillRecord{first, second} {
+		if err := RecordKill(r); err != nil {
+			t.Fatalf("RecordKill: %v", err)
+		}
+	}
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString("not json\n")
+	f.Close()
+
+	records, err := LoadKillLog()
+	if err != nil {
+		t.Fatalf("LoadKillLog: %v", err)
+	}
+	if len(records) != 2 || records[0] != first || records[1] != second {
+		t.Errorf("records = %+v", records)
+	}
+}
@@ -0,0 +1,165 @@
+package session
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WeekUsage is one week of a weekly usage limit: the tokens used each day so
+// far and, at the average rate since the week started, what the rest of the
+// week will use.
+type WeekUsage struct {
+	Start     time.Time  `json:"start"`
+	Reset     time.Time  `json:"reset"`
+	Limit     int        `json:"limit,omitempty"` // weekly cap in tokens; 0 when unknown
+	Used      int        `json:"used"`            // tokens since Start
+	Projected int        `json:"projected"`       // Used plus the projection up to Reset
+	Days      []DayUsage `json:"days"`
+	// HitsLimit is when usage reaches Limit, or did already; zero when the
+	// projection stays below it until Reset.
+	HitsLimit time.Time `json:"hits_limit,omitzero"`
+
+	now     time.Time
+	samples []usageSample
+}
+
+// DayUsage is one day of a WeekUsage. The first and last days may be partial
+// when the week doesn't reset at midnight.
+type DayUsage struct {
+	Date      time.Time `json:"date"`      // start of the day, or of the week
+	Tokens    int       `json:"tokens"`    // used that day
+	Projected int       `json:"projected"` // expected on top of Tokens at the current rate
+}
+
+// WeekBounds returns the week containing now for a weekly reset spec such as
+// "Mon" or "Thu 09:00" (local time).
+func WeekBounds(spec string, now time.Time) (start, reset time.Time, err error) {
+	day, clock, _ := strings.Cut(strings.TrimSpace(spec), " ")
+	weekday := -1
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(day, d.String()[:3]) || strings.EqualFold(day, d.String()) {
+			weekday = int(d)
+		}
+	}
+	hour, minute := 0, 0
+	if clock = strings.TrimSpace(clock); clock != "" {
+		t, perr := time.Parse("15:04", clock)
+		if perr != nil {
+			weekday = -1
+		}
+		hour, minute = t.Hour(), t.Minute()
+	}
+	if weekday < 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid weekly reset %q, want e.g. \"Mon\" or \"Thu 09:00\"", spec)
+	}
+
+	now = now.Local()
+	start = time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	start = start.AddDate(0, 0, -((int(now.Weekday()) - weekday + 7) % 7))
+	if start.After(now) {
+		start = start.AddDate(0, 0, -7)
+	}
+	return start, start.AddDate(0, 0, 7), nil
+}
+
+// ComputeWeek totals the tokens logged by all sessions since start and
+// projects them forward to reset. limit is the weekly cap, 0 if unknown.
+func ComputeWeek(start, reset time.Time, limit int) WeekUsage {
+	now := time.Now()
+	var samples []usageSample
+	sessions, _ := DiscoverHistory(int(now.Sub(start).Hours()/24) + 1)
+	for _, s := range sessions {
+		if s.EndTime.Before(start) {
+			continue
+		}
+		samples = append(samples, scanLogSamples(s.LogFile, start)...)
+	}
+	return projectWeek(start, reset, now, limit, samples)
+}
+
+// WithLimit returns the same week projected against another limit, e.g. one
+// estimated from Used, without reading the logs again.
+func (w WeekUsage) WithLimit(limit int) WeekUsage {
+	return projectWeek(w.Start, w.Reset, w.now, limit, w.samples)
+}
+
+// scanLogSamples returns the usage entries of a log logged at or after since.
+func scanLogSamples(logFile string, since time.Time) []usageSample {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var out []usageSample
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if s, ok := parseUsageSample(scanner.Text()); ok && !s.at.Before(since) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// projectWeek buckets samples into days and extrapolates the average rate
+// since start over the rest of the week.
+func projectWeek(start, reset, now time.Time, limit int, samples []usageSample) WeekUsage {
+	w := WeekUsage{Start: start, Reset: reset, Limit: limit, now: now, samples: samples}
+
+	// Day boundaries: the week start, each local midnight after it, reset.
+	bounds := []time.Time{start}
+	for d := start.Local(); ; {
+		d = time.Date(d.Year(), d.Month(), d.Day()+1, 0, 0, 0, 0, d.Location())
+		if !d.Before(reset) {
+			break
+		}
+		bounds = append(bounds, d)
+	}
+	bounds = append(bounds, reset)
+	for _, b := range bounds[:len(bounds)-1] {
+		w.Days = append(w.Days, DayUsage{Date: b})
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].at.Before(samples[j].at) })
+	for _, s := range samples {
+		if s.at.Before(start) || !s.at.Before(reset) {
+			continue
+		}
+		w.Used += s.tokens
+		if limit > 0 && w.HitsLimit.IsZero() && w.Used >= limit {
+			w.HitsLimit = s.at
+		}
+		i := sort.Search(len(bounds), func(i int) bool { return bounds[i].After(s.at) }) - 1
+		w.Days[i].Tokens += s.tokens
+	}
+
+	// Tokens per second since the week started.
+	elapsed := now.Sub(start).Seconds()
+	if elapsed <= 0 || !now.Before(reset) {
+		w.Projected = w.Used
+		return w
+	}
+	rate := float64(w.Used) / elapsed
+	for i := range w.Days {
+		from, to := bounds[i], bounds[i+1]
+		if from.Before(now) {
+			from = now
+		}
+		if to.After(from) {
+			w.Days[i].Projected = int(rate * to.Sub(from).Seconds())
+		}
+	}
+	w.Projected = w.Used + int(rate*reset.Sub(now).Seconds())
+
+	if limit > 0 && w.HitsLimit.IsZero() && rate > 0 {
+		if hit := now.Add(time.Duration(float64(limit-w.Used) / rate * float64(time.Second))); hit.Before(reset) {
+			w.HitsLimit = hit
+		}
+	}
+	return w
+}
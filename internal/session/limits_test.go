@@ -0,0 +1,74 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekBounds(t *testing.T) {
+	// Wednesday 2026-01-07 10:00.
+	now := time.Date(2026, 1, 7, 10, 0, 0, 0, time.Local)
+	tests := []struct {
+		spec  string
+		start time.Time
+	}{
+		{"Mon", time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)},
+		{"wednesday 09:00", time.Date(2026, 1, 7, 9, 0, 0, 0, time.Local)},
+		{"Wed 11:00", time.Date(2025, 12, 31, 11, 0, 0, 0, time.Local)},
+		{"Thu 15:30", time.Date(2026, 1, 1, 15, 30, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		start, reset, err := WeekBounds(tt.spec, now)
+		if err != nil {
+			t.Errorf("WeekBounds(%q): %v", tt.spec, err)
+			continue
+		}
+		if !start.Equal(tt.start) || !reset.Equal(tt.start.AddDate(0, 0, 7)) {
+			t.Errorf("WeekBounds(%q) = %s → %s, want start %s", tt.spec, start, reset, tt.start)
+		}
+	}
+	for _, spec := range []string{"", "Funday", "Mon 25:00"} {
+		if _, _, err := WeekBounds(spec, now); err == nil {
+			t.Errorf("WeekBounds(%q) accepted", spec)
+		}
+	}
+}
+
+func TestProjectWeek(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.Local) // Monday 09:00
+	reset := start.AddDate(0, 0, 7)
+	now := start.Add(48 * time.Hour) // Wednesday 09:00
+
+	samples := []usageSample{
+		{at: start.Add(-time.Hour), tokens: 999}, // last week
+		{at: start.Add(time.Hour), tokens: 100},
+		{at: start.Add(30 * time.Hour), tokens: 140},
+	}
+	w := projectWeek(start, reset, now, 700, samples)
+
+	// Mon 09:00–24:00, Tue … Sun, Mon 00:00–09:00.
+	if len(w.Days) != 8 {
+		t.Fatalf("days = %d, want 8", len(w.Days))
+	}
+	if w.Used != 240 || w.Days[0].Tokens != 100 || w.Days[1].Tokens != 140 {
+		t.Errorf("used = %d, days = %+v", w.Used, w.Days[:2])
+	}
+	// 5 tokens an hour: 120 more per full day, 45 for the last 9 hours.
+	if w.Days[0].Projected != 0 || w.Days[2].Projected != 75 || w.Days[3].Projected != 120 || w.Days[7].Projected != 45 {
+		t.Errorf("projected = %+v", w.Days)
+	}
+	if w.Projected != 240+5*120 {
+		t.Errorf("projected total = %d", w.Projected)
+	}
+	// 460 tokens left at 5 an hour.
+	if want := now.Add(92 * time.Hour); !w.HitsLimit.Equal(want) {
+		t.Errorf("hits limit %s, want %s", w.HitsLimit, want)
+	}
+
+	if w := w.WithLimit(1000); !w.HitsLimit.IsZero() {
+		t.Errorf("1000 cap hit at %s", w.HitsLimit)
+	}
+	if w := projectWeek(start, reset, now, 200, samples); !w.HitsLimit.Equal(start.Add(30 * time.Hour)) {
+		t.Errorf("200 cap hit at %s, want when it was crossed", w.HitsLimit)
+	}
+}
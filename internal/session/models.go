@@ -0,0 +1,132 @@
+package session
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ModelUsage is the token use of one model family over a period, summed
+// across all sessions.
+type ModelUsage struct {
+	Model    string  `json:"model"` // family, e.g. "opus"; the model id when not a known family
+	Input    int     `json:"input_tokens"`
+	Output   int     `json:"output_tokens"`
+	Cache    int     `json:"cache_tokens"` // cache writes and reads
+	Tokens   int     `json:"total_tokens"`
+	Cost     float64 `json:"cost"`     // estimated USD list price
+	Sessions int     `json:"sessions"` // sessions that used the model
+}
+
+// ComputeModelUsage totals the tokens logged since since per model family,
+// most expensive first.
+func ComputeModelUsage(since time.Time) []ModelUsage {
+	sessions, _ := DiscoverHistory(int(time.Since(since).Hours()/24) + 1)
+	var logs [][]ModelUsage
+	for _, s := range sessions {
+		if s.EndTime.Before(since) {
+			continue
+		}
+		logs = append(logs, scanModelUsage(s.LogFile, since))
+	}
+	return sumModelUsage(logs)
+}
+
+// modelFamily maps a model id such as "claude-opus-4-1-20250805" to its
+// family, the way EstimateCost prices it.
+func modelFamily(model string) string {
+	for _, m := range modelPrices {
+		if strings.Contains(model, m.family) {
+			return m.family
+		}
+	}
+	if model == "" {
+		return "unknown"
+	}
+	return model
+}
+
+// scanModelUsage returns one log's usage entries logged at or after since,
+// per model family, with Sessions set to 1.
+func scanModelUsage(logFile string, since time.Time) []ModelUsage {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	byModel := make(map[string]*ModelUsage)
+	var order []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.Contains(line, `"usage"`) {
+			continue
+		}
+		if ts := extractTimestampFromLine(line); ts.IsZero() || ts.Before(since) {
+			continue
+		}
+		input := extractIntField(line, `"input_tokens":`)
+		output := extractIntField(line, `"output_tokens":`)
+		cacheWrite := extractIntField(line, `"cache_creation_input_tokens":`)
+		cacheRead := extractIntField(line, `"cache_read_input_tokens":`)
+		if input+output+cacheWrite+cacheRead == 0 {
+			continue
+		}
+		model := extractStringField(line, `"model":"`)
+		family := modelFamily(model)
+		m, ok := byModel[family]
+		if !ok {
+			m = &ModelUsage{Model: family, Sessions: 1}
+			byModel[family] = m
+			order = append(order, family)
+		}
+		m.Input += input
+		m.Output += output
+		m.Cache += cacheWrite + cacheRead
+		m.Tokens += input + output + cacheWrite + cacheRead
+		m.Cost += EstimateCost(model, input, output, cacheWrite, cacheRead)
+	}
+
+	out := make([]ModelUsage, 0, len(order))
+	for _, family := range order {
+		out = append(out, *byModel[family])
+	}
+	return out
+}
+
+// sumModelUsage merges the per-log usage of scanModelUsage, most expensive
+// model first.
+func sumModelUsage(logs [][]ModelUsage) []ModelUsage {
+	byModel := make(map[string]*ModelUsage)
+	for _, log := range logs {
+		for _, u := range log {
+			m, ok := byModel[u.Model]
+			if !ok {
+				m = &ModelUsage{Model: u.Model}
+				byModel[u.Model] = m
+			}
+			m.Input += u.Input
+			m.Output += u.Output
+			m.Cache += u.Cache
+			m.Tokens += u.Tokens
+			m.Cost += u.Cost
+			m.Sessions += u.Sessions
+		}
+	}
+
+	out := make([]ModelUsage, 0, len(byModel))
+	for _, m := range byModel {
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Cost != out[j].Cost {
+			return out[i].Cost > out[j].Cost
+		}
+		return out[i].Model < out[j].Model
+	})
+	return out
+}
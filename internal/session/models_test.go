@@ -0,0 +1,48 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestModelUsage(t *testing.T) {
+	since := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	lines := []string{
+		`{"type":"assistant","timestamp":"2026-05-31T23:00:00Z","message":{"model":"claude-opus-4-1","usage":{"input_tokens":999,"output_tokens":999}}}`,
+		`{"type":"assistant","timestamp":"2026-06-01T10:00:00Z","message":{"model":"claude-opus-4-1","usage":{"input_tokens":1000,"output_tokens":100,"cache_read_input_tokens":10000}}}`,
+		`{"type":"assistant","timestamp":"2026-06-01T10:01:00Z","message":{"model":"claude-haiku-4-5","usage":{"input_tokens":500,"output_tokens":50}}}`,
+		`{"type":"assistant","timestamp":"2026-06-01T10:02:00Z","message":{"model":"<synthetic>","usage":{"input_tokens":0,"output_tokens":0}}}`,
+	}
+	dir := t.TempDir()
+	a, _, _ := writeLog(t, dir, "a.jsonl", strings.Join(lines, "\n")+"\n")
+	b, _, _ := writeLog(t, dir, "b.jsonl", lines[1]+"\n")
+
+	got := sumModelUsage([][]ModelUsage{scanModelUsage(a, since), scanModelUsage(b, since)})
+	if len(got) != 2 {
+		t.Fatalf("models = %+v, want opus and haiku", got)
+	}
+	opus, haiku := got[0], got[1]
+	if opus.Model != "opus" || opus.Sessions != 2 || opus.Input != 2000 || opus.Output != 200 || opus.Cache != 20000 || opus.Tokens != 22200 {
+		t.Errorf("opus = %+v", opus)
+	}
+	if want := 2 * EstimateCost("claude-opus-4-1", 1000, 100, 0, 10000); opus.Cost != want {
+		t.Errorf("opus cost = %v, want %v", opus.Cost, want)
+	}
+	if haiku.Model != "haiku" || haiku.Sessions != 1 || haiku.Tokens != 550 {
+		t.Errorf("haiku = %+v", haiku)
+	}
+}
+
+func TestModelFamily(t *testing.T) {
+	for model, want := range map[string]string{
+		"claude-sonnet-4-5-20250929": "sonnet",
+		"claude-3-5-haiku-20241022":  "haiku",
+		"gpt-4o":                     "gpt-4o",
+		"":                           "unknown",
+	} {
+		if got := modelFamily(model); got != want {
+			t.Errorf("modelFamily(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
@@ -51,14 +51,15 @@ func getOAuthTokenDarwin() *OAuthToken {
 	return creds.ClaudeAiOauth
 }
 
-// getOAuthTokenLinux reads the token from ~/.claude/.credentials.json.
+// getOAuthTokenLinux reads the token from .credentials.json in the Claude
+// config directory (~/.claude by default).
 func getOAuthTokenLinux() *OAuthToken {
-	home, err := os.UserHomeDir()
+	dir, err := ClaudeDir()
 	if err != nil {
 		return nil
 	}
 
-	data, err := os.ReadFile(filepath.Join(home, ".claude", ".credentials.json"))
+	data, err := os.ReadFile(filepath.Join(dir, ".credentials.json"))
 	if err != nil {
 		return nil
 	}
@@ -17,11 +17,14 @@ type Origin struct {
 	Category OriginCategory `json:"category,omitempty"`
 	App      string         `json:"app,omitempty"`     // stable slug: "ghostty", "iterm", "zed", "vscode", "cursor", ...
 	Display  string         `json:"display,omitempty"` // pretty name for UI: "Ghostty", "VS Code", ...
+	// Integration is set when the IDE's Claude extension started the session
+	// itself, as opposed to claude typed into the IDE's built-in terminal.
+	Integration bool `json:"integration,omitempty"`
 }
 
 // IsZero reports whether no origin information is set.
 func (o Origin) IsZero() bool {
-	return o.Category == OriginUnknown && o.App == "" && o.Display == ""
+	return o.Category == OriginUnknown && o.App == "" && o.Display == "" && !o.Integration
 }
 
 // appCatalog maps the stable app slug to its pretty display name and category.
@@ -79,6 +82,7 @@ type ProcessInfo struct {
 // input.
 //
 // Precedence (highest wins):
+//  0. IDE extension entry point (CLAUDE_CODE_ENTRYPOINT)
 //  1. IDE env vars (Zed, VS Code, Cursor, JetBrains)
 //  2. IDE ancestor bundle/exe match
 //  3. Claude Desktop (bundle id or Claude.app ancestor)
@@ -86,6 +90,15 @@ type ProcessInfo struct {
 //  5. Terminal ancestor exe match
 //  6. Unknown
 func classifyOrigin(env map[string]string, ancestors []ProcessInfo) Origin {
+	// 0. The VS Code extension (also run by Cursor and VSCodium builds)
+	// launches Claude itself and marks it in CLAUDE_CODE_ENTRYPOINT; claude
+	// started from a terminal, the IDE's own included, says "cli" or nothing.
+	if env["CLAUDE_CODE_ENTRYPOINT"] == "claude-vscode" {
+		o := vscodeVariant(env)
+		o.Integration = true
+		return o
+	}
+
 	// 1. IDE env vars — checked first because an IDE-hosted terminal also
 	// sets TERM_PROGRAM (sometimes to the IDE itself, sometimes to the host
 	// terminal), so we look for IDE-specific markers explicitly.
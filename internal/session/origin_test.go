@@ -22,6 +22,30 @@ func TestClassifyOrigin(t *testing.T) {
 			},
 			want: Origin{Category: OriginTerminal, App: "ghostty", Display: "Ghostty"},
 		},
+		{
+			name: "vscode extension via entry point",
+			env: map[string]string{
+				"CLAUDE_CODE_ENTRYPOINT": "claude-vscode",
+				"VSCODE_PID":             "4242",
+			},
+			want: Origin{Category: OriginIDE, App: "vscode", Display: "VS Code", Integration: true},
+		},
+		{
+			name: "cursor extension via entry point",
+			env: map[string]string{
+				"CLAUDE_CODE_ENTRYPOINT": "claude-vscode",
+				"CURSOR_TRACE_ID":        "abc",
+			},
+			want: Origin{Category: OriginIDE, App: "cursor", Display: "Cursor", Integration: true},
+		},
+		{
+			name: "claude in the vscode terminal is not the extension",
+			env: map[string]string{
+				"CLAUDE_CODE_ENTRYPOINT": "cli",
+				"TERM_PROGRAM":           "vscode",
+			},
+			want: Origin{Category: OriginIDE, App: "vscode", Display: "VS Code"},
+		},
 		{
 			name: "iterm via TERM_PROGRAM",
 			env: map[string]string{
@@ -0,0 +1,64 @@
+package session
+
+import (
+	"sync"
+	"time"
+)
+
+// Timing is where the time of one Discover sweep went, for the --debug log
+// and the live view's perf overlay.
+type Timing struct {
+	At           time.Time
+	Total        time.Duration // the whole sweep
+	ProcessScan  time.Duration // finding running Claude processes (ps, lsof, /proc)
+	Parse        time.Duration // reading logs, summed over all of them
+	Logs         int           // logs read
+	Slowest      string        // project of the log that took longest to read
+	SlowestParse time.Duration
+}
+
+// addParse counts the time taken to read one log of project. A nil Timing
+// records nothing.
+func (t *Timing) addParse(project string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.Parse += d
+	t.Logs++
+	if d > t.SlowestParse {
+		t.Slowest, t.SlowestParse = project, d
+	}
+}
+
+// ParsePerLog is the average time reading one log took.
+func (t Timing) ParsePerLog() time.Duration {
+	if t.Logs == 0 {
+		return 0
+	}
+	return t.Parse / time.Duration(t.Logs)
+}
+
+// timingHistory is how many sweeps Timings keeps.
+const timingHistory = 60
+
+var (
+	timingsMu sync.Mutex
+	timings   []Timing // oldest first
+)
+
+func recordTiming(t Timing) {
+	timingsMu.Lock()
+	defer timingsMu.Unlock()
+	timings = append(timings, t)
+	if n := len(timings) - timingHistory; n > 0 {
+		timings = append(timings[:0], timings[n:]...)
+	}
+}
+
+// Timings returns the latest Discover sweeps, oldest first. Calls served
+// from the result cache did no work and aren't included.
+func Timings() []Timing {
+	timingsMu.Lock()
+	defer timingsMu.Unlock()
+	return append([]Timing(nil), timings...)
+}
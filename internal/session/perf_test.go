@@ -0,0 +1,33 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTiming(t *testing.T) {
+	var tm Timing
+	tm.addParse("api", 3*time.Millisecond)
+	tm.addParse("web", 9*time.Millisecond)
+	if tm.Logs != 2 || tm.Parse != 12*time.Millisecond || tm.ParsePerLog() != 6*time.Millisecond || tm.Slowest != "web" {
+		t.Errorf("timing = %+v", tm)
+	}
+	(*Timing)(nil).addParse("api", time.Second) // no sweep to record into
+
+	timingsMu.Lock()
+	saved := timings
+	timings = nil
+	timingsMu.Unlock()
+	defer func() {
+		timingsMu.Lock()
+		timings = saved
+		timingsMu.Unlock()
+	}()
+	for i := range timingHistory + 5 {
+		recordTiming(Timing{Logs: i})
+	}
+	got := Timings()
+	if len(got) != timingHistory || got[0].Logs != 5 || got[len(got)-1].Logs != timingHistory+4 {
+		t.Errorf("Timings kept %d, from %d to %d", len(got), got[0].Logs, got[len(got)-1].Logs)
+	}
+}
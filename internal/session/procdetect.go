@@ -0,0 +1,210 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Minimal containers and hardened systems may lack ps or lsof. Without them
+// no Claude process is found and every session would look Inactive, so each
+// tool has a fallback: the process list is read from /proc when ps is
+// missing, and on macOS fuser finds the processes holding recent logs open
+// when lsof is missing. When none of that works, ProcessDetectionUnavailable
+// says why, so the views can say statuses are unknown instead of showing
+// every session as finished.
+
+var (
+	detectionMu      sync.Mutex
+	detectionProblem string
+)
+
+// ProcessDetectionUnavailable returns why the last process scan couldn't
+// find running Claude processes, e.g. "ps not found", or "" when it could.
+// While it is set, sessions show as Inactive whether Claude runs or not.
+func ProcessDetectionUnavailable() string {
+	detectionMu.Lock()
+	defer detectionMu.Unlock()
+	return detectionProblem
+}
+
+func setDetectionProblem(problem string) {
+	detectionMu.Lock()
+	changed := problem != detectionProblem
+	detectionProblem = problem
+	detectionMu.Unlock()
+	if changed && problem != "" {
+		slog.Debug("process detection unavailable", "reason", problem)
+	}
+}
+
+// procDir is where the Linux process list is read from when ps is missing.
+var procDir = "/proc"
+
+// claudePIDs returns the PIDs of running processes named claude, from ps,
+// or on Linux from procDir when ps isn't installed.
+func claudePIDs() ([]int, error) {
+	out, err := exec.Command("ps", "ax", "-o", "pid=,comm=").Output()
+	if err == nil {
+		return parsePsClaudePIDs(out), nil
+	}
+	if runtime.GOOS != "linux" {
+		return nil, toolError("ps", err)
+	}
+	pids, procErr := procClaudePIDs(procDir)
+	if procErr != nil {
+		return nil, fmt.Errorf("%v, and %v", toolError("ps", err), procErr)
+	}
+	slog.Debug("ps failed, process list read from /proc", "err", err)
+	return pids, nil
+}
+
+// toolError describes a failed run of an external tool, calling a missing
+// one "not found".
+func toolError(name string, err error) error {
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("%s not found", name)
+	}
+	return fmt.Errorf("%s: %w", name, err)
+}
+
+// parsePsClaudePIDs picks the claude processes out of `ps -o pid=,comm=`
+// output.
+func parsePsClaudePIDs(out []byte) []int {
+	var pids []int
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.HasSuffix(fields[len(fields)-1], "claude") {
+			continue
+		}
+		if pid, err := strconv.Atoi(fields[0]); err == nil && pid != 0 {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// procClaudePIDs lists the claude processes in a procfs mounted at dir, by
+// the command name in each <pid>/comm.
+func procClaudePIDs(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s unreadable", dir)
+	}
+	var pids []int
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		comm, err := os.ReadFile(filepath.Join(dir, e.Name(), "comm"))
+		if err == nil && strings.HasSuffix(strings.TrimSpace(string(comm)), "claude") {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// processName returns the command name of a process, from ps or /proc.
+func processName(pid int) (string, error) {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "comm=").Output()
+	if err == nil {
+		return strings.TrimSpace(string(out)), nil
+	}
+	if runtime.GOOS != "linux" || !errors.Is(err, exec.ErrNotFound) {
+		return "", err
+	}
+	comm, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "comm"))
+	return strings.TrimSpace(string(comm)), err
+}
+
+// fuserProcessFiles stands in for lsof on macOS: it asks fuser which of
+// pids hold a log changed within dormantAge open. Their cwds are those the
+// logs record. Processes that hold no log open can't be placed this way.
+func fuserProcessFiles(pids []int) map[int]runningProcess {
+	procs := make(map[int]runningProcess)
+	if _, err := exec.LookPath("fuser"); err != nil {
+		return procs
+	}
+	wanted := make(map[int]bool, len(pids))
+	for _, pid := range pids {
+		wanted[pid] = true
+	}
+	for _, log := range recentLogs() {
+		// fuser prints the file name on stderr and the PIDs on stdout; one
+		// file per run keeps them apart.
+		out, _ := exec.Command("fuser", log).Output()
+		for _, pid := range parseFuserPIDs(out) {
+			if !wanted[pid] {
+				continue
+			}
+			p := procs[pid]
+			if p.PID == 0 {
+				p = runningProcess{PID: pid, CWD: logCwd(log)}
+			}
+			p.Logs = append(p.Logs, log)
+			procs[pid] = p
+		}
+	}
+	return procs
+}
+
+// placementProblem explains why none of the running Claude processes could
+// be matched to a directory, or returns "" when nothing stops that.
+func placementProblem() string {
+	if runtime.GOOS == "linux" {
+		if _, err := os.Readlink(filepath.Join(procDir, "self", "cwd")); err != nil {
+			return procDir + " unreadable"
+		}
+		return ""
+	}
+	if _, err := exec.LookPath("lsof"); err == nil {
+		return ""
+	}
+	if _, err := exec.LookPath("fuser"); err != nil {
+		return "lsof and fuser not found"
+	}
+	return "lsof not found, and fuser finds no Claude process holding its log open"
+}
+
+// parseFuserPIDs reads the PIDs fuser printed, dropping the letters some
+// versions append for the kind of use (123c, 456e).
+func parseFuserPIDs(out []byte) []int {
+	var pids []int
+	for _, f := range strings.Fields(string(out)) {
+		if pid, err := strconv.Atoi(strings.TrimRight(f, "abcdefmrtx")); err == nil && pid > 0 {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// recentLogs returns the session logs of every profile changed within
+// dormantAge, the ones a running Claude process could be writing.
+func recentLogs() []string {
+	profiles, err := Profiles()
+	if err != nil {
+		return nil
+	}
+	var logs []string
+	for _, p := range profiles {
+		matches, _ := filepath.Glob(filepath.Join(p.Dir, "projects", "*", "*.jsonl"))
+		for _, m := range matches {
+			if strings.HasPrefix(filepath.Base(m), "agent-") {
+				continue
+			}
+			if info, err := os.Stat(m); err == nil && time.Since(info.ModTime()) <= dormantAge {
+				logs = append(logs, m)
+			}
+		}
+	}
+	return logs
+}
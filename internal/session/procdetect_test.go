@@ -0,0 +1,67 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseProcessLists(t *testing.T) {
+	ps := "    1 init\n  412 /usr/local/bin/claude\n  413 claude-helper\n  900 claude\n"
+	if got := parsePsClaudePIDs([]byte(ps)); !slices.Equal(got, []int{412, 900}) {
+		t.Errorf("parsePsClaudePIDs = %v", got)
+	}
+	if got := parseFuserPIDs([]byte(" 412 900c  77e\n")); !slices.Equal(got, []int{412, 900, 77}) {
+		t.Errorf("parseFuserPIDs = %v", got)
+	}
+
+	dir := t.TempDir()
+	for pid, comm := range map[string]string{"412": "claude\n", "413": "bash\n", "self": "claude\n"} {
+		os.Mkdir(filepath.Join(dir, pid), 0o755)
+		os.WriteFile(filepath.Join(dir, pid, "comm"), []byte(comm), 0o644)
+	}
+	if got, err := procClaudePIDs(dir); err != nil || !slices.Equal(got, []int{412}) {
+		t.Errorf("procClaudePIDs = %v, %v", got, err)
+	}
+}
+
+// Test: without ps the process list comes from /proc, and without either
+// the scan says detection is unavailable.
+func TestRunningClaudeDirsWithoutPs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("falls back to /proc")
+	}
+	t.Setenv("PATH", t.TempDir())
+	saved := procDir
+	t.Cleanup(func() {
+		procDir = saved
+		processFiles, processFilesScans = nil, 0
+		setDetectionProblem("")
+	})
+
+	// The test process stands in for Claude: its comm reads "claude".
+	me := strconv.Itoa(os.Getpid())
+	procDir = t.TempDir()
+	os.Mkdir(filepath.Join(procDir, me), 0o755)
+	os.WriteFile(filepath.Join(procDir, me, "comm"), []byte("claude\n"), 0o644)
+	wd, _ := os.Getwd()
+	dirs := getRunningClaudeDirs()
+	if procs := dirs[encodeProjectPath(wd)]; len(procs) != 1 || procs[0].CWD != wd {
+		t.Errorf("dirs = %v, want the test process in %s", dirs, wd)
+	}
+	if p := ProcessDetectionUnavailable(); p != "" {
+		t.Errorf("detection unavailable with /proc: %q", p)
+	}
+
+	procDir = filepath.Join(t.TempDir(), "missing")
+	if dirs := getRunningClaudeDirs(); len(dirs) != 0 {
+		t.Errorf("dirs without ps or /proc = %v", dirs)
+	}
+	if p := ProcessDetectionUnavailable(); !strings.Contains(p, "ps not found") {
+		t.Errorf("detection problem = %q, want ps not found", p)
+	}
+}
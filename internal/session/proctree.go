@@ -0,0 +1,67 @@
+package session
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProcessNode is a process in the tree under a Claude process: a shell
+// running a Bash command, a dev server, a test runner and so on.
+type ProcessNode struct {
+	PID      int           `json:"pid"`
+	Command  string        `json:"command"`
+	Children []ProcessNode `json:"children,omitempty"`
+}
+
+// ProcessTree returns the processes pid has spawned, directly or not. It
+// takes a single ps snapshot, which works the same on Linux and macOS.
+func ProcessTree(pid int) ([]ProcessNode, error) {
+	out, err := exec.Command("ps", "-A", "-o", "pid=,ppid=,args=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("ps: %w", err)
+	}
+	return buildProcessTree(out, pid), nil
+}
+
+// buildProcessTree builds the tree of descendants of root from
+// `ps -o pid=,ppid=,args=` output. Children are ordered by PID, which is
+// roughly the order they were started in.
+func buildProcessTree(out []byte, root int) []ProcessNode {
+	children := make(map[int][]ProcessNode)
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		fields := strings.Fields(string(line))
+		if len(fields) < 3 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil || pid == ppid {
+			continue
+		}
+		children[ppid] = append(children[ppid], ProcessNode{PID: pid, Command: strings.Join(fields[2:], " ")})
+	}
+
+	// A process can't be its own ancestor, but ps snapshots aren't atomic and
+	// PIDs get reused, so don't trust that when recursing.
+	seen := map[int]bool{root: true}
+	var build func(pid int) []ProcessNode
+	build = func(pid int) []ProcessNode {
+		nodes := children[pid]
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].PID < nodes[j].PID })
+		kept := nodes[:0]
+		for _, n := range nodes {
+			if seen[n.PID] {
+				continue
+			}
+			seen[n.PID] = true
+			n.Children = build(n.PID)
+			kept = append(kept, n)
+		}
+		return kept
+	}
+	return build(root)
+}
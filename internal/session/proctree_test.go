@@ -0,0 +1,30 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildProcessTree(t *testing.T) {
+	out := []byte(`    1     0 /sbin/init
+  100     1 -zsh
+  200   100 claude
+  230   200 /bin/zsh -c npm run dev
+  210   200 /bin/zsh -c go test ./...
+  231   230 node /repo/node_modules/.bin/vite
+  300     1 sshd
+  201   201 weird
+`)
+	want := []ProcessNode{
+		{PID: 210, Command: "/bin/zsh -c go test ./..."},
+		{PID: 230, Command: "/bin/zsh -c npm run dev", Children: []ProcessNode{
+			{PID: 231, Command: "node /repo/node_modules/.bin/vite"},
+		}},
+	}
+	if got := buildProcessTree(out, 200); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildProcessTree() = %+v, want %+v", got, want)
+	}
+	if got := buildProcessTree(out, 999); len(got) != 0 {
+		t.Errorf("buildProcessTree(unknown pid) = %+v, want none", got)
+	}
+}
@@ -0,0 +1,138 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Profile is a Claude config directory (e.g. ~/.claude or ~/.claude-work)
+// whose sessions csm monitors. When several profiles are monitored, each
+// session is tagged with its profile's Name.
+type Profile struct {
+	Name string `json:"name"`
+	Dir  string `json:"dir"`
+}
+
+// profileOverride is set by SetProfiles and takes precedence over the
+// environment.
+var profileOverride []Profile
+
+// SetProfiles sets the Claude config directories to monitor. Duplicate
+// directories are dropped; an empty list restores the default lookup.
+func SetProfiles(profiles []Profile) {
+	profileOverride = nil
+	seen := make(map[string]bool)
+	for _, p := range profiles {
+		dir := filepath.Clean(p.Dir)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		p.Dir = dir
+		profileOverride = append(profileOverride, p)
+	}
+}
+
+// ParseProfile parses a --claude-dir value: either a directory or
+// "name=directory". Without a name, one is derived from the directory. A
+// leading "~/" is expanded since config values never pass through a shell.
+func ParseProfile(spec string) Profile {
+	if name, dir, ok := strings.Cut(spec, "="); ok && name != "" && !strings.ContainsRune(name, filepath.Separator) {
+		return Profile{Name: name, Dir: expandHome(dir)}
+	}
+	dir := expandHome(spec)
+	return Profile{Name: profileName(dir), Dir: dir}
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// profileName derives a short profile name from a config directory:
+// ~/.claude-work → "work", ~/work/.claude → "work", ~/.claude → "default".
+func profileName(dir string) string {
+	dir = filepath.Clean(dir)
+	base := strings.TrimPrefix(filepath.Base(dir), ".")
+	if base == "claude" {
+		parent := filepath.Dir(dir)
+		if home, err := os.UserHomeDir(); err == nil && parent == filepath.Clean(home) {
+			return "default"
+		}
+		return filepath.Base(parent)
+	}
+	return strings.TrimPrefix(base, "claude-")
+}
+
+// Profiles returns the monitored Claude config directories: the SetProfiles
+// list, else $CLAUDE_CONFIG_DIR, else ~/.claude.
+func Profiles() ([]Profile, error) {
+	if len(profileOverride) > 0 {
+		return profileOverride, nil
+	}
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return []Profile{{Name: profileName(dir), Dir: dir}}, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine home directory: %w", err)
+	}
+	return []Profile{{Name: "default", Dir: filepath.Join(home, ".claude")}}, nil
+}
+
+// MultiProfile reports whether more than one profile is monitored, in which
+// case sessions carry a Profile tag.
+func MultiProfile() bool {
+	return len(profileOverride) > 1
+}
+
+// profileTag returns the tag for sessions found in profile: its name when
+// several profiles are monitored, otherwise empty.
+func profileTag(profile Profile) string {
+	if !MultiProfile() {
+		return ""
+	}
+	return profile.Name
+}
+
+// MissingProjectsDir returns the projects directory Claude Code would create
+// when no profile has one yet, which usually means Claude Code isn't
+// installed or has never run. It returns "" once any profile has one.
+func MissingProjectsDir() string {
+	profiles, err := Profiles()
+	if err != nil {
+		return ""
+	}
+	for _, p := range profiles {
+		if _, err := os.Stat(filepath.Join(p.Dir, "projects")); !os.IsNotExist(err) {
+			return ""
+		}
+	}
+	return filepath.Join(profiles[0].Dir, "projects")
+}
+
+// ClaudeDir returns the primary (first) Claude config directory.
+func ClaudeDir() (string, error) {
+	profiles, err := Profiles()
+	if err != nil {
+		return "", err
+	}
+	return profiles[0].Dir, nil
+}
+
+// ClaudeProjectsDir returns the path to the primary Claude projects directory
+func ClaudeProjectsDir() (string, error) {
+	dir, err := ClaudeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "projects"), nil
+}
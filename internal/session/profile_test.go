@@ -0,0 +1,80 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Cleanup(func() { SetProfiles(nil) })
+	t.Setenv("HOME", "/home/me")
+
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	if got, _ := ClaudeDir(); got != filepath.Join("/home/me", ".claude") {
+		t.Errorf("default ClaudeDir() = %q", got)
+	}
+
+	t.Setenv("CLAUDE_CONFIG_DIR", "/srv/claude")
+	if got, _ := ClaudeDir(); got != "/srv/claude" {
+		t.Errorf("ClaudeDir() with CLAUDE_CONFIG_DIR = %q, want /srv/claude", got)
+	}
+	if MultiProfile() {
+		t.Error("MultiProfile() = true with a single directory")
+	}
+
+	SetProfiles([]Profile{{Name: "work", Dir: "/tmp/work/"}, {Name: "me", Dir: "/tmp/me"}, {Name: "dup", Dir: "/tmp/work"}})
+	profiles, _ := Profiles()
+	if len(profiles) != 2 || profiles[0].Dir != "/tmp/work" || profiles[1].Name != "me" {
+		t.Errorf("Profiles() = %+v, want work and me with duplicates dropped", profiles)
+	}
+	if got, _ := ClaudeProjectsDir(); got != filepath.Join("/tmp/work", "projects") {
+		t.Errorf("ClaudeProjectsDir() = %q", got)
+	}
+	if !MultiProfile() {
+		t.Error("MultiProfile() = false with two directories")
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+
+	tests := []struct {
+		spec string
+		want Profile
+	}{
+		{"/home/me/.claude", Profile{Name: "default", Dir: "/home/me/.claude"}},
+		{"/home/me/.claude-work", Profile{Name: "work", Dir: "/home/me/.claude-work"}},
+		{"/home/me/personal/.claude", Profile{Name: "personal", Dir: "/home/me/personal/.claude"}},
+		{"/srv/sandbox", Profile{Name: "sandbox", Dir: "/srv/sandbox"}},
+		{"acme=/home/me/.claude-work", Profile{Name: "acme", Dir: "/home/me/.claude-work"}},
+		{"~/.claude-work", Profile{Name: "work", Dir: "/home/me/.claude-work"}},
+		{"acme=~/.claude-work", Profile{Name: "acme", Dir: "/home/me/.claude-work"}},
+		{"/tmp/a=b", Profile{Name: "a=b", Dir: "/tmp/a=b"}},
+	}
+	for _, tt := range tests {
+		if got := ParseProfile(tt.spec); got != tt.want {
+			t.Errorf("ParseProfile(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestMissingProjectsDir(t *testing.T) {
+	t.Cleanup(func() { SetProfiles(nil) })
+	work, me := t.TempDir(), t.TempDir()
+	SetProfiles([]Profile{{Name: "work", Dir: work}, {Name: "me", Dir: me}})
+
+	if got, want := MissingProjectsDir(), filepath.Join(work, "projects"); got != want {
+		t.Errorf("MissingProjectsDir() = %q, want %q", got, want)
+	}
+	if sessions, err := discoverProfile(Profile{Dir: work}, nil, map[string]struct{}{}, nil, nil); err != nil || sessions != nil {
+		t.Errorf("discoverProfile() without projects = %v, %v; want no sessions and no error", sessions, err)
+	}
+
+	if err := os.Mkdir(filepath.Join(me, "projects"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := MissingProjectsDir(); got != "" {
+		t.Errorf("MissingProjectsDir() with one projects dir = %q, want \"\"", got)
+	}
+}
@@ -13,25 +13,34 @@ import (
 
 // UsageStats holds aggregated local token usage across sessions within a rolling window.
 type UsageStats struct {
-	WindowStart  time.Time      `json:"window_start"`
-	WindowEnd    time.Time      `json:"window_end"`
-	InputTokens  int            `json:"input_tokens"`
-	OutputTokens int            `json:"output_tokens"`
-	CacheTokens  int            `json:"cache_tokens"`
-	TotalTokens  int            `json:"total_tokens"`
-	Sessions     []SessionUsage `json:"sessions"`
-}
-
-// SessionUsage holds token usage for a single session.
-type SessionUsage struct {
-	Project      string    `json:"project"`
-	LogFile      string    `json:"log_file"`
+	WindowStart  time.Time `json:"window_start"`
+	WindowEnd    time.Time `json:"window_end"`
 	InputTokens  int       `json:"input_tokens"`
 	OutputTokens int       `json:"output_tokens"`
 	CacheTokens  int       `json:"cache_tokens"`
 	TotalTokens  int       `json:"total_tokens"`
-	StartTime    time.Time `json:"start_time"`
-	EndTime      time.Time `json:"end_time"`
+	Cost         float64   `json:"cost"` // estimated USD list price
+	// The part of the tokens and cost spent on Claude Code's background
+	// tasks (session titles, summaries) rather than user turns.
+	BackgroundTokens int            `json:"background_tokens"`
+	BackgroundCost   float64        `json:"background_cost"`
+	Sessions         []SessionUsage `json:"sessions"`
+}
+
+// SessionUsage holds token usage for a single session.
+type SessionUsage struct {
+	Project      string  `json:"project"`
+	LogFile      string  `json:"log_file"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CacheTokens  int     `json:"cache_tokens"`
+	TotalTokens  int     `json:"total_tokens"`
+	Cost         float64 `json:"cost"`
+	// Background task usage, included in the totals above
+	BackgroundTokens int       `json:"background_tokens"`
+	BackgroundCost   float64   `json:"background_cost"`
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
 }
 
 // APIQuota holds the response from the Anthropic usage API.
@@ -59,15 +68,25 @@ type ExtraUsage struct {
 
 // ComputeUsage aggregates token usage across all sessions within a 5-hour rolling window.
 func ComputeUsage() *UsageStats {
-	now := time.Now()
-	windowStart := now.Add(-5 * time.Hour)
+	return ComputeUsageSince(time.Now().Add(-5 * time.Hour))
+}
 
-	// Discover history covering the window (1 day is enough for 5h)
-	sessions, err := DiscoverHistory(1)
+// ComputeUsageSince aggregates token usage across all sessions from
+// windowStart until now, e.g. since midnight for today's totals.
+func ComputeUsageSince(windowStart time.Time) *UsageStats {
+	return ComputeUsageBetween(windowStart, time.Now())
+}
+
+// ComputeUsageBetween aggregates token usage across all sessions from
+// windowStart until windowEnd, e.g. yesterday's for the daily digest.
+func ComputeUsageBetween(windowStart, windowEnd time.Time) *UsageStats {
+	// Discover history covering the window
+	days := int(time.Since(windowStart).Hours()/24) + 1
+	sessions, err := DiscoverHistory(days)
 	if err != nil {
 		return &UsageStats{
 			WindowStart: windowStart,
-			WindowEnd:   now,
+			WindowEnd:   windowEnd,
 		}
 	}
 
@@ -75,44 +94,47 @@ func ComputeUsage() *UsageStats {
 		totalInput   int
 		totalOutput  int
 		totalCache   int
+		totalCost    float64
+		bgTokens     int
+		bgCost       float64
 		sessionUsage []SessionUsage
 	)
 
 	for _, s := range sessions {
-		// Skip sessions that ended before the window
-		if s.EndTime.Before(windowStart) {
+		// Skip sessions outside the window
+		if s.EndTime.Before(windowStart) || !s.StartTime.Before(windowEnd) {
 			continue
 		}
 
-		input, output, cache, hasTokens := scanLogTokens(s.LogFile, windowStart)
+		su, hasTokens := scanLogTokens(s.LogFile, windowStart, windowEnd)
 		if !hasTokens {
 			continue
 		}
-
-		sessionUsage = append(sessionUsage, SessionUsage{
-			Project:      s.Project,
-			LogFile:      s.LogFile,
-			InputTokens:  input,
-			OutputTokens: output,
-			CacheTokens:  cache,
-			TotalTokens:  input + output + cache,
-			StartTime:    s.StartTime,
-			EndTime:      s.EndTime,
-		})
-
-		totalInput += input
-		totalOutput += output
-		totalCache += cache
+		su.Project = s.Project
+		su.LogFile = s.LogFile
+		su.StartTime = s.StartTime
+		su.EndTime = s.EndTime
+		sessionUsage = append(sessionUsage, su)
+
+		totalInput += su.InputTokens
+		totalOutput += su.OutputTokens
+		totalCache += su.CacheTokens
+		totalCost += su.Cost
+		bgTokens += su.BackgroundTokens
+		bgCost += su.BackgroundCost
 	}
 
 	return &UsageStats{
-		WindowStart:  windowStart,
-		WindowEnd:    now,
-		InputTokens:  totalInput,
-		OutputTokens: totalOutput,
-		CacheTokens:  totalCache,
-		TotalTokens:  totalInput + totalOutput + totalCache,
-		Sessions:     sessionUsage,
+		WindowStart:      windowStart,
+		WindowEnd:        windowEnd,
+		InputTokens:      totalInput,
+		OutputTokens:     totalOutput,
+		CacheTokens:      totalCache,
+		TotalTokens:      totalInput + totalOutput + totalCache,
+		Cost:             totalCost,
+		BackgroundTokens: bgTokens,
+		BackgroundCost:   bgCost,
+		Sessions:         sessionUsage,
 	}
 }
 
@@ -245,11 +267,12 @@ func parseAPIQuotaResponse(body []byte) *APIQuota {
 }
 
 // scanLogTokens scans a JSONL log file for usage entries with timestamps
-// within the window and returns aggregated token counts.
-func scanLogTokens(logFile string, windowStart time.Time) (input, output, cache int, hasTokens bool) {
+// within the window and returns aggregated token counts and their estimated
+// cost.
+func scanLogTokens(logFile string, windowStart, windowEnd time.Time) (su SessionUsage, hasTokens bool) {
 	file, err := os.Open(logFile)
 	if err != nil {
-		return 0, 0, 0, false
+		return su, false
 	}
 	defer file.Close()
 
@@ -257,6 +280,8 @@ func scanLogTokens(logFile string, windowStart time.Time) (input, output, cache
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, 10*1024*1024)
 
+	// Lines before the window still tell the conversation's model.
+	var bg backgroundClassifier
 	for scanner.Scan() {
 		line := scanner.Text()
 		if line == "" {
@@ -267,10 +292,11 @@ func scanLogTokens(logFile string, windowStart time.Time) (input, output, cache
 		if !strings.Contains(line, `"usage"`) {
 			continue
 		}
+		background := bg.background(line)
 
 		// Extract timestamp
 		ts := extractTimestampFromLine(line)
-		if ts.IsZero() || ts.Before(windowStart) {
+		if ts.IsZero() || ts.Before(windowStart) || !ts.Before(windowEnd) {
 			continue
 		}
 
@@ -281,14 +307,22 @@ func scanLogTokens(logFile string, windowStart time.Time) (input, output, cache
 		cacheRead := extractIntField(line, `"cache_read_input_tokens":`)
 
 		if inputTokens > 0 || outputTokens > 0 || cacheCreation > 0 || cacheRead > 0 {
-			input += inputTokens
-			output += outputTokens
-			cache += cacheCreation + cacheRead
+			tokens := inputTokens + outputTokens + cacheCreation + cacheRead
+			cost := EstimateCost(extractStringField(line, `"model":"`), inputTokens, outputTokens, cacheCreation, cacheRead)
+			su.InputTokens += inputTokens
+			su.OutputTokens += outputTokens
+			su.CacheTokens += cacheCreation + cacheRead
+			su.TotalTokens += tokens
+			su.Cost += cost
+			if background {
+				su.BackgroundTokens += tokens
+				su.BackgroundCost += cost
+			}
 			hasTokens = true
 		}
 	}
 
-	return input, output, cache, hasTokens
+	return su, hasTokens
 }
 
 // extractIntField extracts an integer value from a JSON line using fast string matching.
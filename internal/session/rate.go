@@ -0,0 +1,233 @@
+package session
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// modelPrice is a model family's API list price in USD per million tokens.
+type modelPrice struct {
+	input, output, cacheWrite, cacheRead float64
+}
+
+// modelPrices are matched against the model id in order; the first family
+// name it contains wins. Unknown models are priced as Sonnet.
+var modelPrices = []struct {
+	family string
+	price  modelPrice
+}{
+	{"opus", modelPrice{15, 75, 18.75, 1.50}},
+	{"sonnet", modelPrice{3, 15, 3.75, 0.30}},
+	{"haiku", modelPrice{1, 5, 1.25, 0.10}},
+}
+
+// EstimateCost returns the list-price cost in USD of one usage entry. It is
+// an estimate: subscription plans aren't billed per token.
+func EstimateCost(model string, input, output, cacheWrite, cacheRead int) float64 {
+	p := modelPrices[1].price
+	for _, m := range modelPrices {
+		if strings.Contains(model, m.family) {
+			p = m.price
+			break
+		}
+	}
+	return (float64(input)*p.input + float64(output)*p.output +
+		float64(cacheWrite)*p.cacheWrite + float64(cacheRead)*p.cacheRead) / 1e6
+}
+
+// backgroundFamily is the cheap model family Claude Code runs its own
+// background tasks on, such as session titles and summaries.
+const backgroundFamily = "haiku"
+
+// backgroundClassifier tells the usage of background tasks from the
+// conversation's as a log is read in order. Usage on the background model
+// counts as background once the log has shown the session running on
+// another model, unless it comes from a subagent.
+type backgroundClassifier struct {
+	main string // model family of the conversation, once seen
+}
+
+// background reports whether the usage line is background overhead rather
+// than a user turn.
+func (c *backgroundClassifier) background(line string) bool {
+	model := extractStringField(line, `"model":"`)
+	if model == "" || strings.HasPrefix(model, "<") { // e.g. "<synthetic>" API error replies
+		return false
+	}
+	family := modelFamily(model)
+	if family != backgroundFamily {
+		c.main = family
+		return false
+	}
+	return c.main != "" && !strings.Contains(line, `"isSidechain":true`)
+}
+
+// Rate is a session's token burn over a recent window.
+type Rate struct {
+	Tokens           int           // Tokens used in the window (input, output and cache)
+	Cost             float64       // Estimated USD cost of those tokens
+	BackgroundTokens int           // Part of Tokens used by background tasks
+	BackgroundCost   float64       // Part of Cost spent on background tasks
+	Window           time.Duration // Length of the window
+}
+
+// TokensPerMinute returns the average token rate over the window.
+func (r Rate) TokensPerMinute() float64 {
+	if r.Window <= 0 {
+		return 0
+	}
+	return float64(r.Tokens) / r.Window.Minutes()
+}
+
+// CostPerHour returns the estimated cost rate over the window.
+func (r Rate) CostPerHour() float64 {
+	if r.Window <= 0 {
+		return 0
+	}
+	return r.Cost / r.Window.Hours()
+}
+
+// BackgroundCostPerHour returns the part of CostPerHour spent on background
+// tasks.
+func (r Rate) BackgroundCostPerHour() float64 {
+	if r.Window <= 0 {
+		return 0
+	}
+	return r.BackgroundCost / r.Window.Hours()
+}
+
+// RateMeter measures each session's recent token burn. Logs are read
+// incrementally, so polling it on every refresh only costs the lines written
+// since the last poll.
+type RateMeter struct {
+	window time.Duration
+
+	mu    sync.Mutex
+	files map[string]*meteredLog
+}
+
+// meteredLog is how far a log has been read and the usage seen in the window.
+type meteredLog struct {
+	offset  int64
+	samples []usageSample
+	bg      backgroundClassifier
+}
+
+type usageSample struct {
+	at         time.Time
+	tokens     int
+	cost       float64
+	background bool
+}
+
+// NewRateMeter returns a RateMeter averaging over window.
+func NewRateMeter(window time.Duration) *RateMeter {
+	return &RateMeter{window: window, files: make(map[string]*meteredLog)}
+}
+
+// Rate returns the token burn of logFile over the window ending at now.
+func (m *RateMeter) Rate(logFile string, now time.Time) Rate {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ml, ok := m.files[logFile]
+	if !ok {
+		ml = &meteredLog{}
+		m.files[logFile] = ml
+	}
+	m.readNew(logFile, ml)
+
+	since := now.Add(-m.window)
+	kept := ml.samples[:0]
+	rate := Rate{Window: m.window}
+	for _, s := range ml.samples {
+		if s.at.Before(since) {
+			continue
+		}
+		kept = append(kept, s)
+		rate.Tokens += s.tokens
+		rate.Cost += s.cost
+		if s.background {
+			rate.BackgroundTokens += s.tokens
+			rate.BackgroundCost += s.cost
+		}
+	}
+	ml.samples = kept
+	return rate
+}
+
+// Forget drops logs not in keep, so the meter doesn't grow with every
+// session seen over a long run.
+func (m *RateMeter) Forget(keep map[string]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for f := range m.files {
+		if !keep[f] {
+			delete(m.files, f)
+		}
+	}
+}
+
+// readNew appends the usage entries written to logFile since the last read.
+// A trailing partial line is left for the next read; a file that shrank is
+// read again from the start.
+func (m *RateMeter) readNew(logFile string, ml *meteredLog) {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err != nil {
+		return
+	} else if info.Size() < ml.offset {
+		ml.offset = 0
+		ml.samples = nil
+		ml.bg = backgroundClassifier{}
+	}
+	if _, err := file.Seek(ml.offset, io.SeekStart); err != nil {
+		return
+	}
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		ml.offset += int64(len(line))
+		if s, ok := parseUsageSample(line); ok {
+			s.background = ml.bg.background(line)
+			ml.samples = append(ml.samples, s)
+		}
+	}
+}
+
+// parseUsageSample extracts the timestamp, tokens and estimated cost of a
+// log line carrying usage data.
+func parseUsageSample(line string) (usageSample, bool) {
+	if !strings.Contains(line, `"usage"`) {
+		return usageSample{}, false
+	}
+	ts := extractTimestampFromLine(line)
+	if ts.IsZero() {
+		return usageSample{}, false
+	}
+	input := extractIntField(line, `"input_tokens":`)
+	output := extractIntField(line, `"output_tokens":`)
+	cacheWrite := extractIntField(line, `"cache_creation_input_tokens":`)
+	cacheRead := extractIntField(line, `"cache_read_input_tokens":`)
+	tokens := input + output + cacheWrite + cacheRead
+	if tokens == 0 {
+		return usageSample{}, false
+	}
+	return usageSample{
+		at:     ts,
+		tokens: tokens,
+		cost:   EstimateCost(extractStringField(line, `"model":"`), input, output, cacheWrite, cacheRead),
+	}, true
+}
@@ -0,0 +1,99 @@
+package session
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		model string
+		want  float64
+	}{
+		{"claude-opus-4-1", 15 + 75},
+		{"claude-sonnet-4-5", 3 + 15},
+		{"claude-haiku-4-5", 1 + 5},
+		{"something-else", 3 + 15},
+	}
+	for _, tt := range tests {
+		got := EstimateCost(tt.model, 1_000_000, 1_000_000, 0, 0)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("EstimateCost(%q) = %v, want %v", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestRateMeter(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	line := func(at time.Time, out int) string {
+		return `{"type":"assistant","timestamp":"` + at.Format(time.RFC3339) +
+			`","message":{"model":"claude-sonnet-4-5","usage":{"input_tokens":100,"output_tokens":` +
+			strconv.Itoa(out) + `}}}` + "\n"
+	}
+
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	content := line(now.Add(-10*time.Minute), 900) + line(now.Add(-2*time.Minute), 400)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewRateMeter(5 * time.Minute)
+	r := m.Rate(path, now)
+	if r.Tokens != 500 {
+		t.Fatalf("Tokens = %d, want 500 (only the entry inside the window)", r.Tokens)
+	}
+	if got := r.TokensPerMinute(); got != 100 {
+		t.Errorf("TokensPerMinute = %v, want 100", got)
+	}
+	wantCost := EstimateCost("claude-sonnet-4-5", 100, 400, 0, 0) * 12
+	if got := r.CostPerHour(); math.Abs(got-wantCost) > 1e-9 {
+		t.Errorf("CostPerHour = %v, want %v", got, wantCost)
+	}
+
+	// Appended lines are picked up; a partial line waits for its newline.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := line(now.Add(-time.Minute), 0)
+	f.WriteString(next[:20])
+	if r := m.Rate(path, now); r.Tokens != 500 {
+		t.Errorf("Tokens with partial line = %d, want 500", r.Tokens)
+	}
+	f.WriteString(next[20:])
+	f.Close()
+	if r := m.Rate(path, now); r.Tokens != 600 {
+		t.Errorf("Tokens after append = %d, want 600", r.Tokens)
+	}
+}
+
+func TestBackgroundClassifier(t *testing.T) {
+	const (
+		haiku     = `{"type":"assistant","message":{"model":"claude-haiku-4-5","usage":{"input_tokens":100}}}`
+		opus      = `{"type":"assistant","message":{"model":"claude-opus-4-6","usage":{"input_tokens":100}}}`
+		subagent  = `{"type":"assistant","isSidechain":true,"message":{"model":"claude-haiku-4-5","usage":{"input_tokens":100}}}`
+		synthetic = `{"type":"assistant","message":{"model":"<synthetic>","usage":{"input_tokens":0}}}`
+	)
+	tests := []struct {
+		name  string
+		lines []string
+		want  []bool
+	}{
+		{"title on haiku in an opus session", []string{opus, haiku, opus}, []bool{false, true, false}},
+		{"haiku session", []string{haiku, haiku}, []bool{false, false}},
+		{"haiku subagent", []string{opus, subagent}, []bool{false, false}},
+		{"haiku session with a synthetic reply", []string{haiku, synthetic, haiku}, []bool{false, false, false}},
+	}
+	for _, tt := range tests {
+		var c backgroundClassifier
+		for i, line := range tt.lines {
+			if got := c.background(line); got != tt.want[i] {
+				t.Errorf("%s: line %d background = %v, want %v", tt.name, i, got, tt.want[i])
+			}
+		}
+	}
+}
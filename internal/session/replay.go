@@ -0,0 +1,102 @@
+package session
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// ReplayFrame is what the live view would have shown for a session at one
+// moment of its past: just after a log entry was written, or when the clock
+// alone moved a status on (a pending tool call turning into "Needs Input",
+// say).
+type ReplayFrame struct {
+	Time           time.Time
+	Entry          *LogEntry // nil when only time passed
+	Status         Status
+	Task           string
+	ContextPercent float64
+	ContextTokens  int
+	Model          string
+}
+
+// statusTimers are the delays after an entry at which heuristicStatus can
+// change its mind without anything new being logged.
+var statusTimers = []time.Duration{30 * time.Second, recentActivityWindow, 5 * time.Minute}
+
+// LoadReplay reads a whole session log and reconstructs its history as
+// frames in time order, treating the session as running throughout. Entries
+// without a timestamp are skipped.
+func LoadReplay(logFile string) ([]ReplayFrame, error) {
+	pl, err := parseLogFile(logFile, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]LogEntry, 0, len(pl.entries))
+	for _, e := range pl.entries {
+		if !e.Timestamp.IsZero() {
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+	return replayFrames(entries), nil
+}
+
+func replayFrames(entries []LogEntry) []ReplayFrame {
+	var frames []ReplayFrame
+	for i := range entries {
+		// Look at the same window of recent entries the live view keeps.
+		window := entries[max(0, i+1-recentEntries) : i+1]
+		at := entries[i].Timestamp
+		pct, tokens, model := extractContextUsage(window)
+		frame := ReplayFrame{Entry: &entries[i], ContextPercent: pct, ContextTokens: tokens, Model: model}
+		frame.Time = at
+		frame.Status, frame.Task, _ = statusAt(window, true, at, at)
+		frames = append(frames, frame)
+
+		var next time.Time
+		if i+1 < len(entries) {
+			next = entries[i+1].Timestamp
+		}
+		for _, t := range statusChanges(window, at, next) {
+			status, task, _ := statusAt(window, true, at, t)
+			last := frames[len(frames)-1]
+			if status == last.Status && task == last.Task {
+				continue
+			}
+			frame.Entry = nil
+			frame.Time, frame.Status, frame.Task = t, status, task
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+// statusChanges lists the moments after from, and before until (zero means
+// no limit), at which the status of window could change on its own: one of
+// statusTimers after the latest entry of each kind heuristicStatus looks at.
+func statusChanges(window []LogEntry, from, until time.Time) []time.Time {
+	latest := map[string]time.Time{"": from}
+	for i := len(window) - 1; i >= 0; i-- {
+		kind := window[i].Type
+		if kind == "hook_progress" || kind == "agent_progress" {
+			kind = "progress"
+		}
+		if _, ok := latest[kind]; !ok {
+			latest[kind] = window[i].Timestamp
+		}
+	}
+	seen := make(map[time.Time]bool)
+	var times []time.Time
+	for _, t := range latest {
+		for _, d := range statusTimers {
+			at := t.Add(d)
+			if at.After(from) && (until.IsZero() || at.Before(until)) && !seen[at] {
+				seen[at] = true
+				times = append(times, at)
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times
+}
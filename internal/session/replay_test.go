@@ -0,0 +1,57 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadReplay(t *testing.T) {
+	log := `{"type":"user","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"run the migration"}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:05Z","message":{"role":"assistant","model":"claude-sonnet-4-5","content":[{"type":"tool_use","name":"Bash","input":{"command":"make migrate"}}],"usage":{"input_tokens":10,"cache_read_input_tokens":20000,"output_tokens":50}}}
+{"type":"user","timestamp":"2026-01-02T10:10:00Z","message":{"role":"user","content":[{"type":"tool_result","content":"ok"}]}}
+{"type":"assistant","timestamp":"2026-01-02T10:10:05Z","message":{"role":"assistant","model":"claude-sonnet-4-5","stop_reason":"end_turn","content":[{"type":"text","text":"Done."}],"usage":{"input_tokens":10,"cache_read_input_tokens":40000,"output_tokens":50}}}
+{"type":"file-history-snapshot"}
+`
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	if err := os.WriteFile(path, []byte(log), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	frames, err := LoadReplay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	want := []struct {
+		offset time.Duration
+		entry  bool
+		status Status
+	}{
+		{0, true, StatusWorking},
+		{5 * time.Second, true, StatusWorking},
+		// Nothing logged for two minutes while the tool call is pending: the
+		// live view would have flagged it.
+		{5*time.Second + recentActivityWindow, false, StatusNeedsInput},
+		{10 * time.Minute, true, StatusWorking},
+		{10*time.Minute + 5*time.Second, true, StatusWaiting},
+	}
+	if len(frames) != len(want) {
+		for _, f := range frames {
+			t.Logf("%s %v %s %q", f.Time.Sub(start), f.Entry != nil, f.Status, f.Task)
+		}
+		t.Fatalf("got %d frames, want %d", len(frames), len(want))
+	}
+	for i, w := range want {
+		f := frames[i]
+		if !f.Time.Equal(start.Add(w.offset)) || (f.Entry != nil) != w.entry || f.Status != w.status {
+			t.Errorf("frame %d = %s entry=%v %s, want %s entry=%v %s",
+				i, f.Time.Sub(start), f.Entry != nil, f.Status, w.offset, w.entry, w.status)
+		}
+	}
+	if frames[1].ContextTokens != 20060 || frames[4].ContextTokens != 40060 {
+		t.Errorf("context tokens = %d, %d; want context growth 20060 → 40060",
+			frames[1].ContextTokens, frames[4].ContextTokens)
+	}
+}
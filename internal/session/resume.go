@@ -0,0 +1,104 @@
+package session
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FindLatestSession returns the most recently active session whose project
+// matches query. An exact (case-insensitive) project match wins; otherwise the
+// query must be a substring of exactly one project name, so "api" does not
+// silently pick between "org/api" and "org/api-gateway".
+func FindLatestSession(sessions []Session, query string) (Session, error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return Session{}, fmt.Errorf("empty project name")
+	}
+
+	var exact, partial []Session
+	for _, s := range sessions {
+		name := strings.ToLower(s.Project)
+		switch {
+		case name == q:
+			exact = append(exact, s)
+		case strings.Contains(name, q):
+			partial = append(partial, s)
+		}
+	}
+
+	candidates := exact
+	if len(candidates) == 0 {
+		projects := map[string]bool{}
+		for _, s := range partial {
+			projects[s.Project] = true
+		}
+		if len(projects) > 1 {
+			names := make([]string, 0, len(projects))
+			for name := range projects {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return Session{}, fmt.Errorf("%q matches several projects: %s", query, strings.Join(names, ", "))
+		}
+		candidates = partial
+	}
+	if len(candidates) == 0 {
+		return Session{}, fmt.Errorf("no session found for project %q", query)
+	}
+
+	latest := candidates[0]
+	for _, s := range candidates[1:] {
+		if s.LastActivity.After(latest.LastActivity) {
+			latest = s
+		}
+	}
+	return latest, nil
+}
+
+// FindLogFile returns the log of the session with the given id, searching
+// every profile. A unique prefix of the id is enough, as with git hashes.
+func FindLogFile(id string) (string, error) {
+	id = strings.TrimSuffix(strings.TrimSpace(id), ".jsonl")
+	if id == "" {
+		return "", fmt.Errorf("empty session id")
+	}
+	profiles, err := Profiles()
+	if err != nil {
+		return "", err
+	}
+	var matches []string
+	for _, p := range profiles {
+		logs, _ := filepath.Glob(filepath.Join(p.Dir, "projects", "*", id+"*.jsonl"))
+		for _, l := range logs {
+			if filepath.Base(l) == id+".jsonl" {
+				return l, nil
+			}
+			matches = append(matches, l)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no session found with id %q", id)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("%q matches %d sessions; give more of the id", id, len(matches))
+}
+
+// ResumeCommand returns the shell command that resumes s with the Claude CLI.
+// Claude resolves sessions relative to the working directory, so the command
+// changes into the project first when its path is known.
+func ResumeCommand(s Session) string {
+	cmd := "claude --resume " + s.SessionID
+	if s.CWD == "" {
+		return cmd
+	}
+	return "cd " + shellQuote(s.CWD) + " && " + cmd
+}
+
+// shellQuote single-quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
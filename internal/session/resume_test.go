@@ -0,0 +1,100 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindLatestSession(t *testing.T) {
+	now := time.Now()
+	sessions := []Session{
+		{Project: "org/api", SessionID: "old", LastActivity: now.Add(-2 * time.Hour)},
+		{Project: "org/api", SessionID: "new", LastActivity: now.Add(-time.Minute)},
+		{Project: "org/api-gateway", SessionID: "gw", LastActivity: now},
+		{Project: "org/web", SessionID: "web", LastActivity: now},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantID  string
+		wantErr string
+	}{
+		{"exact match picks most recent", "org/api", "new", ""},
+		{"case insensitive", "ORG/API", "new", ""},
+		{"unique substring", "gateway", "gw", ""},
+		{"ambiguous substring", "api", "", "matches several projects"},
+		{"no match", "nothing", "", "no session found"},
+		{"empty", " ", "", "empty project name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindLatestSession(sessions, tt.query)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.SessionID != tt.wantID {
+				t.Errorf("SessionID = %q, want %q", got.SessionID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestResumeCommand(t *testing.T) {
+	s := Session{SessionID: "abc", CWD: "/home/me/it's here"}
+	want := `cd '/home/me/it'\''s here' && claude --resume abc`
+	if got := ResumeCommand(s); got != want {
+		t.Errorf("ResumeCommand = %q, want %q", got, want)
+	}
+
+	s.CWD = ""
+	if got := ResumeCommand(s); got != "claude --resume abc" {
+		t.Errorf("ResumeCommand without cwd = %q", got)
+	}
+}
+
+func TestFindLogFile(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"-a/3f2a9c01.jsonl", "-a/3f2a9c01-b.jsonl", "-b/3f2b0000.jsonl"} {
+		path := filepath.Join(root, "projects", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	SetProfiles([]Profile{{Name: "default", Dir: root}})
+	t.Cleanup(func() { SetProfiles(nil) })
+
+	tests := []struct {
+		id, want, err string
+	}{
+		{"3f2a9c01", "-a/3f2a9c01.jsonl", ""}, // exact id wins over longer ones
+		{"3f2b", "-b/3f2b0000.jsonl", ""},
+		{"3f2a9c01-b.jsonl", "-a/3f2a9c01-b.jsonl", ""},
+		{"3f2", "", "matches 3 sessions"},
+		{"ffff", "", "no session found"},
+	}
+	for _, tt := range tests {
+		got, err := FindLogFile(tt.id)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("FindLogFile(%q) error = %v, want %q", tt.id, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != filepath.Join(root, "projects", tt.want) {
+			t.Errorf("FindLogFile(%q) = %q, %v", tt.id, got, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// StatusRule overrides the detected status of a running session when the
+// last assistant message matches Pattern, for workflows the built-in
+// heuristics misread (plan mode, custom "please review" prompts, ...).
+type StatusRule struct {
+	Pattern *regexp.Regexp
+	Status  Status
+	// From limits the rule to sessions whose detected status is one of
+	// these. Empty means any status.
+	From []Status
+}
+
+var statusRules []StatusRule
+
+// SetStatusRules installs the rules applied by determineStatus. The first
+// matching rule wins.
+func SetStatusRules(rules []StatusRule) {
+	statusRules = rules
+}
+
+// ParseStatus maps a status name from config to a Status. It accepts the
+// display names ("Needs Input") as well as snake_case ("needs_input").
+func ParseStatus(name string) (Status, error) {
+	key := strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(name))
+	for _, st := range []Status{StatusWorking, StatusNeedsInput, StatusWaiting, StatusIdle, StatusInactive} {
+		if key == strings.ToLower(strings.ReplaceAll(string(st), " ", "")) {
+			return st, nil
+		}
+	}
+	return "", fmt.Errorf("unknown status %q", name)
+}
+
+// NewStatusRule compiles a rule from its config form.
+func NewStatusRule(pattern, status string, from []string) (StatusRule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return StatusRule{}, fmt.Errorf("status rule %q: %w", pattern, err)
+	}
+	rule := StatusRule{Pattern: re}
+	if rule.Status, err = ParseStatus(status); err != nil {
+		return StatusRule{}, fmt.Errorf("status rule %q: %w", pattern, err)
+	}
+	for _, name := range from {
+		st, err := ParseStatus(name)
+		if err != nil {
+			return StatusRule{}, fmt.Errorf("status rule %q: %w", pattern, err)
+		}
+		rule.From = append(rule.From, st)
+	}
+	return rule, nil
+}
+
+// applyStatusRules returns the status of the first rule matching the last
+// assistant text, or status unchanged.
+func applyStatusRules(status Status, entries []LogEntry) Status {
+	if len(statusRules) == 0 {
+		return status
+	}
+	text := lastAssistantText(entries)
+	if text == "" {
+		return status
+	}
+	for _, rule := range statusRules {
+		if len(rule.From) > 0 && !slices.Contains(rule.From, status) {
+			continue
+		}
+		if rule.Pattern.MatchString(text) {
+			return rule.Status
+		}
+	}
+	return status
+}
+
+// lastAssistantText returns the full text of the most recent assistant
+// message that has any, unlike extractLastAssistantMessage which keeps only
+// the first line for display.
+func lastAssistantText(entries []LogEntry) string {
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Type != "assistant" || entry.Message == nil {
+			continue
+		}
+		var parts []string
+		for _, content := range entry.Message.Content {
+			if content.Type == "text" && strings.TrimSpace(content.Text) != "" {
+				parts = append(parts, content.Text)
+			}
+		}
+		if len(parts) > 0 {
+			return strings.Join(parts, "\n")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseStatus(t *testing.T) {
+	for _, name := range []string{"needs_input", "Needs Input", "needs-input", "NEEDSINPUT"} {
+		if got, err := ParseStatus(name); err != nil || got != StatusNeedsInput {
+			t.Errorf("ParseStatus(%q) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := ParseStatus("busy"); err == nil {
+		t.Error("ParseStatus accepted an unknown status")
+	}
+}
+
+func TestStatusRules(t *testing.T) {
+	t.Cleanup(func() { SetStatusRules(nil) })
+
+	review, err := NewStatusRule(`(?i)waiting for your review`, "needs_input", []string{"waiting"})
+	if err != nil {
+		t.Fatalf("NewStatusRule: %v", err)
+	}
+	SetStatusRules([]StatusRule{review})
+
+	old := time.Now().Add(-10 * time.Minute)
+	entries := []LogEntry{{
+		Type:      "assistant",
+		Timestamp: old,
+		Message: &Message{
+			Role:       "assistant",
+			StopReason: "end_turn",
+			Content:    []ContentItem{{Type: "text", Text: "Plan is ready.\nWaiting for your review before I start."}},
+		},
+	}}
+
+	if status, _, _ := determineStatus(entries, true, old); status != StatusNeedsInput {
+		t.Errorf("status = %q, want the rule to turn Waiting into Needs Input", status)
+	}
+	if status, _, _ := determineStatus(entries, false, old); status != StatusInactive {
+		t.Errorf("status = %q, rules must not apply to sessions that aren't running", status)
+	}
+
+	entries[0].Message.Content[0].Text = "All done."
+	if status, _, _ := determineStatus(entries, true, old); status != StatusWaiting {
+		t.Errorf("status = %q, want Waiting when no rule matches", status)
+	}
+
+	if _, err := NewStatusRule(`(`, "working", nil); err == nil {
+		t.Error("NewStatusRule accepted an invalid pattern")
+	}
+}
@@ -2,13 +2,15 @@ package session
 
 import (
 	"bufio"
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,35 +22,76 @@ import (
 type Status string
 
 const (
-	StatusWorking      Status = "Working"
-	StatusNeedsInput   Status = "Needs Input"
-	StatusWaiting      Status = "Waiting"
-	StatusIdle         Status = "Idle"
-	StatusInactive     Status = "Inactive"
+	StatusWorking    Status = "Working"
+	StatusNeedsInput Status = "Needs Input"
+	StatusWaiting    Status = "Waiting"
+	StatusIdle       Status = "Idle"
+	StatusInactive   Status = "Inactive"
 )
 
 // Session represents a Claude Code session
 type Session struct {
-	Project        string    `json:"project"`
-	Status         Status    `json:"status"`
-	LastActivity   time.Time `json:"last_activity"`
-	Task           string    `json:"task"`
-	Summary        string    `json:"summary,omitempty"`
-	LastMessage    string    `json:"last_message,omitempty"`
-	LogFile        string    `json:"log_file"`
-	ProjectPath    string    `json:"-"`                         // Full path to the project directory
-	SessionID      string    `json:"session_id,omitempty"`      // Claude session UUID (log filename stem)
-	Origin         Origin    `json:"origin,omitempty"`          // Where the session was launched from
-	IsGhost        bool      `json:"is_ghost,omitempty"`        // True if process running but log is stale
-	GhostPID       int       `json:"ghost_pid,omitempty"`       // PID of the ghost process (for killing)
-	GitBranch      string    `json:"git_branch,omitempty"`      // Current git branch
-	HasUnsandboxed bool      `json:"has_unsandboxed,omitempty"` // True if any command bypassed sandbox
-	ContextPercent float64   `json:"context_percent,omitempty"` // Percentage of context window used
-	ContextTokens  int       `json:"context_tokens,omitempty"`  // Total input tokens from last usage entry
-	Model          string    `json:"model,omitempty"`           // Model id from the latest assistant usage (e.g. "claude-opus-4-7")
-	SessionTitle   string    `json:"session_title,omitempty"`   // Custom title set by user/Claude
+	Project        string        `json:"project"`
+	Status         Status        `json:"status"`
+	LastActivity   time.Time     `json:"last_activity"`
+	Task           string        `json:"task"`
+	Summary        string        `json:"summary,omitempty"`
+	LastMessage    string        `json:"last_message,omitempty"`
+	LogFile        string        `json:"log_file"`
+	ProjectPath    string        `json:"-"`                         // Full path to the project directory
+	CWD            string        `json:"cwd,omitempty"`             // Working directory recorded in the log (real project path)
+	SessionID      string        `json:"session_id,omitempty"`      // Claude session UUID (log filename stem)
+	Origin         Origin        `json:"origin,omitempty"`          // Where the session was launched from
+	Headless       bool          `json:"headless,omitempty"`        // Run non-interactively: claude -p or the Agent SDK
+	IsGhost        bool          `json:"is_ghost,omitempty"`        // True if process running but log is stale
+	Conflicts      int           `json:"conflicts,omitempty"`       // Other active sessions working in the same git working tree
+	Tmux           *TmuxPane     `json:"tmux,omitempty"`            // tmux pane the Claude process runs in
+	GhostPID       int           `json:"ghost_pid,omitempty"`       // PID of the ghost process (for killing)
+	GitBranch      string        `json:"git_branch,omitempty"`      // Current git branch
+	HasUnsandboxed bool          `json:"has_unsandboxed,omitempty"` // True if any command bypassed sandbox
+	WebAccess      int           `json:"web_access,omitempty"`      // WebSearch/WebFetch calls among the recent entries
+	ContextPercent float64       `json:"context_percent,omitempty"` // Percentage of context window used
+	ContextTokens  int           `json:"context_tokens,omitempty"`  // Total input tokens from last usage entry
+	Model          string        `json:"model,omitempty"`           // Model id from the latest assistant usage (e.g. "claude-opus-4-7")
+	OutputSpeed    float64       `json:"output_speed,omitempty"`    // Output tokens per second of the latest assistant reply
+	ModelSwitches  []ModelSwitch `json:"model_switches,omitempty"`  // Changes of model during the session, oldest first
+	APIErrors      int           `json:"api_errors,omitempty"`      // API errors and retries since the last successful reply
+	LastAPIError   string        `json:"last_api_error,omitempty"`  // The most recent of those errors, e.g. "529 overloaded_error: Overloaded"
+	PendingRequest string        `json:"pending_request,omitempty"` // What a Needs Input session asks to do, e.g. "Bash: make deploy"
+	Hook           *HookRun      `json:"hook,omitempty"`            // A hook still running, or one that failed or blocked since the last prompt
+	PermissionMode string        `json:"permission_mode,omitempty"` // Latest permission mode: "default", "acceptEdits", "plan" or "bypassPermissions"
+	SkippedLines   int           `json:"skipped_lines,omitempty"`   // Log lines that failed to parse; a sign the log format changed
+	Version        string        `json:"version,omitempty"`         // Claude Code version that wrote the latest entry, e.g. "2.0.14"
+	Outdated       bool          `json:"outdated,omitempty"`        // Version is older than the configured minimum
+	Tokens         int           `json:"tokens,omitempty"`          // Tokens used over the whole session, subagents included (input, output and cache)
+	Cost           float64       `json:"cost,omitempty"`            // Estimated USD list-price cost of those tokens
+	OutputTokens   int           `json:"output_tokens,omitempty"`   // Output tokens over the whole session, subagents included
+	LastOutput     int           `json:"last_output,omitempty"`     // Output tokens of the latest reply: the size of the last response
+	OverBudget     bool          `json:"over_budget,omitempty"`     // Tokens or Cost exceed the configured session budget
+	SessionTitle   string        `json:"session_title,omitempty"`   // Custom title set by user/Claude
+	Profile        string        `json:"profile,omitempty"`         // Profile name when several Claude config dirs are monitored
+	Host           string        `json:"host,omitempty"`            // Remote host the session was fetched from; empty for local sessions
+	Unreadable     bool          `json:"unreadable,omitempty"`      // The log didn't answer in time (a slow network filesystem); the rest is as last read
+	StartedAt      time.Time     `json:"started_at,omitzero"`       // Timestamp of the first log entry
+	StatusSince    time.Time     `json:"status_since,omitzero"`     // When Status was first observed; set by long-running watchers, not Discover
 }
 
+// ModelSwitch is a point in a session where replies started coming from a
+// different model, such as a fallback from Opus to Sonnet.
+type ModelSwitch struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	At   time.Time `json:"at"`
+}
+
+// Permission modes a session can run in, as logged on user entries.
+const (
+	PermissionDefault     = "default"
+	PermissionAcceptEdits = "acceptEdits"
+	PermissionPlan        = "plan"
+	PermissionBypass      = "bypassPermissions"
+)
+
 // RunningProcess represents a Claude process with its PID and working directory
 type RunningProcess struct {
 	PID int
@@ -57,18 +100,31 @@ type RunningProcess struct {
 
 // LogEntry represents a single line in the JSONL log
 type LogEntry struct {
-	Type        string    `json:"type"`
-	Subtype     string    `json:"subtype,omitempty"`
-	Timestamp   time.Time `json:"timestamp"`
-	Message     *Message  `json:"message,omitempty"`
-	Summary     string    `json:"summary,omitempty"` // For type: "summary" entries
-	GitBranch   string    `json:"gitBranch,omitempty"`
-	CWD         string    `json:"cwd,omitempty"`         // Working directory of the Claude process
-	CustomTitle string    `json:"customTitle,omitempty"`  // User/Claude-set session title
+	Type           string    `json:"type"`
+	Subtype        string    `json:"subtype,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+	IsSidechain    bool      `json:"isSidechain,omitempty"`    // Subagent entry logged in the parent's file
+	PermissionMode string    `json:"permissionMode,omitempty"` // On user entries: "default", "acceptEdits", "plan" or "bypassPermissions"
+	Message        *Message  `json:"message,omitempty"`
+	Summary        string    `json:"summary,omitempty"` // For type: "summary" entries
+	GitBranch      string    `json:"gitBranch,omitempty"`
+	CWD            string    `json:"cwd,omitempty"`         // Working directory of the Claude process
+	CustomTitle    string    `json:"customTitle,omitempty"` // User/Claude-set session title
+	DurationMs     int64     `json:"durationMs,omitempty"`  // Turn length, on system/turn_duration entries
+	Version        string    `json:"version,omitempty"`     // Claude Code version that wrote the entry
+	Entrypoint     string    `json:"entrypoint,omitempty"`  // How Claude was started: "cli", "sdk-cli" (claude -p), "sdk-ts", ...
+
+	// API failures: system/api_error entries carry the error and retry
+	// state; a reply that gave up is an assistant entry flagged as an error.
+	Error             json.RawMessage `json:"error,omitempty"`
+	RetryAttempt      int             `json:"retryAttempt,omitempty"`
+	MaxRetries        int             `json:"maxRetries,omitempty"`
+	IsAPIErrorMessage bool            `json:"isApiErrorMessage,omitempty"`
 }
 
 // Message represents the message field in a log entry
 type Message struct {
+	ID         string        `json:"id,omitempty"` // API message id, shared by the entries of one streamed reply
 	Role       string        `json:"role,omitempty"`
 	Model      string        `json:"model,omitempty"`
 	Content    []ContentItem `json:"-"`
@@ -169,99 +225,153 @@ type Usage struct {
 
 // ContentItem represents an item in the content array
 type ContentItem struct {
-	Type  string          `json:"type"`
-	Text  string          `json:"text,omitempty"`
-	Name  string          `json:"name,omitempty"`  // For tool_use
-	Input json.RawMessage `json:"input,omitempty"` // For tool_use inputs
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`          // For tool_use
+	Name      string          `json:"name,omitempty"`        // For tool_use
+	Input     json.RawMessage `json:"input,omitempty"`       // For tool_use inputs
+	ToolUseID string          `json:"tool_use_id,omitempty"` // For tool_result: the tool_use it answers
 }
 
 // BashToolInput represents the input for a Bash tool_use entry
 type BashToolInput struct {
 	Command                   string `json:"command"`
+	Description               string `json:"description"`
 	DangerouslyDisableSandbox bool   `json:"dangerouslyDisableSandbox"`
 }
 
-// ClaudeProjectsDir returns the path to the Claude projects directory
-func ClaudeProjectsDir() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("unable to determine home directory: %w", err)
-	}
-	return filepath.Join(home, ".claude", "projects"), nil
+// runningProcess is a running Claude process, its real working directory and
+// the session logs it holds open.
+type runningProcess struct {
+	PID  int
+	CWD  string
+	Logs []string // open .jsonl files; empty when the log isn't held open
 }
 
-// getRunningClaudeDirs returns a map of encoded directory names to PIDs where Claude processes are running
+// getRunningClaudeDirs returns a map of encoded directory names to the Claude processes running there
 // The keys are in the same format as the project directory names (e.g., -Users-username-Projects-...)
-// Multiple Claude processes in the same directory are tracked as separate PIDs.
-func getRunningClaudeDirs() map[string][]int {
-	dirs := make(map[string][]int)
+// Multiple Claude processes in the same directory are tracked separately. The
+// encoding is lossy (foo.bar and foo/bar share a key), so each process keeps
+// its real cwd for matching against the logs.
+func getRunningClaudeDirs() map[string][]runningProcess {
+	dirs := make(map[string][]runningProcess)
 
-	// Use ps directly without a shell pipeline to avoid shell injection risks
-	cmd := exec.Command("ps", "ax", "-o", "pid=,comm=")
-	output, err := cmd.Output()
+	pids, err := claudePIDs()
 	if err != nil {
+		setDetectionProblem(err.Error())
 		return dirs
 	}
 
-	// Parse ps output to find claude processes
-	for _, line := range bytes.Split(output, []byte("\n")) {
-		fields := bytes.Fields(line)
-		if len(fields) < 2 {
-			continue
-		}
-		comm := string(fields[len(fields)-1])
-		if !strings.HasSuffix(comm, "claude") {
-			continue
-		}
-
-		pidStr := string(fields[0])
-		pid := 0
-		fmt.Sscanf(pidStr, "%d", &pid)
-		if pid == 0 {
-			continue
-		}
-
-		// Get cwd for each process
-		path, err := getProcessCwd(pid)
-		if err != nil || path == "" {
+	// Get cwd (and open logs) for all of them at once, reusing what earlier
+	// scans found for processes still running
+	procs := cachedProcessFiles(pids)
+	for _, pid := range pids {
+		p, ok := procs[pid]
+		if !ok || p.CWD == "" {
+			slog.Debug("claude process without cwd", "pid", pid)
 			continue
 		}
 		// Convert to encoded format (same as project directory names)
-		encoded := encodeProjectPath(path)
-		dirs[encoded] = append(dirs[encoded], pid)
+		encoded := encodeProjectPath(p.CWD)
+		dirs[encoded] = append(dirs[encoded], p)
 	}
 
+	problem := ""
+	if len(pids) > 0 && len(dirs) == 0 {
+		problem = placementProblem()
+	}
+	setDetectionProblem(problem)
 	return dirs
 }
 
-// getProcessCwd returns the current working directory of a process by PID.
-// On Linux it reads /proc/<pid>/cwd; on Darwin it uses lsof.
+// getProcessFiles returns the current working directory of each process and
+// the session logs (.jsonl files) it has open, keyed by PID. On Linux it reads
+// /proc/<pid>/cwd and /proc/<pid>/fd; on Darwin a single lsof call covers all
+// the processes. Processes that can't be inspected are left out.
 // Note: on Linux, reading /proc/<pid>/cwd requires the caller to be the same
 // user as the target process (or root). If csm runs as a different user,
 // os.Readlink will return a permission error and the process will be skipped.
-func getProcessCwd(pid int) (string, error) {
+func getProcessFiles(pids []int) map[int]runningProcess {
+	procs := make(map[int]runningProcess, len(pids))
+	if len(pids) == 0 {
+		return procs
+	}
 	if runtime.GOOS == "linux" {
-		return os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid))
+		for _, pid := range pids {
+			cwd, err := os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid))
+			if err != nil {
+				slog.Debug("process cwd unreadable", "pid", pid, "err", err)
+				continue
+			}
+			p := runningProcess{PID: pid, CWD: cwd}
+			fdDir := fmt.Sprintf("/proc/%d/fd", pid)
+			fds, _ := os.ReadDir(fdDir)
+			for _, fd := range fds {
+				if target, err := os.Readlink(filepath.Join(fdDir, fd.Name())); err == nil && strings.HasSuffix(target, ".jsonl") {
+					p.Logs = append(p.Logs, target)
+				}
+			}
+			procs[pid] = p
+		}
+		return procs
 	}
 
-	// Darwin: use lsof to find cwd
-	pidStr := fmt.Sprintf("%d", pid)
-	lsofCmd := exec.Command("lsof", "-p", pidStr)
-	lsofOutput, err := lsofCmd.Output()
-	if err != nil {
-		return "", err
+	// Darwin: one lsof for every process, in field output (-F) so names
+	// with spaces parse safely. -n and -P skip DNS and port name lookups for
+	// the sockets listed alongside. lsof exits 1 when some process is gone,
+	// but still reports the others.
+	list := make([]string, len(pids))
+	for i, pid := range pids {
+		list[i] = strconv.Itoa(pid)
 	}
+	out, err := exec.Command("lsof", "-n", "-P", "-p", strings.Join(list, ","), "-Fpfn").Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return fuserProcessFiles(pids)
+	}
+	if err != nil && len(out) == 0 {
+		slog.Debug("lsof failed", "pids", pids, "err", err)
+		return procs
+	}
+	return parseLsofFields(out)
+}
 
-	lines := bytes.Split(lsofOutput, []byte("\n"))
-	for _, l := range lines {
-		if bytes.Contains(l, []byte(" cwd ")) {
-			lFields := bytes.Fields(l)
-			if len(lFields) >= 9 {
-				return string(lFields[len(lFields)-1]), nil
+// parseLsofFields extracts each process's cwd and open .jsonl files from
+// `lsof -Fpfn` output: a "p<pid>" line starts a process, then every file is
+// an "f<fd>" line followed by "n<name>".
+func parseLsofFields(out []byte) map[int]runningProcess {
+	procs := make(map[int]runningProcess)
+	var p *runningProcess
+	fd := ""
+	for _, l := range strings.Split(string(out), "\n") {
+		if l == "" {
+			continue
+		}
+		value := l[1:]
+		switch l[0] {
+		case 'p':
+			if p != nil {
+				procs[p.PID] = *p
+			}
+			p = nil
+			if pid, err := strconv.Atoi(value); err == nil {
+				p = &runningProcess{PID: pid}
+			}
+		case 'f':
+			fd = value
+		case 'n':
+			switch {
+			case p == nil:
+			case fd == "cwd":
+				p.CWD = value
+			case strings.HasSuffix(value, ".jsonl"):
+				p.Logs = append(p.Logs, value)
 			}
 		}
 	}
-	return "", fmt.Errorf("cwd not found in lsof output for pid %d", pid)
+	if p != nil {
+		procs[p.PID] = *p
+	}
+	return procs
 }
 
 // sessionIDFromLogFile returns the session UUID from a log file path.
@@ -289,12 +399,8 @@ func Discover() ([]Session, error) {
 		return cached, nil
 	}
 
-	projectsDir, err := ClaudeProjectsDir()
-	if err != nil {
-		return nil, err
-	}
-
-	entries, err := os.ReadDir(projectsDir)
+	start := time.Now()
+	profiles, err := Profiles()
 	if err != nil {
 		return nil, err
 	}
@@ -302,12 +408,89 @@ func Discover() ([]Session, error) {
 	// Get directories where Claude is currently running (TTL-cached to avoid
 	// spawning ps/lsof on every refresh).
 	runningDirs := cachedRunningClaudeDirs()
+	scanned := time.Since(start)
 
 	var sessions []Session
 	// Track the log files we actually parse this sweep so stale entries can be
 	// evicted from the parse cache afterwards (see pruneParseCache).
 	liveFiles := map[string]struct{}{}
+	reads := newReadBudget()
+	timing := Timing{At: start, ProcessScan: scanned}
+
+	for _, profile := range profiles {
+		found, err := discoverProfile(profile, runningDirs, liveFiles, reads, &timing)
+		if err != nil {
+			slog.Debug("profile skipped", "profile", profile.Name, "dir", profile.Dir, "err", err)
+			// A missing secondary profile shouldn't hide the others.
+			if len(profiles) == 1 {
+				return nil, err
+			}
+			continue
+		}
+		sessions = append(sessions, found...)
+	}
+
+	// Evict parse-cache entries for logs no longer in the active set, keeping the
+	// cache bounded to the current working set over a long-running server.
+	pruneParseCache(liveFiles)
 
+	markConflicts(sessions)
+	markTmuxPanes(sessions)
+	SortSessions(sessions)
+	timing.Total = time.Since(start)
+	recordTiming(timing)
+	slog.Debug("discover", "sessions", len(sessions), "logs", len(liveFiles), "process_scan", scanned.String(),
+		"parse", timing.Parse.String(), "slowest_log", timing.Slowest, "slowest_parse", timing.SlowestParse.String(), "duration", timing.Total.String())
+
+	storeResult(sessions)
+	return sessions, nil
+}
+
+// ActiveSince returns the sessions whose last activity is at or after since,
+// in the same order.
+func ActiveSince(sessions []Session, since time.Time) []Session {
+	var active []Session
+	for _, s := range sessions {
+		if !s.LastActivity.Before(since) {
+			active = append(active, s)
+		}
+	}
+	return active
+}
+
+// SortSessions orders sessions the way Discover returns them: by status
+// priority, then by most recent activity. Callers merging sessions from
+// several sources use it to restore that order.
+func SortSessions(sessions []Session) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		// Priority: Working > NeedsInput > Waiting > Idle > Inactive
+		pi, pj := statusPriority(sessions[i].Status), statusPriority(sessions[j].Status)
+		if pi != pj {
+			return pi < pj
+		}
+		return sessions[i].LastActivity.After(sessions[j].LastActivity)
+	})
+}
+
+// discoverProfile collects active sessions from one profile's projects
+// directory, recording each parsed log in liveFiles and the time reading it
+// took in timing.
+func discoverProfile(profile Profile, runningDirs map[string][]runningProcess, liveFiles map[string]struct{}, reads *readBudget, timing *Timing) ([]Session, error) {
+	projectsDir := filepath.Join(profile.Dir, "projects")
+	entries, err := readWithin(reads, projectsDir, func() ([]os.DirEntry, error) { return os.ReadDir(projectsDir) })
+	if errors.Is(err, errSlowRead) {
+		return keepUnreadable(unreadableProfile(projectsDir), liveFiles), nil
+	}
+	if os.IsNotExist(err) {
+		// Claude hasn't run yet; sessions may still appear (see MissingProjectsDir).
+		slog.Debug("no projects directory", "dir", projectsDir)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []Session
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
@@ -319,47 +502,123 @@ func Discover() ([]Session, error) {
 		}
 
 		projectDir := filepath.Join(projectsDir, entry.Name())
-		pids := runningDirs[entry.Name()]
+		procs := runningDirs[entry.Name()]
 
-		logFiles, err := findActiveLogs(projectDir, len(pids))
+		logFiles, err := readWithin(reads, projectDir, func() ([]string, error) { return findActiveLogs(projectDir, len(procs)) })
+		if errors.Is(err, errSlowRead) {
+			sessions = append(sessions, keepUnreadable(unreadableProject(projectDir), liveFiles)...)
+			continue
+		}
 		if err != nil || len(logFiles) == 0 {
 			continue
 		}
 
+		// Cwds only matter for pairing, and reading them parses the logs.
+		project := decodeProjectName(entry.Name())
+		logCwds := make([]string, len(logFiles))
+		cwdReads := make([]time.Duration, len(logFiles))
+		if len(procs) > 0 {
+			for i, logFile := range logFiles {
+				readStart := time.Now()
+				logCwds[i], _ = readWithin(reads, logFile, func() (string, error) { return logCwd(logFile), nil })
+				cwdReads[i] = time.Since(readStart)
+			}
+		}
+		pids := pairProcesses(logFiles, logCwds, procs)
+		if len(procs) > 0 {
+			slog.Debug("paired processes", "project", entry.Name(), "processes", procs, "logs", logFiles, "log_cwds", logCwds, "pids", pids)
+		}
+
 		for i, logFile := range logFiles {
 			liveFiles[logFile] = struct{}{}
 
-			// Pair each log file with a PID by index (most recent log gets first PID)
 			var sessionPids []int
-			if i < len(pids) {
+			if pids[i] != 0 {
 				sessionPids = []int{pids[i]}
 			}
 
-			session, err := parseSession(entry.Name(), logFile, sessionPids)
-			if err != nil {
+			readStart := time.Now()
+			session, err := readWithin(reads, logFile, func() (Session, error) { return parseSession(entry.Name(), logFile, sessionPids) })
+			timing.addParse(project, cwdReads[i]+time.Since(readStart))
+			if errors.Is(err, errSlowRead) {
+				session = unreadableSession(entry.Name(), logFile, sessionPids)
+			} else if err != nil {
 				continue
 			}
 
+			session.Profile = profileTag(profile)
 			sessions = append(sessions, session)
 		}
 	}
+	return sessions, nil
+}
 
-	// Evict parse-cache entries for logs no longer in the active set, keeping the
-	// cache bounded to the current working set over a long-running server.
-	pruneParseCache(liveFiles)
+// keepUnreadable records the logs of sessions listed from what was last known
+// in liveFiles, so their last known state isn't pruned while they don't
+// answer.
+func keepUnreadable(sessions []Session, liveFiles map[string]struct{}) []Session {
+	for _, s := range sessions {
+		liveFiles[s.LogFile] = struct{}{}
+	}
+	return sessions
+}
 
-	// Sort by status priority, then by last activity
-	sort.Slice(sessions, func(i, j int) bool {
-		// Priority: Working > NeedsInput > Waiting > Idle > Inactive
-		pi, pj := statusPriority(sessions[i].Status), statusPriority(sessions[j].Status)
-		if pi != pj {
-			return pi < pj
+// logCwd returns the working directory recorded in a session log, or "" if
+// the log doesn't carry one yet (e.g. a session that just started). The parse
+// is cached, so parseSession reuses it.
+func logCwd(logFile string) string {
+	info, err := os.Stat(logFile)
+	if err != nil {
+		return ""
+	}
+	pl, err := cachedParseLogFile(logFile, info.ModTime(), info.Size(), recentEntries)
+	if err != nil {
+		return ""
+	}
+	return pl.cwd
+}
+
+// pairProcesses assigns running processes to log files, returning the PID for
+// each log (0 for none). Logs are ordered most recent first, with their cwds
+// in logCwds. A process that holds a log open pairs with it first, which tells
+// apart several sessions in one directory. Next a process pairs with a log
+// from its own cwd, so projects whose paths encode to the same directory name
+// don't steal each other's PIDs. Logs left over then take the remaining
+// processes in order: a log without a cwd (just started) takes any, a log
+// with one only takes a process whose cwd no log claims.
+func pairProcesses(logFiles, logCwds []string, procs []runningProcess) []int {
+	pids := make([]int, len(logCwds))
+	used := make([]bool, len(procs))
+	claimed := make(map[string]bool, len(logCwds))
+	for _, cwd := range logCwds {
+		claimed[cwd] = true
+	}
+
+	take := func(match func(runningProcess) bool) int {
+		for j, p := range procs {
+			if !used[j] && match(p) {
+				used[j] = true
+				return p.PID
+			}
 		}
-		return sessions[i].LastActivity.After(sessions[j].LastActivity)
-	})
+		return 0
+	}
 
-	storeResult(sessions)
-	return sessions, nil
+	for i, logFile := range logFiles {
+		pids[i] = take(func(p runningProcess) bool { return slices.Contains(p.Logs, logFile) })
+	}
+	for i, cwd := range logCwds {
+		if cwd != "" && pids[i] == 0 {
+			pids[i] = take(func(p runningProcess) bool { return p.CWD == cwd })
+		}
+	}
+	for i, cwd := range logCwds {
+		if pids[i] != 0 {
+			continue
+		}
+		pids[i] = take(func(p runningProcess) bool { return cwd == "" || !claimed[p.CWD] })
+	}
+	return pids
 }
 
 // statusPriority returns the sort priority for a status (lower = higher priority)
@@ -380,61 +639,6 @@ func statusPriority(s Status) int {
 	}
 }
 
-// findMostRecentLog finds the most recently modified .jsonl file in a directory
-func findMostRecentLog(dir string) (string, error) {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return "", err
-	}
-
-	// Track both most recent non-empty and most recent overall
-	var mostRecent string
-	var mostRecentTime time.Time
-	var newestOverall string
-	var newestOverallTime time.Time
-
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		if !strings.HasSuffix(entry.Name(), ".jsonl") {
-			continue
-		}
-
-		// Skip agent files (subagents) - only track main sessions
-		if strings.HasPrefix(entry.Name(), "agent-") {
-			continue
-		}
-
-		filePath := filepath.Join(dir, entry.Name())
-		info, err := entry.Info()
-		if err != nil {
-			continue
-		}
-
-		// Track newest file regardless of size (for detecting fresh sessions)
-		if info.ModTime().After(newestOverallTime) {
-			newestOverallTime = info.ModTime()
-			newestOverall = filePath
-		}
-
-		// Track newest non-empty file
-		if info.Size() > 0 && info.ModTime().After(mostRecentTime) {
-			mostRecentTime = info.ModTime()
-			mostRecent = filePath
-		}
-	}
-
-	// If there's a newer empty file, a fresh session just started;
-	// return it so parseSession sees 0 entries and shows "-" context
-	if newestOverall != mostRecent && newestOverallTime.After(mostRecentTime) {
-		return newestOverall, nil
-	}
-
-	return mostRecent, nil
-}
-
 // findActiveLogs returns all active JSONL log files for a project directory.
 // If runningCount > 0, returns at least that many files (the most recently modified),
 // plus any additional files modified within the last 5 minutes.
@@ -520,6 +724,22 @@ func findActiveLogs(dir string, runningCount int) ([]string, error) {
 	return result, nil
 }
 
+// recentEntries is how many of a log's last entries are kept for status,
+// usage and message extraction.
+const recentEntries = 100
+
+// dormantAge is how long a log without a running process must have been
+// untouched before discovery stops reading it. Such a session can only be
+// Inactive, and its row needs little more than the file's stat. A var so
+// tests can change it.
+var dormantAge = time.Hour
+
+// isHeadless reports whether a log's entrypoint is a non-interactive run:
+// `claude -p` logs "sdk-cli", the Agent SDKs "sdk-ts" and "sdk-py".
+func isHeadless(entrypoint string) bool {
+	return strings.HasPrefix(entrypoint, "sdk-")
+}
+
 // parsedLog holds everything a single pass over a JSONL log file yields.
 // These fields only change when the file itself changes, so they are safe to
 // cache against the file's (modTime, size); the time-relative status is derived
@@ -532,12 +752,28 @@ type parsedLog struct {
 	lastMessage    string
 	gitBranch      string
 	hasUnsandboxed bool
+	webAccess      int
 	contextPercent float64
 	contextTokens  int
 	model          string
+	outputSpeed    float64
+	modelSwitches  []ModelSwitch
+	apiErrors      int
+	lastAPIError   string
+	hook           *HookRun
+	permissionMode string
+	version        string
+	entrypoint     string
+	tokens         int
+	cost           float64
+	outputTokens   int
+	lastOutput     int
+	skipped        skippedLines
 	// lastEntryTime is the most recent non-zero entry timestamp, used as
 	// LastActivity when present (falls back to file modTime otherwise).
 	lastEntryTime time.Time
+	// firstEntryTime is the earliest entry timestamp: when the session started.
+	firstEntryTime time.Time
 }
 
 // parseLogFile scans a JSONL log file exactly once and extracts every field the
@@ -556,6 +792,8 @@ func parseLogFile(logFile string, keep int) (parsedLog, error) {
 
 	var pl parsedLog
 	var entries []LogEntry
+	var lastModel string
+	var lastReplyID string
 
 	scanner := bufio.NewScanner(file)
 	buf := make([]byte, 0, 64*1024)
@@ -591,8 +829,65 @@ func parseLogFile(logFile string, keep int) (parsedLog, error) {
 
 		var entry LogEntry
 		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			pl.skipped.add(line, err)
 			continue
 		}
+		if pl.firstEntryTime.IsZero() && !entry.Timestamp.IsZero() {
+			pl.firstEntryTime = entry.Timestamp
+		}
+		// Model switches are tracked over the whole file, not just the kept
+		// entries, so an early fallback isn't forgotten.
+		if model := replyModel(entry); model != "" {
+			if lastModel != "" && model != lastModel {
+				pl.modelSwitches = append(pl.modelSwitches, ModelSwitch{From: lastModel, To: model, At: entry.Timestamp})
+			}
+			lastModel = model
+			// A successful reply clears earlier API trouble.
+			pl.apiErrors, pl.lastAPIError = 0, ""
+		}
+		if desc, ok := apiError(entry); ok {
+			pl.apiErrors++
+			pl.lastAPIError = desc
+		}
+		// A running hook is only interesting until something else is logged;
+		// a failure until the next prompt.
+		if h, ok := hookActivity(line, entry); ok {
+			pl.hook = h
+		} else if pl.hook != nil && pl.hook.Running {
+			pl.hook = nil
+		}
+		if entry.Type == "user" && !entry.IsSidechain && isUserPrompt(&entry) {
+			pl.hook = nil
+		}
+		if entry.PermissionMode != "" && !entry.IsSidechain {
+			pl.permissionMode = entry.PermissionMode
+		}
+		// A resumed session may run on a newer CLI; the latest entry counts.
+		if entry.Version != "" {
+			pl.version = entry.Version
+		}
+		if pl.entrypoint == "" {
+			pl.entrypoint = entry.Entrypoint
+		}
+		// Counted like the daily usage totals, so budgets agree with them.
+		if entry.Message != nil && entry.Message.Usage != nil {
+			u := entry.Message.Usage
+			pl.tokens += u.InputTokens + u.OutputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+			pl.cost += EstimateCost(entry.Message.Model, u.InputTokens, u.OutputTokens, u.CacheCreationInputTokens, u.CacheReadInputTokens)
+
+			// A streamed reply is logged as several entries of one message,
+			// each with the usage so far; its output is counted once.
+			if entry.IsSidechain {
+				pl.outputTokens += u.OutputTokens
+			} else {
+				if entry.Message.ID == "" || entry.Message.ID != lastReplyID {
+					pl.lastOutput = 0
+				}
+				pl.outputTokens += max(u.OutputTokens-pl.lastOutput, 0)
+				pl.lastOutput = max(pl.lastOutput, u.OutputTokens)
+				lastReplyID = entry.Message.ID
+			}
+		}
 		entries = append(entries, entry)
 	}
 
@@ -606,7 +901,9 @@ func parseLogFile(logFile string, keep int) (parsedLog, error) {
 	pl.lastMessage = extractLastAssistantMessage(entries)
 	pl.gitBranch = extractGitBranch(entries)
 	pl.hasUnsandboxed = detectUnsandboxedCommands(entries)
+	pl.webAccess = countWebAccess(entries)
 	pl.contextPercent, pl.contextTokens, pl.model = extractContextUsage(entries)
+	pl.outputSpeed = extractOutputSpeed(entries)
 	for i := len(entries) - 1; i >= 0; i-- {
 		if !entries[i].Timestamp.IsZero() {
 			pl.lastEntryTime = entries[i].Timestamp
@@ -614,7 +911,25 @@ func parseLogFile(logFile string, keep int) (parsedLog, error) {
 		}
 	}
 
-	return pl, scanner.Err()
+	if pl.skipped.count > 0 {
+		slog.Debug("skipped unparsable lines", "file", logFile, "lines", pl.skipped.count, "samples", pl.skipped.samples)
+	}
+	if err := scanner.Err(); err != nil {
+		slog.Debug("log read stopped", "file", logFile, "entries", len(entries), "err", err)
+		return pl, err
+	}
+	return pl, nil
+}
+
+// replyModel returns the model that wrote an assistant entry of the main
+// conversation. Subagents may run on other models, and placeholders such as
+// "<synthetic>" mark locally generated replies, so both give "".
+func replyModel(entry LogEntry) string {
+	if entry.Type != "assistant" || entry.IsSidechain || entry.Message == nil ||
+		strings.HasPrefix(entry.Message.Model, "<") {
+		return ""
+	}
+	return entry.Message.Model
 }
 
 // parseSession parses a session from its log file
@@ -654,9 +969,29 @@ func parseSession(projectName, logFile string, pids []int) (Session, error) {
 	}
 	session.LastActivity = info.ModTime()
 
+	// A dormant session is listed from its stat and the head of its log,
+	// unless an earlier refresh already parsed it.
+	if !isRunning && time.Since(info.ModTime()) > dormantAge {
+		if pl, ok := peekParseCache(logFile, info.ModTime(), info.Size()); ok && len(pl.entries) > 0 {
+			applyParsedLog(&session, pl, false, 0, info.ModTime())
+			return session, nil
+		}
+		firstCwd, entrypoint := cachedLogHead(logFile, info.ModTime(), info.Size())
+		session.Headless = isHeadless(entrypoint)
+		cwd := indexProjectPath(filepath.Dir(logFile), session.SessionID)
+		if cwd == "" {
+			cwd = firstCwd
+		}
+		if cwd != "" {
+			session.Project = extractProjectName(cwd)
+			session.CWD = cwd
+		}
+		return session, nil
+	}
+
 	// Fetch the parsed log (single full-file pass), reusing the cache when the
 	// file is unchanged since it was last parsed.
-	pl, err := cachedParseLogFile(logFile, info.ModTime(), info.Size(), 100)
+	pl, err := cachedParseLogFile(logFile, info.ModTime(), info.Size(), recentEntries)
 	if err != nil {
 		return session, nil // Return with defaults
 	}
@@ -674,8 +1009,15 @@ func parseSession(projectName, logFile string, pids []int) (Session, error) {
 // on every call because they depend on wall-clock time and the running-process
 // set, both of which change without the file changing.
 func applyParsedLog(session *Session, pl parsedLog, isRunning bool, pid int, fileModTime time.Time) {
-	if pl.cwd != "" {
-		session.Project = extractProjectName(pl.cwd)
+	// The real path gives the project name; decodeProjectName's guess from
+	// the lossy encoded directory name is only the last resort.
+	cwd := pl.cwd
+	if cwd == "" {
+		cwd = indexProjectPath(filepath.Dir(session.LogFile), session.SessionID)
+	}
+	if cwd != "" {
+		session.Project = extractProjectName(cwd)
+		session.CWD = cwd
 	}
 	if pl.title != "" {
 		session.SessionTitle = pl.title
@@ -684,12 +1026,44 @@ func applyParsedLog(session *Session, pl parsedLog, isRunning bool, pid int, fil
 	session.LastMessage = pl.lastMessage
 	session.GitBranch = pl.gitBranch
 	session.HasUnsandboxed = pl.hasUnsandboxed
+	session.WebAccess = pl.webAccess
 	session.ContextPercent = pl.contextPercent
 	session.ContextTokens = pl.contextTokens
 	session.Model = pl.model
+	session.OutputSpeed = pl.outputSpeed
+	session.ModelSwitches = pl.modelSwitches
+	session.APIErrors = pl.apiErrors
+	session.LastAPIError = pl.lastAPIError
+	session.PermissionMode = pl.permissionMode
+	session.SkippedLines = pl.skipped.count
+	session.Version = pl.version
+	session.Headless = isHeadless(pl.entrypoint)
+	session.Outdated = versionOutdated(pl.version)
+	session.Tokens = pl.tokens
+	session.Cost = pl.cost
+	session.OutputTokens = pl.outputTokens
+	session.LastOutput = pl.lastOutput
+	session.OverBudget = overSessionBudget(pl.tokens, pl.cost)
+	session.StartedAt = pl.firstEntryTime
 
 	// Time-relative + running-dependent: must be recomputed each call.
 	session.Status, session.Task, session.IsGhost = determineStatus(pl.entries, isRunning, fileModTime)
+	session.PendingRequest = ""
+	if session.Status == StatusNeedsInput {
+		if c := pendingToolUse(pl.entries); c != nil {
+			session.PendingRequest = describeToolUse(*c)
+		}
+	}
+
+	// A hook can only still be running while Claude is.
+	session.Hook = pl.hook
+	if session.Hook != nil && session.Hook.Running {
+		if !isRunning {
+			session.Hook = nil
+		} else if session.Status == StatusWorking {
+			session.Task = "Hook: " + session.Hook.Label()
+		}
+	}
 
 	if isRunning && pid > 0 {
 		session.GhostPID = pid
@@ -748,29 +1122,55 @@ func detectUnsandboxedCommands(entries []LogEntry) bool {
 			continue
 		}
 		for _, content := range entry.Message.Content {
-			if content.Type == "tool_use" && content.Name == "Bash" && len(content.Input) > 0 {
-				var input BashToolInput
-				if json.Unmarshal(content.Input, &input) == nil {
-					if input.DangerouslyDisableSandbox {
-						return true
-					}
-				}
+			if input, ok := bashInput(content); ok && input.DangerouslyDisableSandbox {
+				return true
 			}
 		}
 	}
 	return false
 }
 
+// webTools are the built-in tools that reach the open internet.
+var webTools = map[string]bool{
+	"WebSearch": true,
+	"WebFetch":  true,
+}
+
+// countWebAccess counts the WebSearch and WebFetch calls in entries, subagent
+// calls included.
+func countWebAccess(entries []LogEntry) int {
+	n := 0
+	for _, entry := range entries {
+		if entry.Type != "assistant" || entry.Message == nil {
+			continue
+		}
+		for _, c := range entry.Message.Content {
+			if c.Type == "tool_use" && webTools[c.Name] {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// bashInput decodes the input of a Bash tool call.
+func bashInput(c ContentItem) (BashToolInput, bool) {
+	var input BashToolInput
+	if c.Type != "tool_use" || c.Name != "Bash" || len(c.Input) == 0 || json.Unmarshal(c.Input, &input) != nil {
+		return BashToolInput{}, false
+	}
+	return input, true
+}
+
 // extractContextUsage extracts context usage from the last assistant entry with usage data.
 // Returns the percentage of context window used, total input tokens, and the model id.
-// Only considers entries after the most recent compact/microcompact boundary,
-// since context is reset during compaction.
+// Only considers entries after the most recent context reset (compaction or
+// /clear), since the usage before it no longer applies.
 func extractContextUsage(entries []LogEntry) (float64, int, string) {
-	// Find the most recent compact/microcompact boundary
+	// Find the most recent reset
 	lastBoundaryIdx := -1
 	for i := len(entries) - 1; i >= 0; i-- {
-		if entries[i].Type == "system" &&
-			(entries[i].Subtype == "compact_boundary" || entries[i].Subtype == "microcompact_boundary") {
+		if resetsContext(entries[i]) {
 			lastBoundaryIdx = i
 			break
 		}
@@ -793,14 +1193,65 @@ func extractContextUsage(entries []LogEntry) (float64, int, string) {
 			continue
 		}
 
-		window := contextWindowForModel(entry.Message.Model)
-		percent := float64(totalTokens) / float64(window) * 100
+		percent := float64(totalTokens) / float64(contextLimit(entry.Message.Model)) * 100
 		return percent, totalTokens, entry.Message.Model
 	}
 
 	return 0, 0, ""
 }
 
+// resetsContext reports whether entry empties the context window: a
+// compact/microcompact boundary, or the user running /clear. Claude Code
+// usually starts a new log on /clear, which begins with usage 0 anyway, but
+// the command is also logged into the conversation it clears.
+func resetsContext(entry LogEntry) bool {
+	switch {
+	case entry.Type == "system":
+		return entry.Subtype == "compact_boundary" || entry.Subtype == "microcompact_boundary"
+	case entry.Type == "user" && !entry.IsSidechain && entry.Message != nil:
+		for _, c := range entry.Message.Content {
+			if c.Type == "text" && strings.Contains(c.Text, "<command-name>/clear</command-name>") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractOutputSpeed returns the output tokens per second of the latest
+// assistant reply with usage data: its output tokens over the time since the
+// entry before it, which includes the wait for the first token. A streamed
+// reply is logged as several entries with the same message id, so the gap is
+// measured from before the first of them. Returns 0 when there is no usable
+// gap.
+func extractOutputSpeed(entries []LogEntry) float64 {
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Type != "assistant" || entry.Message == nil || entry.Message.Usage == nil ||
+			entry.Message.Usage.OutputTokens == 0 {
+			continue
+		}
+		j := i - 1
+		for ; j >= 0; j-- {
+			prev := entries[j]
+			sameReply := prev.Type == "assistant" && prev.Message != nil &&
+				entry.Message.ID != "" && prev.Message.ID == entry.Message.ID
+			if !sameReply && !prev.Timestamp.IsZero() {
+				break
+			}
+		}
+		if j < 0 {
+			return 0
+		}
+		gap := entry.Timestamp.Sub(entries[j].Timestamp)
+		if gap <= 0 {
+			return 0
+		}
+		return float64(entry.Message.Usage.OutputTokens) / gap.Seconds()
+	}
+	return 0
+}
+
 // decodeProjectName converts the directory name to a readable project name
 func decodeProjectName(name string) string {
 	// Format: -Users-username-Projects-org-project
@@ -847,6 +1298,43 @@ const DefaultContextWindow = 200000
 // generation 4.6 onward.
 const ExtendedContextWindow = 1_000_000
 
+// compactReserve is how much of the window Claude Code keeps free before it
+// auto-compacts: room for the summary it writes (20K output tokens) plus a
+// safety buffer (13K).
+const compactReserve = 33_000
+
+var contextToCompact bool
+
+// SetContextToCompact makes context percentages relative to where Claude
+// Code auto-compacts (the window minus what it reserves for output), as its
+// own status line reports, instead of to the whole window.
+func SetContextToCompact(on bool) {
+	contextToCompact = on
+}
+
+// contextLimit returns the token count that 100% context usage stands for.
+func contextLimit(model string) int {
+	window := contextWindowForModel(model)
+	if contextToCompact {
+		return window - compactReserve
+	}
+	return window
+}
+
+// ShortModelName returns a compact name for a model id, such as "opus-4.6"
+// for "claude-opus-4-6" or "fable-5"; ids it can't parse lose only their
+// "claude-" prefix.
+func ShortModelName(model string) string {
+	family, major, minor, ok := parseClaudeModel(model)
+	if !ok {
+		return strings.TrimPrefix(model, "claude-")
+	}
+	if minor == 0 && major >= 5 {
+		return fmt.Sprintf("%s-%d", family, major)
+	}
+	return fmt.Sprintf("%s-%d.%d", family, major, minor)
+}
+
 // ContextWindowForModel is the exported variant of contextWindowForModel,
 // for use by the UI layer to label the active context window.
 func ContextWindowForModel(model string) int {
@@ -915,8 +1403,26 @@ const recentActivityWindow = 2 * time.Minute
 // determineStatus analyzes log entries to determine session status.
 // fileModTime is the log file's modification time, used to detect recent writes
 // that may not yet appear as parsed entries (e.g., during streaming).
-// Returns: status, task description, and whether this is a ghost process.
+// Configured status rules (SetStatusRules) get the last word for running
+// sessions. Returns: status, task description, and whether this is a ghost
+// process.
 func determineStatus(entries []LogEntry, isRunning bool, fileModTime time.Time) (Status, string, bool) {
+	return statusAt(entries, isRunning, fileModTime, time.Now())
+}
+
+// statusAt is determineStatus as it would have been at the given moment,
+// which lets a replay reconstruct past statuses.
+func statusAt(entries []LogEntry, isRunning bool, fileModTime, now time.Time) (Status, string, bool) {
+	status, task, ghost := heuristicStatus(entries, isRunning, fileModTime, now)
+	if isRunning && len(entries) > 0 {
+		status = applyStatusRules(status, entries)
+	}
+	return status, task, ghost
+}
+
+// heuristicStatus is the built-in status detection, before any configured
+// status rules are applied. Recency is measured against now.
+func heuristicStatus(entries []LogEntry, isRunning bool, fileModTime, now time.Time) (Status, string, bool) {
 	if len(entries) == 0 {
 		if isRunning {
 			// Process running but no log entries - new session starting up
@@ -999,7 +1505,7 @@ func determineStatus(entries []LogEntry, isRunning bool, fileModTime time.Time)
 				// All tools got results - check if turn completed or still working
 				if lastSystem != nil && lastSystem.Timestamp.After(lastUser.Timestamp) {
 					// Turn completed after tool results
-				} else if time.Since(lastUser.Timestamp) < recentActivityWindow {
+				} else if now.Sub(lastUser.Timestamp) < recentActivityWindow {
 					// No turn_duration marker yet, but the tool result is recent —
 					// Claude is very likely still working (about to continue the turn).
 					return StatusWorking, "Processing...", false
@@ -1022,7 +1528,12 @@ func determineStatus(entries []LogEntry, isRunning bool, fileModTime time.Time)
 	// execute without user interaction. A recent pending tool_use likely means
 	// the tool is currently executing, not waiting for approval.
 	if hasPendingToolUse {
-		if lastAssistant != nil && time.Since(lastAssistant.Timestamp) < recentActivityWindow {
+		// Some tools exist only to ask the user something (approve a plan,
+		// answer a question), so they always wait for input.
+		if decisionTools[pendingToolName] {
+			return StatusNeedsInput, "Using: " + pendingToolName, false
+		}
+		if lastAssistant != nil && now.Sub(lastAssistant.Timestamp) < recentActivityWindow {
 			return StatusWorking, "Using: " + pendingToolName, false
 		}
 		return StatusNeedsInput, "Using: " + pendingToolName, false
@@ -1040,7 +1551,7 @@ func determineStatus(entries []LogEntry, isRunning bool, fileModTime time.Time)
 			// must not stay pinned on "Working"; fall through to the staleness
 			// checks below, which resolve it to Waiting.
 			if lastUser != nil && lastUser.Timestamp.After(lastSystem.Timestamp) &&
-				time.Since(lastUser.Timestamp) < recentActivityWindow {
+				now.Sub(lastUser.Timestamp) < recentActivityWindow {
 				return StatusWorking, "Processing...", false
 			}
 			if lastUser == nil || !lastUser.Timestamp.After(lastSystem.Timestamp) {
@@ -1062,14 +1573,14 @@ func determineStatus(entries []LogEntry, isRunning bool, fileModTime time.Time)
 	// Progress heartbeats (progress, hook_progress, agent_progress) indicate
 	// active work: tool execution, hook callbacks, or subagent activity.
 	// A recent heartbeat is a strong signal that the session is working.
-	if lastProgress != nil && time.Since(lastProgress.Timestamp) < recentActivityWindow {
+	if lastProgress != nil && now.Sub(lastProgress.Timestamp) < recentActivityWindow {
 		task := extractTask(lastAssistant)
 		return StatusWorking, task, false
 	}
 
 	// If the log file was recently modified (within 30s), the session is actively
 	// writing — even if parsed entries are stale (e.g., streaming writes in progress).
-	if !fileModTime.IsZero() && time.Since(fileModTime) < 30*time.Second {
+	if !fileModTime.IsZero() && now.Sub(fileModTime) < 30*time.Second {
 		task := extractTask(lastAssistant)
 		return StatusWorking, task, false
 	}
@@ -1077,7 +1588,7 @@ func determineStatus(entries []LogEntry, isRunning bool, fileModTime time.Time)
 	// If process is running but log is stale, it's Waiting (not ghost)
 	// The user may be away or thinking - this is a valid active session
 	// Ghost detection is only for --kill-ghosts to find truly orphaned processes
-	if time.Since(lastTimestamp) > 5*time.Minute {
+	if now.Sub(lastTimestamp) > 5*time.Minute {
 		return StatusWaiting, "-", false
 	}
 
@@ -1085,7 +1596,7 @@ func determineStatus(entries []LogEntry, isRunning bool, fileModTime time.Time)
 	// flipping to "Waiting" during brief gaps between log writes.
 	if lastAssistant != nil {
 		task := extractTask(lastAssistant)
-		if time.Since(lastAssistant.Timestamp) < recentActivityWindow {
+		if now.Sub(lastAssistant.Timestamp) < recentActivityWindow {
 			return StatusWorking, task, false
 		}
 	}
@@ -1099,7 +1610,7 @@ func determineStatus(entries []LogEntry, isRunning bool, fileModTime time.Time)
 	// prompt left unanswered (user walked away, or Claude stalled) must age out to
 	// Waiting instead of staying pinned on "Working".
 	if lastUser != nil && (lastAssistant == nil || lastUser.Timestamp.After(lastAssistant.Timestamp)) {
-		if isUserPrompt(lastUser) && time.Since(lastUser.Timestamp) < recentActivityWindow {
+		if isUserPrompt(lastUser) && now.Sub(lastUser.Timestamp) < recentActivityWindow {
 			return StatusWorking, "Processing...", false
 		}
 	}
@@ -1107,6 +1618,12 @@ func determineStatus(entries []LogEntry, isRunning bool, fileModTime time.Time)
 	return StatusWaiting, "-", false
 }
 
+// decisionTools are tools whose call is a question to the user.
+var decisionTools = map[string]bool{
+	"ExitPlanMode":    true,
+	"AskUserQuestion": true,
+}
+
 // isUserPrompt reports whether a user log entry is a genuine user prompt
 // (carries text) rather than only a tool_result echoed back to Claude. Claude's
 // tool results are recorded as user-role messages, so distinguishing them is
@@ -1152,9 +1669,10 @@ func extractTask(entry *LogEntry) string {
 
 // GhostProcess represents an orphaned Claude process
 type GhostProcess struct {
-	PID     int
-	Project string
-	Age     time.Duration
+	PID       int
+	Project   string
+	SessionID string
+	Age       time.Duration
 }
 
 // FindGhostProcesses returns a list of potentially orphaned Claude processes
@@ -1181,9 +1699,10 @@ func FindGhostProcesses() ([]GhostProcess, error) {
 		age := time.Since(s.LastActivity)
 		if age > time.Hour {
 			ghosts = append(ghosts, GhostProcess{
-				PID:     s.GhostPID,
-				Project: s.Project,
-				Age:     age,
+				PID:       s.GhostPID,
+				Project:   s.Project,
+				SessionID: s.SessionID,
+				Age:       age,
 			})
 		}
 	}
@@ -1194,12 +1713,8 @@ func FindGhostProcesses() ([]GhostProcess, error) {
 // isClaudeProcess checks whether the given PID belongs to a process named "claude".
 // This guards against PID reuse where a stale PID now belongs to an unrelated process.
 func isClaudeProcess(pid int) bool {
-	out, err := exec.Command("ps", "-p", fmt.Sprintf("%d", pid), "-o", "comm=").Output()
-	if err != nil {
-		return false
-	}
-	comm := strings.TrimSpace(string(out))
-	return strings.HasSuffix(comm, "claude")
+	comm, err := processName(pid)
+	return err == nil && strings.HasSuffix(comm, "claude")
 }
 
 // KillGhostProcesses terminates all ghost Claude processes
@@ -1212,29 +1727,29 @@ func KillGhostProcesses() ([]GhostProcess, error) {
 
 	var killed []GhostProcess
 	for _, ghost := range ghosts {
-		// Verify the PID still belongs to a claude process (guards against PID reuse)
-		if !isClaudeProcess(ghost.PID) {
+		if TerminateProcess(ghost.PID) != nil {
+			// Not a claude process anymore, or already gone
 			continue
 		}
-
-		// Send SIGTERM to gracefully terminate the process
-		process, err := os.FindProcess(ghost.PID)
-		if err != nil {
-			continue
-		}
-
-		err = process.Signal(syscall.SIGTERM)
-		if err != nil {
-			// Process might already be gone
-			continue
-		}
-
 		killed = append(killed, ghost)
 	}
 
 	return killed, nil
 }
 
+// TerminateProcess sends SIGTERM to a Claude process. It refuses PIDs that no
+// longer belong to claude, which guards against PID reuse.
+func TerminateProcess(pid int) error {
+	if !isClaudeProcess(pid) {
+		return fmt.Errorf("PID %d is not a claude process", pid)
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Signal(syscall.SIGTERM)
+}
+
 // FormatAge formats a duration as a human-readable age string
 func FormatAge(d time.Duration) string {
 	if d < time.Minute {
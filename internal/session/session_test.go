@@ -2,6 +2,10 @@ package session
 
 import (
 	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 )
@@ -164,6 +168,32 @@ func TestExtractContextUsage(t *testing.T) {
 			wantTokens:     0,
 			wantHasContext: false,
 		},
+		{
+			name: "/clear after last assistant resets context",
+			entries: []LogEntry{
+				{
+					Type: "assistant",
+					Message: &Message{
+						Role:  "assistant",
+						Model: "claude-opus-4-6",
+						Usage: &Usage{
+							InputTokens:          100,
+							CacheReadInputTokens: 800000,
+						},
+					},
+				},
+				{
+					Type: "user",
+					Message: &Message{
+						Role:    "user",
+						Content: []ContentItem{{Type: "text", Text: "<command-name>/clear</command-name>\n<command-message>clear</command-message>\n<command-args></command-args>"}},
+					},
+				},
+			},
+			wantPercent:    0,
+			wantTokens:     0,
+			wantHasContext: false,
+		},
 		{
 			name: "assistant after compact_boundary returns correct usage",
 			entries: []LogEntry{
@@ -272,6 +302,92 @@ func TestExtractContextUsage(t *testing.T) {
 	}
 }
 
+func TestExtractContextUsageToCompact(t *testing.T) {
+	t.Cleanup(func() { SetContextToCompact(false) })
+	entries := []LogEntry{{
+		Type:    "assistant",
+		Message: &Message{Model: "claude-sonnet-4-5", Usage: &Usage{InputTokens: 100, CacheReadInputTokens: 133500}},
+	}}
+
+	if pct, _, _ := extractContextUsage(entries); math.Abs(pct-66.8) > 0.01 {
+		t.Errorf("window percent = %.2f, want 66.8", pct)
+	}
+	// 200K window less 33K reserved: auto-compaction at 167K.
+	SetContextToCompact(true)
+	if pct, _, _ := extractContextUsage(entries); math.Abs(pct-80) > 0.01 {
+		t.Errorf("compact percent = %.2f, want 80", pct)
+	}
+}
+
+func TestExtractOutputSpeed(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	reply := func(id string, at time.Duration, output int) LogEntry {
+		return LogEntry{Type: "assistant", Timestamp: t0.Add(at), Message: &Message{ID: id, Usage: &Usage{OutputTokens: output}}}
+	}
+	tests := []struct {
+		name    string
+		entries []LogEntry
+		want    float64
+	}{
+		{"no entries", nil, 0},
+		{"nothing before the reply", []LogEntry{reply("m1", 0, 100)}, 0},
+		{
+			"gap to the prompt",
+			[]LogEntry{{Type: "user", Timestamp: t0}, reply("m1", 4*time.Second, 200)},
+			50,
+		},
+		{
+			"streamed reply measured from before its first entry",
+			[]LogEntry{
+				{Type: "user", Timestamp: t0},
+				reply("m1", 2*time.Second, 5),
+				reply("m1", 10*time.Second, 400),
+				{Type: "system", Subtype: "turn_duration", Timestamp: t0.Add(11 * time.Second)},
+			},
+			40,
+		},
+		{
+			"latest reply wins",
+			[]LogEntry{
+				{Type: "user", Timestamp: t0},
+				reply("m1", 2*time.Second, 100),
+				{Type: "user", Timestamp: t0.Add(3 * time.Second)},
+				reply("m2", 13*time.Second, 100),
+			},
+			10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractOutputSpeed(tt.entries); got != tt.want {
+				t.Errorf("extractOutputSpeed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountWebAccess(t *testing.T) {
+	call := func(names ...string) LogEntry {
+		var content []ContentItem
+		for _, n := range names {
+			content = append(content, ContentItem{Type: "tool_use", Name: n})
+		}
+		return LogEntry{Type: "assistant", Message: &Message{Content: content}}
+	}
+	entries := []LogEntry{
+		call("WebSearch", "Read"),
+		{Type: "user", Message: &Message{Content: []ContentItem{{Type: "tool_result", Name: "WebSearch"}}}},
+		call("WebFetch", "WebFetch"),
+		call("Bash"),
+	}
+	if got := countWebAccess(entries); got != 3 {
+		t.Errorf("countWebAccess() = %d, want 3", got)
+	}
+	if got := countWebAccess(entries[3:]); got != 0 {
+		t.Errorf("countWebAccess() without web calls = %d, want 0", got)
+	}
+}
+
 func TestContextWindowForModel(t *testing.T) {
 	tests := []struct {
 		model string
@@ -424,6 +540,11 @@ func TestExtractProjectName(t *testing.T) {
 			path: "/opt/myproject",
 			want: "opt/myproject",
 		},
+		{
+			name: "macOS home without a marker keeps dots and dashes",
+			path: "/Users/me/dev.tools/my-app.v2",
+			want: "dev.tools/my-app.v2",
+		},
 	}
 
 	for _, tt := range tests {
@@ -479,6 +600,124 @@ func TestEncodeProjectPath(t *testing.T) {
 	}
 }
 
+func TestFindActiveLogs(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	write := func(name string, age time.Duration) {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, now.Add(-age), now.Add(-age))
+	}
+	// Two terminals in the same repo, an old session and a subagent log,
+	// which is never a session of its own.
+	write("a.jsonl", time.Minute)
+	write("b.jsonl", 2*time.Minute)
+	write("c.jsonl", 3*time.Hour)
+	write("agent-1.jsonl", time.Second)
+
+	base := func(paths []string) []string {
+		var names []string
+		for _, p := range paths {
+			names = append(names, filepath.Base(p))
+		}
+		return names
+	}
+	tests := []struct {
+		running int
+		want    []string
+	}{
+		{0, []string{"a.jsonl"}},
+		{1, []string{"a.jsonl", "b.jsonl"}}, // b was written recently: still shown
+		{2, []string{"a.jsonl", "b.jsonl"}},
+		{3, []string{"a.jsonl", "b.jsonl", "c.jsonl"}},
+	}
+	for _, tt := range tests {
+		logs, err := findActiveLogs(dir, tt.running)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := base(logs); !slices.Equal(got, tt.want) {
+			t.Errorf("findActiveLogs(%d running) = %v, want %v", tt.running, got, tt.want)
+		}
+	}
+}
+
+func TestPairProcesses(t *testing.T) {
+	tests := []struct {
+		name     string
+		logFiles []string
+		logCwds  []string
+		procs    []runningProcess
+		want     []int
+	}{
+		{
+			name:    "colliding paths pair by real cwd",
+			logCwds: []string{"/src/foo/bar", "/src/foo.bar"},
+			procs:   []runningProcess{{PID: 1, CWD: "/src/foo.bar"}, {PID: 2, CWD: "/src/foo/bar"}},
+			want:    []int{2, 1},
+		},
+		{
+			name:    "no process for the other path",
+			logCwds: []string{"/src/foo/bar", "/src/foo.bar"},
+			procs:   []runningProcess{{PID: 1, CWD: "/src/foo.bar"}},
+			want:    []int{0, 1},
+		},
+		{
+			name:    "fresh log without cwd takes a leftover process",
+			logCwds: []string{"", "/src/app"},
+			procs:   []runningProcess{{PID: 1, CWD: "/src/app"}, {PID: 2, CWD: "/src/app"}},
+			want:    []int{2, 1},
+		},
+		{
+			name:    "unclaimed cwd still pairs",
+			logCwds: []string{"/src/app"},
+			procs:   []runningProcess{{PID: 7, CWD: "/src/app/sub"}},
+			want:    []int{7},
+		},
+		{
+			name:     "same cwd pairs by open log",
+			logFiles: []string{"/p/a.jsonl", "/p/b.jsonl"},
+			logCwds:  []string{"/src/app", "/src/app"},
+			procs: []runningProcess{
+				{PID: 1, CWD: "/src/app", Logs: []string{"/p/b.jsonl"}},
+				{PID: 2, CWD: "/src/app", Logs: []string{"/p/a.jsonl"}},
+			},
+			want: []int{2, 1},
+		},
+		{
+			name:     "open log first, the rest by cwd",
+			logFiles: []string{"/p/a.jsonl", "/p/b.jsonl"},
+			logCwds:  []string{"/src/app", "/src/app"},
+			procs: []runningProcess{
+				{PID: 1, CWD: "/src/app"},
+				{PID: 2, CWD: "/src/app", Logs: []string{"/p/b.jsonl"}},
+			},
+			want: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFiles := tt.logFiles
+			if logFiles == nil {
+				logFiles = make([]string, len(tt.logCwds))
+			}
+			got := pairProcesses(logFiles, tt.logCwds, tt.procs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("pairProcesses() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("pairProcesses() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
 func TestUsageJSONParsing(t *testing.T) {
 	// Test that real JSONL usage data parses correctly
 	raw := `{"type":"assistant","message":{"role":"assistant","model":"claude-opus-4-6","content":[{"type":"text","text":"hello"}],"usage":{"input_tokens":10,"cache_creation_input_tokens":1000,"cache_read_input_tokens":19000,"output_tokens":500,"service_tier":"standard"}}}`
@@ -596,6 +835,18 @@ func TestDetermineStatus(t *testing.T) {
 			wantStatus: StatusNeedsInput,
 			wantTask:   "Using: Bash",
 		},
+		{
+			// Plan approval waits on the user from the moment it is asked
+			name: "pending ExitPlanMode recent",
+			entries: []LogEntry{
+				{Type: "assistant", Timestamp: ago(5 * time.Second), Message: &Message{
+					Content: []ContentItem{{Type: "tool_use", Name: "ExitPlanMode"}},
+				}},
+			},
+			isRunning:  true,
+			wantStatus: StatusNeedsInput,
+			wantTask:   "Using: ExitPlanMode",
+		},
 		{
 			name: "tool_use with tool_result and still processing",
 			entries: []LogEntry{
@@ -909,3 +1160,47 @@ func TestDetermineStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLsofFields(t *testing.T) {
+	out := []byte(`p4242
+fcwd
+n/Users/me/repos/my api
+ftxt
+n/usr/local/bin/node
+f21
+n/Users/me/.claude/projects/-Users-me-repos-my-api/abc.jsonl
+f22
+n127.0.0.1:50000->127.0.0.1:443
+p4343
+fcwd
+n/Users/me/repos/web
+`)
+	procs := parseLsofFields(out)
+	if len(procs) != 2 {
+		t.Fatalf("procs = %+v, want 2", procs)
+	}
+	api := procs[4242]
+	if api.PID != 4242 || api.CWD != "/Users/me/repos/my api" {
+		t.Errorf("api = %+v", api)
+	}
+	if want := []string{"/Users/me/.claude/projects/-Users-me-repos-my-api/abc.jsonl"}; !slices.Equal(api.Logs, want) {
+		t.Errorf("logs = %v, want %v", api.Logs, want)
+	}
+	if web := procs[4343]; web.CWD != "/Users/me/repos/web" || len(web.Logs) != 0 {
+		t.Errorf("web = %+v", web)
+	}
+}
+
+func TestActiveSince(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sessions := []Session{
+		{Project: "org/api", LastActivity: now.Add(-10 * time.Minute)},
+		{Project: "org/stale", LastActivity: now.Add(-3 * time.Hour)},
+		{Project: "org/edge", LastActivity: now.Add(-time.Hour)},
+		{Project: "org/unknown"},
+	}
+	got := ActiveSince(sessions, now.Add(-time.Hour))
+	if len(got) != 2 || got[0].Project != "org/api" || got[1].Project != "org/edge" {
+		t.Errorf("ActiveSince = %+v, want org/api and org/edge", got)
+	}
+}
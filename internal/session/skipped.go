@@ -0,0 +1,64 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// maxSkipSamples is how many skipped lines are kept as examples.
+const maxSkipSamples = 3
+
+// skippedLines counts the log lines that failed to parse, keeping the first
+// few as samples. Lines are skipped silently while reading, so without this
+// a change in Claude's log format would only show as sessions stuck in the
+// wrong status.
+type skippedLines struct {
+	count   int
+	samples []string
+}
+
+// add records a line that failed to parse with err.
+func (s *skippedLines) add(line string, err error) {
+	s.count++
+	if len(s.samples) < maxSkipSamples {
+		s.samples = append(s.samples, fmt.Sprintf("%s: %s", err, truncateString(line, 120)))
+	}
+}
+
+// LogParseStats reports how much of a log could be parsed.
+type LogParseStats struct {
+	File    string
+	Lines   int      // non-empty lines
+	Skipped int      // lines that are not valid log entries
+	Samples []string // the first few skipped lines with their parse error
+}
+
+// CheckLogFile parses every line of a log as parseLogFile does and reports
+// the lines it would skip.
+func CheckLogFile(logFile string) (LogParseStats, error) {
+	stats := LogParseStats{File: logFile}
+	file, err := os.Open(logFile)
+	if err != nil {
+		return stats, err
+	}
+	defer file.Close()
+
+	var skipped skippedLines
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		stats.Lines++
+		var entry LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			skipped.add(line, err)
+		}
+	}
+	stats.Skipped, stats.Samples = skipped.count, skipped.samples
+	return stats, scanner.Err()
+}
@@ -0,0 +1,165 @@
+package session
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logs on network filesystems (NFS, SMB home directories) can take seconds
+// to stat or read when the server is slow or gone. Reads of a log therefore
+// run in the background and are waited for only so long; a session whose log
+// doesn't answer in time is listed as Unreadable with what was last known
+// about it, rather than freezing the refresh that asked.
+
+var (
+	// fileReadTimeout is how long one log read is waited for.
+	fileReadTimeout = 2 * time.Second
+	// sweepReadBudget caps the time one Discover waits on slow reads in
+	// total, so a dozen logs on a dead mount don't add up to a dozen
+	// timeouts. Once it is spent, reads get minReadWait each.
+	sweepReadBudget = 3 * time.Second
+	minReadWait     = 50 * time.Millisecond
+)
+
+var (
+	slowReadsMu sync.Mutex
+	slowReads   = map[string]chan struct{}{} // log file → closed when its timed-out read finishes
+)
+
+// readBudget is the time one Discover sweep has left to wait on reads.
+type readBudget struct {
+	deadline time.Time
+}
+
+func newReadBudget() *readBudget {
+	return &readBudget{deadline: time.Now().Add(sweepReadBudget)}
+}
+
+// wait is how long the next read may take.
+func (b *readBudget) wait() time.Duration {
+	if b == nil {
+		return fileReadTimeout
+	}
+	return max(min(fileReadTimeout, time.Until(b.deadline)), minReadWait)
+}
+
+// within runs read, which stats or parses logFile, and reports whether it
+// finished in time. A read that times out keeps running, as a blocked
+// syscall can't be cancelled; until it finishes, further reads of the file
+// report false at once instead of piling up. read must not touch anything
+// the caller uses after a false return.
+func (b *readBudget) within(logFile string, read func()) bool {
+	slowReadsMu.Lock()
+	if _, blocked := slowReads[logFile]; blocked {
+		slowReadsMu.Unlock()
+		return false
+	}
+	slowReadsMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		read()
+		close(done)
+	}()
+	timer := time.NewTimer(b.wait())
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+	}
+
+	slowReadsMu.Lock()
+	slowReads[logFile] = done
+	slowReadsMu.Unlock()
+	go func() {
+		<-done
+		slowReadsMu.Lock()
+		delete(slowReads, logFile)
+		slowReadsMu.Unlock()
+	}()
+	return false
+}
+
+// errSlowRead is returned by readWithin for a read that didn't finish in
+// time.
+var errSlowRead = errors.New("read timed out")
+
+// readWithin runs read under b.within and returns what it returned, or
+// errSlowRead if it didn't finish in time. The result is handed over on a
+// channel the read owns, so one that finishes late writes nothing the caller
+// sees.
+func readWithin[T any](b *readBudget, path string, read func() (T, error)) (T, error) {
+	type readResult struct {
+		v   T
+		err error
+	}
+	res := make(chan readResult, 1)
+	if !b.within(path, func() {
+		v, err := read()
+		res <- readResult{v, err}
+	}) {
+		var zero T
+		return zero, errSlowRead
+	}
+	r := <-res
+	return r.v, r.err
+}
+
+// unreadableSession stands in for a session whose log didn't answer in
+// time: as a Discover last saw it, else all that is known without
+// reading, with a running process taken to be waiting.
+func unreadableSession(projectName, logFile string, pids []int) Session {
+	parseCacheMu.Lock()
+	prev, ok := lastKnown[logFile]
+	parseCacheMu.Unlock()
+	if ok {
+		prev.Unreadable = true
+		return prev
+	}
+
+	s := Session{
+		Project:     decodeProjectName(projectName),
+		LogFile:     logFile,
+		Status:      StatusInactive,
+		ProjectPath: projectName,
+		SessionID:   sessionIDFromLogFile(logFile),
+		Unreadable:  true,
+	}
+	if len(pids) > 0 {
+		s.Status = StatusWaiting
+		s.GhostPID = pids[0]
+	}
+	return s
+}
+
+// unreadableProject stands in for the sessions of a project directory that
+// didn't list in time: those last seen there.
+func unreadableProject(projectDir string) []Session {
+	return lastKnownWhere(func(logFile string) bool { return filepath.Dir(logFile) == projectDir })
+}
+
+// unreadableProfile stands in for the sessions of a projects directory that
+// didn't list in time: those last seen in any of its projects.
+func unreadableProfile(projectsDir string) []Session {
+	prefix := projectsDir + string(filepath.Separator)
+	return lastKnownWhere(func(logFile string) bool { return strings.HasPrefix(logFile, prefix) })
+}
+
+// lastKnownWhere returns the last known sessions whose log matches, marked
+// Unreadable.
+func lastKnownWhere(match func(logFile string) bool) []Session {
+	parseCacheMu.Lock()
+	defer parseCacheMu.Unlock()
+	var sessions []Session
+	for logFile, s := range lastKnown {
+		if match(logFile) {
+			s.Unreadable = true
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions
+}
@@ -0,0 +1,115 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadBudgetWithin(t *testing.T) {
+	defer func(d time.Duration) { fileReadTimeout = d }(fileReadTimeout)
+	fileReadTimeout = 20 * time.Millisecond
+
+	var reads *readBudget
+	if !reads.within("fast.jsonl", func() {}) {
+		t.Fatal("fast read timed out")
+	}
+
+	release := make(chan struct{})
+	start := time.Now()
+	if reads.within("slow.jsonl", func() { <-release }) {
+		t.Fatal("blocked read reported done")
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("waited %v on a blocked read", waited)
+	}
+
+	// Until the blocked read returns, the file isn't read again.
+	ran := false
+	if reads.within("slow.jsonl", func() { ran = true }) || ran {
+		t.Error("read a file whose previous read is still blocked")
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for !reads.within("slow.jsonl", func() {}) {
+		if time.Now().After(deadline) {
+			t.Fatal("file still blocked after its read returned")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestUnreadableSession(t *testing.T) {
+	defer saveLastKnown()()
+	storeResult([]Session{{Project: "web", LogFile: "/p/-home-me-web/a.jsonl", Status: StatusWorking, LastMessage: "building"}})
+	// A new log appearing drops the result cache; what was last known stays.
+	InvalidateCaches()
+
+	if s := unreadableSession("-home-me-web", "/p/-home-me-web/a.jsonl", nil); !s.Unreadable || s.Status != StatusWorking || s.LastMessage != "building" {
+		t.Errorf("known session = %+v, want it as last read", s)
+	}
+	if s := unreadableSession("-home-me-web", "/p/-home-me-web/b.jsonl", []int{42}); !s.Unreadable || s.Status != StatusWaiting || s.GhostPID != 42 || s.SessionID != "b" {
+		t.Errorf("new session = %+v, want a waiting placeholder", s)
+	}
+	if got := unreadableProject("/p/-home-me-web"); len(got) != 1 || !got[0].Unreadable {
+		t.Errorf("unreadableProject = %+v", got)
+	}
+	if got := unreadableProfile("/p"); len(got) != 1 || !got[0].Unreadable {
+		t.Errorf("unreadableProfile = %+v", got)
+	}
+	if got := unreadableProfile("/q"); len(got) != 0 {
+		t.Errorf("unreadableProfile of another profile = %+v", got)
+	}
+}
+
+func TestDiscoverProfileStuckProjectsDir(t *testing.T) {
+	defer saveLastKnown()()
+	dir := t.TempDir()
+	projectsDir := filepath.Join(dir, "projects")
+	logFile := filepath.Join(projectsDir, "-home-me-web", "a.jsonl")
+	storeResult([]Session{{Project: "web", LogFile: logFile, Status: StatusIdle}})
+	InvalidateCaches()
+
+	// An earlier listing of the projects directory is still hanging.
+	slowReadsMu.Lock()
+	slowReads[projectsDir] = make(chan struct{})
+	slowReadsMu.Unlock()
+	defer func() {
+		slowReadsMu.Lock()
+		delete(slowReads, projectsDir)
+		slowReadsMu.Unlock()
+	}()
+
+	liveFiles := map[string]struct{}{}
+	sessions, err := discoverProfile(Profile{Dir: dir}, nil, liveFiles, newReadBudget(), &Timing{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || !sessions[0].Unreadable || sessions[0].Status != StatusIdle {
+		t.Errorf("sessions = %+v, want the last known one, unreadable", sessions)
+	}
+	if _, ok := liveFiles[logFile]; !ok {
+		t.Error("last known log not kept live")
+	}
+}
+
+// saveLastKnown empties the last known sessions and the result cache, and
+// returns a func restoring them.
+func saveLastKnown() func() {
+	parseCacheMu.Lock()
+	saved := lastKnown
+	lastKnown = map[string]Session{}
+	parseCacheMu.Unlock()
+	resultMu.Lock()
+	savedResult, savedAt := result, resultAt
+	resultMu.Unlock()
+	return func() {
+		parseCacheMu.Lock()
+		lastKnown = saved
+		parseCacheMu.Unlock()
+		resultMu.Lock()
+		result, resultAt = savedResult, savedAt
+		resultMu.Unlock()
+	}
+}
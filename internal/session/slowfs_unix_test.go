@@ -0,0 +1,86 @@
+//go:build unix
+
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// A FIFO stands in for a file on a dead mount: opening it for reading blocks
+// until release opens the other end.
+func mkfifo(t *testing.T, path string) (release func()) {
+	t.Helper()
+	if err := syscall.Mkfifo(path, 0o644); err != nil {
+		t.Skipf("mkfifo: %v", err)
+	}
+	released := false
+	release = func() {
+		if released {
+			return
+		}
+		released = true
+		if f, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+			f.Close()
+		}
+	}
+	t.Cleanup(release)
+	return release
+}
+
+// waitUnblocked waits until path's timed-out read has returned.
+func waitUnblocked(t *testing.T, path string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		slowReadsMu.Lock()
+		_, blocked := slowReads[path]
+		slowReadsMu.Unlock()
+		if !blocked {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("read of %s still blocked", path)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDiscoverProfileStuckLog(t *testing.T) {
+	defer func(d time.Duration) { fileReadTimeout = d }(fileReadTimeout)
+	fileReadTimeout = 20 * time.Millisecond
+	defer saveLastKnown()()
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "projects", "-home-me-web")
+	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	logFile := filepath.Join(projectDir, "a.jsonl")
+	release := mkfifo(t, logFile)
+	storeResult([]Session{{Project: "web", LogFile: logFile, Status: StatusWorking, LastMessage: "building"}})
+	InvalidateCaches()
+
+	liveFiles := map[string]struct{}{}
+	sessions, err := discoverProfile(Profile{Dir: dir}, nil, liveFiles, newReadBudget(), &Timing{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Let the parse finish late; the session returned must not change.
+	release()
+	waitUnblocked(t, logFile)
+
+	if len(sessions) != 1 {
+		t.Fatalf("sessions = %+v, want the stuck log's", sessions)
+	}
+	if s := sessions[0]; !s.Unreadable || s.Status != StatusWorking || s.LastMessage != "building" {
+		t.Errorf("stuck log listed as %+v, want it as last known", s)
+	}
+	if _, ok := liveFiles[logFile]; !ok {
+		t.Error("stuck log not kept live")
+	}
+}
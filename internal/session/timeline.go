@@ -12,7 +12,7 @@ import (
 
 // TimelineContent represents a single content block in a timeline entry
 type TimelineContent struct {
-	Type  string `json:"type"`            // text, tool_use, tool_result
+	Type  string `json:"type"` // text, tool_use, tool_result
 	Text  string `json:"text,omitempty"`
 	Tool  string `json:"tool,omitempty"`  // tool name for tool_use
 	Input string `json:"input,omitempty"` // stringified JSON for tool_use
@@ -21,7 +21,7 @@ type TimelineContent struct {
 // TimelineEntry represents a single entry in a session timeline
 type TimelineEntry struct {
 	Timestamp time.Time         `json:"timestamp"`
-	Type      string            `json:"type"`                 // user, assistant, system, summary
+	Type      string            `json:"type"` // user, assistant, system, summary
 	Subtype   string            `json:"subtype,omitempty"`
 	Model     string            `json:"model,omitempty"`
 	Content   []TimelineContent `json:"content,omitempty"`
@@ -32,26 +32,35 @@ type TimelineEntry struct {
 
 // SessionMetrics contains aggregated metrics for a session log file
 type SessionMetrics struct {
-	TotalInputTokens         int            `json:"total_input_tokens"`
-	TotalOutputTokens        int            `json:"total_output_tokens"`
-	TotalCacheCreationTokens int            `json:"total_cache_creation_tokens"`
-	TotalCacheReadTokens     int            `json:"total_cache_read_tokens"`
-	ToolUsageCounts          map[string]int `json:"tool_usage_counts"`
-	UserPromptCount          int            `json:"user_prompt_count"`
-	ToolResultCount          int            `json:"tool_result_count"`
-	AssistantMessageCount    int            `json:"assistant_message_count"`
-	TurnCount                int            `json:"turn_count"`
-	CompactCount             int            `json:"compact_count"`
-	ContextPercent           float64        `json:"context_percent"`
-	ContextTokens            int            `json:"context_tokens"`
-	FirstTimestamp           time.Time      `json:"first_timestamp"`
-	LastTimestamp             time.Time      `json:"last_timestamp"`
+	TotalInputTokens         int              `json:"total_input_tokens"`
+	TotalOutputTokens        int              `json:"total_output_tokens"`
+	TotalCacheCreationTokens int              `json:"total_cache_creation_tokens"`
+	TotalCacheReadTokens     int              `json:"total_cache_read_tokens"`
+	ToolUsageCounts          map[string]int   `json:"tool_usage_counts"`
+	UserPromptCount          int              `json:"user_prompt_count"`
+	ToolResultCount          int              `json:"tool_result_count"`
+	AssistantMessageCount    int              `json:"assistant_message_count"`
+	TurnCount                int              `json:"turn_count"`
+	AvgTurnDuration          time.Duration    `json:"avg_turn_duration"`     // nanoseconds
+	LongestTurnDuration      time.Duration    `json:"longest_turn_duration"` // nanoseconds
+	CompactCount             int              `json:"compact_count"`
+	ContextPercent           float64          `json:"context_percent"`
+	ContextTokens            int              `json:"context_tokens"`
+	FirstTimestamp           time.Time        `json:"first_timestamp"`
+	LastTimestamp            time.Time        `json:"last_timestamp"`
+	APIErrorCount            int              `json:"api_error_count"`
+	LastAPIError             string           `json:"last_api_error,omitempty"`
+	LastAPIErrorAt           time.Time        `json:"last_api_error_at,omitzero"`
+	Version                  string           `json:"version,omitempty"`  // Claude Code version of the latest entry
+	Outdated                 bool             `json:"outdated,omitempty"` // Version is older than the configured minimum
+	Config                   ConfigFiles      `json:"config"`             // profile, settings and CLAUDE.md files in effect
+	ContextBreakdown         ContextBreakdown `json:"context_breakdown"`  // the latest context by token type
 }
 
-// ValidateLogFilePath checks that a log file path is under the Claude projects
-// directory and ends with .jsonl. Returns an error if the path is invalid.
+// ValidateLogFilePath checks that a log file path is under a monitored Claude
+// projects directory and ends with .jsonl. Returns an error if the path is invalid.
 func ValidateLogFilePath(filePath string) error {
-	projectsDir, err := ClaudeProjectsDir()
+	profiles, err := Profiles()
 	if err != nil {
 		return fmt.Errorf("cannot determine projects directory: %w", err)
 	}
@@ -67,20 +76,21 @@ func ValidateLogFilePath(filePath string) error {
 		return fmt.Errorf("invalid path: %w", err)
 	}
 
-	realProjectsDir, err := filepath.EvalSymlinks(projectsDir)
-	if err != nil {
-		return fmt.Errorf("cannot resolve projects directory: %w", err)
-	}
-
-	if !strings.HasPrefix(realPath, realProjectsDir+string(filepath.Separator)) {
-		return fmt.Errorf("path is not under Claude projects directory")
-	}
-
 	if !strings.HasSuffix(realPath, ".jsonl") {
 		return fmt.Errorf("path must end with .jsonl")
 	}
 
-	return nil
+	for _, profile := range profiles {
+		realProjectsDir, err := filepath.EvalSymlinks(filepath.Join(profile.Dir, "projects"))
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(realPath, realProjectsDir+string(filepath.Separator)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("path is not under Claude projects directory")
 }
 
 // ParseTimeline reads a JSONL log file and returns paginated timeline entries.
@@ -171,6 +181,10 @@ func parseMetricsInternal(logFile string) (*SessionMetrics, error) {
 
 	var lastUsage *Usage
 	var lastUsageModel string
+	var lastPrompt time.Time
+	var totalTurns time.Duration
+	cwd := ""
+	var nested []string
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -193,12 +207,34 @@ func parseMetricsInternal(logFile string) (*SessionMetrics, error) {
 			}
 		}
 
+		if entry.Version != "" {
+			m.Version = entry.Version
+		}
+		if cwd == "" {
+			cwd = entry.CWD
+		}
+		if path := nestedMemoryPath(line); path != "" {
+			nested = append(nested, path)
+		}
+
+		if resetsContext(entry) {
+			lastUsage = nil
+			lastUsageModel = ""
+		}
+
+		if desc, ok := apiError(entry); ok {
+			m.APIErrorCount++
+			m.LastAPIError = desc
+			m.LastAPIErrorAt = entry.Timestamp
+		}
+
 		switch entry.Type {
 		case "user":
 			if entry.Message != nil && hasToolResult(entry.Message.Content) {
 				m.ToolResultCount++
 			} else {
 				m.UserPromptCount++
+				lastPrompt = entry.Timestamp
 			}
 
 		case "assistant":
@@ -227,11 +263,14 @@ func parseMetricsInternal(logFile string) (*SessionMetrics, error) {
 		case "system":
 			if entry.Subtype == "turn_duration" {
 				m.TurnCount++
+				d := turnDuration(entry, lastPrompt)
+				totalTurns += d
+				if d > m.LongestTurnDuration {
+					m.LongestTurnDuration = d
+				}
 			}
 			if entry.Subtype == "compact_boundary" || entry.Subtype == "microcompact_boundary" {
 				m.CompactCount++
-				lastUsage = nil
-				lastUsageModel = ""
 			}
 		}
 	}
@@ -240,17 +279,36 @@ func parseMetricsInternal(logFile string) (*SessionMetrics, error) {
 		return nil, err
 	}
 
+	if m.TurnCount > 0 {
+		m.AvgTurnDuration = totalTurns / time.Duration(m.TurnCount)
+	}
+
 	// Calculate context usage from the last usage entry
 	if lastUsage != nil {
 		totalTokens := lastUsage.InputTokens + lastUsage.CacheCreationInputTokens + lastUsage.CacheReadInputTokens + lastUsage.OutputTokens
 		m.ContextTokens = totalTokens
-		window := contextWindowForModel(lastUsageModel)
-		m.ContextPercent = float64(totalTokens) / float64(window) * 100
+		m.ContextPercent = float64(totalTokens) / float64(contextLimit(lastUsageModel)) * 100
+		m.ContextBreakdown = contextBreakdown(lastUsage, lastUsageModel)
 	}
+	m.Outdated = versionOutdated(m.Version)
+	m.Config = sessionConfigFiles(logFile, cwd, nested)
 
 	return m, nil
 }
 
+// turnDuration returns the length of the turn a turn_duration entry closes:
+// its durationMs when recorded, else the time since the prompt that started
+// the turn.
+func turnDuration(entry LogEntry, prompt time.Time) time.Duration {
+	if entry.DurationMs > 0 {
+		return time.Duration(entry.DurationMs) * time.Millisecond
+	}
+	if prompt.IsZero() || entry.Timestamp.Before(prompt) {
+		return 0
+	}
+	return entry.Timestamp.Sub(prompt)
+}
+
 // logEntryToTimeline converts a LogEntry to a TimelineEntry, or nil if skipped
 func logEntryToTimeline(entry LogEntry) *TimelineEntry {
 	te := &TimelineEntry{
@@ -88,6 +88,9 @@ func TestParseMetrics(t *testing.T) {
 	if m.TurnCount != 2 {
 		t.Errorf("TurnCount = %d, want 2", m.TurnCount)
 	}
+	if m.AvgTurnDuration != 10*time.Second || m.LongestTurnDuration != 10*time.Second {
+		t.Errorf("turn durations avg=%v longest=%v, want 10s each", m.AvgTurnDuration, m.LongestTurnDuration)
+	}
 	if m.TotalInputTokens != 600 {
 		t.Errorf("TotalInputTokens = %d, want 600", m.TotalInputTokens)
 	}
@@ -108,6 +111,22 @@ func TestParseMetrics(t *testing.T) {
 	}
 }
 
+func TestTurnDuration(t *testing.T) {
+	prompt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	entry := LogEntry{Type: "system", Subtype: "turn_duration", Timestamp: prompt.Add(30 * time.Second)}
+
+	if got := turnDuration(entry, prompt); got != 30*time.Second {
+		t.Errorf("without durationMs = %v, want the time since the prompt", got)
+	}
+	entry.DurationMs = 95000
+	if got := turnDuration(entry, prompt); got != 95*time.Second {
+		t.Errorf("with durationMs = %v, want 1m35s", got)
+	}
+	if got := turnDuration(LogEntry{Timestamp: prompt}, time.Time{}); got != 0 {
+		t.Errorf("without a prompt = %v, want 0", got)
+	}
+}
+
 func TestParseTimeline(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -0,0 +1,256 @@
+package session
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TmuxPane is the tmux pane a session's Claude process runs in.
+type TmuxPane struct {
+	ID      string `json:"id"`      // Pane id, e.g. "%3"; stable while the pane lives
+	Session string `json:"session"` // tmux session name
+	Window  int    `json:"window"`  // Window index within the session
+	Pane    int    `json:"pane"`    // Pane index within the window
+}
+
+// Label names the pane as "window.pane", e.g. "2.1".
+func (p TmuxPane) Label() string {
+	return fmt.Sprintf("%d.%d", p.Window, p.Pane)
+}
+
+// tmuxPaneFormat is what `tmux list-panes` prints per pane; parseTmuxPanes
+// reads it back.
+const tmuxPaneFormat = "#{pane_pid} #{pane_id} #{window_index} #{pane_index} #{session_name}"
+
+var (
+	tmuxMu     sync.Mutex
+	tmuxAt     time.Time
+	tmuxPanes  map[int]TmuxPane // by the pid of the pane's shell
+	tmuxOwners = map[int]int{}  // Claude pid -> pane shell pid, 0 if outside tmux
+)
+
+// markTmuxPanes sets Tmux on every local session whose Claude process runs
+// inside a tmux pane. Nothing is spawned unless a session has a process.
+func markTmuxPanes(sessions []Session) {
+	var panes map[int]TmuxPane
+	for i := range sessions {
+		s := &sessions[i]
+		s.Tmux = nil
+		if s.GhostPID == 0 || s.Host != "" {
+			continue
+		}
+		if panes == nil {
+			if panes = cachedTmuxPanes(); len(panes) == 0 {
+				return
+			}
+		}
+		if p, ok := panes[tmuxOwner(s.GhostPID, panes)]; ok {
+			s.Tmux = &p
+		}
+	}
+	if panes != nil {
+		pruneTmuxOwners(sessions)
+	}
+}
+
+// pruneTmuxOwners forgets the processes no session runs in any more.
+func pruneTmuxOwners(sessions []Session) {
+	live := make(map[int]bool, len(sessions))
+	for _, s := range sessions {
+		live[s.GhostPID] = true
+	}
+	tmuxMu.Lock()
+	defer tmuxMu.Unlock()
+	for pid := range tmuxOwners {
+		if !live[pid] {
+			delete(tmuxOwners, pid)
+		}
+	}
+}
+
+// cachedTmuxPanes lists the panes of the tmux server, reusing the result for
+// processScanTTL. Without a running server (or tmux) the list is empty.
+func cachedTmuxPanes() map[int]TmuxPane {
+	tmuxMu.Lock()
+	defer tmuxMu.Unlock()
+	if tmuxPanes != nil && processScanTTL > 0 && time.Since(tmuxAt) < processScanTTL {
+		return tmuxPanes
+	}
+	tmuxPanes = map[int]TmuxPane{}
+	if out, err := exec.Command("tmux", "list-panes", "-a", "-F", tmuxPaneFormat).Output(); err == nil {
+		tmuxPanes = parseTmuxPanes(string(out))
+	}
+	tmuxAt = time.Now()
+	return tmuxPanes
+}
+
+// parseTmuxPanes parses `tmux list-panes -F tmuxPaneFormat` output. Session
+// names may contain spaces, so the name is everything after the fourth field.
+func parseTmuxPanes(out string) map[int]TmuxPane {
+	panes := make(map[int]TmuxPane)
+	for _, line := range strings.Split(out, "\n") {
+		f := strings.SplitN(strings.TrimSpace(line), " ", 5)
+		if len(f) < 5 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(f[0])
+		window, err2 := strconv.Atoi(f[2])
+		pane, err3 := strconv.Atoi(f[3])
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		panes[pid] = TmuxPane{ID: f[1], Session: f[4], Window: window, Pane: pane}
+	}
+	return panes
+}
+
+// tmuxOwner returns the pid of the pane shell that pid descends from, or 0.
+// The ancestor walk is remembered per pid: a process never changes parents,
+// and walking spawns a ps per hop on macOS.
+func tmuxOwner(pid int, panes map[int]TmuxPane) int {
+	tmuxMu.Lock()
+	owner, ok := tmuxOwners[pid]
+	tmuxMu.Unlock()
+	if ok {
+		if _, live := panes[owner]; live || owner == 0 {
+			return owner
+		}
+	}
+	owner = 0
+	for _, p := range parentChain(pid) {
+		if _, ok := panes[p.PID]; ok {
+			owner = p.PID
+			break
+		}
+	}
+	tmuxMu.Lock()
+	tmuxOwners[pid] = owner
+	tmuxMu.Unlock()
+	return owner
+}
+
+// JumpToTmuxPane makes p the active pane of its window and shows that window
+// in the current tmux client. It only works when csm itself runs inside tmux.
+func JumpToTmuxPane(p TmuxPane) error {
+	for _, args := range [][]string{
+		{"switch-client", "-t", p.Session},
+		{"select-window", "-t", p.ID},
+		{"select-pane", "-t", p.ID},
+	} {
+		if out, err := exec.Command("tmux", args...).CombinedOutput(); err != nil {
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				return fmt.Errorf("tmux %s: %s", args[0], msg)
+			}
+			return fmt.Errorf("tmux %s: %w", args[0], err)
+		}
+	}
+	return nil
+}
+
+// permissionPromptLines is how many of the pane's last non-blank lines are
+// searched for a permission prompt.
+const permissionPromptLines = 20
+
+// permissionOption matches the first option of Claude's permission prompt,
+// e.g. "❯ 1. Yes".
+var permissionOption = regexp.MustCompile(`^[❯>\s]*1\.\s+Yes\b`)
+
+// showsPermissionPrompt reports whether a pane's screen ends with one of
+// Claude's permission prompts: a "Do you want ..." question followed by the
+// numbered choices, "1. Yes" first.
+func showsPermissionPrompt(screen string) bool {
+	var lines []string
+	for _, l := range strings.Split(screen, "\n") {
+		if l = strings.TrimSpace(strings.Trim(l, "│ ")); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	lines = lines[max(len(lines)-permissionPromptLines, 0):]
+	question := false
+	for _, l := range lines {
+		if strings.HasPrefix(l, "Do you want") {
+			question = true
+		} else if question && permissionOption.MatchString(l) {
+			return true
+		}
+	}
+	return false
+}
+
+// promptShowsRequest reports whether the permission prompt at the end of a
+// pane's screen asks for request, a Session.PendingRequest such as "Bash:
+// make deploy": its command, or the file name of its path, must appear in the
+// prompt's box. Whitespace is ignored, as the box wraps long commands, and
+// only the start of a long subject is compared.
+func promptShowsRequest(screen, request string) bool {
+	_, subject, ok := strings.Cut(request, ": ")
+	if !ok || strings.TrimSpace(subject) == "" {
+		return false
+	}
+	if strings.HasPrefix(subject, "/") {
+		subject = filepath.Base(subject)
+	}
+	needle := []rune(strings.Join(strings.Fields(subject), ""))
+	needle = needle[:min(len(needle), 60)]
+
+	lines := strings.Split(screen, "\n")
+	question := -1
+	for i, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(strings.Trim(l, "│ ")), "Do you want") {
+			question = i
+		}
+	}
+	if question < 0 {
+		return false
+	}
+	start := max(question-permissionPromptLines, 0)
+	for i := question; i >= start; i-- {
+		if strings.Contains(lines[i], "╭") {
+			start = i
+			break
+		}
+	}
+	box := strings.Join(strings.Fields(strings.ReplaceAll(strings.Join(lines[start:question+1], ""), "│", "")), "")
+	return strings.Contains(box, string(needle))
+}
+
+// AnswerPermission approves or denies the tool request a Needs Input session
+// waits on by typing into its tmux pane: "1" picks "Yes", Escape declines.
+// The pane is checked to show a permission prompt for s.PendingRequest first,
+// so keys never end up in Claude's prompt input or answer a newer request
+// than the one shown.
+func AnswerPermission(s Session, approve bool) error {
+	if s.Status != StatusNeedsInput {
+		return fmt.Errorf("%s is not waiting for approval", s.Project)
+	}
+	if s.Tmux == nil {
+		return fmt.Errorf("%s is not running in tmux; answer it in its terminal", s.Project)
+	}
+	screen, err := exec.Command("tmux", "capture-pane", "-p", "-t", s.Tmux.ID).Output()
+	if err != nil {
+		return fmt.Errorf("tmux capture-pane: %w", err)
+	}
+	if !showsPermissionPrompt(string(screen)) {
+		return fmt.Errorf("no permission prompt on screen in tmux pane %s:%s", s.Tmux.Session, s.Tmux.Label())
+	}
+	if !promptShowsRequest(string(screen), s.PendingRequest) {
+		return fmt.Errorf("the prompt in tmux pane %s:%s is not for %q; answer it in its pane", s.Tmux.Session, s.Tmux.Label(), s.PendingRequest)
+	}
+	key := "Escape"
+	if approve {
+		key = "1"
+	}
+	if out, err := exec.Command("tmux", "send-keys", "-t", s.Tmux.ID, key).CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("tmux send-keys: %s", msg)
+		}
+		return fmt.Errorf("tmux send-keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package session
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTmuxPanes(t *testing.T) {
+	out := "4211 %0 1 0 main\n" +
+		"4388 %3 2 1 my project\n" +
+		"garbage\n" +
+		"\n"
+	want := map[int]TmuxPane{
+		4211: {ID: "%0", Session: "main", Window: 1, Pane: 0},
+		4388: {ID: "%3", Session: "my project", Window: 2, Pane: 1},
+	}
+	got := parseTmuxPanes(out)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTmuxPanes() = %+v, want %+v", got, want)
+	}
+	if label := got[4388].Label(); label != "2.1" {
+		t.Errorf("Label() = %q, want %q", label, "2.1")
+	}
+}
+
+func TestShowsPermissionPrompt(t *testing.T) {
+	prompt := `╭──────────────────────────────────────────────╮
+│ Bash command                                 │
+│                                              │
+│   make deploy                                │
+│                                              │
+│ Do you want to proceed?                      │
+│ ❯ 1. Yes                                     │
+│   2. Yes, and don't ask again for make       │
+│   3. No, and tell Claude what to do (esc)    │
+╰──────────────────────────────────────────────╯
+
+`
+	if !showsPermissionPrompt(prompt) {
+		t.Error("permission prompt not recognised")
+	}
+	edit := "Edit file\n  main.go\nDo you want to make this edit to main.go?\n> 1. Yes\n  2. No\n"
+	if !showsPermissionPrompt(edit) {
+		t.Error("edit prompt not recognised")
+	}
+
+	for _, screen := range []string{
+		"> Do you want me to list the options?\n1. Yes\n", // Claude's prompt input, typed by the user
+		"● Done. Do you want anything else?\n\n> ",
+		"Do you want to proceed?\n❯ 1. Yes\n" + strings.Repeat("output\n", 30), // scrolled away
+	} {
+		if showsPermissionPrompt(screen) {
+			t.Errorf("prompt recognised in %q", screen)
+		}
+	}
+}
+
+func TestPromptShowsRequest(t *testing.T) {
+	old := "╭────────────────╮\n│ Bash command   │\n│   make deploy  │\n│ Do you want to proceed? │\n│ ❯ 1. Yes │\n╰────────────────╯\n"
+	screen := old + "⏺ Deployed.\n╭────────────────────────╮\n│ Bash command           │\n│   rm -rf build &&      │\n│   make release         │\n│ Do you want to proceed? │\n│ ❯ 1. Yes               │\n╰────────────────────────╯\n"
+
+	if !promptShowsRequest(screen, "Bash: rm -rf build && make release") {
+		t.Error("wrapped command not found in its prompt")
+	}
+	if promptShowsRequest(screen, "Bash: make deploy") {
+		t.Error("request answered earlier matched the newer prompt")
+	}
+	edit := "Edit file\n  src/main.go\nDo you want to make this edit to main.go?\n> 1. Yes\n"
+	if !promptShowsRequest(edit, "Edit: /home/me/api/src/main.go") {
+		t.Error("edit prompt not matched by file name")
+	}
+	if promptShowsRequest(edit, "Edit") || promptShowsRequest(edit, "") {
+		t.Error("request without a subject matched")
+	}
+}
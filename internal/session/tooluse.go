@@ -0,0 +1,74 @@
+package session
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// pendingToolUse returns the most recent tool call of the current turn that
+// has no result yet: the request a Needs Input session is waiting on. Calls
+// are matched to results by id; logs without ids fall back to the last call.
+func pendingToolUse(entries []LogEntry) *ContentItem {
+	resolved := make(map[string]bool)
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := &entries[i]
+		if entry.Message == nil || entry.IsSidechain {
+			continue
+		}
+		switch entry.Type {
+		case "user":
+			if isUserPrompt(entry) {
+				return nil // calls before the prompt belong to an earlier turn
+			}
+			for _, c := range entry.Message.Content {
+				if c.Type == "tool_result" && c.ToolUseID != "" {
+					resolved[c.ToolUseID] = true
+				}
+			}
+		case "assistant":
+			content := entry.Message.Content
+			for j := len(content) - 1; j >= 0; j-- {
+				if content[j].Type == "tool_use" && (content[j].ID == "" || !resolved[content[j].ID]) {
+					return &content[j]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// toolInputFields are the input fields that say what a tool call does, in
+// order of preference.
+var toolInputFields = []string{"command", "file_path", "notebook_path", "url", "query", "pattern", "description", "prompt"}
+
+// describeToolUse summarizes a tool call on one line as "Tool: subject", the
+// subject being the command, file path, URL or similar, so a pending request
+// can be judged at a glance. Calls without a recognizable subject give just
+// the tool name.
+func describeToolUse(c ContentItem) string {
+	var input map[string]any
+	if json.Unmarshal(c.Input, &input) != nil {
+		return c.Name
+	}
+	// A finished plan waits for approval; its first line is usually the title.
+	if plan, ok := input["plan"].(string); ok && c.Name == "ExitPlanMode" {
+		return "Plan ready: " + firstLine(plan)
+	}
+	for _, field := range toolInputFields {
+		if v, ok := input[field].(string); ok && strings.TrimSpace(v) != "" {
+			return c.Name + ": " + strings.Join(strings.Fields(v), " ")
+		}
+	}
+	return c.Name
+}
+
+// firstLine returns the first non-blank line of s without Markdown heading
+// marks.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(line, "# ")); line != "" {
+			return line
+		}
+	}
+	return ""
+}
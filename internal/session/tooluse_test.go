@@ -0,0 +1,65 @@
+package session
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPendingToolUse(t *testing.T) {
+	parse := func(lines ...string) []LogEntry {
+		var entries []LogEntry
+		for _, l := range lines {
+			var e LogEntry
+			if err := json.Unmarshal([]byte(l), &e); err != nil {
+				t.Fatal(err)
+			}
+			entries = append(entries, e)
+		}
+		return entries
+	}
+	prompt := `{"type":"user","message":{"role":"user","content":"deploy it"}}`
+	read := `{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/app/Makefile"}}]}}`
+	bash := `{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"make\n  deploy"}}]}}`
+	readDone := `{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"..."}]}}`
+	bashDone := `{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"ok"}]}}`
+
+	tests := []struct {
+		name    string
+		entries []LogEntry
+		want    string
+	}{
+		{"parallel calls, one answered", parse(prompt, read, bash, readDone), "Bash: make deploy"},
+		{"all answered", parse(prompt, read, bash, readDone, bashDone), ""},
+		{"calls of an earlier turn", parse(read, prompt), ""},
+		{"no tool ids", parse(prompt, `{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","name":"WebFetch","input":{"url":"https://example.com"}}]}}`), "WebFetch: https://example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if c := pendingToolUse(tt.entries); c != nil {
+				got = describeToolUse(*c)
+			}
+			if got != tt.want {
+				t.Errorf("pending request = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeToolUse(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"Edit", `{"file_path":"/app/main.go","old_string":"a","new_string":"b"}`, "Edit: /app/main.go"},
+		{"WebSearch", `{"query":"go 1.25 release notes"}`, "WebSearch: go 1.25 release notes"},
+		{"Task", `{"description":"Find callers","prompt":"..."}`, "Task: Find callers"},
+		{"mcp__github__create_issue", `{"title":"x"}`, "mcp__github__create_issue"},
+		{"ExitPlanMode", `{"plan":"\n## Add retry to the uploader\n\n1. ..."}`, "Plan ready: Add retry to the uploader"},
+		{"Bash", `not json`, "Bash"},
+	}
+	for _, tt := range tests {
+		if got := describeToolUse(ContentItem{Type: "tool_use", Name: tt.name, Input: json.RawMessage(tt.input)}); got != tt.want {
+			t.Errorf("describeToolUse(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
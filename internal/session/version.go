@@ -0,0 +1,73 @@
+package session
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var minVersion string
+
+// SetMinVersion sets the oldest Claude Code version considered current;
+// sessions running an older one are flagged Outdated. Empty disables the
+// check.
+func SetMinVersion(v string) error {
+	if v != "" && parseVersion(v) == nil {
+		return fmt.Errorf("invalid minimum version %q", v)
+	}
+	minVersion = v
+	return nil
+}
+
+// versionOutdated reports whether v is older than the configured minimum.
+// Unknown versions are never outdated.
+func versionOutdated(v string) bool {
+	if minVersion == "" || v == "" {
+		return false
+	}
+	return compareVersions(v, minVersion) < 0
+}
+
+// compareVersions compares two dotted versions such as "2.0.14" numerically,
+// returning -1, 0 or 1. Missing parts count as 0 and suffixes like "-beta"
+// are ignored. Unparsable versions compare equal to anything.
+func compareVersions(a, b string) int {
+	pa, pb := parseVersion(a), parseVersion(b)
+	if pa == nil || pb == nil {
+		return 0
+	}
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseVersion splits "2.0.14" (or "v2.0.14-beta.1") into its numeric parts,
+// returning nil if it doesn't start with a number.
+func parseVersion(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+ "); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,38 @@
+package session
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.0.14", "2.0.14", 0},
+		{"2.0.9", "2.0.14", -1},
+		{"2.1.0", "2.0.14", 1},
+		{"2.0", "2.0.0", 0},
+		{"v1.0.3-beta", "1.0.3", 0},
+		{"garbage", "1.0.0", 0},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestVersionOutdated(t *testing.T) {
+	defer SetMinVersion("")
+	if err := SetMinVersion("2.0"); err != nil {
+		t.Fatal(err)
+	}
+	if !versionOutdated("1.0.128") {
+		t.Error("1.0.128 should be outdated with minimum 2.0")
+	}
+	if versionOutdated("2.0.14") || versionOutdated("") {
+		t.Error("2.0.14 and unknown versions should not be outdated")
+	}
+	if err := SetMinVersion("latest"); err == nil {
+		t.Error("SetMinVersion(latest) should fail")
+	}
+}
@@ -0,0 +1,138 @@
+// Package state is the machine-readable snapshot written by `csm daemon`.
+//
+// Prompt segments, menu bar apps, and editor plugins read the snapshot
+// instead of running their own session discovery. The file is replaced
+// atomically, so readers never see a partial write.
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// Version is bumped on incompatible changes to the State layout.
+const Version = 1
+
+// State is the daemon's snapshot of all sessions.
+type State struct {
+	Version   int               `json:"version"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Counts    Counts            `json:"counts"`
+	Sessions  []session.Session `json:"sessions"`
+}
+
+// Counts tallies sessions by status so simple consumers (e.g. a shell prompt)
+// don't have to walk the session list.
+type Counts struct {
+	Working    int `json:"working"`
+	NeedsInput int `json:"needs_input"`
+	Waiting    int `json:"waiting"`
+	Idle       int `json:"idle"`
+	Inactive   int `json:"inactive"`
+}
+
+// Build returns the snapshot of sessions taken at now.
+func Build(sessions []session.Session, now time.Time) State {
+	st := State{
+		Version:   Version,
+		UpdatedAt: now,
+		Sessions:  sessions,
+	}
+	if st.Sessions == nil {
+		st.Sessions = []session.Session{}
+	}
+	for _, s := range sessions {
+		switch s.Status {
+		case session.StatusWorking:
+			st.Counts.Working++
+		case session.StatusNeedsInput:
+			st.Counts.NeedsInput++
+		case session.StatusWaiting:
+			st.Counts.Waiting++
+		case session.StatusIdle:
+			st.Counts.Idle++
+		case session.StatusInactive:
+			st.Counts.Inactive++
+		}
+	}
+	return st
+}
+
+// pathFn is overridable in tests.
+var pathFn = defaultPath
+
+func defaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".claude-monitor", "state.json"), nil
+}
+
+// DefaultPath returns the well-known state file location,
+// ~/.claude-monitor/state.json.
+func DefaultPath() (string, error) {
+	return pathFn()
+}
+
+// Write atomically replaces the file at path with st.
+func Write(path string, st State) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op if rename succeeded
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
+
+// Read loads a state file written by Write.
+func Read(path string) (State, error) {
+	var st State
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return st, nil
+}
+
+// Serve answers every connection on ln with the current snapshot from get
+// and closes it, so `nc -U ~/.claude-monitor/csm.sock` prints the state.
+// It returns when ln is closed.
+func Serve(ln net.Listener, get func() State) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+			json.NewEncoder(conn).Encode(get())
+		}(conn)
+	}
+}
@@ -0,0 +1,78 @@
+package state
+
+import (
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestBuildCounts(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	st := Build([]session.Session{
+		{Status: session.StatusWorking},
+		{Status: session.StatusWorking},
+		{Status: session.StatusNeedsInput},
+		{Status: session.StatusInactive},
+	}, now)
+
+	want := Counts{Working: 2, NeedsInput: 1, Inactive: 1}
+	if st.Counts != want {
+		t.Errorf("Counts = %+v, want %+v", st.Counts, want)
+	}
+	if st.Version != Version || !st.UpdatedAt.Equal(now) {
+		t.Errorf("Build header = v%d %v", st.Version, st.UpdatedAt)
+	}
+
+	if empty := Build(nil, now); empty.Sessions == nil {
+		t.Error("Build(nil) left Sessions nil; consumers expect []")
+	}
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+	want := Build([]session.Session{{Project: "org/api", Status: session.StatusIdle}}, time.Now().UTC())
+
+	if err := Write(path, want); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got.Counts != want.Counts || len(got.Sessions) != 1 || got.Sessions[0].Project != "org/api" {
+		t.Errorf("Read = %+v, want %+v", got, want)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp"))
+	if len(matches) != 0 {
+		t.Errorf("temp files left behind: %v", matches)
+	}
+}
+
+func TestServe(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "csm.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer ln.Close()
+	go Serve(ln, func() State { return Build([]session.Session{{Status: session.StatusWorking}}, time.Now()) })
+
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+
+	var got State
+	if err := json.NewDecoder(conn).Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got.Counts.Working != 1 {
+		t.Errorf("Counts = %+v, want one working", got.Counts)
+	}
+}
@@ -0,0 +1,68 @@
+// Package statsd is a minimal StatsD / DogStatsD client over UDP.
+//
+// Metrics are fire-and-forget: send errors are ignored so an absent agent
+// never affects csm. Tags use the DogStatsD "|#key:value" extension, which
+// plain StatsD servers ignore or reject per line without harm to the rest.
+package statsd
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Client sends metrics to a StatsD agent.
+type Client struct {
+	conn   net.Conn
+	prefix string
+	tags   []string
+}
+
+// Dial returns a Client sending to addr (host:port, usually
+// 127.0.0.1:8125). Every metric name gets prefix + "." and every metric
+// carries tags in addition to its own.
+func Dial(addr, prefix string, tags []string) (*Client, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, prefix: prefix, tags: tags}, nil
+}
+
+// Gauge records the current value of name.
+func (c *Client) Gauge(name string, value float64, tags ...string) {
+	c.send(name, strconv.FormatFloat(value, 'f', -1, 64), "g", tags)
+}
+
+// Count adds value to the counter name.
+func (c *Client) Count(name string, value int64, tags ...string) {
+	c.send(name, strconv.FormatInt(value, 10), "c", tags)
+}
+
+// Close releases the socket.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) send(name, value, typ string, tags []string) {
+	c.conn.Write([]byte(format(c.prefix, name, value, typ, append(c.tags[:len(c.tags):len(c.tags)], tags...))))
+}
+
+// format renders one metric line: "prefix.name:value|type|#tag1,tag2".
+func format(prefix, name, value, typ string, tags []string) string {
+	var b strings.Builder
+	if prefix != "" {
+		b.WriteString(prefix)
+		b.WriteByte('.')
+	}
+	b.WriteString(name)
+	b.WriteByte(':')
+	b.WriteString(value)
+	b.WriteByte('|')
+	b.WriteString(typ)
+	if len(tags) > 0 {
+		b.WriteString("|#")
+		b.WriteString(strings.Join(tags, ","))
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package statsd
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		prefix, name, value, typ string
+		tags                     []string
+		want                     string
+	}{
+		{"csm", "sessions", "3", "g", nil, "csm.sessions:3|g"},
+		{"", "ghosts.killed", "1", "c", nil, "ghosts.killed:1|c"},
+		{"csm", "sessions", "2", "g", []string{"env:dev", "status:working"}, "csm.sessions:2|g|#env:dev,status:working"},
+	}
+	for _, tt := range tests {
+		if got := format(tt.prefix, tt.name, tt.value, tt.typ, tt.tags); got != tt.want {
+			t.Errorf("format(%q, %q) = %q, want %q", tt.prefix, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClientSends(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp unavailable: %v", err)
+	}
+	defer pc.Close()
+
+	c, err := Dial(pc.LocalAddr().String(), "csm", []string{"env:dev"})
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer c.Close()
+
+	c.Gauge("sessions", 2, "status:working")
+	c.Count("tokens", 1500)
+
+	buf := make([]byte, 512)
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for _, want := range []string{"csm.sessions:2|g|#env:dev,status:working", "csm.tokens:1500|c|#env:dev"} {
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("ReadFrom: %v", err)
+		}
+		if got := string(buf[:n]); got != want {
+			t.Errorf("packet = %q, want %q", got, want)
+		}
+	}
+}
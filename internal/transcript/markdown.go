@@ -0,0 +1,136 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxOutputLines bounds how much of each tool result goes into the
+// document; long outputs are cut with a note.
+const maxOutputLines = 40
+
+// WriteMarkdown renders the transcript as Markdown: a title and summary,
+// then one section per turn (a user prompt and Claude's reply), with tool
+// calls and their output in fenced code blocks. project is the display name
+// used in the title.
+func WriteMarkdown(w io.Writer, t *Transcript, project string) error {
+	bw := bufio.NewWriter(w)
+
+	title := project
+	if t.Title != "" {
+		title = t.Title
+	}
+	fmt.Fprintf(bw, "# %s\n\n", title)
+	writeSummary(bw, t, project)
+	fmt.Fprintln(bw)
+
+	turn := 0
+	lastRole := ""
+	for _, m := range t.Messages {
+		switch {
+		case m.Prompt():
+			turn++
+			fmt.Fprintf(bw, "## Turn %d · %s\n\n### User\n\n", turn, m.Timestamp.Local().Format("2006-01-02 15:04"))
+			lastRole = "user"
+		case m.Role == "assistant" && lastRole != "assistant":
+			fmt.Fprintf(bw, "### Claude\n\n")
+			lastRole = "assistant"
+		}
+		for _, p := range m.Parts {
+			writePart(bw, p)
+		}
+	}
+	return bw.Flush()
+}
+
+func writeSummary(w io.Writer, t *Transcript, project string) {
+	fmt.Fprintf(w, "- **Project:** %s\n", project)
+	if t.CWD != "" {
+		fmt.Fprintf(w, "- **Path:** `%s`\n", t.CWD)
+	}
+	if t.GitBranch != "" {
+		fmt.Fprintf(w, "- **Branch:** `%s`\n", t.GitBranch)
+	}
+	if t.SessionID != "" {
+		fmt.Fprintf(w, "- **Session:** `%s`\n", t.SessionID)
+	}
+	if n := len(t.Messages); n > 0 {
+		start, end := t.Messages[0].Timestamp, t.Messages[n-1].Timestamp
+		fmt.Fprintf(w, "- **Time:** %s – %s (%s)\n",
+			start.Local().Format("2006-01-02 15:04"), end.Local().Format("15:04"), end.Sub(start).Round(time.Minute))
+	}
+	var models []string
+	for _, m := range t.Messages {
+		// Skip placeholders such as "<synthetic>" on locally generated replies.
+		if m.Model != "" && !strings.HasPrefix(m.Model, "<") && !slices.Contains(models, m.Model) {
+			models = append(models, m.Model)
+		}
+	}
+	if len(models) > 0 {
+		fmt.Fprintf(w, "- **Model:** %s\n", strings.Join(models, ", "))
+	}
+}
+
+func writePart(w io.Writer, p Part) {
+	switch p.Kind {
+	case "text":
+		fmt.Fprintf(w, "%s\n\n", strings.TrimSpace(p.Text))
+	case "tool_use":
+		lang, body := toolInput(p)
+		fmt.Fprintf(w, "**%s**\n\n", p.Tool)
+		writeFenced(w, lang, body)
+	case "tool_result":
+		summary := "Output"
+		if p.IsError {
+			summary = "Error"
+		}
+		out := strings.TrimRight(p.Text, "\n")
+		if out == "" {
+			return
+		}
+		fmt.Fprintf(w, "<details><summary>%s</summary>\n\n", summary)
+		writeFenced(w, "", cutLines(out, maxOutputLines))
+		fmt.Fprintf(w, "</details>\n\n")
+	}
+}
+
+// toolInput picks how to show a tool call: the command for shell tools, the
+// pretty-printed input otherwise.
+func toolInput(p Part) (lang, body string) {
+	var fields map[string]any
+	if json.Unmarshal(p.Input, &fields) == nil {
+		if cmd, ok := fields["command"].(string); ok && p.Tool == "Bash" {
+			return "sh", cmd
+		}
+	}
+	var buf bytes.Buffer
+	if json.Indent(&buf, p.Input, "", "  ") != nil {
+		return "", string(p.Input)
+	}
+	return "json", buf.String()
+}
+
+// writeFenced writes body in a code fence longer than any backtick run in
+// it, so code containing ``` can't end the block early.
+func writeFenced(w io.Writer, lang, body string) {
+	fence := "```"
+	for strings.Contains(body, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(w, "%s%s\n%s\n%s\n\n", fence, lang, strings.TrimRight(body, "\n"), fence)
+}
+
+// cutLines keeps the first max lines of s, noting how many were dropped.
+func cutLines(s string, max int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) <= max {
+		return s
+	}
+	return strings.Join(lines[:max], "\n") + fmt.Sprintf("\n… (%d more lines)", len(lines)-max)
+}
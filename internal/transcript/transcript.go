@@ -0,0 +1,180 @@
+// Package transcript reads a session log as a conversation, turn by turn,
+// and renders it for people: as a Markdown document to share.
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+)
+
+// Part is one piece of a message: text, a tool call or a tool result.
+type Part struct {
+	Kind    string // "text", "tool_use" or "tool_result"
+	Text    string // text, or the tool result's output
+	Tool    string // tool name, for tool_use
+	Input   json.RawMessage
+	IsError bool // tool_result reported an error
+}
+
+// Message is a user or assistant message.
+type Message struct {
+	Role      string // "user" or "assistant"
+	Timestamp time.Time
+	Model     string
+	Parts     []Part
+}
+
+// Prompt reports whether the message is something the user typed, as
+// opposed to tool results sent back on their behalf.
+func (m Message) Prompt() bool {
+	if m.Role != "user" {
+		return false
+	}
+	for _, p := range m.Parts {
+		if p.Kind == "text" && strings.TrimSpace(p.Text) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Transcript is a session's conversation plus what the log says about it.
+type Transcript struct {
+	SessionID string
+	CWD       string
+	GitBranch string
+	Title     string
+	Messages  []Message
+}
+
+// rawEntry is the subset of a log line the transcript needs.
+type rawEntry struct {
+	Type        string    `json:"type"`
+	Timestamp   time.Time `json:"timestamp"`
+	IsSidechain bool      `json:"isSidechain"`
+	CWD         string    `json:"cwd"`
+	GitBranch   string    `json:"gitBranch"`
+	SessionID   string    `json:"sessionId"`
+	CustomTitle string    `json:"customTitle"`
+	Message     *struct {
+		Role    string          `json:"role"`
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+type rawPart struct {
+	Type    string          `json:"type"`
+	Text    string          `json:"text"`
+	Name    string          `json:"name"`
+	Input   json.RawMessage `json:"input"`
+	Content json.RawMessage `json:"content"`
+	IsError bool            `json:"is_error"`
+}
+
+// Read parses a session log into a transcript. Sidechain (subagent) entries
+// and lines that don't parse are skipped.
+func Read(logFile string) (*Transcript, error) {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	t := &Transcript{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var e rawEntry
+		if json.Unmarshal(scanner.Bytes(), &e) != nil || e.IsSidechain {
+			continue
+		}
+		if t.SessionID == "" {
+			t.SessionID = e.SessionID
+		}
+		if t.CWD == "" {
+			t.CWD = e.CWD
+		}
+		if e.GitBranch != "" {
+			t.GitBranch = e.GitBranch
+		}
+		if e.CustomTitle != "" {
+			t.Title = e.CustomTitle
+		}
+		if (e.Type != "user" && e.Type != "assistant") || e.Message == nil {
+			continue
+		}
+		parts := parseContent(e.Message.Content)
+		if len(parts) == 0 {
+			continue
+		}
+		t.Messages = append(t.Messages, Message{
+			Role:      e.Type,
+			Timestamp: e.Timestamp,
+			Model:     e.Message.Model,
+			Parts:     parts,
+		})
+	}
+	return t, scanner.Err()
+}
+
+// parseContent decodes message content: a plain string or an array of
+// parts. Thinking blocks and images are left out.
+func parseContent(raw json.RawMessage) []Part {
+	if s, ok := stringContent(raw); ok {
+		if strings.TrimSpace(s) == "" {
+			return nil
+		}
+		return []Part{{Kind: "text", Text: s}}
+	}
+	var items []rawPart
+	if json.Unmarshal(raw, &items) != nil {
+		return nil
+	}
+	var parts []Part
+	for _, it := range items {
+		switch it.Type {
+		case "text":
+			if strings.TrimSpace(it.Text) != "" {
+				parts = append(parts, Part{Kind: "text", Text: it.Text})
+			}
+		case "tool_use":
+			parts = append(parts, Part{Kind: "tool_use", Tool: it.Name, Input: it.Input})
+		case "tool_result":
+			parts = append(parts, Part{Kind: "tool_result", Text: resultText(it.Content), IsError: it.IsError})
+		}
+	}
+	return parts
+}
+
+// resultText flattens a tool result's content, a string or text parts.
+func resultText(raw json.RawMessage) string {
+	if s, ok := stringContent(raw); ok {
+		return s
+	}
+	var items []rawPart
+	if json.Unmarshal(raw, &items) != nil {
+		return ""
+	}
+	var texts []string
+	for _, it := range items {
+		if it.Type == "text" {
+			texts = append(texts, it.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func stringContent(raw json.RawMessage) (string, bool) {
+	if len(raw) == 0 || raw[0] != '"' {
+		return "", false
+	}
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		return "", false
+	}
+	return s, true
+}
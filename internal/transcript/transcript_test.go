@@ -0,0 +1,79 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testLog = `{"type":"user","cwd":"/home/me/repos/api","gitBranch":"main","sessionId":"s1","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"fix the migration script"}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:05Z","message":{"role":"assistant","model":"claude-sonnet-4-5","content":[{"type":"thinking","thinking":"hmm"},{"type":"text","text":"Let me run it."},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"make migrate"}}]}}
+{"type":"user","timestamp":"2026-01-02T10:00:09Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":"error: ` + "```" + `column missing"}]}}
+{"type":"assistant","isSidechain":true,"timestamp":"2026-01-02T10:00:10Z","message":{"role":"assistant","content":[{"type":"text","text":"subagent chatter"}]}}
+{"type":"assistant","timestamp":"2026-01-02T10:01:00Z","message":{"role":"assistant","model":"claude-sonnet-4-5","content":[{"type":"text","text":"Fixed."}]}}
+not json
+{"type":"user","timestamp":"2026-01-02T10:05:00Z","message":{"role":"user","content":[{"type":"text","text":"thanks"}]}}
+`
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s1.jsonl")
+	if err := os.WriteFile(path, []byte(testLog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.SessionID != "s1" || tr.CWD != "/home/me/repos/api" || tr.GitBranch != "main" {
+		t.Errorf("metadata = %q %q %q", tr.SessionID, tr.CWD, tr.GitBranch)
+	}
+	if len(tr.Messages) != 5 {
+		t.Fatalf("got %d messages, want 5 (sidechain and bad lines skipped)", len(tr.Messages))
+	}
+	var prompts int
+	for _, m := range tr.Messages {
+		if m.Prompt() {
+			prompts++
+		}
+	}
+	if prompts != 2 {
+		t.Errorf("got %d prompts, want 2 (tool results aren't prompts)", prompts)
+	}
+	if parts := tr.Messages[1].Parts; len(parts) != 2 || parts[1].Tool != "Bash" {
+		t.Errorf("assistant parts = %+v, want text and tool call without thinking", parts)
+	}
+
+	var b strings.Builder
+	if err := WriteMarkdown(&b, tr, "api"); err != nil {
+		t.Fatal(err)
+	}
+	md := b.String()
+	for _, want := range []string{
+		"# api\n",
+		"## Turn 1 ·",
+		"## Turn 2 ·",
+		"### Claude\n\nLet me run it.",
+		"**Bash**\n\n```sh\nmake migrate\n```",
+		"<summary>Error</summary>",
+		"````\nerror: ```column missing\n````",
+		"- **Model:** claude-sonnet-4-5\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "subagent chatter") || strings.Count(md, "### Claude") != 1 {
+		t.Errorf("unexpected markdown:\n%s", md)
+	}
+}
+
+func TestCutLines(t *testing.T) {
+	s := strings.Repeat("x\n", 9) + "x"
+	if got := cutLines(s, 10); got != s {
+		t.Errorf("short output changed: %q", got)
+	}
+	if got := cutLines(s, 3); got != "x\nx\nx\n… (7 more lines)" {
+		t.Errorf("cutLines = %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/i18n"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// cardLayoutWidth is the terminal width below which sessions are shown as
+// stacked cards instead of a table (tmux side panes, phone SSH clients).
+const cardLayoutWidth = 50
+
+// useCards reports whether a terminal of the given width gets the card layout.
+func useCards(width int) bool {
+	return width < cardLayoutWidth
+}
+
+// renderSessionCard renders a session as a three-line card: status and name,
+// context bar and activity, then the last message. A blank line follows.
+func renderSessionCard(w io.Writer, s session.Session, width int, selected bool, nl string) {
+	status := strings.TrimRight(formatSessionStatus(s, fixedStatusWidth+statusSinceWidth), " ")
+	statusLen := visibleWidth(status)
+	nameWidth := width - statusLen - 1
+	if nameWidth < 1 {
+		nameWidth = 1
+	}
+	fmt.Fprint(w, status+" "+strings.TrimRight(formatProject(s, nameWidth, selected), " ")+nl)
+
+	activity := formatActivity(s.LastActivity, time.Now())
+	if s.Status == session.StatusWorking {
+		activity = i18n.T("now")
+	}
+	fmt.Fprint(w, "  "+strings.TrimRight(formatContext(s, 0), " ")+"  "+Dim+activity+Reset+nl)
+
+	if msg := messageLine(s, width-2); msg != "" {
+		fmt.Fprint(w, "  "+msg+nl)
+	}
+
+	fmt.Fprint(w, nl)
+}
+
+// wrapKeys joins footer key hints with " | ", starting a new line whenever the
+// next hint would not fit in width.
+func wrapKeys(keys []string, width int) []string {
+	var lines []string
+	line := ""
+	for _, k := range keys {
+		switch {
+		case line == "":
+			line = k
+		case len([]rune(line))+3+len([]rune(k)) <= width:
+			line += " | " + k
+		default:
+			lines = append(lines, line)
+			line = k
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestRenderSessionCard(t *testing.T) {
+	s := session.Session{
+		Project:        "org/a-rather-long-project-name",
+		Status:         session.StatusNeedsInput,
+		LastActivity:   time.Now().Add(-3 * time.Minute),
+		LastMessage:    "Should I also update the migration scripts for the staging database?",
+		ContextTokens:  84000,
+		ContextPercent: 42,
+	}
+	var b strings.Builder
+	renderSessionCard(&b, s, 40, false, "\n")
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 4 || lines[3] != "" {
+		t.Fatalf("card = %q, want three lines and a blank one", lines)
+	}
+	for i, line := range lines[:3] {
+		if w := visibleWidth(line); w > 40 {
+			t.Errorf("line %d is %d columns wide, want at most 40: %q", i, w, line)
+		}
+	}
+	if !strings.Contains(lines[0], "Needs Input") || !strings.Contains(lines[0], "org/") {
+		t.Errorf("first line = %q, want status and name", lines[0])
+	}
+	if !strings.Contains(lines[1], "42%") || !strings.Contains(lines[1], "3m ago") {
+		t.Errorf("second line = %q, want context and activity", lines[1])
+	}
+	if !strings.Contains(lines[2], "Should I also") {
+		t.Errorf("third line = %q, want the last message", lines[2])
+	}
+}
+
+func TestWrapKeys(t *testing.T) {
+	keys := []string{"j/k: select", "o: open", "Ctrl+C: quit"}
+	if got := wrapKeys(keys, 80); !reflect.DeepEqual(got, []string{"j/k: select | o: open | Ctrl+C: quit"}) {
+		t.Errorf("wide = %q", got)
+	}
+	if got := wrapKeys(keys, 24); !reflect.DeepEqual(got, []string{"j/k: select | o: open", "Ctrl+C: quit"}) {
+		t.Errorf("narrow = %q", got)
+	}
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/events"
+	"github.com/itk-dev/claude-sessions-monitor/internal/i18n"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// RenderChanges renders the status changes panel of the live view: the
+// latest status transitions across sessions, newest first, e.g.
+// "14:02  org/api  Working → Needs Input  after 4m 32s".
+func RenderChanges(evs []events.Event) {
+	var b strings.Builder
+	renderChanges(&b, evs, getTerminalWidth(), getTerminalHeight())
+	liveScreen.draw(b.String())
+}
+
+func renderChanges(w io.Writer, evs []events.Event, width, height int) {
+	fmt.Fprintf(w, "%s%s%s %s%s\r\n\r\n", Bold, i18n.T("Status Changes"), Reset,
+		Dim+i18n.T("(last %d, since csm started)", events.DefaultRecent), Reset)
+
+	// Room for the title, the footer and the blank lines around them.
+	rows := max(height-5, 1)
+	project := 0
+	for _, e := range evs[:min(len(evs), rows)] {
+		project = max(project, len([]rune(changeProject(e))))
+	}
+	project = min(project, max(width/3, 10))
+
+	if len(evs) == 0 {
+		fmt.Fprintf(w, "%s%s%s\r\n", Dim, i18n.T("No status changes yet."), Reset)
+	}
+	for _, e := range evs[:min(len(evs), rows)] {
+		from, fromColor := statusName(e.PrevStatus), statusColor(e.PrevStatus)
+		to, toColor := statusName(e.Status), statusColor(e.Status)
+		after := ""
+		if e.Elapsed >= time.Second {
+			after = "  " + Dim + i18n.T("after %s", formatTurnLength(e.Elapsed)) + Reset
+		}
+		fmt.Fprintf(w, "%s%s%s  %-*s  %s%s%s → %s%s%s%s\r\n",
+			Dim, formatClock(e.Time), Reset,
+			project, truncate(sanitizeForTerminal(changeProject(e)), project),
+			fromColor, from, Reset, toColor, to, Reset, after)
+	}
+
+	fmt.Fprintf(w, "\r\n%s%s | %s | %s | %s%s\r\n", Dim, i18n.T("l: live view"), i18n.T("h: history"), i18n.T("u: usage"), i18n.T("Ctrl+C: quit"), Reset)
+}
+
+// changeProject names the session of a transition, with its host for remote
+// sessions.
+func changeProject(e events.Event) string {
+	if e.Host != "" {
+		return e.Host + ":" + e.Project
+	}
+	return e.Project
+}
+
+// statusColor is the color a status is shown in.
+func statusColor(status session.Status) string {
+	_, color := getStatusDisplay(status)
+	return color
+}
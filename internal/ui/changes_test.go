@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/events"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestRenderChanges(t *testing.T) {
+	at := time.Date(2026, 1, 2, 14, 2, 0, 0, time.Local)
+	evs := []events.Event{
+		{Time: at, Kind: events.StatusChanged, Project: "org/api", PrevStatus: session.StatusWorking, Status: session.StatusNeedsInput, Elapsed: 272 * time.Second},
+		{Time: at.Add(-time.Minute), Kind: events.Ended, Project: "web", Host: "devbox", PrevStatus: session.StatusWaiting, Status: session.StatusInactive},
+	}
+	var b strings.Builder
+	renderChanges(&b, evs, 100, 20)
+	lines := strings.Split(b.String(), "\r\n")
+
+	first := stripANSIForTest(lines[2])
+	if !strings.HasPrefix(first, "14:02") || !strings.Contains(first, "org/api") || !strings.Contains(first, "Working → Needs Input") || !strings.HasSuffix(first, "after 4m 32s") {
+		t.Errorf("first change = %q", first)
+	}
+	if second := stripANSIForTest(lines[3]); !strings.Contains(second, "devbox:web") || !strings.Contains(second, "Waiting → Inactive") {
+		t.Errorf("second change = %q", second)
+	}
+
+	b.Reset()
+	renderChanges(&b, nil, 100, 20)
+	if !strings.Contains(b.String(), "No status changes yet.") {
+		t.Errorf("empty panel = %q", b.String())
+	}
+}
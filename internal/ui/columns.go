@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// optionalColumn is a live-table column that is off by default and enabled
+// with --columns (or the "columns" config key). Enabled columns sit between
+// the origin and context columns, in the order the user listed them.
+type optionalColumn struct {
+	name   string // key accepted by --columns
+	header string
+	width  int // minimum (and, without maxWidth, fixed) width
+	// maxWidth lets calcSessionLayout grow the column up to this width when
+	// the project column has room to spare. Zero keeps it fixed.
+	maxWidth int
+	// cell returns the plain cell text and its color. The caller sanitizes,
+	// truncates, and pads the text to the column width.
+	cell func(s session.Session) (string, string)
+}
+
+// optionalColumns is the registry of every column users can opt into.
+var optionalColumns = map[string]optionalColumn{
+	"id": {
+		name:   "id",
+		header: "SESSION",
+		width:  9, // 8-char short UUID + padding
+		cell: func(s session.Session) (string, string) {
+			id := s.SessionID
+			if len(id) > 8 {
+				id = id[:8]
+			}
+			return id, Gray
+		},
+	},
+	"host": {
+		name:   "host",
+		header: "HOST",
+		width:  14,
+		cell: func(s session.Session) (string, string) {
+			if s.Host == "" {
+				return "local", Dim
+			}
+			return s.Host, Blue
+		},
+	},
+	"started": {
+		name:   "started",
+		header: "STARTED",
+		width:  16, // "Jan 02 (12d 3h)" + padding
+		cell: func(s session.Session) (string, string) {
+			if s.StartedAt.IsZero() {
+				return "", ""
+			}
+			d := time.Since(s.StartedAt)
+			color := Dim
+			if d >= marathonSession {
+				color = Yellow
+			}
+			return formatStartTime(s.StartedAt) + " (" + formatDurationCompact(d) + ")", color
+		},
+	},
+	"branch": {
+		name:     "branch",
+		header:   "BRANCH",
+		width:    10,
+		maxWidth: 28,
+		cell: func(s session.Session) (string, string) {
+			return s.GitBranch, Gray
+		},
+	},
+	"speed": {
+		name:   "speed",
+		header: "TOK/S",
+		width:  7,
+		cell: func(s session.Session) (string, string) {
+			if s.OutputSpeed == 0 {
+				return "", ""
+			}
+			color := Gray
+			if s.OutputSpeed < slowOutputSpeed {
+				color = Yellow
+			}
+			return fmt.Sprintf("%.0f", s.OutputSpeed), color
+		},
+	},
+	"output": {
+		name:   "output",
+		header: "OUTPUT",
+		width:  13, // "1.2M (64K)" + padding
+		cell: func(s session.Session) (string, string) {
+			if s.OutputTokens == 0 {
+				return "", ""
+			}
+			return formatTokenCount(s.OutputTokens) + " (" + formatTokenCount(s.LastOutput) + ")", Gray
+		},
+	},
+	"profile": {
+		name:   "profile",
+		header: "PROFILE",
+		width:  10,
+		cell: func(s session.Session) (string, string) {
+			return s.Profile, Cyan
+		},
+	},
+}
+
+// RegisterColumn adds an optional column whose cell text comes from cell,
+// such as a plugin column backed by an external command. Built-in names
+// cannot be replaced.
+func RegisterColumn(name, header string, width int, cell func(s session.Session) string) error {
+	name = strings.ToLower(name)
+	if _, ok := optionalColumns[name]; ok {
+		return fmt.Errorf("column %q already exists", name)
+	}
+	optionalColumns[name] = optionalColumn{
+		name:   name,
+		header: header,
+		width:  width,
+		cell: func(s session.Session) (string, string) {
+			return cell(s), ""
+		},
+	}
+	return nil
+}
+
+// marathonSession is how long a session must have been running before the
+// started column highlights it.
+const marathonSession = 4 * time.Hour
+
+// slowOutputSpeed is the output tokens per second below which the speed
+// column highlights a reply, a hint that the API is degraded.
+const slowOutputSpeed = 15
+
+// formatStartTime shows the clock time for sessions started today and the
+// date for older ones.
+func formatStartTime(t time.Time) string {
+	t, now := t.Local(), time.Now()
+	if t.YearDay() == now.YearDay() && t.Year() == now.Year() {
+		return t.Format(clockLayout)
+	}
+	return t.Format("Jan 02")
+}
+
+// enabledColumns holds the optional columns chosen via SetColumns.
+var enabledColumns []optionalColumn
+
+// SetColumns enables the named optional columns for the live and list views.
+// Unknown names are rejected so typos don't silently do nothing.
+func SetColumns(names []string) error {
+	var cols []optionalColumn
+	for _, name := range names {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+		c, ok := optionalColumns[name]
+		if !ok {
+			return fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(OptionalColumnNames(), ", "))
+		}
+		cols = append(cols, c)
+	}
+	enabledColumns = cols
+	return nil
+}
+
+// OptionalColumnNames returns the names accepted by SetColumns, sorted.
+func OptionalColumnNames() []string {
+	names := make([]string, 0, len(optionalColumns))
+	for name := range optionalColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatColumnCell renders an optional column cell padded to exactly width
+// visible chars. Empty values render as a dim "-".
+func formatColumnCell(c optionalColumn, s session.Session, width int) string {
+	text, color := c.cell(s)
+	text = truncate(sanitizeForTerminal(text), width)
+	if text == "" {
+		text, color = "-", Dim
+	}
+	padding := width - len([]rune(text))
+	if padding < 0 {
+		padding = 0
+	}
+	return color + text + Reset + strings.Repeat(" ", padding)
+}
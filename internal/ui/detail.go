@@ -0,0 +1,337 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/i18n"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// DetailView is the state of the focused `csm watch <project>` screen.
+type DetailView struct {
+	Session session.Session
+	Detail  session.Detail
+	Message string // flash message or error shown above the footer
+	Tmux    bool   // csm runs inside tmux, so the session's pane can be jumped to
+	// DiffScroll is the first line shown of the pending change's diff.
+	DiffScroll int
+}
+
+// pendingDiff returns the diff to preview: the change a session needing
+// input asks to make, if it is a file edit.
+func (v DetailView) pendingDiff() *session.PendingChange {
+	if v.Session.Status != session.StatusNeedsInput {
+		return nil
+	}
+	return v.Detail.Pending
+}
+
+// answerable reports whether s waits for a permission csm can answer by
+// typing into its tmux pane.
+func answerable(s session.Session) bool {
+	return s.Status == session.StatusNeedsInput && s.Tmux != nil
+}
+
+// detailRows splits the rows left under the header of the detail view
+// between the pending diff, the tool log and the messages. The diff gets up
+// to half when there is one; of the rest the messages get the larger share.
+func detailRows(v DetailView, height int) (diffRows, toolRows, msgRows int) {
+	// What's left after the 9 lines above, 2 headings with a blank line
+	// between them and 3 of message and footer.
+	rows := max(height-15, 2)
+	if pc := v.pendingDiff(); pc != nil {
+		// The diff's heading and the blank line after it.
+		diffRows = min(len(pc.Diff), max((rows-2)/2, 1))
+		rows = max(rows-diffRows-2, 2)
+	}
+	toolRows = min(max(len(v.Detail.Tools), 1), max(rows/3, 1))
+	msgRows = max(rows-toolRows, 1)
+	return diffRows, toolRows, msgRows
+}
+
+// MaxDiffScroll is the largest useful DiffScroll: the one showing the end of
+// the pending diff.
+func (v DetailView) MaxDiffScroll() int {
+	pc := v.pendingDiff()
+	if pc == nil {
+		return 0
+	}
+	diffRows, _, _ := detailRows(v, getTerminalHeight())
+	return max(len(pc.Diff)-diffRows, 0)
+}
+
+// RenderDetail redraws the focused view of one session in place. Uses \r\n
+// for raw terminal mode.
+func RenderDetail(v DetailView) {
+	var b strings.Builder
+	renderDetail(&b, v, getTerminalWidth(), getTerminalHeight())
+	liveScreen.draw(b.String())
+}
+
+func renderDetail(w io.Writer, v DetailView, width, height int) {
+	s, d := v.Session, v.Detail
+
+	branch := ""
+	if s.GitBranch != "" {
+		branch = " " + Dim + "@" + truncate(sanitizeForTerminal(s.GitBranch), 30) + Reset
+	}
+	fmt.Fprintf(w, "%scsm watch%s  %s%s  %s%s%s\r\n\r\n", Bold, Reset,
+		truncate(sanitizeForTerminal(s.Project), max(width-60, 10)), branch, Dim, s.SessionID, Reset)
+
+	detail := messageLine(s, max(width-fixedStatusWidth-10, 10))
+	if detail == "" && s.Task != "" && s.Task != "-" {
+		detail = truncate(sanitizeForTerminal(s.Task), max(width-fixedStatusWidth-10, 10))
+	}
+	fmt.Fprintf(w, "%s%s %s\r\n", detailLabel("Status"), formatSessionStatus(s, fixedStatusWidth), detail)
+	tokens := ""
+	if s.ContextTokens > 0 {
+		tokens = fmt.Sprintf(" %s%s%s", Dim, i18n.T("%s tokens", formatTokenCount(s.ContextTokens)), Reset)
+	}
+	if s.Model != "" {
+		tokens += "  " + Dim + session.ShortModelName(s.Model) + Reset
+	}
+	if d.Window.Total() > 0 {
+		fmt.Fprintf(w, "%s%s%s\r\n", detailLabel("Context"), contextBreakdownBar(d.Window), tokens)
+	} else {
+		fmt.Fprintf(w, "%s%s%s\r\n", detailLabel("Context"), formatContext(s, 0), tokens)
+	}
+	fmt.Fprintf(w, "%s%s\r\n", detailLabel("Trend"), sparkline(d.Context, max(width-10, 10)))
+	fmt.Fprintf(w, "%s%s\r\n", detailLabel("Turns"), turnSummary(d.Turns))
+	settings := Dim + i18n.T("no settings files") + Reset
+	if len(d.Config.Settings) > 0 {
+		settings = truncate(i18n.T("settings %s", shortPaths(d.Config.Settings, s.CWD)), max(width-42, 10))
+	}
+	fmt.Fprintf(w, "%s%s  %s\r\n", detailLabel("Config"), truncate(sanitizeForTerminal(shortPath(d.Config.ConfigDir, "")), 30), settings)
+	memory := Dim + i18n.T("no CLAUDE.md") + Reset
+	if len(d.Config.Memory) > 0 {
+		memory = truncate(shortPaths(d.Config.Memory, s.CWD), max(width-10, 10))
+	}
+	fmt.Fprintf(w, "%s%s\r\n\r\n", detailLabel("Memory"), memory)
+
+	diffRows, toolRows, msgRows := detailRows(v, height)
+	if pc := v.pendingDiff(); pc != nil {
+		renderPendingDiff(w, pc, s.CWD, v.DiffScroll, diffRows, width)
+	}
+
+	fmt.Fprintf(w, "%s%s%s\r\n", Bold, i18n.T("Tools"), Reset)
+	if len(d.Tools) == 0 {
+		fmt.Fprintf(w, "  %s%s%s\r\n", Dim, i18n.T("no tool calls yet"), Reset)
+	}
+	for _, t := range d.Tools[max(len(d.Tools)-toolRows, 0):] {
+		mark := Green + "✓" + Reset
+		if !t.Done {
+			mark = Yellow + "…" + Reset
+		}
+		fmt.Fprintf(w, "  %s%s%s %s %s\r\n", Dim, t.Time.Local().Format(secondsLayout), Reset, mark,
+			truncate(sanitizeForTerminal(t.Call), max(width-14, 10)))
+	}
+	fmt.Fprint(w, "\r\n")
+
+	fmt.Fprintf(w, "%s%s%s\r\n", Bold, i18n.T("Messages"), Reset)
+	for _, line := range messageLines(d.Messages, width, msgRows) {
+		fmt.Fprintf(w, "%s\r\n", line)
+	}
+
+	fmt.Fprint(w, "\r\n")
+	if v.Message != "" {
+		fmt.Fprintf(w, "%s%s%s", Yellow, truncate(v.Message, width), Reset)
+	}
+	fmt.Fprint(w, "\r\n")
+	keys := i18n.T("q: quit") + " | " + i18n.T("f: focus")
+	if v.Tmux && s.Tmux != nil {
+		keys += " | " + i18n.T("t: jump to pane")
+	}
+	if answerable(s) {
+		keys += " | " + i18n.T("a/d: approve/deny")
+	}
+	if pc := v.pendingDiff(); pc != nil && len(pc.Diff) > diffRows {
+		keys += " | " + i18n.T("j/k: scroll diff")
+	}
+	fmt.Fprintf(w, "%s%s%s\r\n", Dim, keys, Reset)
+}
+
+// detailLabel is the label column of the detail view's summary lines,
+// padded so the values line up.
+func detailLabel(name string) string {
+	return fmt.Sprintf("%-8s ", i18n.T(name))
+}
+
+// renderPendingDiff draws rows lines of a pending file change from scroll
+// on, under a heading naming the file and the size of the change.
+func renderPendingDiff(w io.Writer, pc *session.PendingChange, cwd string, scroll, rows, width int) {
+	scroll = min(max(scroll, 0), max(len(pc.Diff)-rows, 0))
+	stats := fmt.Sprintf("%s+%d%s %s-%d%s", Green, pc.Added, Reset, Red, pc.Removed, Reset)
+	if pc.NewFile {
+		stats += Dim + " " + i18n.T("new file") + Reset
+	}
+	if len(pc.Diff) > rows {
+		stats += fmt.Sprintf("  %s%s%s", Dim, i18n.T("lines %d-%d of %d", scroll+1, scroll+rows, len(pc.Diff)), Reset)
+	}
+	fmt.Fprintf(w, "%s%s%s %s  %s\r\n", Bold, i18n.T("Pending %s", pc.Tool), Reset,
+		truncate(sanitizeForTerminal(shortPath(pc.FilePath, cwd)), max(width-40, 10)), stats)
+	for _, l := range pc.Diff[scroll:min(scroll+rows, len(pc.Diff))] {
+		fmt.Fprintf(w, "  %s\r\n", formatDiffLine(l, max(width-2, 10)))
+	}
+	fmt.Fprint(w, "\r\n")
+}
+
+// formatDiffLine colors a diff line like `git diff`, with tabs expanded.
+func formatDiffLine(l session.DiffLine, width int) string {
+	text := l.Text
+	if l.Op != '@' {
+		text = string(l.Op) + text
+	}
+	text = truncate(sanitizeForTerminal(strings.ReplaceAll(text, "\t", "    ")), width)
+	switch l.Op {
+	case '@':
+		return Cyan + text + Reset
+	case '+':
+		return Green + text + Reset
+	case '-':
+		return Red + text + Reset
+	}
+	return text
+}
+
+// breakdownBarWidth is the width of the context bar of the detail view.
+const breakdownBarWidth = 30
+
+// contextBreakdownBar draws the context as cache-read, cache-created and
+// fresh tokens in lighter and lighter shades, e.g.
+// "███████▓▒░░░ 42%  read 80k · created 3k · fresh 1k".
+func contextBreakdownBar(b session.ContextBreakdown) string {
+	total := b.Total()
+	pct := float64(total) / float64(max(b.Limit, 1)) * 100
+	color := contextColor(pct)
+
+	// Segment ends are rounded from the running total so the widths add up.
+	cells := func(tokens int) int {
+		return min(int(float64(tokens)/float64(max(b.Limit, 1))*breakdownBarWidth+0.5), breakdownBarWidth)
+	}
+	read := cells(b.CacheRead)
+	created := cells(b.CacheRead+b.CacheCreated) - read
+	fresh := cells(total) - read - created
+
+	bar := color + strings.Repeat("█", read) + strings.Repeat("▓", created) + strings.Repeat("▒", fresh) + Reset +
+		Dim + strings.Repeat("░", breakdownBarWidth-read-created-fresh) + Reset
+	return fmt.Sprintf("%s %.0f%%  %s%s%s", bar, pct, Dim, i18n.T("read %s · created %s · fresh %s",
+		formatTokenCount(b.CacheRead), formatTokenCount(b.CacheCreated), formatTokenCount(b.Fresh)), Reset)
+}
+
+// shortPaths joins paths for display, see shortPath.
+func shortPaths(paths []string, cwd string) string {
+	short := make([]string, len(paths))
+	for i, p := range paths {
+		short[i] = sanitizeForTerminal(shortPath(p, cwd))
+	}
+	return strings.Join(short, ", ")
+}
+
+// shortPath shows a path relative to cwd when it is inside it, else with the
+// home directory as "~".
+func shortPath(path, cwd string) string {
+	if cwd != "" {
+		if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.Join("~", rel)
+		}
+	}
+	return path
+}
+
+// turnSummary describes finished turns as "12 · last 4m 32s · avg 1m 5s ·
+// longest 9m 2s".
+func turnSummary(turns []time.Duration) string {
+	if len(turns) == 0 {
+		return Dim + i18n.T("no finished turns yet") + Reset
+	}
+	var total time.Duration
+	for _, t := range turns {
+		total += t
+	}
+	return i18n.T("%d · last %s · avg %s · longest %s", len(turns),
+		formatTurnLength(turns[len(turns)-1]), formatTurnLength(total/time.Duration(len(turns))), formatTurnLength(slices.Max(turns)))
+}
+
+// formatTurnLength formats a turn like "45s", "4m 32s" or "1h 5m".
+func formatTurnLength(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm %ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// messageLines lays out the latest messages, wrapped to width, in at most
+// rows lines. The newest message is at the bottom; older ones are cut from
+// the top.
+func messageLines(msgs []session.DetailMessage, width, rows int) []string {
+	var lines []string
+	for i := len(msgs) - 1; i >= 0 && len(lines) < rows; i-- {
+		m := msgs[i]
+		role := Cyan + "claude" + Reset
+		if m.Role == "user" {
+			role = Green + "you   " + Reset
+		}
+		// Continuation lines line up with the text: "  15:04:05 claude ".
+		at := m.Time.Local().Format(secondsLayout)
+		indent := 2 + len(at) + 1 + 6 + 1
+		wrapped := wrapText(sanitizeForTerminal(m.Text), max(width-indent, 10))
+		for j := range wrapped {
+			if j == 0 {
+				wrapped[j] = fmt.Sprintf("  %s%s%s %s %s", Dim, at, Reset, role, wrapped[j])
+			} else {
+				wrapped[j] = strings.Repeat(" ", indent) + wrapped[j]
+			}
+		}
+		lines = append(wrapped, lines...)
+	}
+	if len(lines) > rows {
+		lines = lines[len(lines)-rows:]
+	}
+	return lines
+}
+
+// wrapText breaks s into lines of at most width runes at spaces, splitting
+// words that are longer than a line.
+func wrapText(s string, width int) []string {
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(s) {
+		rw := []rune(word)
+		for len(rw) > width {
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			lines = append(lines, string(rw[:width]))
+			rw = rw[width:]
+		}
+		switch {
+		case len(line) == 0:
+			line = rw
+		case len(line)+1+len(rw) <= width:
+			line = append(append(line, ' '), rw...)
+		default:
+			lines = append(lines, string(line))
+			line = rw
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
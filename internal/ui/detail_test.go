@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestWrapText(t *testing.T) {
+	got := wrapText("the quick brown fox jumps over supercalifragilistic", 10)
+	want := []string{"the quick", "brown fox", "jumps over", "supercalif", "ragilistic"}
+	if !slices.Equal(got, want) {
+		t.Errorf("wrapText = %q, want %q", got, want)
+	}
+}
+
+func TestMessageLinesKeepsNewest(t *testing.T) {
+	at := time.Date(2026, 1, 2, 10, 0, 0, 0, time.Local)
+	msgs := []session.DetailMessage{
+		{Time: at, Role: "user", Text: "first prompt"},
+		{Time: at, Role: "assistant", Text: "a reply long enough to wrap onto a second line"},
+	}
+	lines := messageLines(msgs, 60, 2)
+	if len(lines) != 2 {
+		t.Fatalf("messageLines = %q, want 2 lines", lines)
+	}
+	if !strings.Contains(lines[0], "claude") || strings.Contains(lines[1], "claude") {
+		t.Errorf("the wrapped reply should fill both rows: %q", lines)
+	}
+	if strings.Contains(strings.Join(lines, ""), "first prompt") {
+		t.Errorf("older message should be cut: %q", lines)
+	}
+}
+
+func TestTurnSummary(t *testing.T) {
+	got := turnSummary([]time.Duration{30 * time.Second, 9*time.Minute + 2*time.Second, 4*time.Minute + 32*time.Second})
+	if want := "3 · last 4m 32s · avg 4m 41s · longest 9m 2s"; got != want {
+		t.Errorf("turnSummary = %q, want %q", got, want)
+	}
+}
+
+func TestContextBreakdownBar(t *testing.T) {
+	got := contextBreakdownBar(session.ContextBreakdown{CacheRead: 50000, CacheCreated: 10000, Fresh: 3000, Limit: 100000})
+	bar := Green + strings.Repeat("█", 15) + strings.Repeat("▓", 3) + "▒" + Reset + Dim + strings.Repeat("░", 11) + Reset
+	if !strings.HasPrefix(got, bar+" 63%") || !strings.Contains(got, "read 50K · created 10K · fresh 3K") {
+		t.Errorf("contextBreakdownBar = %q", got)
+	}
+}
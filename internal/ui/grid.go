@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// gridLayoutWidth is the terminal width from which the live view lays
+// sessions out in two side-by-side tables instead of one long one.
+const gridLayoutWidth = 200
+
+// gridGutter separates the two tables of the grid layout.
+const gridGutter = " " + Dim + "│" + Reset + " "
+
+func useGrid(width int) bool {
+	return width >= gridLayoutWidth
+}
+
+// renderSessionGrid renders sessions as two tables side by side, filled row
+// by row (1 2 / 3 4 / ...) so the sort order still reads left to right. It
+// returns where each session and sortable header went, with lines counted
+// from the header.
+func renderSessionGrid(w io.Writer, sessions []session.Session, width int, selected string) (rows, headers []hitArea) {
+	colWidth := (width - visibleWidth(gridGutter)) / 2
+	l := calcSessionLayout(colWidth)
+	if slices.ContainsFunc(sessions, func(s session.Session) bool { return !s.StatusSince.IsZero() }) {
+		l.widenStatus()
+	}
+
+	header := padVisible(sessionHeader(l), colWidth)
+	rule := strings.Repeat("─", l.totalWidth)
+	io.WriteString(w, header+gridGutter+sessionHeader(l)+"\r\n")
+	io.WriteString(w, padVisible(rule, colWidth)+gridGutter+rule+"\r\n")
+	right := colWidth + visibleWidth(gridGutter)
+	headers = append(headerHits(l, 0, 0), headerHits(l, 0, right)...)
+
+	block := func(s session.Session) []string {
+		var b strings.Builder
+		renderSessionRow(&b, s, l, selected != "" && s.LogFile == selected, "\n")
+		return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	}
+	line := 2
+	for i := 0; i < len(sessions); i += 2 {
+		lb := block(sessions[i])
+		var rb []string
+		if i+1 < len(sessions) {
+			rb = block(sessions[i+1])
+			rows = append(rows, hitArea{line: line, lines: len(rb), x0: right, x1: width, target: sessions[i+1].LogFile})
+		}
+		rows = append(rows, hitArea{line: line, lines: len(lb), x1: colWidth, target: sessions[i].LogFile})
+		for j := 0; j < max(len(lb), len(rb)); j++ {
+			var lt, rt string
+			if j < len(lb) {
+				lt = lb[j]
+			}
+			if j < len(rb) {
+				rt = rb[j]
+			}
+			io.WriteString(w, strings.TrimRight(padVisible(lt, colWidth)+gridGutter+rt, " ")+"\r\n")
+		}
+		line += max(len(lb), len(rb))
+	}
+	return rows, headers
+}
+
+// padVisible pads s with spaces to width terminal columns.
+func padVisible(s string, width int) string {
+	if pad := width - visibleWidth(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestRenderSessionGrid(t *testing.T) {
+	now := time.Now()
+	sessions := []session.Session{
+		{Project: "org/one", Status: session.StatusWorking, LastActivity: now, LastMessage: "first"},
+		{Project: "org/two", Status: session.StatusWaiting, LastActivity: now},
+		{Project: "org/three", Status: session.StatusWaiting, LastActivity: now, LastMessage: "third"},
+	}
+	var b strings.Builder
+	renderSessionGrid(&b, sessions, 220, "")
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	for i, line := range lines {
+		if w := visibleWidth(line); w > 220 {
+			t.Errorf("line %d is %d columns wide, want at most 220", i, w)
+		}
+	}
+	if strings.Count(lines[0], "PROJECT") != 2 {
+		t.Errorf("header = %q, want two tables", lines[0])
+	}
+	if !strings.Contains(lines[2], "org/one") || !strings.Contains(lines[2], "org/two") {
+		t.Errorf("first row = %q, want sessions 1 and 2 side by side", lines[2])
+	}
+	if !strings.Contains(lines[3], "first") {
+		t.Errorf("second row = %q, want the first session's message", lines[3])
+	}
+	if i := strings.Index(lines[5], "org/three"); i < 0 || visibleWidth(lines[5][:i]) > 20 {
+		t.Errorf("third session should start the next grid row on the left: %q", lines[5])
+	}
+}
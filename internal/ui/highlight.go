@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Highlight colors the parts of a last message that match Pattern.
+type Highlight struct {
+	Pattern *regexp.Regexp
+	Color   string // ANSI sequence, see ParseColor
+}
+
+var highlights []Highlight
+
+// SetHighlights installs the keyword highlights for the last-message line.
+// Earlier rules win where matches overlap.
+func SetHighlights(h []Highlight) {
+	highlights = h
+}
+
+var colorNames = map[string]string{
+	"red":    Red,
+	"yellow": Yellow,
+	"green":  Green,
+	"blue":   Blue,
+	"cyan":   Cyan,
+	"gray":   Gray,
+	"bold":   Bold,
+}
+
+// ParseColor maps a color name from config (red, yellow, green, blue, cyan,
+// gray, bold) to its ANSI sequence.
+func ParseColor(name string) (string, error) {
+	if c, ok := colorNames[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown color %q (available: red, yellow, green, blue, cyan, gray, bold)", name)
+}
+
+// highlightMessage renders an already sanitized and truncated message dimmed,
+// with highlight matches in their color.
+func highlightMessage(msg string) string {
+	if len(highlights) == 0 {
+		return Dim + msg + Reset
+	}
+
+	// colors[i] is the color of byte i; "" keeps the dim default.
+	colors := make([]string, len(msg))
+	for _, h := range highlights {
+		for _, m := range h.Pattern.FindAllStringIndex(msg, -1) {
+			for i := m[0]; i < m[1]; i++ {
+				if colors[i] == "" {
+					colors[i] = h.Color
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(Dim)
+	for i := 0; i < len(msg); {
+		j := i
+		for j < len(msg) && colors[j] == colors[i] {
+			j++
+		}
+		if colors[i] == "" {
+			b.WriteString(msg[i:j])
+		} else {
+			b.WriteString(Reset + colors[i] + msg[i:j] + Reset + Dim)
+		}
+		i = j
+	}
+	b.WriteString(Reset)
+	return b.String()
+}
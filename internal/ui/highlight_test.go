@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestHighlightMessage(t *testing.T) {
+	t.Cleanup(func() { SetHighlights(nil) })
+
+	if got := highlightMessage("all good"); got != Dim+"all good"+Reset {
+		t.Errorf("without highlights = %q", got)
+	}
+
+	SetHighlights([]Highlight{
+		{Pattern: regexp.MustCompile(`(?i)failed`), Color: Red},
+		{Pattern: regexp.MustCompile(`\?`), Color: Yellow},
+		{Pattern: regexp.MustCompile(`(?i)tests failed`), Color: Green},
+	})
+	got := highlightMessage("3 tests FAILED, retry?")
+	want := Dim + "3 " + Reset + Green + "tests " + Reset + Dim + Reset + Red + "FAILED" + Reset + Dim +
+		", retry" + Reset + Yellow + "?" + Reset + Dim + Reset
+	if got != want {
+		t.Errorf("highlightMessage = %q, want %q", got, want)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	if c, err := ParseColor(" Red "); err != nil || c != Red {
+		t.Errorf("ParseColor(Red) = %q, %v", c, err)
+	}
+	if _, err := ParseColor("mauve"); err == nil {
+		t.Error("ParseColor accepted an unknown color")
+	}
+}
@@ -2,9 +2,11 @@ package ui
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
+	"github.com/itk-dev/claude-sessions-monitor/internal/i18n"
 	"github.com/itk-dev/claude-sessions-monitor/internal/session"
 )
 
@@ -18,7 +20,7 @@ func RenderHistory(sessions []session.HistorySession, days int, showFooter bool)
 	}
 
 	if len(sessions) == 0 {
-		fmt.Printf("No sessions found in the past %d days.%s", days, nl)
+		fmt.Print(i18n.T("No sessions found in the past %d days.", days) + nl)
 		return
 	}
 
@@ -37,15 +39,15 @@ func RenderHistory(sessions []session.HistorySession, days int, showFooter bool)
 	}
 
 	// Header
-	fmt.Printf("%sSession History%s (past %d days)%s%s", Bold, Reset, days, nl, nl)
+	fmt.Printf("%s%s%s %s%s%s", Bold, i18n.T("Session History"), Reset, i18n.T("(past %d days)", days), nl, nl)
 
 	// Column headers (once at the top)
 	colHeader := fmt.Sprintf("%-*s %-*s %-*s %-*s %*s",
-		l.project, "PROJECT",
-		l.branch, "BRANCH",
-		l.startTime, "TIME",
-		l.duration, "DURATION",
-		l.msgs, "MSGS")
+		l.project, i18n.T("PROJECT"),
+		l.branch, i18n.T("BRANCH"),
+		l.startTime, i18n.T("TIME"),
+		l.duration, i18n.T("DURATION"),
+		l.msgs, i18n.T("MSGS"))
 	fmt.Print(colHeader + nl)
 
 	// Group sessions by date
@@ -82,13 +84,13 @@ func RenderHistory(sessions []session.HistorySession, days int, showFooter bool)
 		}
 
 		// Format start time
-		startTime := s.StartTime.Format("15:04")
+		startTime := formatClock(s.StartTime)
 
 		// Format duration
 		duration := formatDuration(s.Duration)
 
 		row := fmt.Sprintf("%-*s %s%-*s%s %-*s %-*s %*d",
-			l.project, truncate(s.Project, l.project),
+			l.project, historyProject(s, l.project),
 			Gray, l.branch, truncate(s.GitBranch, l.branch), Reset,
 			l.startTime, startTime,
 			l.duration, duration,
@@ -102,15 +104,63 @@ func RenderHistory(sessions []session.HistorySession, days int, showFooter bool)
 
 	// Truncation indicator
 	if truncated > 0 {
-		fmt.Printf("%s  ... and %d more sessions%s%s", Dim, truncated, Reset, nl)
+		fmt.Printf("%s  %s%s%s", Dim, i18n.T("... and %d more sessions", truncated), Reset, nl)
 	}
 
 	// Footer with totals
 	fmt.Printf("%s%s%s%s%s", nl, Dim, strings.Repeat("─", l.totalWidth), Reset, nl)
-	fmt.Printf("%sTotal: %d sessions, %s%s%s", Dim, totalSessions, formatDuration(totalDuration), Reset, nl)
+	fmt.Printf("%s%s%s%s", Dim, i18n.T("Total: %d sessions, %s", totalSessions, formatDuration(totalDuration)), Reset, nl)
 
 	if showFooter {
-		fmt.Printf("%s%sl: live view | u: usage | Ctrl+C: quit%s%s", nl, Dim, Reset, nl)
+		fmt.Printf("%s%s%s | %s | %s%s%s", nl, Dim, i18n.T("l: live view"), i18n.T("u: usage"), i18n.T("Ctrl+C: quit"), Reset, nl)
+	}
+}
+
+// historyProject formats the project cell, marking a chain of resumed
+// sessions with the number of logs it spans (e.g. "org/api ↻3").
+func historyProject(s session.HistorySession, width int) string {
+	if s.Chain < 2 {
+		return truncate(s.Project, width)
+	}
+	mark := fmt.Sprintf(" ↻%d", s.Chain)
+	return truncate(s.Project, width-len([]rune(mark))) + mark
+}
+
+// renderToday renders the bottom half of the split view: sessions finished
+// today, newest first, in at most maxRows rows (header included).
+func renderToday(w io.Writer, sessions []session.HistorySession, width, maxRows int) {
+	if maxRows < 3 {
+		maxRows = 3
+	}
+	title := i18n.T("Finished today (%d)", len(sessions))
+	rule := width - len([]rune(title)) - 5
+	if rule < 1 {
+		rule = 1
+	}
+	fmt.Fprintf(w, "%s━━━ %s %s%s\r\n", Dim, title, strings.Repeat("━", rule), Reset)
+	if len(sessions) == 0 {
+		fmt.Fprintf(w, "%s%s%s\r\n", Dim, i18n.T("Nothing finished yet today."), Reset)
+		return
+	}
+
+	// END (5) + gap + DURATION (8) + gap, the project gets the rest.
+	const fixed = 5 + 1 + 8 + 1
+	project := width - fixed
+	if project < 1 {
+		project = 1
+	}
+	shown := sessions
+	if len(shown) > maxRows-1 {
+		shown = shown[:maxRows-2]
+	}
+	for _, s := range shown {
+		fmt.Fprintf(w, "%s%s%s %-*s %s\r\n",
+			Dim, formatClock(s.EndTime), Reset,
+			8, formatDuration(s.Duration),
+			truncate(sanitizeForTerminal(s.Project), project))
+	}
+	if more := len(sessions) - len(shown); more > 0 {
+		fmt.Fprintf(w, "%s  %s%s\r\n", Dim, i18n.T("... and %d more", more), Reset)
 	}
 }
 
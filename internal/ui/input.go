@@ -2,12 +2,32 @@ package ui
 
 import (
 	"os"
+	"os/exec"
+	"sync"
 
+	"golang.org/x/sys/unix"
 	"golang.org/x/term"
 )
 
 var originalState *term.State
 
+// Special keys decoded from terminal escape sequences. They live in the
+// Unicode private use area so they can share the rune channel with plain keys.
+const (
+	KeyUp rune = 0xE000 + iota
+	KeyDown
+	KeyRight
+	KeyLeft
+)
+
+// inputMu is held by ReadKey around each poll+read, so RunInTerminal can stop
+// the reader from stealing keystrokes meant for an external program.
+var inputMu sync.Mutex
+
+// inputPollMillis bounds how long ReadKey blocks before re-checking done and
+// yielding inputMu.
+const inputPollMillis = 100
+
 // SetupRawInput puts the terminal into raw mode for single-key input
 func SetupRawInput() error {
 	var err error
@@ -22,23 +42,115 @@ func CleanupRawInput() {
 	}
 }
 
-// ReadKey reads a single keypress from stdin (non-blocking with channel)
+// ReadKey reads keypresses from stdin and sends them on keyCh until done is
+// closed. Arrow-key escape sequences are decoded into KeyUp/KeyDown/etc., and
+// mouse reports (see EnableMouse) into keys MouseEvent decodes.
 func ReadKey(keyCh chan<- rune, done <-chan struct{}) {
-	buf := make([]byte, 1)
+	fd := int(os.Stdin.Fd())
+	buf := make([]byte, 32)
 	for {
 		select {
 		case <-done:
 			return
 		default:
-			n, err := os.Stdin.Read(buf)
-			if err != nil || n == 0 {
-				continue
-			}
+		}
+
+		inputMu.Lock()
+		n := readWithTimeout(fd, buf)
+		inputMu.Unlock()
+		if n == 0 {
+			continue
+		}
+
+		for _, key := range decodeKeys(buf[:n]) {
 			select {
-			case keyCh <- rune(buf[0]):
+			case keyCh <- key:
 			case <-done:
 				return
 			}
 		}
 	}
 }
+
+// readWithTimeout waits up to inputPollMillis for input on fd and reads it.
+// Returns 0 on timeout or error.
+func readWithTimeout(fd int, buf []byte) int {
+	fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+	ready, err := unix.Poll(fds, inputPollMillis)
+	if err != nil || ready == 0 {
+		return 0
+	}
+	n, err := unix.Read(fd, buf)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// decodeKeys turns a chunk of raw terminal input into keys. A single read
+// normally carries one whole escape sequence, so sequences are not buffered
+// across reads.
+func decodeKeys(b []byte) []rune {
+	var keys []rune
+	for i := 0; i < len(b); i++ {
+		if m, n, ok := sgrMouse(b[i:]); n > 0 {
+			if ok {
+				keys = append(keys, mouseKey(m))
+			}
+			i += n - 1
+			continue
+		}
+		// CSI (ESC [) and SS3 (ESC O) arrow sequences.
+		if b[i] == 0x1b && i+2 < len(b) && (b[i+1] == '[' || b[i+1] == 'O') {
+			if key, ok := arrowKey(b[i+2]); ok {
+				keys = append(keys, key)
+				i += 2
+				continue
+			}
+		}
+		keys = append(keys, rune(b[i]))
+	}
+	return keys
+}
+
+func arrowKey(c byte) (rune, bool) {
+	switch c {
+	case 'A':
+		return KeyUp, true
+	case 'B':
+		return KeyDown, true
+	case 'C':
+		return KeyRight, true
+	case 'D':
+		return KeyLeft, true
+	}
+	return 0, false
+}
+
+// RunInTerminal hands the terminal to cmd (an editor, pager, ...) and waits
+// for it to exit. Raw mode and the hidden cursor are restored afterwards, and
+// the key reader is paused meanwhile so it doesn't swallow the program's input.
+func RunInTerminal(cmd *exec.Cmd) error {
+	inputMu.Lock()
+	defer inputMu.Unlock()
+
+	mouse := mouseEnabled
+	if mouse {
+		DisableMouse()
+	}
+	CleanupRawInput()
+	ShowCursor()
+	ClearScreen()
+
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+
+	SetupRawInput()
+	HideCursor()
+	if mouse {
+		EnableMouse()
+	}
+	return err
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []rune
+	}{
+		{"plain keys", "jk", []rune{'j', 'k'}},
+		{"csi arrows", "\x1b[A\x1b[B", []rune{KeyUp, KeyDown}},
+		{"ss3 arrows", "\x1bOC\x1bOD", []rune{KeyRight, KeyLeft}},
+		{"lone escape", "\x1b", []rune{0x1b}},
+		{"unknown sequence passes through", "\x1b[Z", []rune{0x1b, '[', 'Z'}},
+		{"ctrl+c", "\x03", []rune{3}},
+		{"mouse click and release", "\x1b[<0;12;5M\x1b[<0;12;5mj", []rune{mouseKey(Mouse{MouseLeft, 12, 5}), 'j'}},
+		{"mouse wheel with shift", "\x1b[<69;1;2M", []rune{mouseKey(Mouse{MouseWheelDown, 1, 2})}},
+		{"right button dropped", "\x1b[<2;3;4Mk", []rune{'k'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeKeys([]byte(tt.in))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeKeys(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
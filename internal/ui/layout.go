@@ -3,10 +3,12 @@ package ui
 // Column width constraints for session table
 const (
 	fixedStatusWidth   = 14 // "● Needs Input" = 13 chars + 1 padding
+	statusSinceWidth   = 4  // " 59m" after the status text
 	fixedOriginWidth   = 10 // "Claude Desktop" truncated; most origins fit in 9
 	fixedContextWidth  = 21 // progress bar (10) + " 100%" (5) + " (1M)" suffix (5) + 1 padding
 	fixedActivityWidth = 15 // "LAST ACTIVITY" header + padding
 	minProjectWidth    = 15
+	prefProjectWidth   = 40 // growable optional columns only widen past this
 	originColumnMinTTY = 90 // drop the origin column below this terminal width
 )
 
@@ -16,6 +18,7 @@ type sessionLayout struct {
 	status     int
 	project    int
 	origin     int
+	extras     []optionalColumn // enabled optional columns that fit
 	context    int
 	activity   int
 	totalWidth int
@@ -23,8 +26,11 @@ type sessionLayout struct {
 
 // calcSessionLayout computes column widths for the given terminal width.
 // Fixed columns (status, origin, context, activity) keep their size.
-// All remaining space goes to the project column. The origin column is
-// dropped on narrow terminals to keep the project column readable.
+// Growable optional columns widen while the project column stays at least
+// prefProjectWidth, and all remaining space goes to the project column. The origin column is
+// dropped on narrow terminals to keep the project column readable, and
+// enabled optional columns are only added while the project column keeps
+// at least minProjectWidth.
 // Accounts for one separator space between each pair of adjacent columns.
 func calcSessionLayout(width int) sessionLayout {
 	l := sessionLayout{
@@ -42,17 +48,58 @@ func calcSessionLayout(width int) sessionLayout {
 		gaps = 4 // status|project|origin|context|activity
 	}
 	fixed := l.status + l.origin + l.context + l.activity + gaps
+
+	for _, c := range enabledColumns {
+		if width-(fixed+c.width+1) < minProjectWidth {
+			break
+		}
+		l.extras = append(l.extras, c)
+		fixed += c.width + 1
+		gaps++
+	}
+
 	remaining := width - fixed
 	if remaining < 1 {
 		remaining = 1
 	}
+
+	// Growable columns (e.g. branch) take space the project column doesn't
+	// need, up to their maxWidth.
+	for i := range l.extras {
+		c := &l.extras[i]
+		if grow := min(c.maxWidth-c.width, remaining-prefProjectWidth); grow > 0 {
+			c.width += grow
+			remaining -= grow
+			fixed += grow
+		}
+	}
 	l.project = remaining
 
-	l.totalWidth = l.status + l.project + l.origin + l.context + l.activity + gaps
+	l.totalWidth = fixed + l.project
 
 	return l
 }
 
+// hasColumn reports whether the named optional column is part of the layout.
+func (l sessionLayout) hasColumn(name string) bool {
+	for _, c := range l.extras {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// widenStatus makes room for the time-in-status suffix, taking the space
+// from the project column.
+func (l *sessionLayout) widenStatus() {
+	if l.project-statusSinceWidth < 1 {
+		return
+	}
+	l.status += statusSinceWidth
+	l.project -= statusSinceWidth
+}
+
 // Column width constraints for history table
 const (
 	minHistProjectWidth  = 15
@@ -1,6 +1,12 @@
 package ui
 
-import "testing"
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
 
 func TestCalcSessionLayout_WideTerminal(t *testing.T) {
 	l := calcSessionLayout(140)
@@ -122,3 +128,113 @@ func TestCalcHistoryLayout_NarrowTerminal(t *testing.T) {
 		t.Errorf("expected totalWidth=%d, got %d", expected, l.totalWidth)
 	}
 }
+
+func TestCalcSessionLayout_OptionalColumns(t *testing.T) {
+	if err := SetColumns([]string{"id"}); err != nil {
+		t.Fatalf("SetColumns: %v", err)
+	}
+	t.Cleanup(func() { enabledColumns = nil })
+
+	l := calcSessionLayout(140)
+	if len(l.extras) != 1 || l.extras[0].name != "id" {
+		t.Fatalf("expected id column enabled, got %+v", l.extras)
+	}
+	if l.totalWidth != 140 {
+		t.Errorf("expected totalWidth=140, got %d", l.totalWidth)
+	}
+
+	// Too narrow to keep minProjectWidth: the optional column is dropped.
+	l = calcSessionLayout(60)
+	if len(l.extras) != 0 {
+		t.Errorf("expected no optional columns at width=60, got %d", len(l.extras))
+	}
+	if l.totalWidth != 60 {
+		t.Errorf("expected totalWidth=60, got %d", l.totalWidth)
+	}
+}
+
+func TestSetColumns_Unknown(t *testing.T) {
+	t.Cleanup(func() { enabledColumns = nil })
+	if err := SetColumns([]string{"nope"}); err == nil {
+		t.Fatal("expected error for unknown column")
+	}
+}
+
+func TestWidenStatus(t *testing.T) {
+	l := calcSessionLayout(140)
+	project := l.project
+	l.widenStatus()
+	if l.status != fixedStatusWidth+statusSinceWidth || l.project != project-statusSinceWidth {
+		t.Errorf("widenStatus: status=%d project=%d", l.status, l.project)
+	}
+	if l.status+l.project != fixedStatusWidth+project {
+		t.Error("widenStatus changed the total width")
+	}
+}
+
+func TestFormatSessionStatus(t *testing.T) {
+	width := fixedStatusWidth + statusSinceWidth
+	s := session.Session{Status: session.StatusNeedsInput, StatusSince: time.Now().Add(-59 * time.Minute)}
+	got := formatSessionStatus(s, width)
+	if !strings.Contains(got, "Needs Input"+Reset+" "+Dim+"59m") {
+		t.Errorf("formatSessionStatus = %q, want the time in status", got)
+	}
+	if visible := len([]rune(stripANSIForTest(got))); visible != width {
+		t.Errorf("visible width = %d, want %d", visible, width)
+	}
+
+	s.StatusSince = time.Now().Add(-20 * time.Second)
+	if got := formatSessionStatus(s, width); got != formatStatus(s.Status, width) {
+		t.Errorf("under a minute = %q, want the plain status", got)
+	}
+}
+
+func stripANSIForTest(s string) string {
+	for _, code := range []string{Reset, Dim, Yellow} {
+		s = strings.ReplaceAll(s, code, "")
+	}
+	return s
+}
+
+func TestStartedColumn(t *testing.T) {
+	cell := optionalColumns["started"].cell
+
+	if text, _ := cell(session.Session{}); text != "" {
+		t.Errorf("without a start time = %q, want empty", text)
+	}
+
+	started := time.Now().Add(-5 * time.Hour)
+	text, color := cell(session.Session{StartedAt: started})
+	if want := formatStartTime(started) + " (5h 0m)"; text != want {
+		t.Errorf("cell = %q, want %q", text, want)
+	}
+	if color != Yellow {
+		t.Error("a five-hour session should be highlighted")
+	}
+	if got := formatStartTime(time.Date(2020, 3, 7, 9, 5, 0, 0, time.Local)); got != "Mar 07" {
+		t.Errorf("formatStartTime for an old session = %q, want Mar 07", got)
+	}
+}
+
+func TestCalcSessionLayout_BranchColumnGrows(t *testing.T) {
+	if err := SetColumns([]string{"branch"}); err != nil {
+		t.Fatalf("SetColumns: %v", err)
+	}
+	t.Cleanup(func() { enabledColumns = nil })
+
+	wide := calcSessionLayout(200)
+	if len(wide.extras) != 1 || wide.extras[0].width != 28 {
+		t.Fatalf("branch at width=200: %+v, want it grown to 28", wide.extras)
+	}
+	if wide.totalWidth != 200 {
+		t.Errorf("expected totalWidth=200, got %d", wide.totalWidth)
+	}
+	if enabledColumns[0].width != 10 {
+		t.Error("growing a layout column must not change the registry")
+	}
+
+	narrow := calcSessionLayout(100)
+	if narrow.extras[0].width != 10 || narrow.project < minProjectWidth {
+		t.Errorf("branch at width=100: width=%d project=%d, want the minimum width", narrow.extras[0].width, narrow.project)
+	}
+}
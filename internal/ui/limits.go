@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// limitsBarWidth is the number of block characters in a `csm limits` day bar.
+const limitsBarWidth = 30
+
+// RenderLimits prints the weekly limit projection of `csm limits`. source
+// says where the limit comes from, e.g. "config" or "estimated from the
+// usage API".
+func RenderLimits(w session.WeekUsage, source string) {
+	renderLimits(os.Stdout, w, source, time.Now())
+}
+
+func renderLimits(out io.Writer, w session.WeekUsage, source string, now time.Time) {
+	dayLayout := "Mon 02 Jan"
+	whenLayout := dayLayout + " " + clockLayout
+
+	fmt.Fprintf(out, "%sWeekly usage%s  %s → %s\n\n", Bold, Reset,
+		w.Start.Local().Format(whenLayout), w.Reset.Local().Format(whenLayout))
+
+	limit := "unknown (set limits.weekly_tokens)"
+	if w.Limit > 0 {
+		limit = formatTokenCount(w.Limit) + " tokens"
+		if source != "" {
+			limit += " " + Dim + "(" + source + ")" + Reset
+		}
+	}
+	fmt.Fprintf(out, "  Limit       %s\n", limit)
+	fmt.Fprintf(out, "  Used        %s%s\n", formatTokenCount(w.Used), percentOf(w.Used, w.Limit))
+	fmt.Fprintf(out, "  Projected   %s%s by the reset\n\n", formatTokenCount(w.Projected), percentOf(w.Projected, w.Limit))
+
+	switch {
+	case w.HitsLimit.IsZero() && w.Limit > 0:
+		fmt.Fprintf(out, "  %sAt this rate you'll stay under the weekly cap.%s\n\n", Green, Reset)
+	case w.HitsLimit.IsZero():
+	case !w.HitsLimit.After(now):
+		fmt.Fprintf(out, "  %sYou hit the weekly cap %s; it resets in %s.%s\n\n", Red,
+			w.HitsLimit.Local().Format(whenLayout), formatDurationCompact(w.Reset.Sub(now)), Reset)
+	default:
+		fmt.Fprintf(out, "  %sAt this rate you'll hit the weekly cap %s, %s before the reset.%s\n\n", Yellow,
+			w.HitsLimit.Local().Format(whenLayout), formatDurationCompact(w.Reset.Sub(w.HitsLimit)), Reset)
+	}
+
+	// Bars are cumulative against the limit, so the day the cap is crossed
+	// is the one that fills up. Without a limit they scale to the projected
+	// total.
+	scale := w.Limit
+	if scale <= 0 {
+		scale = max(w.Projected, 1)
+	}
+	used, projected := 0, 0
+	for _, d := range w.Days {
+		used += d.Tokens
+		projected += d.Tokens + d.Projected
+
+		solid := min(used*limitsBarWidth/scale, limitsBarWidth)
+		shaded := min(projected*limitsBarWidth/scale, limitsBarWidth) - solid
+		color := Green
+		switch {
+		case w.Limit > 0 && projected >= w.Limit:
+			color = Red
+		case w.Limit > 0 && projected*4 >= w.Limit*3:
+			color = Yellow
+		}
+		bar := color + strings.Repeat("█", solid) + Reset +
+			Dim + color + strings.Repeat("▒", shaded) + Reset +
+			Dim + strings.Repeat("░", limitsBarWidth-solid-shaded) + Reset
+
+		label := d.Date.Local().Format(dayLayout)
+		amount := formatTokenCount(d.Tokens)
+		if d.Projected > 0 {
+			amount = fmt.Sprintf("%s %s+%s projected%s", amount, Dim, formatTokenCount(d.Projected), Reset)
+		}
+		pct := ""
+		if w.Limit > 0 {
+			pct = fmt.Sprintf(" %4d%%", projected*100/w.Limit)
+		}
+		fmt.Fprintf(out, "  %s  %s%s  %s\n", label, bar, pct, amount)
+	}
+}
+
+// percentOf formats n as a share of limit, e.g. " (42%)"; "" without a limit.
+func percentOf(n, limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d%%)", n*100/limit)
+}
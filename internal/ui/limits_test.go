@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestRenderLimits(t *testing.T) {
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+	now := start.Add(36 * time.Hour)
+	w := session.WeekUsage{
+		Start:     start,
+		Reset:     start.AddDate(0, 0, 7),
+		Limit:     1000,
+		Used:      300,
+		Projected: 1400,
+		HitsLimit: time.Date(2026, 1, 8, 15, 0, 0, 0, time.Local),
+		Days: []session.DayUsage{
+			{Date: start, Tokens: 200},
+			{Date: start.AddDate(0, 0, 1), Tokens: 100, Projected: 100},
+			{Date: start.AddDate(0, 0, 2), Projected: 1000},
+		},
+	}
+	var b strings.Builder
+	renderLimits(&b, w, "config", now)
+	out := b.String()
+
+	if !strings.Contains(out, "hit the weekly cap Thu 08 Jan 15:00, 3d 9h before the reset") {
+		t.Errorf("missing the projection:\n%s", out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	days := lines[len(lines)-3:]
+	for i, want := range []string{"Mon 05 Jan", "Tue 06 Jan", "Wed 07 Jan"} {
+		if !strings.Contains(days[i], want) {
+			t.Errorf("row %d = %q, want %s", i, days[i], want)
+		}
+	}
+	// Cumulative: 20% after Monday, 40% after Tuesday, then over the cap.
+	if !strings.Contains(days[0], strings.Repeat("█", 6)+Reset) || !strings.Contains(days[1], "  40%") ||
+		!strings.Contains(days[2], " 140%") || !strings.Contains(days[2], Red) {
+		t.Errorf("bars:\n%s", strings.Join(days, "\n"))
+	}
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// projectLinks controls the hyperlinks on project names: nil disables them,
+// an empty template links to the project directory.
+var projectLinks *string
+
+// SetProjectLinks makes project names clickable OSC 8 hyperlinks. template
+// may use {path}, {project} and {branch}; "" links to the project directory
+// as a file:// URL and "off" turns links off.
+func SetProjectLinks(template string) {
+	if template == "off" {
+		projectLinks = nil
+		return
+	}
+	projectLinks = &template
+}
+
+// projectLink returns the URL a session's project name links to, or "".
+// Sessions on other hosts only get templated links; their directories are
+// not reachable from here.
+func projectLink(s session.Session) string {
+	if projectLinks == nil {
+		return ""
+	}
+	if *projectLinks == "" {
+		if s.CWD == "" || s.Host != "" {
+			return ""
+		}
+		host, _ := os.Hostname()
+		return (&url.URL{Scheme: "file", Host: host, Path: s.CWD}).String()
+	}
+	if s.CWD == "" && strings.Contains(*projectLinks, "{path}") {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{path}", (&url.URL{Path: s.CWD}).EscapedPath(),
+		"{project}", (&url.URL{Path: s.Project}).EscapedPath(),
+		"{branch}", (&url.URL{Path: s.GitBranch}).EscapedPath(),
+	).Replace(*projectLinks)
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// RenderModels prints the per-model usage table of `csm models` for the last
+// days days.
+func RenderModels(models []session.ModelUsage, days int) {
+	renderModels(os.Stdout, models, days)
+}
+
+func renderModels(out io.Writer, models []session.ModelUsage, days int) {
+	period := "today"
+	if days > 1 {
+		period = fmt.Sprintf("last %d days", days)
+	}
+	fmt.Fprintf(out, "%sUsage by model%s  %s\n\n", Bold, Reset, period)
+	if len(models) == 0 {
+		fmt.Fprintf(out, "%sNo token usage logged.%s\n", Dim, Reset)
+		return
+	}
+
+	var total session.ModelUsage
+	for _, m := range models {
+		total.Tokens += m.Tokens
+		total.Input += m.Input
+		total.Output += m.Output
+		total.Cache += m.Cache
+		total.Cost += m.Cost
+	}
+
+	fmt.Fprintf(out, "%-12s %8s %8s %8s %8s %10s %6s %8s\n",
+		"MODEL", "TOKENS", "INPUT", "OUTPUT", "CACHE", "COST", "SHARE", "SESSIONS")
+	for _, m := range models {
+		share := 0.0
+		if total.Cost > 0 {
+			share = m.Cost * 100 / total.Cost
+		}
+		fmt.Fprintf(out, "%-12s %8s %8s %8s %8s %10s %5.0f%% %8d\n",
+			truncate(m.Model, 12), formatTokenCount(m.Tokens), formatTokenCount(m.Input),
+			formatTokenCount(m.Output), formatTokenCount(m.Cache), fmt.Sprintf("$%.2f", m.Cost), share, m.Sessions)
+	}
+	fmt.Fprintf(out, "%s%-12s %8s %8s %8s %8s %10s%s\n", Dim, "total", formatTokenCount(total.Tokens),
+		formatTokenCount(total.Input), formatTokenCount(total.Output), formatTokenCount(total.Cache),
+		fmt.Sprintf("$%.2f", total.Cost), Reset)
+	fmt.Fprintf(out, "\n%sCosts are estimates at API list prices; SHARE is each model's part of the cost.%s\n", Dim, Reset)
+}
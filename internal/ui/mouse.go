@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Mouse reporting (xterm modes 1000 and 1006): presses, releases and wheel
+// notches arrive as SGR sequences, ESC [ < button ; column ; row M (m for a
+// release).
+const (
+	mouseOn  = "\033[?1000h\033[?1006h"
+	mouseOff = "\033[?1006l\033[?1000l"
+)
+
+// mouseEnabled is set between EnableMouse and DisableMouse, so RunInTerminal
+// can hand external programs a terminal without mouse reporting.
+var mouseEnabled bool
+
+// EnableMouse turns on mouse reporting. While it is on, the terminal no
+// longer selects text on its own; most terminals still do with Shift held
+// (Option in macOS Terminal and iTerm2).
+func EnableMouse() {
+	mouseEnabled = true
+	fmt.Print(mouseOn)
+}
+
+// DisableMouse turns mouse reporting off again.
+func DisableMouse() {
+	mouseEnabled = false
+	fmt.Print(mouseOff)
+}
+
+// MouseButton is the button of a mouse event.
+type MouseButton int
+
+const (
+	MouseLeft MouseButton = iota + 1
+	MouseWheelUp
+	MouseWheelDown
+)
+
+// Mouse is a click or wheel notch at a 1-based terminal column and row.
+type Mouse struct {
+	Button MouseButton
+	X, Y   int
+}
+
+// Mouse events travel on ReadKey's channel as runes above the Unicode range:
+// mouseFlag, the button in bits 24-27 and the column and row in 12 bits each.
+const mouseFlag rune = 1 << 30
+
+func mouseKey(m Mouse) rune {
+	return mouseFlag | rune(m.Button)<<24 | rune(min(m.X, 0xfff))<<12 | rune(min(m.Y, 0xfff))
+}
+
+// MouseEvent decodes a key read by ReadKey as a mouse event; ok is false for
+// keyboard keys.
+func MouseEvent(key rune) (m Mouse, ok bool) {
+	if key&mouseFlag == 0 {
+		return Mouse{}, false
+	}
+	return Mouse{Button: MouseButton(key >> 24 & 0xf), X: int(key >> 12 & 0xfff), Y: int(key & 0xfff)}, true
+}
+
+// sgrMouse parses the SGR mouse report b starts with. n is the length of the
+// report, 0 when b doesn't start with a whole one; ok reports whether it is
+// an event csm acts on, a left-button press or a wheel notch. Releases,
+// other buttons and drags are dropped.
+func sgrMouse(b []byte) (m Mouse, n int, ok bool) {
+	if !bytes.HasPrefix(b, []byte("\x1b[<")) {
+		return m, 0, false
+	}
+	end := bytes.IndexAny(b, "Mm")
+	if end < 0 {
+		return m, 0, false
+	}
+	fields := strings.Split(string(b[3:end]), ";")
+	if len(fields) != 3 {
+		return m, 0, false
+	}
+	var v [3]int
+	for i, f := range fields {
+		x, err := strconv.Atoi(f)
+		if err != nil {
+			return m, 0, false
+		}
+		v[i] = x
+	}
+	n = end + 1
+	if b[end] == 'm' {
+		return m, n, false
+	}
+	switch v[0] &^ (4 | 8 | 16) { // Shift, Meta and Ctrl
+	case 0:
+		m.Button = MouseLeft
+	case 64:
+		m.Button = MouseWheelUp
+	case 65:
+		m.Button = MouseWheelDown
+	default:
+		return m, n, false
+	}
+	m.X, m.Y = v[1], v[2]
+	return m, n, true
+}
+
+// hitArea is a part of the last live frame a click can land on: a session
+// row, identified by its LogFile, or a column header, by its sort key.
+type hitArea struct {
+	line, lines int // first frame line (0-based) and number of lines
+	x0, x1      int // columns [x0, x1), 0-based
+	target      string
+}
+
+func (a hitArea) contains(x, y int) bool {
+	return y >= a.line && y < a.line+a.lines && x >= a.x0 && x < a.x1
+}
+
+// The session rows and sortable headers of the last live frame.
+var liveRowHits, liveHeaderHits []hitArea
+
+// RowAt returns the LogFile of the session drawn at the 1-based terminal
+// column x and row y in the last live view frame, or "" for none.
+func RowAt(x, y int) string {
+	return hitAt(liveRowHits, x, y)
+}
+
+// HeaderAt returns the sort key of the column header at the 1-based terminal
+// column x and row y in the last live view frame, or "" for none.
+func HeaderAt(x, y int) string {
+	return hitAt(liveHeaderHits, x, y)
+}
+
+func hitAt(areas []hitArea, x, y int) string {
+	for _, a := range areas {
+		if a.contains(x-1, y-1) {
+			return a.target
+		}
+	}
+	return ""
+}
+
+// headerHits returns the sortable cells of a header drawn by sessionHeader
+// at line, starting at column x.
+func headerHits(l sessionLayout, line, x int) []hitArea {
+	var hits []hitArea
+	cell := func(key string, width int) {
+		if key != "" {
+			hits = append(hits, hitArea{line: line, lines: 1, x0: x, x1: x + width, target: key})
+		}
+		x += width + 1
+	}
+	cell(SortStatus, l.status)
+	cell(SortProject, l.project)
+	if l.origin > 0 {
+		cell("", l.origin)
+	}
+	for _, c := range l.extras {
+		cell("", c.width)
+	}
+	cell(SortContext, l.context)
+	cell(SortActivity, l.activity)
+	return hits
+}
+
+// lineCount is the number of lines written to a frame so far.
+func lineCount(b *strings.Builder) int {
+	return strings.Count(b.String(), "\r\n")
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestMouseEvent(t *testing.T) {
+	m := Mouse{Button: MouseWheelUp, X: 250, Y: 61}
+	if got, ok := MouseEvent(mouseKey(m)); !ok || got != m {
+		t.Errorf("MouseEvent(mouseKey(%v)) = %v, %v", m, got, ok)
+	}
+	for _, key := range []rune{'j', KeyUp, 3} {
+		if _, ok := MouseEvent(key); ok {
+			t.Errorf("MouseEvent(%q) decoded a keyboard key", key)
+		}
+	}
+}
+
+func TestHeaderHits(t *testing.T) {
+	l := calcSessionLayout(120)
+	header := sessionHeader(l)
+	for _, h := range headerHits(l, 3, 0) {
+		if h.line != 3 {
+			t.Errorf("%s header on line %d, want 3", h.target, h.line)
+		}
+		text := []rune(header)[h.x0:h.x1]
+		want := map[string]string{SortStatus: "STATUS", SortProject: "PROJECT", SortContext: "CONTEXT", SortActivity: "LAST ACTIVITY"}[h.target]
+		if !strings.HasPrefix(string(text), want) {
+			t.Errorf("%s header hit covers %q", h.target, string(text))
+		}
+	}
+	liveHeaderHits = headerHits(l, 3, 0)
+	defer func() { liveHeaderHits = nil }()
+	if got := HeaderAt(l.status+2, 4); got != SortProject {
+		t.Errorf("HeaderAt just right of the status column = %q, want project", got)
+	}
+	if got := HeaderAt(1, 5); got != "" {
+		t.Errorf("HeaderAt below the header = %q", got)
+	}
+}
+
+func TestSortLiveRows(t *testing.T) {
+	defer SetLiveSort(SortStatus, false)
+	now := time.Now()
+	rows := func() []session.Session {
+		return []session.Session{
+			{Project: "b", ContextPercent: 10, LastActivity: now.Add(-time.Minute)},
+			{Project: "c", ContextPercent: 80, LastActivity: now.Add(-time.Hour)},
+			{Project: "a", ContextPercent: 40, LastActivity: now},
+		}
+	}
+	tests := []struct {
+		key     string
+		reverse bool
+		want    string
+	}{
+		{SortStatus, false, "bca"},
+		{SortStatus, true, "acb"},
+		{SortProject, false, "abc"},
+		{SortProject, true, "cba"},
+		{SortContext, false, "cab"},
+		{SortActivity, false, "abc"},
+	}
+	for _, tt := range tests {
+		SetLiveSort(tt.key, tt.reverse)
+		r := rows()
+		sortLiveRows(r)
+		got := r[0].Project + r[1].Project + r[2].Project
+		if got != tt.want {
+			t.Errorf("sort by %s (reverse %v) = %s, want %s", tt.key, tt.reverse, got, tt.want)
+		}
+	}
+	SetLiveSort(SortProject, true)
+	if l := sessionHeader(calcSessionLayout(120)); !strings.Contains(l, "PROJECT ↑") || strings.Contains(l, "STATUS ↓") {
+		t.Errorf("header = %q, want the project column marked", l)
+	}
+}
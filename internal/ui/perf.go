@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// renderTimes are how long the latest live frames took to build and draw,
+// oldest first.
+var renderTimes []time.Duration
+
+// renderHistory is how many frames renderTimes keeps.
+const renderHistory = 60
+
+func recordRender(d time.Duration) {
+	renderTimes = append(renderTimes, d)
+	if n := len(renderTimes) - renderHistory; n > 0 {
+		renderTimes = append(renderTimes[:0], renderTimes[n:]...)
+	}
+}
+
+// perfOverlay renders the live view's hidden perf overlay (P) as two lines:
+// discover time over the recorded sweeps, charted as a sparkline, then where
+// the last sweep's time went and how long frames take to draw. Returns nil
+// before the first sweep.
+func perfOverlay(ts []session.Timing, renders []time.Duration, width int) []string {
+	if len(ts) == 0 {
+		return nil
+	}
+	var total, slowest, scan time.Duration
+	for _, t := range ts {
+		total += t.Total
+		slowest = max(slowest, t.Total)
+		scan += t.ProcessScan
+	}
+	last := ts[len(ts)-1]
+
+	head := fmt.Sprintf("perf  discover avg %s · max %s · last %s  ",
+		formatPerfDuration(total/time.Duration(len(ts))), formatPerfDuration(slowest), formatPerfDuration(last.Total))
+	pcts := make([]float64, len(ts))
+	for i, t := range ts {
+		pcts[i] = float64(t.Total) / float64(max(slowest, 1)) * 100
+	}
+	chart := sparkline(pcts, max(width-len([]rune(head)), 0))
+
+	detail := fmt.Sprintf("      process scan avg %s · parse %s/log over %d logs",
+		formatPerfDuration(scan/time.Duration(len(ts))), formatPerfDuration(last.ParsePerLog()), last.Logs)
+	if last.Slowest != "" {
+		detail += fmt.Sprintf(" · slowest %s %s", sanitizeForTerminal(last.Slowest), formatPerfDuration(last.SlowestParse))
+	}
+	if len(renders) > 0 {
+		var sum time.Duration
+		for _, d := range renders {
+			sum += d
+		}
+		detail += fmt.Sprintf(" · render avg %s · max %s", formatPerfDuration(sum/time.Duration(len(renders))), formatPerfDuration(slices.Max(renders)))
+	}
+	return []string{
+		Cyan + truncate(head, width) + Reset + Cyan + chart + Reset,
+		Dim + truncate(detail, width) + Reset,
+	}
+}
+
+// formatPerfDuration formats a timing like "420µs", "3.2ms", "48ms" or "1.25s".
+func formatPerfDuration(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	case d < 10*time.Millisecond:
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestPerfOverlay(t *testing.T) {
+	if lines := perfOverlay(nil, nil, 120); lines != nil {
+		t.Errorf("overlay before any sweep = %q", lines)
+	}
+	ts := []session.Timing{
+		{Total: 10 * time.Millisecond, ProcessScan: 2 * time.Millisecond},
+		{Total: 30 * time.Millisecond, ProcessScan: 4 * time.Millisecond, Parse: 12 * time.Millisecond, Logs: 4, Slowest: "org/api", SlowestParse: 7 * time.Millisecond},
+	}
+	lines := perfOverlay(ts, []time.Duration{time.Millisecond, 3 * time.Millisecond}, 160)
+	if len(lines) != 2 {
+		t.Fatalf("overlay = %q", lines)
+	}
+	head, detail := stripANSIForTest(lines[0]), stripANSIForTest(lines[1])
+	if !strings.Contains(head, "discover avg 20ms · max 30ms · last 30ms") || !strings.HasSuffix(head, "▃█") {
+		t.Errorf("head = %q", head)
+	}
+	for _, want := range []string{"process scan avg 3.0ms", "parse 3.0ms/log over 4 logs", "slowest org/api 7.0ms", "render avg 2.0ms · max 3.0ms"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("detail = %q, want %q", detail, want)
+		}
+	}
+}
+
+func TestFormatPerfDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		420 * time.Microsecond:  "420µs",
+		3200 * time.Microsecond: "3.2ms",
+		48 * time.Millisecond:   "48ms",
+		1250 * time.Millisecond: "1.25s",
+	} {
+		if got := formatPerfDuration(d); got != want {
+			t.Errorf("formatPerfDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
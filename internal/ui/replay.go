@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// ReplayView is the state of a `csm replay` screen.
+type ReplayView struct {
+	Project   string
+	SessionID string
+	Frames    []session.ReplayFrame
+	Pos       int // index of the frame being shown
+	Speed     float64
+	Paused    bool
+}
+
+// replayTransitions is how many status transitions the replay lists.
+const replayTransitions = 6
+
+// sparkBlocks draw the context growth graph, lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// RenderReplay redraws the replay screen in place. Uses \r\n for raw
+// terminal mode.
+func RenderReplay(v ReplayView) {
+	var b strings.Builder
+	renderReplay(&b, v, getTerminalWidth(), getTerminalHeight())
+	liveScreen.draw(b.String())
+}
+
+func renderReplay(w io.Writer, v ReplayView, width, height int) {
+	if len(v.Frames) == 0 {
+		fmt.Fprintf(w, "%sNo entries to replay.%s\r\n", Dim, Reset)
+		return
+	}
+	frame := v.Frames[v.Pos]
+	start := v.Frames[0].Time
+
+	state := fmt.Sprintf("▶ %gx", v.Speed)
+	if v.Paused {
+		state = "⏸ paused"
+	}
+	fmt.Fprintf(w, "%scsm replay%s  %s  %s%s%s\r\n", Bold, Reset,
+		truncate(sanitizeForTerminal(v.Project), max(width-40, 10)), Dim, v.SessionID, Reset)
+	fmt.Fprintf(w, "%s  %s  +%s  %sentry %d/%d%s\r\n\r\n",
+		state, frame.Time.Local().Format("2006-01-02 "+secondsLayout), frame.Time.Sub(start).Round(time.Second),
+		Dim, v.Pos+1, len(v.Frames), Reset)
+
+	task := ""
+	if frame.Task != "" && frame.Task != "-" {
+		task = " " + truncate(sanitizeForTerminal(frame.Task), max(width-fixedStatusWidth-12, 10))
+	}
+	fmt.Fprintf(w, "Status   %s%s\r\n", formatStatus(frame.Status, fixedStatusWidth), task)
+	ctx := session.Session{ContextPercent: frame.ContextPercent, ContextTokens: frame.ContextTokens, Model: frame.Model}
+	tokens := ""
+	if frame.ContextTokens > 0 {
+		tokens = fmt.Sprintf(" %s%s tokens%s", Dim, formatTokenCount(frame.ContextTokens), Reset)
+	}
+	fmt.Fprintf(w, "Context  %s%s\r\n", formatContext(ctx, 0), tokens)
+	fmt.Fprintf(w, "Growth   %s\r\n\r\n", contextSparkline(v.Frames[:v.Pos+1], max(width-10, 10)))
+
+	fmt.Fprintf(w, "%sTransitions%s\r\n", Bold, Reset)
+	transitions := replayStatusChanges(v.Frames[:v.Pos+1])
+	if len(transitions) > replayTransitions {
+		transitions = transitions[len(transitions)-replayTransitions:]
+	}
+	for _, i := range transitions {
+		f := v.Frames[i]
+		from := "start"
+		if i > 0 {
+			from = string(v.Frames[i-1].Status)
+		}
+		fmt.Fprintf(w, "  %s+%-9s%s %s → %s\r\n", Dim, f.Time.Sub(start).Round(time.Second), Reset, from, formatStatus(f.Status, 0))
+	}
+	fmt.Fprintln(w, "\r")
+
+	// The entries leading up to the current one fill the rest of the screen:
+	// 7 header/status lines, the transitions, 2 lines of headings and spacing
+	// and 2 of footer.
+	rows := max(height-11-len(transitions), 1)
+	fmt.Fprintf(w, "%sLog%s\r\n", Bold, Reset)
+	var lines []string
+	for i := v.Pos; i >= 0 && len(lines) < rows; i-- {
+		f := v.Frames[i]
+		if f.Entry == nil {
+			continue
+		}
+		kind, text := replayEntryText(f.Entry)
+		if kind == "" {
+			continue
+		}
+		color := ""
+		if i == v.Pos {
+			color = Bold
+		}
+		lines = append(lines, fmt.Sprintf("  %s%s %s%-9s%s %s%s", color, f.Time.Local().Format(secondsLayout), Gray, kind, Reset+color,
+			truncate(sanitizeForTerminal(text), max(width-22, 10)), Reset))
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		fmt.Fprintf(w, "%s\r\n", lines[i])
+	}
+
+	fmt.Fprintf(w, "\r\n%sspace: pause/step | ←/→: step | p: play | +/-: speed | q: quit%s\r\n", Dim, Reset)
+}
+
+// replayStatusChanges returns the indexes of the frames whose status differs
+// from the frame before, the first frame included.
+func replayStatusChanges(frames []session.ReplayFrame) []int {
+	var idx []int
+	for i, f := range frames {
+		if i == 0 || f.Status != frames[i-1].Status {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// contextSparkline draws context usage over the frames as block characters,
+// one per column, so growth and compactions show as a shape.
+func contextSparkline(frames []session.ReplayFrame, width int) string {
+	pcts := make([]float64, len(frames))
+	for i, f := range frames {
+		pcts[i] = f.ContextPercent
+	}
+	return sparkline(pcts, width)
+}
+
+// sparkline draws percentages as block characters, at most width of them.
+// When there are more values than columns, each column shows the last value
+// of its slice.
+func sparkline(pcts []float64, width int) string {
+	if len(pcts) == 0 {
+		return ""
+	}
+	n := min(len(pcts), width)
+	var b strings.Builder
+	for col := range n {
+		pct := min(max(pcts[(col+1)*len(pcts)/n-1], 0), 100)
+		b.WriteRune(sparkBlocks[int(pct/100*float64(len(sparkBlocks)-1)+0.5)])
+	}
+	return b.String()
+}
+
+// replayEntryText describes a log entry in a word and a line, or returns an
+// empty kind for entries not worth showing.
+func replayEntryText(e *session.LogEntry) (kind, text string) {
+	switch e.Type {
+	case "user", "assistant":
+		if e.Message == nil {
+			return "", ""
+		}
+		var parts []string
+		for _, c := range e.Message.Content {
+			switch c.Type {
+			case "text":
+				if t := strings.Join(strings.Fields(c.Text), " "); t != "" {
+					parts = append(parts, t)
+				}
+			case "tool_use":
+				var in session.BashToolInput
+				if c.Name == "Bash" && json.Unmarshal(c.Input, &in) == nil && in.Command != "" {
+					parts = append(parts, "Bash: "+strings.Join(strings.Fields(in.Command), " "))
+				} else {
+					parts = append(parts, c.Name)
+				}
+			case "tool_result":
+				kind = "result"
+			}
+		}
+		if kind == "" {
+			kind = e.Type
+		}
+		return kind, strings.Join(parts, " · ")
+	case "system":
+		text := e.Subtype
+		if e.DurationMs > 0 {
+			text += " " + (time.Duration(e.DurationMs) * time.Millisecond).Round(time.Second).String()
+		}
+		return "system", text
+	}
+	return "", ""
+}
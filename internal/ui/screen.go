@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Synchronized output (DEC mode 2026): terminals that support it show the
+// whole frame at once; others ignore the sequence.
+const (
+	syncBegin = "\033[?2026h"
+	syncEnd   = "\033[?2026l"
+)
+
+// screen redraws a full-screen view by rewriting only the lines that differ
+// from the previous frame, which avoids the flicker of clearing the screen
+// on every refresh (most visible on slow terminals and over SSH).
+type screen struct {
+	out   io.Writer
+	prev  []string // lines of the last frame; nil forces a full redraw
+	width int      // terminal width the last frame was drawn for
+}
+
+// liveScreen is the screen the live view draws to.
+var liveScreen = &screen{out: os.Stdout}
+
+// draw shows frame, whose lines are separated by "\r\n". It falls back to a
+// full redraw after a resize, after something else drew on the screen, or
+// when a line is wider than the terminal, since a wrapped line would shift
+// every row below it.
+func (sc *screen) draw(frame string) {
+	lines := strings.Split(strings.TrimSuffix(frame, "\r\n"), "\r\n")
+	width := getTerminalWidth()
+
+	full := sc.prev == nil || width != sc.width
+	for _, line := range lines {
+		if visibleWidth(line) > width {
+			full = true
+			break
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(syncBegin)
+	if full {
+		b.WriteString("\033[2J\033[H")
+		// No newline after the last line: a frame that fills the screen
+		// (the live view's bottom status bar) must not scroll it.
+		b.WriteString(strings.Join(lines, "\r\n"))
+	} else {
+		for i, line := range lines {
+			if i < len(sc.prev) && sc.prev[i] == line {
+				continue
+			}
+			fmt.Fprintf(&b, "\033[%d;1H%s\033[K", i+1, line)
+		}
+		if len(lines) < len(sc.prev) {
+			// The frame got shorter: clear what is left of the old one.
+			fmt.Fprintf(&b, "\033[%d;1H\033[J", len(lines)+1)
+		}
+	}
+	b.WriteString(syncEnd)
+	io.WriteString(sc.out, b.String())
+
+	sc.prev = lines
+	sc.width = width
+}
+
+// invalidate forgets the previous frame so the next draw repaints everything.
+func (sc *screen) invalidate() {
+	sc.prev = nil
+}
+
+// visibleWidth is the number of terminal columns s takes, skipping CSI
+// (ESC [ ... letter) and OSC (ESC ] ... BEL or ESC \) escape sequences.
+func visibleWidth(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b && i+1 < len(s) {
+			switch s[i+1] {
+			case '[':
+				j := i + 2
+				for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+					j++
+				}
+				i = j + 1
+				continue
+			case ']':
+				j := i + 2
+				for j < len(s) && s[j] != 0x07 && !(s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\') {
+					j++
+				}
+				if j < len(s) && s[j] == 0x1b {
+					j++
+				}
+				i = j + 1
+				continue
+			}
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		n++
+		i += size
+	}
+	return n
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScreenDraw(t *testing.T) {
+	var out strings.Builder
+	sc := &screen{out: &out}
+
+	sc.draw("title\r\nrow one\r\nrow two\r\nfooter\r\n")
+	if !strings.Contains(out.String(), "\033[2J") {
+		t.Fatalf("first frame should be a full redraw: %q", out.String())
+	}
+
+	out.Reset()
+	sc.draw("title\r\nrow one\r\nrow 2\r\nfooter\r\n")
+	got := out.String()
+	if strings.Contains(got, "\033[2J") || strings.Contains(got, "title") || strings.Contains(got, "row one") {
+		t.Errorf("unchanged lines were redrawn: %q", got)
+	}
+	if !strings.Contains(got, "\033[3;1Hrow 2\033[K") {
+		t.Errorf("changed line not rewritten in place: %q", got)
+	}
+
+	out.Reset()
+	sc.draw("title\r\n")
+	if !strings.Contains(out.String(), "\033[2;1H\033[J") {
+		t.Errorf("a shorter frame should clear the leftover lines: %q", out.String())
+	}
+
+	out.Reset()
+	sc.invalidate()
+	sc.draw("title\r\n")
+	if !strings.Contains(out.String(), "\033[2J") {
+		t.Errorf("invalidate should force a full redraw: %q", out.String())
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"plain", 5},
+		{Green + "● Working" + Reset, 9},
+		{terminalLink("https://status.claude.com/", "status"), 6},
+		{"\033]0;title\007x", 1},
+	}
+	for _, tt := range tests {
+		if got := visibleWidth(tt.in); got != tt.want {
+			t.Errorf("visibleWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// Orders of the live view's rows, chosen by clicking a column header.
+const (
+	SortStatus   = "status"   // status, then latest activity, as Discover returns them (default)
+	SortProject  = "project"  // by name
+	SortContext  = "context"  // fullest context first
+	SortActivity = "activity" // latest activity first
+)
+
+var (
+	liveSort        = SortStatus
+	liveSortReverse bool
+)
+
+// SetLiveSort sets the order of the live view's rows, one of the Sort
+// constants; reverse flips it.
+func SetLiveSort(key string, reverse bool) {
+	liveSort, liveSortReverse = key, reverse
+}
+
+// LiveSort returns the order set by SetLiveSort.
+func LiveSort() (key string, reverse bool) {
+	return liveSort, liveSortReverse
+}
+
+// sortLiveRows puts rows in the live sort order.
+func sortLiveRows(rows []session.Session) {
+	var compare func(a, b session.Session) int
+	switch liveSort {
+	case SortProject:
+		compare = func(a, b session.Session) int { return strings.Compare(a.Project, b.Project) }
+	case SortContext:
+		compare = func(a, b session.Session) int { return cmp.Compare(b.ContextPercent, a.ContextPercent) }
+	case SortActivity:
+		compare = func(a, b session.Session) int { return b.LastActivity.Compare(a.LastActivity) }
+	default:
+		if liveSortReverse {
+			slices.Reverse(rows)
+		}
+		return
+	}
+	slices.SortStableFunc(rows, func(a, b session.Session) int {
+		if liveSortReverse {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+}
+
+// sortMark is the arrow after the header of the column rows are sorted by,
+// "" for other columns and for the default order.
+func sortMark(key string) string {
+	switch {
+	case key != liveSort || (key == SortStatus && !liveSortReverse):
+		return ""
+	case liveSortReverse:
+		return " ↑"
+	}
+	return " ↓"
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/i18n"
+)
+
+// StatusBar holds the slow-changing figures for the live view's bottom bar.
+// They are gathered in the background since they need a log scan and an API
+// call.
+type StatusBar struct {
+	TodayTokens   int     // tokens consumed since local midnight
+	OverBudget    bool    // today's usage exceeds the daily budget
+	WindowPercent float64 // 5-hour quota utilization from the usage API
+	HasWindow     bool    // WindowPercent is known
+}
+
+// formatStatusBar renders the bar as one reverse-video line of exactly width
+// columns: clock, today's tokens, 5-hour window usage and hidden sessions.
+func formatStatusBar(bar *StatusBar, hidden, width int, now time.Time) string {
+	parts := []string{now.Format(clockLayout)}
+	if bar != nil {
+		today := i18n.T("today %s tokens", formatTokenCount(bar.TodayTokens))
+		if bar.OverBudget {
+			today += " " + i18n.T("(over budget)")
+		}
+		parts = append(parts, today)
+		if bar.HasWindow {
+			parts = append(parts, i18n.T("5h window %.0f%%", bar.WindowPercent))
+		}
+	}
+	if hidden > 0 {
+		parts = append(parts, i18n.T("%d hidden", hidden))
+	}
+
+	text := truncate(" "+strings.Join(parts, " · "), width)
+	if pad := width - len([]rune(text)); pad > 0 {
+		text += strings.Repeat(" ", pad)
+	}
+	return Reverse + text + Reset
+}
+
+// TodayTotals sums today's work across all sessions for the live view's
+// header.
+type TodayTotals struct {
+	Active    time.Duration // session time since local midnight
+	Input     int           // input tokens, cache reads and writes included
+	Output    int           // output tokens
+	Cost      float64       // estimated USD list price
+	Completed int           // sessions active today that have since ended
+}
+
+// formatTodayTotals renders t as the header's one-line summary, e.g.
+// "Today: 3h 12m active · 1.2M in / 84K out · $4.20 · 5 sessions done".
+func formatTodayTotals(t TodayTotals) string {
+	done := i18n.T("%d sessions done", t.Completed)
+	if t.Completed == 1 {
+		done = i18n.T("1 session done")
+	}
+	return i18n.T("Today: %s active · %s in / %s out · $%.2f · %s",
+		formatDuration(t.Active), formatTokenCount(t.Input), formatTokenCount(t.Output), t.Cost, done)
+}
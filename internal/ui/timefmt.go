@@ -0,0 +1,46 @@
+package ui
+
+import "time"
+
+// Time display settings, see SetTimeFormat.
+var (
+	absoluteTimes bool
+	clockLayout   = "15:04"
+	secondsLayout = "15:04:05"
+)
+
+// SetTimeFormat chooses how times are shown: with absolute, last activity is
+// a clock time ("14:32") instead of relative ("3m ago"); with hour12, clock
+// times use a 12-hour clock ("2:32pm"). Times are in time.Local, so a
+// timezone override is applied by setting that.
+func SetTimeFormat(absolute, hour12 bool) {
+	absoluteTimes = absolute
+	clockLayout, secondsLayout = "15:04", "15:04:05"
+	if hour12 {
+		clockLayout, secondsLayout = "3:04pm", "3:04:05pm"
+	}
+}
+
+// formatClock formats t as a local clock time, e.g. "14:32".
+func formatClock(t time.Time) string {
+	return t.Local().Format(clockLayout)
+}
+
+// FormatDateTime formats t as a local date and clock time, e.g.
+// "2026-01-02 14:32".
+func FormatDateTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 " + clockLayout)
+}
+
+// formatActivity says when a session was last active: "3m ago", or with
+// absolute times the clock time, prefixed by the date if it was not today.
+func formatActivity(t, now time.Time) string {
+	if !absoluteTimes {
+		return formatElapsed(now.Sub(t))
+	}
+	t, now = t.Local(), now.Local()
+	if t.YearDay() != now.YearDay() || t.Year() != now.Year() {
+		return t.Format("Jan 2 " + clockLayout)
+	}
+	return t.Format(clockLayout)
+}
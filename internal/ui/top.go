@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// Sort keys for the top view.
+const (
+	TopByTokens = "tokens"
+	TopByCost   = "cost"
+)
+
+// TopRow is one session in the top view with its recent token burn.
+type TopRow struct {
+	Session session.Session
+	Rate    session.Rate
+}
+
+// SortTop orders rows by burn rate, highest first, by tokens per minute or
+// cost per hour. Ties fall back to the most recent activity.
+func SortTop(rows []TopRow, by string) {
+	key := func(r TopRow) float64 {
+		if by == TopByCost {
+			return r.Rate.CostPerHour()
+		}
+		return r.Rate.TokensPerMinute()
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		ki, kj := key(rows[i]), key(rows[j])
+		if ki != kj {
+			return ki > kj
+		}
+		return rows[i].Session.LastActivity.After(rows[j].Session.LastActivity)
+	})
+}
+
+// Top view column widths; the project takes the rest of the line.
+const (
+	topRateWidth   = 9
+	topCostWidth   = 8
+	topTokensWidth = 7
+	topModelWidth  = 16
+)
+
+// RenderTop redraws the top view in place: totals in the header and one row
+// per session, sorted by the given key. Uses \r\n for raw terminal mode.
+func RenderTop(rows []TopRow, by string) {
+	var b strings.Builder
+	renderTop(&b, rows, by, getTerminalWidth(), getTerminalHeight())
+	liveScreen.draw(b.String())
+}
+
+func renderTop(w io.Writer, rows []TopRow, by string, width, height int) {
+	var tokens float64
+	var cost, background float64
+	burning := 0
+	for _, r := range rows {
+		tokens += r.Rate.TokensPerMinute()
+		cost += r.Rate.CostPerHour()
+		background += r.Rate.BackgroundCostPerHour()
+		if r.Rate.Tokens > 0 {
+			burning++
+		}
+	}
+	window := "5m"
+	if len(rows) > 0 {
+		window = formatDurationCompact(rows[0].Rate.Window)
+	}
+
+	// Background tasks (session titles, summaries) are overhead on top of
+	// the work the sessions were asked to do.
+	overhead := ""
+	if background > 0 {
+		overhead = fmt.Sprintf(" %s(~$%.2f/h background)%s", Dim, background, Reset)
+	}
+	fmt.Fprintf(w, "%scsm top%s  %s tok/min  ~$%.2f/h%s  %d of %d sessions burning %s(last %s)%s\r\n\r\n",
+		Bold, Reset, formatTokenCount(int(tokens)), cost, overhead, burning, len(rows), Dim, window, Reset)
+
+	project := width - topRateWidth - topCostWidth - topTokensWidth - fixedStatusWidth - topModelWidth - 5
+	if project < 10 {
+		project = 10
+	}
+	rateHeader, costHeader := "TOK/MIN", "$/HOUR"
+	if by == TopByCost {
+		costHeader = "$/HOUR▼"
+	} else {
+		rateHeader = "TOK/MIN▼"
+	}
+	header := fmt.Sprintf("%*s %*s %*s %-*s %-*s %s",
+		topRateWidth, rateHeader, topCostWidth, costHeader, topTokensWidth, "TOKENS",
+		fixedStatusWidth, "STATUS", topModelWidth, "MODEL", "PROJECT")
+	fmt.Fprintf(w, "%s%s%s\r\n", Bold, header, Reset)
+
+	// Header (3 lines) and footer (2 lines) around the table.
+	maxRows := height - 5
+	if maxRows < 1 {
+		maxRows = 1
+	}
+	for i, r := range rows {
+		if i == maxRows {
+			fmt.Fprintf(w, "%s  ... and %d more%s\r\n", Dim, len(rows)-i, Reset)
+			break
+		}
+		color := ""
+		if r.Rate.Tokens == 0 {
+			color = Dim
+		}
+		fmt.Fprintf(w, "%s%*s %*s %*s%s %s %s%-*s%s %s\r\n",
+			color,
+			topRateWidth, formatTokenCount(int(r.Rate.TokensPerMinute())),
+			topCostWidth, fmt.Sprintf("$%.2f", r.Rate.CostPerHour()),
+			topTokensWidth, formatTokenCount(r.Rate.Tokens), Reset,
+			formatStatus(r.Session.Status, fixedStatusWidth),
+			Gray, topModelWidth, truncate(r.Session.Model, topModelWidth), Reset,
+			truncate(sanitizeForTerminal(r.Session.Project), project))
+	}
+	if len(rows) == 0 {
+		fmt.Fprintf(w, "%sNo sessions.%s\r\n", Dim, Reset)
+	}
+
+	fmt.Fprintf(w, "\r\n%st: sort by tokens | c: sort by cost | q: quit%s\r\n", Dim, Reset)
+}
@@ -1,12 +1,17 @@
 package ui
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/itk-dev/claude-sessions-monitor/internal/i18n"
 	"github.com/itk-dev/claude-sessions-monitor/internal/session"
 )
 
@@ -15,11 +20,13 @@ const (
 	Reset   = "\033[0m"
 	Bold    = "\033[1m"
 	Dim     = "\033[2m"
+	Reverse = "\033[7m"
 	Green   = "\033[32m"
 	Yellow  = "\033[33m"
 	Blue    = "\033[34m"
 	Red     = "\033[31m"
 	Gray    = "\033[90m"
+	Cyan    = "\033[36m"
 	BgGreen = "\033[42m"
 )
 
@@ -34,118 +41,330 @@ const (
 
 // RenderList renders sessions as a simple list (for -l flag)
 func RenderList(sessions []session.Session) {
+	if msg := detectionMessage(); msg != "" {
+		fmt.Println(Yellow + msg + Reset)
+	}
 	if len(sessions) == 0 {
-		fmt.Println("No active Claude sessions found.")
+		if msg := noClaudeMessage(); msg != "" {
+			fmt.Println(msg)
+			return
+		}
+		fmt.Println(i18n.T("No active Claude sessions found."))
+		return
+	}
+
+	width := getTerminalWidth()
+	if useCards(width) {
+		for _, s := range sessions {
+			renderSessionCard(os.Stdout, s, width, false, "\n")
+		}
 		return
 	}
 
-	l := calcSessionLayout(getTerminalWidth())
+	l := calcSessionLayout(width)
 
 	// Header
 	fmt.Println(sessionHeader(l))
 	fmt.Println(strings.Repeat("─", l.totalWidth))
 
 	for _, s := range sessions {
-		renderSessionRow(s, l, "\n")
+		renderSessionRow(os.Stdout, s, l, false, "\n")
 	}
 }
 
 // sessionHeader returns the column header row matching the given layout.
 func sessionHeader(l sessionLayout) string {
+	parts := []string{
+		fmt.Sprintf("%-*s", l.status, i18n.T("STATUS")+sortMark(SortStatus)),
+		fmt.Sprintf("%-*s", l.project, i18n.T("PROJECT")+sortMark(SortProject)),
+	}
 	if l.origin > 0 {
-		return fmt.Sprintf("%-*s %-*s %-*s %-*s %-*s",
-			l.status, "STATUS",
-			l.project, "PROJECT",
-			l.origin, "ORIGIN",
-			l.context, "CONTEXT",
-			l.activity, "LAST ACTIVITY")
+		parts = append(parts, fmt.Sprintf("%-*s", l.origin, i18n.T("ORIGIN")))
+	}
+	for _, c := range l.extras {
+		parts = append(parts, fmt.Sprintf("%-*s", c.width, i18n.T(c.header)))
 	}
-	return fmt.Sprintf("%-*s %-*s %-*s %-*s",
-		l.status, "STATUS",
-		l.project, "PROJECT",
-		l.context, "CONTEXT",
-		l.activity, "LAST ACTIVITY")
+	parts = append(parts,
+		fmt.Sprintf("%-*s", l.context, i18n.T("CONTEXT")+sortMark(SortContext)),
+		fmt.Sprintf("%-*s", l.activity, i18n.T("LAST ACTIVITY")+sortMark(SortActivity)))
+	return strings.Join(parts, " ")
 }
 
 // RenderJSON renders sessions as JSON
 func RenderJSON(sessions []session.Session) error {
+	if sessions == nil {
+		sessions = []session.Session{} // "[]" rather than "null" for scripts
+	}
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(sessions)
 }
 
+// OneLine summarizes the active sessions in a single plain-text line, e.g.
+// "2 working, 1 needs input (org/api), 3 waiting", for scripts, MOTDs and
+// other tools' status lines.
+func OneLine(sessions []session.Session) string {
+	active := LiveRows(sessions)
+	if len(active) == 0 {
+		return "no active sessions"
+	}
+	counts := countByStatus(active)
+
+	var parts []string
+	for _, st := range []session.Status{session.StatusWorking, session.StatusNeedsInput, session.StatusWaiting, session.StatusIdle} {
+		n := counts[st]
+		if n == 0 {
+			continue
+		}
+		part := fmt.Sprintf("%d %s", n, strings.ToLower(string(st)))
+		if st == session.StatusNeedsInput {
+			var projects []string
+			for _, s := range active {
+				if s.Status == st && !slices.Contains(projects, s.Project) {
+					projects = append(projects, s.Project)
+				}
+			}
+			part += " (" + strings.Join(projects, ", ") + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// LiveOptions carries the interactive state the live view renders around the
+// session table.
+type LiveOptions struct {
+	WebURL       string                   // web dashboard URL shown in the footer; empty when --web is off
+	ClaudeStatus *session.ClaudeStatus    // service status from status.claude.com
+	Selected     string                   // LogFile of the selected row; empty for no selection
+	Message      string                   // one-line feedback from the last action (e.g. an open error)
+	Notify       bool                     // desktop notifications are on; enables the mute key hint
+	UpdateHint   string                   // newer release version, e.g. "v1.4.0"; empty when up to date
+	NeedsInput   bool                     // only needs-input sessions are shown (--only-needs-input)
+	Split        bool                     // split view: today's finished sessions below the live table
+	Today        []session.HistorySession // sessions finished today, for the split view
+	StatusBar    *StatusBar               // today's tokens and quota for the bottom bar; nil until first gathered
+	TodayTotals  *TodayTotals             // today's totals for the header; nil until first gathered
+	Hidden       int                      // running sessions left out of the table (ghosts, --only-needs-input)
+	Identity     string                   // user@hostname (or a configured label) shown in the header
+	Tmux         bool                     // csm runs inside tmux; enables the jump key hints
+	Progress     bool                     // report sessions through the terminal progress indicator
+	ITerm2       bool                     // set the iTerm2 badge and tab color
+	Scroll       int                      // rows scrolled off the top of the table with the mouse wheel
+	Perf         bool                     // show the perf overlay (the hidden P key)
+}
+
+// LiveRows returns the sessions shown as rows in the live view, in display
+// order (see SetLiveSort). Inactive and ghost sessions are summarised in the
+// header only.
+func LiveRows(sessions []session.Session) []session.Session {
+	var active []session.Session
+	for _, s := range sessions {
+		if !s.IsGhost && s.Status != session.StatusInactive {
+			active = append(active, s)
+		}
+	}
+	sortLiveRows(active)
+	return active
+}
+
 // RenderLive renders the live dashboard view
 // Uses \r\n for newlines to work correctly in raw terminal mode
-// If webURL is non-empty, the web dashboard shortcut is shown in the footer.
-func RenderLive(sessions []session.Session, webURL string, claudeStatus *session.ClaudeStatus) {
+// If opts.WebURL is non-empty, the web dashboard shortcut is shown in the footer.
+func RenderLive(sessions []session.Session, opts LiveOptions) {
+	start := time.Now()
+	defer func() { recordRender(time.Since(start)) }()
+
 	// Set terminal title with status summary
 	SetTerminalTitle(buildTerminalTitle(sessions))
+	if opts.Progress {
+		fmt.Print(terminalProgress(sessions))
+	}
+	if opts.ITerm2 {
+		fmt.Print(itermStatus(sessions))
+	}
 
-	// Clear screen and move cursor to top
-	fmt.Print("\033[2J\033[H")
+	// The frame is built in memory and handed to liveScreen, which only
+	// rewrites the lines that changed since the previous frame.
+	var b strings.Builder
 
 	// Header
-	fmt.Printf("%sClaude Code Sessions%s\r\n\r\n", Bold, Reset)
-
-	// Split sessions into active and inactive (ghosts are included in inactive)
-	var active, inactive []session.Session
-	for _, s := range sessions {
-		if s.IsGhost || s.Status == session.StatusInactive {
-			inactive = append(inactive, s)
-		} else {
-			active = append(active, s)
-		}
+	fmt.Fprintf(&b, "%s%s%s", Bold, i18n.T("Claude Code Sessions"), Reset)
+	if opts.Identity != "" {
+		fmt.Fprintf(&b, "  %s%s%s", Cyan, sanitizeForTerminal(opts.Identity), Reset)
+	}
+	if opts.TodayTotals != nil {
+		fmt.Fprintf(&b, "\r\n%s%s%s", Dim, truncate(formatTodayTotals(*opts.TodayTotals), getTerminalWidth()), Reset)
 	}
+	fmt.Fprint(&b, "\r\n\r\n")
 
-	// Status summary (only active sessions)
+	active := LiveRows(sessions)
+
+	// Status summary (only active sessions). In needs-input mode the table
+	// only holds sessions waiting on the user, so say that loudly instead.
+	width := getTerminalWidth()
 	counts := countByStatus(active)
-	fmt.Printf("%s%s Working: %d%s  ", Green, SymbolWorking, counts[session.StatusWorking], Reset)
-	fmt.Printf("%s%s Needs Input: %d%s  ", Yellow, SymbolNeedsInput, counts[session.StatusNeedsInput], Reset)
-	fmt.Printf("%s%s Waiting: %d%s", Blue, SymbolWaiting, counts[session.StatusWaiting], Reset)
-	fmt.Print("\r\n")
+	if opts.NeedsInput {
+		renderNeedsInputBanner(&b, counts[session.StatusNeedsInput])
+	} else if useCards(width) {
+		fmt.Fprintf(&b, "%s%s %d%s  %s%s %d%s  %s%s %d%s\r\n",
+			Green, SymbolWorking, counts[session.StatusWorking], Reset,
+			Yellow, SymbolNeedsInput, counts[session.StatusNeedsInput], Reset,
+			Blue, SymbolWaiting, counts[session.StatusWaiting], Reset)
+	} else {
+		fmt.Fprintf(&b, "%s%s %s: %d%s  ", Green, SymbolWorking, statusName(session.StatusWorking), counts[session.StatusWorking], Reset)
+		fmt.Fprintf(&b, "%s%s %s: %d%s  ", Yellow, SymbolNeedsInput, statusName(session.StatusNeedsInput), counts[session.StatusNeedsInput], Reset)
+		fmt.Fprintf(&b, "%s%s %s: %d%s", Blue, SymbolWaiting, statusName(session.StatusWaiting), counts[session.StatusWaiting], Reset)
+		fmt.Fprint(&b, "\r\n")
+	}
 
-	fmt.Print("\r\n")
+	detection := detectionMessage()
+	if detection != "" {
+		fmt.Fprintf(&b, "%s%s%s\r\n", Yellow, truncate(detection, width), Reset)
+	}
+	fmt.Fprint(&b, "\r\n")
+
+	// Rows scrolled off the top are left out and counted above the rest.
+	// The grid scrolls by whole grid rows.
+	liveRowHits, liveHeaderHits = nil, nil
+	skip := min(opts.Scroll, max(len(active)-1, 0))
+	if useGrid(width) && !useCards(width) {
+		skip -= skip % 2
+	}
+	shown := active[skip:]
+	if skip > 0 {
+		fmt.Fprintf(&b, "%s%s%s\r\n", Dim, i18n.T("↑ %d more", skip), Reset)
+	}
+	row := func(s session.Session, line int) {
+		liveRowHits = append(liveRowHits, hitArea{line: line, lines: lineCount(&b) - line, x1: width, target: s.LogFile})
+	}
 
 	if len(active) == 0 {
-		fmt.Printf("%sNo active Claude sessions.%s\r\n", Dim, Reset)
+		if msg := noClaudeMessage(); msg != "" && len(sessions) == 0 {
+			fmt.Fprintf(&b, "%s%s%s\r\n", Dim, sanitizeForTerminal(msg), Reset)
+		} else if !opts.NeedsInput && detection == "" {
+			fmt.Fprintf(&b, "%s%s%s\r\n", Dim, i18n.T("No active Claude sessions."), Reset)
+		}
+	} else if useCards(width) {
+		fmt.Fprintf(&b, "%s\r\n", strings.Repeat("─", width))
+		for _, s := range shown {
+			line := lineCount(&b)
+			renderSessionCard(&b, s, width, opts.Selected != "" && s.LogFile == opts.Selected, "\r\n")
+			row(s, line)
+		}
+	} else if useGrid(width) {
+		top := lineCount(&b)
+		rows, headers := renderSessionGrid(&b, shown, width, opts.Selected)
+		for _, h := range rows {
+			h.line += top
+			liveRowHits = append(liveRowHits, h)
+		}
+		for _, h := range headers {
+			h.line += top
+			liveHeaderHits = append(liveHeaderHits, h)
+		}
 	} else {
-		l := calcSessionLayout(getTerminalWidth())
+		l := calcSessionLayout(width)
+		if slices.ContainsFunc(active, func(s session.Session) bool { return !s.StatusSince.IsZero() }) {
+			l.widenStatus()
+		}
 
 		// Column headers
-		fmt.Printf("%s\r\n", sessionHeader(l))
-		fmt.Printf("%s\r\n", strings.Repeat("─", l.totalWidth))
+		liveHeaderHits = headerHits(l, lineCount(&b), 0)
+		fmt.Fprintf(&b, "%s\r\n", sessionHeader(l))
+		fmt.Fprintf(&b, "%s\r\n", strings.Repeat("─", l.totalWidth))
+
+		for _, s := range shown {
+			line := lineCount(&b)
+			renderSessionRow(&b, s, l, opts.Selected != "" && s.LogFile == opts.Selected, "\r\n")
+			row(s, line)
+		}
+	}
 
-		for _, s := range active {
-			renderSessionRow(s, l, "\r\n")
+	if opts.Split {
+		// Leave room for the status, message and footer lines below.
+		budget := getTerminalHeight() - strings.Count(b.String(), "\r\n") - 7
+		renderToday(&b, opts.Today, width, budget)
+	}
+
+	if opts.Perf {
+		fmt.Fprint(&b, "\r\n")
+		for _, line := range perfOverlay(session.Timings(), renderTimes, width) {
+			fmt.Fprintf(&b, "%s\r\n", line)
 		}
 	}
 
 	// Show Claude service status
+	claudeStatus := opts.ClaudeStatus
 	statusLink := terminalLink("https://status.claude.com/", "status.claude.com")
-	fmt.Print("\r\n")
+	fmt.Fprint(&b, "\r\n")
 	if claudeStatus != nil && claudeStatus.Available {
 		switch claudeStatus.Indicator {
 		case "minor":
-			fmt.Printf("%s%s Claude: %s - %s%s\r\n", Yellow, "\u26A0", claudeStatus.Description, statusLink, Reset)
+			fmt.Fprintf(&b, "%s%s Claude: %s - %s%s\r\n", Yellow, "\u26A0", claudeStatus.Description, statusLink, Reset)
 		case "major", "critical":
-			fmt.Printf("%s%s Claude: %s - %s%s\r\n", Red, "\u2716", claudeStatus.Description, statusLink, Reset)
+			fmt.Fprintf(&b, "%s%s Claude: %s - %s%s\r\n", Red, "\u2716", claudeStatus.Description, statusLink, Reset)
 		default:
-			fmt.Printf("%sClaude: %s - %s%s\r\n", Dim, claudeStatus.Description, statusLink, Reset)
+			fmt.Fprintf(&b, "%sClaude: %s - %s%s\r\n", Dim, claudeStatus.Description, statusLink, Reset)
 		}
 	} else {
-		fmt.Printf("%sClaude: Status unavailable - %s%s\r\n", Dim, statusLink, Reset)
+		fmt.Fprintf(&b, "%sClaude: %s - %s%s\r\n", Dim, i18n.T("Status unavailable"), statusLink, Reset)
+	}
+
+	if opts.Message != "" {
+		fmt.Fprintf(&b, "%s%s%s\r\n", Yellow, sanitizeForTerminal(opts.Message), Reset)
 	}
 
 	// Show help footer
-	if webURL != "" {
-		fmt.Printf("%sh: history | u: usage | w: open webview (%s) | Ctrl+C: quit%s\r\n", Dim, webURL, Reset)
-	} else {
-		fmt.Printf("%sh: history | u: usage | Ctrl+C: quit%s\r\n", Dim, Reset)
+	keys := []string{i18n.T("j/k: select"), i18n.T("f: focus"), i18n.T("o: open project"), i18n.T("L: view log"), i18n.T("x: kill")}
+	if opts.Tmux {
+		keys = append(keys, i18n.T("t/T: jump to pane/needs input"))
+	}
+	if slices.ContainsFunc(active, answerable) {
+		keys = append(keys, i18n.T("a/d: approve/deny"))
+	}
+	if opts.Notify {
+		keys = append(keys, i18n.T("m: mute"))
+	}
+	keys = append(keys, i18n.T("s: split"), i18n.T("h: history"), i18n.T("u: usage"), i18n.T("e: changes"))
+	if opts.WebURL != "" {
+		keys = append(keys, i18n.T("w: open webview (%s)", opts.WebURL))
+	}
+	keys = append(keys, i18n.T("Ctrl+C: quit"))
+	for _, line := range wrapKeys(keys, width) {
+		fmt.Fprintf(&b, "%s%s%s\r\n", Dim, line, Reset)
 	}
+
+	if opts.UpdateHint != "" {
+		fmt.Fprintf(&b, "%s%s%s\r\n", Dim, i18n.T("%s available", sanitizeForTerminal(opts.UpdateHint)), Reset)
+	}
+
+	// Bottom status bar, pinned to the last terminal row when the frame is
+	// shorter than the screen.
+	for n := strings.Count(b.String(), "\r\n"); n < getTerminalHeight()-1; n++ {
+		fmt.Fprint(&b, "\r\n")
+	}
+	fmt.Fprint(&b, formatStatusBar(opts.StatusBar, opts.Hidden, width, time.Now()))
+
+	liveScreen.draw(b.String())
 }
 
-// ClearScreen clears the terminal screen
+// renderNeedsInputBanner prints the headline of the needs-input inbox.
+func renderNeedsInputBanner(w io.Writer, n int) {
+	switch n {
+	case 0:
+		fmt.Fprintf(w, "%s%s %s%s\r\n", Green, SymbolWaiting, i18n.T("Nothing needs your input"), Reset)
+	case 1:
+		fmt.Fprintf(w, "%s%s%s %s%s\r\n", Bold, Yellow, SymbolNeedsInput, i18n.T("1 session needs your input"), Reset)
+	default:
+		fmt.Fprintf(w, "%s%s%s %s%s\r\n", Bold, Yellow, SymbolNeedsInput, i18n.T("%d sessions need your input", n), Reset)
+	}
+}
+
+// ClearScreen clears the terminal screen. The live view repaints fully on
+// its next render.
 func ClearScreen() {
+	liveScreen.invalidate()
 	fmt.Print("\033[2J\033[H")
 }
 
@@ -165,6 +384,12 @@ func SetTerminalTitle(title string) {
 	fmt.Printf("\033]0;%s\007", sanitizeForTerminal(title))
 }
 
+// Sanitize removes control characters from log content that commands outside
+// this package print, so it can't inject terminal escape sequences.
+func Sanitize(s string) string {
+	return sanitizeForTerminal(s)
+}
+
 // sanitizeForTerminal removes control characters that could be used
 // for terminal escape sequence injection attacks
 func sanitizeForTerminal(s string) string {
@@ -187,6 +412,76 @@ func ResetTerminalTitle() {
 	fmt.Print("\033]0;\007")
 }
 
+// Terminal progress states of the OSC 9;4 sequence.
+const (
+	progressHidden = 0
+	progressNormal = 1
+	progressError  = 2
+)
+
+// terminalProgress returns the OSC 9;4 sequence that sets the terminal's
+// progress indicator: a red alert when a session needs input, otherwise the
+// highest context usage among active sessions, hidden when none are active.
+func terminalProgress(sessions []session.Session) string {
+	state, percent := progressHidden, 0
+	for _, s := range sessions {
+		if s.Status == session.StatusInactive || s.IsGhost {
+			continue
+		}
+		if s.Status == session.StatusNeedsInput {
+			state, percent = progressError, 100
+			break
+		}
+		state = progressNormal
+		percent = max(percent, min(int(s.ContextPercent), 100))
+	}
+	return fmt.Sprintf("\033]9;4;%d;%d\007", state, percent)
+}
+
+// ResetTerminalProgress hides the terminal progress indicator.
+func ResetTerminalProgress() {
+	fmt.Print(terminalProgress(nil))
+}
+
+// itermStatus returns the iTerm2 sequences that show the status summary as
+// the session badge and color the tab yellow when a session needs input or
+// green when every active session is working. Other mixes keep the default
+// tab color.
+func itermStatus(sessions []session.Session) string {
+	counts := make(map[session.Status]int)
+	active := 0
+	for _, s := range sessions {
+		if s.Status != session.StatusInactive && !s.IsGhost {
+			counts[s.Status]++
+			active++
+		}
+	}
+	badge := ""
+	if active > 0 {
+		badge = strings.TrimPrefix(buildTerminalTitle(sessions), "CSM: ")
+	}
+	seq := "\033]1337;SetBadgeFormat=" + base64.StdEncoding.EncodeToString([]byte(badge)) + "\007"
+	switch {
+	case counts[session.StatusNeedsInput] > 0:
+		seq += itermTabColor(229, 192, 123)
+	case active > 0 && counts[session.StatusWorking] == active:
+		seq += itermTabColor(152, 195, 121)
+	default:
+		seq += "\033]6;1;bg;*;default\007"
+	}
+	return seq
+}
+
+// itermTabColor returns the iTerm2 sequences that set the tab color.
+func itermTabColor(r, g, b int) string {
+	return fmt.Sprintf("\033]6;1;bg;red;brightness;%d\007\033]6;1;bg;green;brightness;%d\007\033]6;1;bg;blue;brightness;%d\007", r, g, b)
+}
+
+// ResetITerm2 clears the iTerm2 badge and tab color.
+func ResetITerm2() {
+	fmt.Print(itermStatus(nil))
+}
+
 // buildTerminalTitle creates a status summary for the terminal title
 func buildTerminalTitle(sessions []session.Session) string {
 	counts := make(map[session.Status]int)
@@ -200,17 +495,17 @@ func buildTerminalTitle(sessions []session.Session) string {
 	var parts []string
 
 	if n := counts[session.StatusNeedsInput]; n > 0 {
-		parts = append(parts, fmt.Sprintf("%d needs input", n))
+		parts = append(parts, i18n.T("%d needs input", n))
 	}
 	if n := counts[session.StatusWorking]; n > 0 {
-		parts = append(parts, fmt.Sprintf("%d working", n))
+		parts = append(parts, i18n.T("%d working", n))
 	}
 	if n := counts[session.StatusWaiting]; n > 0 {
-		parts = append(parts, fmt.Sprintf("%d waiting", n))
+		parts = append(parts, i18n.T("%d waiting", n))
 	}
 
 	if len(parts) == 0 {
-		return "CSM: no active sessions"
+		return "CSM: " + i18n.T("no active sessions")
 	}
 
 	return "CSM: " + strings.Join(parts, ", ")
@@ -234,11 +529,17 @@ func getStatusDisplay(status session.Status) (string, string) {
 	}
 }
 
+// statusName is a status as shown in the views, in the current language.
+func statusName(status session.Status) string {
+	return i18n.T(string(status))
+}
+
 // formatStatus formats the status cell with symbol and padding to exact width
 func formatStatus(status session.Status, width int) string {
 	symbol, color := getStatusDisplay(status)
-	text := symbol + " " + string(status)
-	visibleLen := 2 + len(string(status)) // symbol(1) + space(1) + status text
+	name := statusName(status)
+	text := symbol + " " + name
+	visibleLen := 2 + utf8.RuneCountInString(name) // symbol(1) + space(1) + status text
 
 	// Pad to width
 	if visibleLen < width {
@@ -248,6 +549,63 @@ func formatStatus(status session.Status, width int) string {
 	return color + text + Reset
 }
 
+// formatSessionStatus is formatStatus plus how long the session has been in
+// that status ("● Working 7m") when it is known and at least a minute, and
+// the column has room for it.
+func formatSessionStatus(s session.Session, width int) string {
+	since := ""
+	if !s.StatusSince.IsZero() && s.Status != session.StatusInactive && width >= fixedStatusWidth+statusSinceWidth {
+		since = formatStatusAge(time.Since(s.StatusSince))
+	}
+	if since == "" {
+		return formatStatus(s.Status, width)
+	}
+	symbol, color := getStatusDisplay(s.Status)
+	name := statusName(s.Status)
+	visibleLen := 2 + utf8.RuneCountInString(name) + 1 + len(since)
+	padding := ""
+	if visibleLen < width {
+		padding = strings.Repeat(" ", width-visibleLen)
+	}
+	return color + symbol + " " + name + Reset + " " + Dim + since + Reset + padding
+}
+
+// formatStatusAge renders a time-in-status as "7m", "3h" or "2d"; under a
+// minute it is left out.
+func formatStatusAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return ""
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours())/24)
+	}
+}
+
+// noClaudeMessage explains an empty view when Claude Code has never created
+// its projects directory; "" when it exists. The live view keeps polling, so
+// sessions show up as soon as Claude Code starts.
+func noClaudeMessage() string {
+	dir := session.MissingProjectsDir()
+	if dir == "" {
+		return ""
+	}
+	return i18n.T("No Claude Code sessions found — is Claude Code installed? (%s does not exist yet)", dir)
+}
+
+// detectionMessage warns that running Claude processes can't be found, so
+// every session shows as Inactive; "" when they can.
+func detectionMessage() string {
+	reason := session.ProcessDetectionUnavailable()
+	if reason == "" {
+		return ""
+	}
+	return "\u26A0 " + i18n.T("Process detection unavailable (%s): running sessions can't be told from finished ones (see csm doctor)", reason)
+}
+
 // countByStatus counts sessions by their status
 func countByStatus(sessions []session.Session) map[session.Status]int {
 	counts := make(map[session.Status]int)
@@ -260,18 +618,23 @@ func countByStatus(sessions []session.Session) map[session.Status]int {
 // formatElapsed formats a duration as a human-readable elapsed time
 func formatElapsed(d time.Duration) string {
 	if d < time.Second {
-		return "just now"
+		return i18n.T("just now")
 	}
 	if d < time.Minute {
-		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+		return i18n.T("%ds ago", int(d.Seconds()))
 	}
 	if d < time.Hour {
-		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+		return i18n.T("%dm ago", int(d.Minutes()))
 	}
 	if d < 24*time.Hour {
-		return fmt.Sprintf("%dh ago", int(d.Hours()))
+		return i18n.T("%dh ago", int(d.Hours()))
 	}
-	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	return i18n.T("%dd ago", int(d.Hours()/24))
+}
+
+// Truncate shortens s to max runes, ending in "..." when cut.
+func Truncate(s string, max int) string {
+	return truncate(s, max)
 }
 
 // truncate truncates a string to a maximum visible length (in runes, not bytes).
@@ -295,6 +658,18 @@ const contextBarWidth = 10
 
 // formatContext renders a visual progress bar with percentage label
 // Example: "████████░░ 80%"
+// contextColor is the color of a context bar filled to pct percent.
+func contextColor(pct float64) string {
+	switch {
+	case pct >= 91:
+		return Red
+	case pct >= 76:
+		return Yellow
+	default:
+		return Green
+	}
+}
+
 func formatContext(s session.Session, width int) string {
 	if s.ContextTokens == 0 {
 		text := "-"
@@ -317,16 +692,7 @@ func formatContext(s session.Session, width int) string {
 	}
 	empty := contextBarWidth - filled
 
-	// Color based on percentage
-	var color string
-	switch {
-	case pct >= 91:
-		color = Red
-	case pct >= 76:
-		color = Yellow
-	default:
-		color = Green
-	}
+	color := contextColor(pct)
 
 	// Build bar: colored filled blocks + dim empty blocks + percentage
 	label := fmt.Sprintf(" %.0f%%", pct)
@@ -383,52 +749,112 @@ func formatOrigin(o session.Origin, width int) string {
 }
 
 // renderSessionRow renders a single session row using the given layout.
-// The main row shows status, project, origin (optional), context, and activity.
+// The main row shows status, project, origin (optional), any enabled optional
+// columns, context, and activity.
 // A second indented line shows the last message using the full width.
-func renderSessionRow(s session.Session, l sessionLayout, nl string) {
-	activity := formatElapsed(time.Since(s.LastActivity))
+// A selected row has its project name rendered in reverse video.
+func renderSessionRow(w io.Writer, s session.Session, l sessionLayout, selected bool, nl string) {
+	activity := formatActivity(s.LastActivity, time.Now())
 	if s.Status == session.StatusWorking {
-		activity = "Now"
+		activity = i18n.T("Now")
 	}
 
-	var row string
+	// With a branch column the project cell drops its @branch suffix.
+	name := s
+	if l.hasColumn("branch") {
+		name.GitBranch = ""
+	}
+	parts := []string{
+		formatSessionStatus(s, l.status),
+		formatProject(name, l.project, selected),
+	}
 	if l.origin > 0 {
-		row = fmt.Sprintf("%s %s %s %s %-*s",
-			formatStatus(s.Status, l.status),
-			formatProject(s, l.project),
-			formatOrigin(s.Origin, l.origin),
-			formatContext(s, l.context),
-			l.activity, activity)
-	} else {
-		row = fmt.Sprintf("%s %s %s %-*s",
-			formatStatus(s.Status, l.status),
-			formatProject(s, l.project),
-			formatContext(s, l.context),
-			l.activity, activity)
+		parts = append(parts, formatOrigin(s.Origin, l.origin))
+	}
+	for _, c := range l.extras {
+		parts = append(parts, formatColumnCell(c, s, c.width))
 	}
-	fmt.Print(row + nl)
+	parts = append(parts,
+		formatContext(s, l.context),
+		fmt.Sprintf("%-*s", l.activity, activity))
+	row := strings.Join(parts, " ")
+	fmt.Fprint(w, row+nl)
 
 	// Second line: last message aligned with status text (after "● ")
-	// Sanitize to prevent ANSI escape injection from log content
+	indent := 2 // align with status text (after symbol + space)
+	if msg := messageLine(s, l.totalWidth-indent); msg != "" {
+		fmt.Fprint(w, strings.Repeat(" ", indent)+msg+nl)
+	}
+
+	// Blank line after each session block for visual grouping
+	fmt.Fprint(w, nl)
+}
+
+// modelSwitchLabel describes a model switch as "opus→sonnet", or with
+// versions ("opus-4.5→opus-4.6") when the family stayed the same.
+func modelSwitchLabel(sw session.ModelSwitch) string {
+	from, to := session.ShortModelName(sw.From), session.ShortModelName(sw.To)
+	fromFamily, _, _ := strings.Cut(from, "-")
+	toFamily, _, _ := strings.Cut(to, "-")
+	if fromFamily != toFamily {
+		from, to = fromFamily, toFamily
+	}
+	return sanitizeForTerminal(from + "→" + to)
+}
+
+// pendingLabel prefixes the request a session is waiting to have approved.
+const pendingLabel = "Pending: "
+
+// unreadableLabel prefixes the last message of a session whose log timed out.
+const unreadableLabel = "Unreadable (slow fs): "
+
+// messageLine is the line shown under a session, cut to width: a note that
+// its log timed out, the request awaiting approval when there is one,
+// otherwise the last message or task. Log content is sanitized to prevent
+// ANSI escape injection. Returns "" when there is nothing to show.
+func messageLine(s session.Session, width int) string {
+	if label := i18n.T(unreadableLabel); s.Unreadable && width > utf8.RuneCountInString(label) {
+		return Red + label + Reset + truncate(sanitizeForTerminal(s.LastMessage), width-utf8.RuneCountInString(label))
+	}
+	if label := i18n.T(pendingLabel); s.PendingRequest != "" && width > utf8.RuneCountInString(label) {
+		return Yellow + label + Reset + truncate(sanitizeForTerminal(s.PendingRequest), width-utf8.RuneCountInString(label))
+	}
+	if label, detail := hookMessage(s.Hook); label != "" && width > utf8.RuneCountInString(label) {
+		color := Red
+		if s.Hook.Running {
+			color = Yellow
+		}
+		return color + label + Reset + truncate(sanitizeForTerminal(detail), width-utf8.RuneCountInString(label))
+	}
 	desc := sanitizeForTerminal(s.LastMessage)
 	if desc == "" {
 		desc = sanitizeForTerminal(s.Task)
 	}
-	if desc != "" && desc != "-" {
-		indent := 2 // align with status text (after symbol + space)
-		msgWidth := l.totalWidth - indent
-		if msgWidth > 0 {
-			msg := truncate(desc, msgWidth)
-			fmt.Printf("%s%s%s%s", strings.Repeat(" ", indent), Dim, msg, Reset+nl)
-		}
+	if desc == "" || desc == "-" || width <= 0 {
+		return ""
 	}
+	return highlightMessage(truncate(desc, width))
+}
 
-	// Blank line after each session block for visual grouping
-	fmt.Print(nl)
+// hookMessage describes a session's hook trouble as a label and the detail
+// that follows it, e.g. "Hook PreToolUse:Bash blocked: " and the reason.
+// Returns "" when there is nothing to report.
+func hookMessage(h *session.HookRun) (label, detail string) {
+	switch {
+	case h == nil:
+		return "", ""
+	case h.Running:
+		return i18n.T("Running hook %s: ", sanitizeForTerminal(h.Label())), h.Command
+	case h.Blocked:
+		return i18n.T("Hook %s blocked: ", sanitizeForTerminal(h.Label())), h.Error
+	default:
+		return i18n.T("Hook %s failed: ", sanitizeForTerminal(h.Label())), h.Error
+	}
 }
 
-// formatProject formats the project name with optional indicators, padded to maxLen visible chars
-func formatProject(s session.Session, maxLen int) string {
+// formatProject formats the project name with optional indicators, padded to maxLen visible chars.
+// When selected is true the name is highlighted in reverse video.
+func formatProject(s session.Session, maxLen int, selected bool) string {
 	// Sanitize to prevent ANSI escape injection from log/filesystem content
 	name := sanitizeForTerminal(s.Project)
 	var suffixes []string
@@ -464,12 +890,92 @@ func formatProject(s session.Session, maxLen int) string {
 		suffixLens = append(suffixLens, 7) // [ghost]
 	}
 
+	// Several sessions in one working tree overwrite each other's changes
+	if s.Conflicts > 0 {
+		suffixes = append(suffixes, Red+"[conflict]"+Reset)
+		suffixLens = append(suffixLens, 10) // [conflict]
+	}
+
+	// Started by an IDE extension rather than from a terminal
+	if s.Origin.Integration {
+		suffixes = append(suffixes, Blue+"[IDE]"+Reset)
+		suffixLens = append(suffixLens, 5) // [IDE]
+	}
+
+	// Run by claude -p or the Agent SDK, only listed with --show-headless
+	if s.Headless {
+		suffixes = append(suffixes, Dim+"[headless]"+Reset)
+		suffixLens = append(suffixLens, 10) // [headless]
+	}
+
+	// A hook holding the session up, or one that failed or blocked
+	if s.Hook != nil {
+		label, color := "[hook failed]", Red
+		switch {
+		case s.Hook.Running:
+			label, color = "[hook]", Yellow
+		case s.Hook.Blocked:
+			label = "[hook blocked]"
+		}
+		suffixes = append(suffixes, color+label+Reset)
+		suffixLens = append(suffixLens, len(label))
+	}
+
+	// API errors/retries since the last good reply: why a session is stuck
+	if s.APIErrors > 0 {
+		label := fmt.Sprintf("[err %d]", s.APIErrors)
+		suffixes = append(suffixes, Red+label+Reset)
+		suffixLens = append(suffixLens, len(label))
+	}
+
 	// Unsandboxed indicator (security warning)
 	if s.HasUnsandboxed {
 		suffixes = append(suffixes, Yellow+"[!S]"+Reset)
 		suffixLens = append(suffixLens, 4) // [!S]
 	}
 
+	// Permission mode: bypassed checks mean a fully autonomous session
+	switch s.PermissionMode {
+	case session.PermissionBypass:
+		suffixes = append(suffixes, Red+"[!P]"+Reset)
+		suffixLens = append(suffixLens, 4) // [!P]
+	case session.PermissionAcceptEdits:
+		suffixes = append(suffixes, Yellow+"[AE]"+Reset)
+		suffixLens = append(suffixLens, 4) // [AE]
+	case session.PermissionPlan:
+		// Plan mode ends with a plan to approve or reject
+		suffixes = append(suffixes, Blue+"[plan]"+Reset)
+		suffixLens = append(suffixLens, 6) // [plan]
+	}
+
+	// Web access: the session searched or fetched pages on the internet
+	if s.WebAccess > 0 {
+		count := fmt.Sprint(s.WebAccess)
+		suffixes = append(suffixes, Blue+"🌐"+count+Reset)
+		suffixLens = append(suffixLens, 2+len(count)) // the globe is two cells wide
+	}
+
+	// Claude Code older than the configured minimum
+	if s.Outdated {
+		label := "[v" + s.Version + "]"
+		suffixes = append(suffixes, Yellow+label+Reset)
+		suffixLens = append(suffixLens, len(label))
+	}
+
+	// Model switch indicator: replies now come from another model
+	if n := len(s.ModelSwitches); n > 0 {
+		label := "[" + modelSwitchLabel(s.ModelSwitches[n-1]) + "]"
+		suffixes = append(suffixes, Cyan+label+Reset)
+		suffixLens = append(suffixLens, len([]rune(label)))
+	}
+
+	// tmux pane, to find the session among many windows
+	if s.Tmux != nil {
+		label := "[tmux:" + s.Tmux.Label() + "]"
+		suffixes = append(suffixes, Dim+label+Reset)
+		suffixLens = append(suffixLens, len(label))
+	}
+
 	// Drop suffixes from the end until they fit, keeping at least 4 chars for the name
 	const minNameWidth = 4
 	totalSuffixLen := 0
@@ -492,7 +998,21 @@ func formatProject(s session.Session, maxLen int) string {
 	visibleLen := len(truncated)
 
 	// Build result
+	// Sessions over their budget are named in red.
+	style := ""
+	if s.OverBudget {
+		style = Red
+	}
+	if selected {
+		style = Reverse + style
+	}
 	result := truncated
+	if style != "" {
+		result = style + truncated + Reset
+	}
+	if link := projectLink(s); link != "" {
+		result = terminalLink(sanitizeForTerminal(link), result)
+	}
 	for i, suffix := range suffixes {
 		result += " " + suffix
 		visibleLen += 1 + suffixLens[i] // space + indicator visible length
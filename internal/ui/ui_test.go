@@ -0,0 +1,295 @@
+package ui
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestOneLine(t *testing.T) {
+	if got := OneLine(nil); got != "no active sessions" {
+		t.Errorf("OneLine(nil) = %q", got)
+	}
+
+	sessions := []session.Session{
+		{Project: "org/web", Status: session.StatusWaiting},
+		{Project: "org/api", Status: session.StatusNeedsInput},
+		{Project: "org/cli", Status: session.StatusWorking},
+		{Project: "org/api", Status: session.StatusNeedsInput},
+		{Project: "org/old", Status: session.StatusInactive},
+		{Project: "org/ghost", Status: session.StatusWorking, IsGhost: true},
+	}
+	want := "1 working, 2 needs input (org/api), 1 waiting"
+	if got := OneLine(sessions); got != want {
+		t.Errorf("OneLine = %q, want %q", got, want)
+	}
+}
+
+func TestRenderToday(t *testing.T) {
+	var sessions []session.HistorySession
+	for _, name := range []string{"org/a", "org/b", "org/c", "org/d"} {
+		sessions = append(sessions, session.HistorySession{Project: name, Duration: 90 * time.Minute})
+	}
+
+	var b strings.Builder
+	renderToday(&b, sessions, 60, 4)
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(lines) != 4 {
+		t.Fatalf("renderToday used %d rows, want 4: %q", len(lines), lines)
+	}
+	if !strings.Contains(lines[0], "Finished today (4)") || !strings.Contains(lines[1], "1h 30m") {
+		t.Errorf("unexpected rows: %q", lines)
+	}
+	if !strings.Contains(lines[3], "and 2 more") {
+		t.Errorf("last row = %q, want the overflow count", lines[3])
+	}
+}
+
+func TestFormatStatusBar(t *testing.T) {
+	now := time.Date(2025, 1, 1, 14, 32, 0, 0, time.Local)
+	bar := &StatusBar{TodayTokens: 1250000, WindowPercent: 42, HasWindow: true}
+
+	got := formatStatusBar(bar, 2, 80, now)
+	want := " 14:32 · today 1.2M tokens · 5h window 42% · 2 hidden"
+	if !strings.HasPrefix(got, Reverse+want) {
+		t.Errorf("formatStatusBar = %q, want it to start with %q", got, want)
+	}
+	if w := visibleWidth(got); w != 80 {
+		t.Errorf("bar is %d columns wide, want 80", w)
+	}
+
+	bar.OverBudget = true
+	if got := formatStatusBar(bar, 0, 80, now); !strings.Contains(got, "today 1.2M tokens (over budget) · ") {
+		t.Errorf("over budget = %q", got)
+	}
+
+	if got := formatStatusBar(nil, 0, 20, now); got != Reverse+" 14:32"+strings.Repeat(" ", 14)+Reset {
+		t.Errorf("before the first scan = %q", got)
+	}
+}
+
+func TestFormatTodayTotals(t *testing.T) {
+	got := formatTodayTotals(TodayTotals{Active: 3*time.Hour + 12*time.Minute, Input: 1_240_000, Output: 84_000, Cost: 4.2, Completed: 5})
+	if want := "Today: 3h 12m active · 1.2M in / 84K out · $4.20 · 5 sessions done"; got != want {
+		t.Errorf("formatTodayTotals = %q, want %q", got, want)
+	}
+	if got := formatTodayTotals(TodayTotals{Completed: 1}); !strings.HasSuffix(got, "· 1 session done") {
+		t.Errorf("one session = %q", got)
+	}
+}
+
+func TestSortTop(t *testing.T) {
+	w := 5 * time.Minute
+	rows := []TopRow{
+		{Session: session.Session{Project: "cheap-but-busy"}, Rate: session.Rate{Tokens: 5000, Cost: 0.01, Window: w}},
+		{Session: session.Session{Project: "idle"}, Rate: session.Rate{Window: w}},
+		{Session: session.Session{Project: "expensive"}, Rate: session.Rate{Tokens: 1000, Cost: 0.50, BackgroundTokens: 100, BackgroundCost: 0.01, Window: w}},
+	}
+
+	SortTop(rows, TopByTokens)
+	if rows[0].Session.Project != "cheap-but-busy" || rows[2].Session.Project != "idle" {
+		t.Errorf("by tokens: %s, %s, %s", rows[0].Session.Project, rows[1].Session.Project, rows[2].Session.Project)
+	}
+	SortTop(rows, TopByCost)
+	if rows[0].Session.Project != "expensive" {
+		t.Errorf("by cost: first = %s, want expensive", rows[0].Session.Project)
+	}
+
+	var b strings.Builder
+	renderTop(&b, rows, TopByCost, 100, 40)
+	out := b.String()
+	if !strings.Contains(out, "2 of 3 sessions burning") {
+		t.Errorf("header missing totals:\n%s", out)
+	}
+	if !strings.Contains(out, "$/HOUR▼") || !strings.Contains(out, "$6.00") {
+		t.Errorf("cost column missing sort marker or rate:\n%s", out)
+	}
+	if !strings.Contains(out, "(~$0.12/h background)") {
+		t.Errorf("header missing background cost:\n%s", out)
+	}
+}
+
+func TestContextSparkline(t *testing.T) {
+	var frames []session.ReplayFrame
+	for _, pct := range []float64{0, 20, 50, 100, 120, 10} {
+		frames = append(frames, session.ReplayFrame{ContextPercent: pct})
+	}
+	if got := contextSparkline(frames, 10); got != "▁▂▅██▂" {
+		t.Errorf("contextSparkline = %q", got)
+	}
+	// Squeezed into fewer columns, each shows the last frame of its share.
+	if got := contextSparkline(frames, 3); got != "▂█▂" {
+		t.Errorf("contextSparkline(width 3) = %q", got)
+	}
+}
+
+func TestModelSwitchLabel(t *testing.T) {
+	tests := []struct{ from, to, want string }{
+		{"claude-opus-4-6", "claude-sonnet-4-5-20250929", "opus→sonnet"},
+		{"claude-opus-4-5", "claude-opus-4-6", "opus-4.5→opus-4.6"},
+		{"claude-sonnet-4-5", "claude-fable-5", "sonnet→fable"},
+		{"gpt-x", "claude-opus-4-6", "gpt→opus"},
+	}
+	for _, tt := range tests {
+		if got := modelSwitchLabel(session.ModelSwitch{From: tt.from, To: tt.to}); got != tt.want {
+			t.Errorf("modelSwitchLabel(%s, %s) = %q, want %q", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestMessageLine(t *testing.T) {
+	s := session.Session{LastMessage: "Shall I deploy?", Task: "Using: Bash"}
+	if got := messageLine(s, 40); got != highlightMessage("Shall I deploy?") {
+		t.Errorf("messageLine = %q, want the last message", got)
+	}
+	s.PendingRequest = "Bash: make deploy ENV=production"
+	got := messageLine(s, 30)
+	if !strings.Contains(got, pendingLabel) || !strings.HasSuffix(got, "Bash: make deploy ...") {
+		t.Errorf("messageLine = %q, want the pending request cut to fit", got)
+	}
+	if got := messageLine(session.Session{Task: "-"}, 40); got != "" {
+		t.Errorf("messageLine = %q, want nothing", got)
+	}
+	s = session.Session{LastMessage: "Shall I deploy?", Hook: &session.HookRun{Name: "PreToolUse:Bash", Blocked: true, Error: "make is not allowed"}}
+	if got := messageLine(s, 60); !strings.Contains(got, "Hook PreToolUse:Bash blocked: ") || !strings.HasSuffix(got, "make is not allowed") {
+		t.Errorf("messageLine = %q, want the hook's block reason", got)
+	}
+	s.Unreadable = true
+	if got := messageLine(s, 60); !strings.Contains(got, unreadableLabel) || !strings.HasSuffix(got, "Shall I deploy?") {
+		t.Errorf("messageLine = %q, want the log marked unreadable", got)
+	}
+}
+
+func TestProjectLink(t *testing.T) {
+	defer SetProjectLinks("off")
+	host, _ := os.Hostname()
+	s := session.Session{Project: "org/my api", CWD: "/home/me/org/my api", GitBranch: "feat/x"}
+
+	tests := []struct {
+		name, template string
+		s              session.Session
+		want           string
+	}{
+		{"directory", "", s, "file://" + host + "/home/me/org/my%20api"},
+		{"template", "https://github.com/{project}/tree/{branch}", s, "https://github.com/org/my%20api/tree/feat/x"},
+		{"remote directory", "", session.Session{CWD: "/srv/app", Host: "build1"}, ""},
+		{"path unknown", "vscode://file{path}", session.Session{Project: "app"}, ""},
+	}
+	for _, tt := range tests {
+		SetProjectLinks(tt.template)
+		if got := projectLink(tt.s); got != tt.want {
+			t.Errorf("%s: projectLink() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	SetProjectLinks("off")
+	if got := projectLink(s); got != "" {
+		t.Errorf("off: projectLink() = %q, want \"\"", got)
+	}
+}
+
+func TestTerminalProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		sessions []session.Session
+		want     string
+	}{
+		{"no sessions", nil, "\033]9;4;0;0\007"},
+		{"only inactive", []session.Session{{Status: session.StatusInactive, ContextPercent: 90}}, "\033]9;4;0;0\007"},
+		{"highest context", []session.Session{
+			{Status: session.StatusWorking, ContextPercent: 42.7},
+			{Status: session.StatusWaiting, ContextPercent: 61.2},
+			{Status: session.StatusWorking, ContextPercent: 99, IsGhost: true},
+		}, "\033]9;4;1;61\007"},
+		{"needs input", []session.Session{
+			{Status: session.StatusWorking, ContextPercent: 42},
+			{Status: session.StatusNeedsInput, ContextPercent: 10},
+		}, "\033]9;4;2;100\007"},
+	}
+	for _, tt := range tests {
+		if got := terminalProgress(tt.sessions); got != tt.want {
+			t.Errorf("%s: terminalProgress() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestITermStatus(t *testing.T) {
+	badge := func(text string) string {
+		return "\033]1337;SetBadgeFormat=" + base64.StdEncoding.EncodeToString([]byte(text)) + "\007"
+	}
+	tests := []struct {
+		name     string
+		sessions []session.Session
+		want     string
+	}{
+		{"no sessions", nil, badge("") + "\033]6;1;bg;*;default\007"},
+		{"all working", []session.Session{{Status: session.StatusWorking}, {Status: session.StatusWorking}},
+			badge("2 working") + itermTabColor(152, 195, 121)},
+		{"needs input", []session.Session{{Status: session.StatusWorking}, {Status: session.StatusNeedsInput}},
+			badge("1 needs input, 1 working") + itermTabColor(229, 192, 123)},
+		{"working and waiting", []session.Session{{Status: session.StatusWorking}, {Status: session.StatusWaiting}},
+			badge("1 working, 1 waiting") + "\033]6;1;bg;*;default\007"},
+	}
+	for _, tt := range tests {
+		if got := itermStatus(tt.sessions); got != tt.want {
+			t.Errorf("%s: itermStatus() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatActivity(t *testing.T) {
+	defer SetTimeFormat(false, false)
+	now := time.Date(2026, 3, 4, 15, 0, 0, 0, time.Local)
+	today := now.Add(-28 * time.Minute)
+	earlier := now.AddDate(0, 0, -2)
+
+	tests := []struct {
+		name             string
+		absolute, hour12 bool
+		t                time.Time
+		want             string
+	}{
+		{"relative", false, false, today, "28m ago"},
+		{"absolute today", true, false, today, "14:32"},
+		{"absolute 12h", true, true, today, "2:32pm"},
+		{"absolute other day", true, false, earlier, "Mar 2 15:00"},
+	}
+	for _, tt := range tests {
+		SetTimeFormat(tt.absolute, tt.hour12)
+		if got := formatActivity(tt.t, now); got != tt.want {
+			t.Errorf("%s: formatActivity() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRenderPendingDiff(t *testing.T) {
+	pc := &session.PendingChange{Tool: "Edit", FilePath: "/repo/main.go", Added: 1, Removed: 1, Diff: []session.DiffLine{
+		{Op: '@', Text: "@@ -1,3 +1,3 @@"}, {Op: ' ', Text: "func main() {"}, {Op: '-', Text: "\tprintln(1)"},
+		{Op: '+', Text: "\tprintln(2)"}, {Op: ' ', Text: "}"},
+	}}
+	var b strings.Builder
+	renderPendingDiff(&b, pc, "/repo", 10, 3, 80)
+	out := b.String()
+	for _, want := range []string{"Pending Edit" + Reset + " main.go", "lines 3-5 of 5", Red + "-    println(1)" + Reset, Green + "+    println(2)" + Reset} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%q", want, out)
+		}
+	}
+	if strings.Contains(out, "@@") {
+		t.Errorf("scrolled past the hunk header but it is shown:\n%q", out)
+	}
+
+	v := DetailView{Session: session.Session{Status: session.StatusWaiting}, Detail: session.Detail{Pending: pc}}
+	if v.pendingDiff() != nil {
+		t.Error("diff previewed for a session not waiting for approval")
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	if got := Sanitize("ok \x1b]0;pwned\x07 \x1b[2Jdone\r\n"); got != "ok ]0;pwned [2Jdone" {
+		t.Errorf("Sanitize = %q", got)
+	}
+}
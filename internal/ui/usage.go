@@ -67,6 +67,10 @@ func RenderUsage(usage *session.UsageStats, apiQuota *session.APIQuota, showFoot
 			formatTokenCount(usage.OutputTokens),
 			formatTokenCount(usage.CacheTokens),
 			nl)
+		if usage.BackgroundTokens > 0 {
+			fmt.Printf("  Background:    %s (~$%.2f of ~$%.2f) %son session titles and summaries, not your turns%s%s",
+				formatTokenCount(usage.BackgroundTokens), usage.BackgroundCost, usage.Cost, Dim, Reset, nl)
+		}
 		fmt.Printf("  Sessions:      %d%s", len(usage.Sessions), nl)
 		fmt.Print(nl)
 
@@ -0,0 +1,140 @@
+// Package update checks, at most once a day, whether a newer csm release is
+// available so the live view can show a gentle hint.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// releaseURL is the GitHub API endpoint for the latest release.
+const releaseURL = "https://api.github.com/repos/yepzdk/claude-sessions-monitor/releases/latest"
+
+// checkInterval is how long a cached answer is trusted.
+const checkInterval = 24 * time.Hour
+
+// cacheEntry is the on-disk record of the last check.
+type cacheEntry struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+// Overridable in tests.
+var (
+	cachePathFn = defaultCachePath
+	fetchFn     = fetchLatest
+)
+
+func defaultCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".claude-monitor", "version-check.json"), nil
+}
+
+// Available returns the latest release version (e.g. "v1.4.0") when it is
+// newer than current, or "". The network is consulted at most once per
+// checkInterval; failures are silent since this is only a hint. Development
+// builds (current "dev") never report an update.
+func Available(ctx context.Context, current string) string {
+	if _, ok := parseVersion(current); !ok {
+		return ""
+	}
+
+	latest := ""
+	path, err := cachePathFn()
+	if err != nil {
+		return ""
+	}
+	var entry cacheEntry
+	if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &entry) == nil && time.Since(entry.CheckedAt) < checkInterval {
+		latest = entry.Latest
+	} else {
+		latest, err = fetchFn(ctx)
+		if err != nil {
+			return ""
+		}
+		entry = cacheEntry{CheckedAt: time.Now(), Latest: latest}
+		if data, err := json.Marshal(entry); err == nil {
+			os.MkdirAll(filepath.Dir(path), 0o755)
+			os.WriteFile(path, data, 0o644)
+		}
+	}
+
+	if Newer(latest, current) {
+		return latest
+	}
+	return ""
+}
+
+func fetchLatest(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releaseURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("release check: %s", resp.Status)
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", err
+	}
+	return release.TagName, nil
+}
+
+// Newer reports whether version latest is greater than current. Both may
+// carry a leading "v"; anything that isn't MAJOR.MINOR.PATCH compares false.
+func Newer(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses "v1.2.3" or "1.2.3", ignoring any pre-release or build
+// suffix on the patch number.
+func parseVersion(v string) ([3]int, bool) {
+	var out [3]int
+	parts := strings.SplitN(strings.TrimPrefix(v, "v"), ".", 3)
+	if len(parts) != 3 {
+		return out, false
+	}
+	parts[2], _, _ = strings.Cut(parts[2], "-")
+	parts[2], _, _ = strings.Cut(parts[2], "+")
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
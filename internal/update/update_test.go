@@ -0,0 +1,70 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.4.0", "v1.3.9", true},
+		{"v1.4.0", "1.4.0", false},
+		{"v1.10.0", "v1.9.3", true},
+		{"v2.0.0", "v1.99.99", true},
+		{"v1.3.0", "v1.4.0", false},
+		{"v1.4.1", "v1.4.0-rc1", true},
+		{"v1.4.0", "dev", false},
+		{"nightly", "v1.0.0", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestAvailableCachesDaily(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "version-check.json")
+	cachePathFn = func() (string, error) { return path, nil }
+	calls := 0
+	fetchFn = func(context.Context) (string, error) {
+		calls++
+		return "v1.4.0", nil
+	}
+	t.Cleanup(func() {
+		cachePathFn = defaultCachePath
+		fetchFn = fetchLatest
+	})
+
+	if got := Available(context.Background(), "v1.3.0"); got != "v1.4.0" {
+		t.Errorf("Available = %q, want v1.4.0", got)
+	}
+	if got := Available(context.Background(), "v1.4.0"); got != "" {
+		t.Errorf("Available on latest = %q, want empty", got)
+	}
+	if calls != 1 {
+		t.Errorf("fetched %d times, want 1 (second call should use the cache)", calls)
+	}
+
+	if got := Available(context.Background(), "dev"); got != "" || calls != 1 {
+		t.Errorf("dev build checked for updates: %q, %d calls", got, calls)
+	}
+}
+
+func TestAvailableSilentOnError(t *testing.T) {
+	cachePathFn = func() (string, error) { return filepath.Join(t.TempDir(), "v.json"), nil }
+	fetchFn = func(context.Context) (string, error) { return "", errors.New("offline") }
+	t.Cleanup(func() {
+		cachePathFn = defaultCachePath
+		fetchFn = fetchLatest
+	})
+
+	if got := Available(context.Background(), "v1.0.0"); got != "" {
+		t.Errorf("Available offline = %q, want empty", got)
+	}
+}
@@ -0,0 +1,204 @@
+// Package wakatime sends WakaTime heartbeats for Claude sessions that are
+// working, so Claude-assisted time shows up in WakaTime dashboards next to
+// editor time.
+//
+// Heartbeats go to the WakaTime API (or a compatible server such as Wakapi)
+// with the API key from csm's config or, as the editor plugins do, from
+// ~/.wakatime.cfg.
+package wakatime
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// DefaultAPIURL is WakaTime's own API.
+const DefaultAPIURL = "https://api.wakatime.com/api/v1"
+
+// Interval is how often a session that keeps working is reported again;
+// WakaTime joins heartbeats up to its timeout (15 minutes by default) apart
+// into continuous time.
+const Interval = 2 * time.Minute
+
+// Heartbeat is one WakaTime heartbeat, as the API takes it.
+type Heartbeat struct {
+	Entity   string  `json:"entity"`
+	Type     string  `json:"type"`
+	Category string  `json:"category"`
+	Time     float64 `json:"time"` // Unix seconds
+	Project  string  `json:"project,omitempty"`
+	Branch   string  `json:"branch,omitempty"`
+	Language string  `json:"language"`
+}
+
+// Client posts heartbeats to a WakaTime API.
+type Client struct {
+	apiURL    string
+	apiKey    string
+	userAgent string
+	http      *http.Client
+}
+
+// NewClient returns a client for the API at apiURL (DefaultAPIURL when
+// empty). userAgent names the sender, e.g. "csm/1.4.0".
+func NewClient(apiURL, apiKey, userAgent string) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Client{
+		apiURL:    strings.TrimSuffix(apiURL, "/"),
+		apiKey:    apiKey,
+		userAgent: userAgent,
+		http:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send posts heartbeats in one bulk request.
+func (c *Client) Send(heartbeats []Heartbeat) error {
+	if len(heartbeats) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(heartbeats)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", c.apiURL+"/users/current/heartbeats.bulk", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.apiKey)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("wakatime API returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Tracker decides which sessions are due a heartbeat: those Working, at
+// most once per Interval each.
+type Tracker struct {
+	sent map[string]time.Time // LogFile → last heartbeat
+}
+
+// NewTracker returns a Tracker that has sent nothing yet.
+func NewTracker() *Tracker {
+	return &Tracker{sent: make(map[string]time.Time)}
+}
+
+// Due returns the heartbeats to send at now for sessions, and records them as
+// sent. Sessions no longer working are forgotten, so the next turn is
+// reported right away.
+func (t *Tracker) Due(sessions []session.Session, now time.Time) []Heartbeat {
+	var out []Heartbeat
+	working := make(map[string]bool)
+	for _, s := range sessions {
+		if s.Status != session.StatusWorking || s.IsGhost {
+			continue
+		}
+		working[s.LogFile] = true
+		if last, ok := t.sent[s.LogFile]; ok && now.Sub(last) < Interval {
+			continue
+		}
+		t.sent[s.LogFile] = now
+		out = append(out, heartbeat(s, now))
+	}
+	for f := range t.sent {
+		if !working[f] {
+			delete(t.sent, f)
+		}
+	}
+	return out
+}
+
+// heartbeat describes a working session: the project directory as the
+// entity, coded in "Claude".
+func heartbeat(s session.Session, now time.Time) Heartbeat {
+	entity := s.ProjectPath
+	if entity == "" {
+		entity = s.Project
+	}
+	return Heartbeat{
+		Entity:   entity,
+		Type:     "app",
+		Category: "ai coding",
+		Time:     float64(now.UnixNano()) / 1e9,
+		Project:  s.Project,
+		Branch:   s.GitBranch,
+		Language: "Claude",
+	}
+}
+
+// cfgPathFn is overridable in tests.
+var cfgPathFn = defaultCfgPath
+
+func defaultCfgPath() (string, error) {
+	if home := os.Getenv("WAKATIME_HOME"); home != "" {
+		return filepath.Join(home, ".wakatime.cfg"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".wakatime.cfg"), nil
+}
+
+// ReadConfig returns the api_key and api_url of the [settings] section of
+// ~/.wakatime.cfg, the file WakaTime's editor plugins share.
+func ReadConfig() (apiKey, apiURL string, err error) {
+	path, err := cfgPathFn()
+	if err != nil {
+		return "", "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+		case section == "settings":
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			switch strings.TrimSpace(key) {
+			case "api_key":
+				apiKey = strings.TrimSpace(value)
+			case "api_url":
+				apiURL = strings.TrimSpace(value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", err
+	}
+	if apiKey == "" {
+		return "", "", errors.New(path + " has no api_key")
+	}
+	return apiKey, apiURL, nil
+}
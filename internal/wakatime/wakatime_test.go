@@ -0,0 +1,82 @@
+package wakatime
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+func TestTrackerDue(t *testing.T) {
+	now := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	api := session.Session{LogFile: "a.jsonl", Project: "api", ProjectPath: "/repos/api", GitBranch: "main", Status: session.StatusWorking}
+	web := session.Session{LogFile: "b.jsonl", Project: "web", Status: session.StatusWaiting}
+
+	tr := NewTracker()
+	got := tr.Due([]session.Session{api, web}, now)
+	if len(got) != 1 {
+		t.Fatalf("heartbeats = %+v, want one for api", got)
+	}
+	want := Heartbeat{Entity: "/repos/api", Type: "app", Category: "ai coding", Time: float64(now.Unix()),
+		Project: "api", Branch: "main", Language: "Claude"}
+	if got[0] != want {
+		t.Errorf("heartbeat = %+v, want %+v", got[0], want)
+	}
+
+	if got := tr.Due([]session.Session{api}, now.Add(time.Minute)); len(got) != 0 {
+		t.Errorf("heartbeat again within the interval: %+v", got)
+	}
+	if got := tr.Due([]session.Session{api}, now.Add(Interval)); len(got) != 1 {
+		t.Errorf("no heartbeat after the interval")
+	}
+
+	// A new turn is reported at once.
+	api.Status = session.StatusWaiting
+	tr.Due([]session.Session{api}, now.Add(Interval+time.Second))
+	api.Status = session.StatusWorking
+	if got := tr.Due([]session.Session{api}, now.Add(Interval+2*time.Second)); len(got) != 1 {
+		t.Errorf("no heartbeat when work resumes")
+	}
+}
+
+func TestClientSend(t *testing.T) {
+	var got []Heartbeat
+	var auth, path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, path = r.Header.Get("Authorization"), r.URL.Path
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL+"/api/v1/", "waka_123", "csm/test")
+	if err := c.Send([]Heartbeat{{Entity: "/repos/api", Project: "api"}}); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/api/v1/users/current/heartbeats.bulk" || auth != "Basic d2FrYV8xMjM=" || len(got) != 1 || got[0].Project != "api" {
+		t.Errorf("request: path %q, auth %q, heartbeats %+v", path, auth, got)
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".wakatime.cfg")
+	orig := cfgPathFn
+	cfgPathFn = func() (string, error) { return path, nil }
+	t.Cleanup(func() { cfgPathFn = orig })
+
+	os.WriteFile(path, []byte("[settings]\napi_key = waka_123\napi_url = https://wakapi.example.com/api\n\n[git]\napi_key = other\n"), 0o600)
+	key, url, err := ReadConfig()
+	if err != nil || key != "waka_123" || url != "https://wakapi.example.com/api" {
+		t.Errorf("ReadConfig = %q, %q, %v", key, url, err)
+	}
+
+	os.WriteFile(path, []byte("[settings]\ndebug = true\n"), 0o600)
+	if _, _, err := ReadConfig(); err == nil {
+		t.Error("ReadConfig accepted a file without api_key")
+	}
+}
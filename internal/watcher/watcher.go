@@ -2,28 +2,39 @@ package watcher
 
 import (
 	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/itk-dev/claude-sessions-monitor/internal/session"
 )
 
+// DefaultProbe is how often Changes checks the projects directories.
+const DefaultProbe = 250 * time.Millisecond
+
 // Watcher polls the filesystem for session changes
 type Watcher struct {
 	interval time.Duration
+	probe    time.Duration
 }
 
 // New creates a new watcher with the specified polling interval
 func New(interval time.Duration) *Watcher {
 	return &Watcher{
 		interval: interval,
+		probe:    DefaultProbe,
 	}
 }
 
 // Watch starts polling and sends session updates to the callback
-// It runs until the context is cancelled
+// It runs until the context is cancelled. Besides every interval, sessions
+// are rediscovered as soon as a project or session log is created.
 func (w *Watcher) Watch(ctx context.Context, callback func([]session.Session)) error {
 	ticker := time.NewTicker(w.interval)
 	defer ticker.Stop()
+	changes := Changes(ctx, w.probe)
 
 	// Initial scan
 	sessions, _ := session.Discover()
@@ -33,12 +44,79 @@ func (w *Watcher) Watch(ctx context.Context, callback func([]session.Session)) e
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-changes:
+			session.InvalidateCaches()
 		case <-ticker.C:
-			sessions, err := session.Discover()
-			if err != nil {
+		}
+		sessions, err := session.Discover()
+		if err != nil {
+			continue
+		}
+		callback(sessions)
+	}
+}
+
+// tree maps the projects directory of every profile, and each project
+// directory in it, to its modification time. A directory's time changes when
+// an entry is created, removed or renamed in it, so a new project or session
+// log changes the tree, and appending to a log does not.
+type tree map[string]time.Time
+
+// scanTree stats the projects directories. Profiles without one yet are
+// left out until Claude creates it.
+func scanTree() tree {
+	t := make(tree)
+	profiles, err := session.Profiles()
+	if err != nil {
+		return t
+	}
+	for _, p := range profiles {
+		root := filepath.Join(p.Dir, "projects")
+		info, err := os.Stat(root)
+		if err != nil {
+			continue
+		}
+		t[root] = info.ModTime()
+		entries, _ := os.ReadDir(root)
+		for _, e := range entries {
+			if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
 				continue
 			}
-			callback(sessions)
+			if info, err := e.Info(); err == nil {
+				t[filepath.Join(root, e.Name())] = info.ModTime()
+			}
 		}
 	}
+	return t
+}
+
+// Changes signals on the returned channel when a project directory or
+// session log is created or removed, checking every probe until ctx is done.
+// At most one signal is pending: a creation while the receiver is still
+// discovering after the previous one is signalled again, so it is never
+// missed.
+func Changes(ctx context.Context, probe time.Duration) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	go func() {
+		ticker := time.NewTicker(probe)
+		defer ticker.Stop()
+		last := scanTree()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			next := scanTree()
+			if maps.EqualFunc(next, last, time.Time.Equal) {
+				continue
+			}
+			last = next
+			select {
+			case ch <- struct{}{}:
+			default: // one is already pending
+			}
+		}
+	}()
+	return ch
 }
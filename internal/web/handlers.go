@@ -42,8 +42,8 @@ func filterLiveSessions(all []session.Session) []session.Session {
 }
 
 // handleSessions returns active and recently-stopped sessions as JSON
-func handleSessions(w http.ResponseWriter, r *http.Request) {
-	sessions, err := session.Discover()
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.source()
 	if err != nil {
 		writeError(w, "failed to discover sessions", http.StatusInternalServerError)
 		return
@@ -53,7 +53,7 @@ func handleSessions(w http.ResponseWriter, r *http.Request) {
 
 // handleHistory returns past sessions as JSON, merging index-based history
 // with inactive sessions from Discover() so they always appear somewhere.
-func handleHistory(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
 	const maxDays = 365
 	days := 7
 	if d := r.URL.Query().Get("days"); d != "" {
@@ -75,7 +75,7 @@ func handleHistory(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Merge inactive sessions from Discover() so they are visible in history
-	liveSessions, err := session.Discover()
+	liveSessions, err := s.source()
 	if err == nil {
 		// Track log files already in history to avoid duplicates
 		seen := make(map[string]bool, len(sessions))
@@ -128,7 +128,7 @@ func handleHistory(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	writeJSON(w, sessions)
+	writeJSON(w, session.ChainHistory(sessions))
 }
 
 // handleTimeline returns paginated message timeline for a log file
@@ -200,3 +200,41 @@ func handleMetrics(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, metrics)
 }
+
+// handleProcesses returns the process tree under a session's Claude process.
+// Only PIDs of local sessions are accepted, so the endpoint can't be used to
+// list arbitrary processes.
+func (s *Server) handleProcesses(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil || pid <= 0 {
+		writeError(w, "pid parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	sessions, err := s.source()
+	if err != nil {
+		writeError(w, "failed to discover sessions", http.StatusInternalServerError)
+		return
+	}
+	known := false
+	for _, sess := range sessions {
+		if sess.GhostPID == pid && sess.Host == "" {
+			known = true
+			break
+		}
+	}
+	if !known {
+		writeError(w, "no running session with that pid", http.StatusNotFound)
+		return
+	}
+
+	tree, err := session.ProcessTree(pid)
+	if err != nil {
+		writeError(w, "failed to list processes", http.StatusInternalServerError)
+		return
+	}
+	if tree == nil {
+		tree = []session.ProcessNode{}
+	}
+	writeJSON(w, tree)
+}
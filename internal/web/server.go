@@ -8,15 +8,27 @@ import (
 	"net"
 	"net/http"
 	"runtime"
+	"strconv"
 	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
 )
 
 //go:embed static
 var staticFiles embed.FS
 
+// Source supplies the sessions the dashboard shows. The default is local
+// discovery; the hub serves sessions pushed by agents instead.
+type Source func() ([]session.Session, error)
+
 // Server is the web dashboard HTTP server
 type Server struct {
 	port   int
+	host   string
+	source Source
+	routes map[string]http.Handler
+	remote bool // sessions come from elsewhere; see RemoteOnly
+	auth   func(http.Handler) http.Handler
 	hub    *SSEHub
 	server *http.Server
 }
@@ -24,25 +36,64 @@ type Server struct {
 // NewServer creates a new web dashboard server
 func NewServer(port int) *Server {
 	return &Server{
-		port: port,
-		hub:  NewSSEHub(),
+		port:   port,
+		host:   "localhost",
+		source: session.Discover,
+		routes: make(map[string]http.Handler),
 	}
 }
 
+// SetSource replaces the session source. Call before Start.
+func (s *Server) SetSource(src Source) {
+	s.source = src
+}
+
+// SetHost sets the interface to listen on (default "localhost"). Use
+// "0.0.0.0" to accept connections from other machines. Call before Start.
+func (s *Server) SetHost(host string) {
+	s.host = host
+}
+
+// Handle registers an extra route, e.g. the hub's push endpoint. Call before
+// Start.
+func (s *Server) Handle(pattern string, h http.Handler) {
+	s.routes[pattern] = h
+}
+
+// RemoteOnly leaves out the routes that read this machine's logs, processes
+// and usage quota (history, timelines, metrics, processes, usage), for
+// servers like the hub whose sessions come from elsewhere. Call before Start.
+func (s *Server) RemoteOnly() {
+	s.remote = true
+}
+
+// SetAuth puts auth in front of every route, static files included. Call
+// before Start.
+func (s *Server) SetAuth(auth func(http.Handler) http.Handler) {
+	s.auth = auth
+}
+
 // Start starts the web server in the background. It returns once the server
 // is listening, or returns an error if it fails to bind. The server runs
 // until ctx is cancelled. Any serve error is sent on the returned channel.
 func (s *Server) Start(ctx context.Context) (<-chan error, error) {
 	mux := http.NewServeMux()
+	s.hub = NewSSEHub(s.source)
 
 	// API routes
-	mux.HandleFunc("/api/sessions", handleSessions)
-	mux.HandleFunc("/api/history", handleHistory)
-	mux.HandleFunc("/api/sessions/timeline", handleTimeline)
-	mux.HandleFunc("/api/sessions/metrics", handleMetrics)
-	mux.HandleFunc("/api/usage", handleUsage)
+	mux.HandleFunc("/api/sessions", s.handleSessions)
+	if !s.remote {
+		mux.HandleFunc("/api/history", s.handleHistory)
+		mux.HandleFunc("/api/sessions/timeline", handleTimeline)
+		mux.HandleFunc("/api/sessions/metrics", handleMetrics)
+		mux.HandleFunc("/api/sessions/processes", s.handleProcesses)
+		mux.HandleFunc("/api/usage", handleUsage)
+	}
 	mux.HandleFunc("/api/claude-status", handleClaudeStatus)
 	mux.HandleFunc("/api/events", s.hub.HandleSSE)
+	for pattern, h := range s.routes {
+		mux.Handle(pattern, h)
+	}
 
 	// Static files
 	staticFS, err := fs.Sub(staticFiles, "static")
@@ -51,10 +102,14 @@ func (s *Server) Start(ctx context.Context) (<-chan error, error) {
 	}
 	mux.Handle("/", http.FileServer(http.FS(staticFS)))
 
-	addr := fmt.Sprintf("localhost:%d", s.port)
+	var handler http.Handler = mux
+	if s.auth != nil {
+		handler = s.auth(handler)
+	}
+	addr := s.Addr()
 	s.server = &http.Server{
 		Addr:    addr,
-		Handler: securityHeaders(mux),
+		Handler: securityHeaders(handler),
 	}
 
 	// Start SSE hub
@@ -101,7 +156,7 @@ func securityHeaders(next http.Handler) http.Handler {
 
 // Addr returns the address the server is configured to listen on.
 func (s *Server) Addr() string {
-	return fmt.Sprintf("localhost:%d", s.port)
+	return net.JoinHostPort(s.host, strconv.Itoa(s.port))
 }
 
 // ProbeCSMServer checks if a csm web server is already running on the given port
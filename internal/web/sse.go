@@ -7,21 +7,21 @@ import (
 	"net/http"
 	"sync"
 	"time"
-
-	"github.com/itk-dev/claude-sessions-monitor/internal/session"
 )
 
 // SSEHub manages Server-Sent Events connections
 type SSEHub struct {
+	source     Source
 	clients    map[chan []byte]struct{}
 	register   chan chan []byte
 	unregister chan chan []byte
 	mu         sync.Mutex
 }
 
-// NewSSEHub creates a new SSE hub
-func NewSSEHub() *SSEHub {
+// NewSSEHub creates a new SSE hub broadcasting sessions from source
+func NewSSEHub(source Source) *SSEHub {
 	return &SSEHub{
+		source:     source,
 		clients:    make(map[chan []byte]struct{}),
 		register:   make(chan chan []byte),
 		unregister: make(chan chan []byte),
@@ -60,7 +60,7 @@ func (h *SSEHub) Run(ctx context.Context) {
 			h.mu.Unlock()
 
 		case <-ticker.C:
-			allSessions, err := session.Discover()
+			allSessions, err := h.source()
 			if err != nil {
 				continue
 			}
@@ -124,7 +124,7 @@ func (h *SSEHub) HandleSSE(w http.ResponseWriter, r *http.Request) {
 	h.register <- client
 
 	// Send initial session data immediately (active + recently stopped sessions)
-	allSessions, err := session.Discover()
+	allSessions, err := h.source()
 	if err == nil {
 		live := filterLiveSessions(allSessions)
 		data, err := json.Marshal(live)
@@ -181,12 +181,25 @@
             return `<div class="${cardCls}" data-logfile="${esc(s.log_file || '')}" data-project="${esc(s.project)}">
                 <div class="session-top">
                     <span class="session-status ${cls}" title="${esc(s.status)}">${symbol}</span>
-                    <span class="session-project">${esc(s.project)}</span>
+                    <span class="session-project${s.over_budget ? ' over-budget' : ''}"${s.over_budget ? ` title="Over budget: ${s.tokens} tokens, $${(s.cost || 0).toFixed(2)}"` : ''}>${esc(s.project)}</span>
+                    ${s.host ? `<span class="badge session-host" title="Host">${esc(s.host)}</span>` : ''}
+                    ${s.profile ? `<span class="badge session-profile" title="Claude profile">${esc(s.profile)}</span>` : ''}
                     ${stoppedBadge}
                     ${s.git_branch ? `<span class="session-branch">${esc(s.git_branch)}</span>` : ''}
                     ${s.session_title ? `<span class="session-title">${esc(s.session_title)}</span>` : ''}
                     ${s.origin && s.origin.category ? `<span class="badge session-origin origin-${esc(s.origin.category)}" title="${esc(s.origin.app || '')}">${esc(s.origin.display || s.origin.app || '')}</span>` : ''}
+                    ${s.origin && s.origin.integration ? `<span class="badge session-ide" title="Started by the ${esc(s.origin.display || 'IDE')} extension">IDE</span>` : ''}
+                    ${s.headless ? `<span class="badge session-headless" title="Run non-interactively (claude -p or the Agent SDK)">headless</span>` : ''}
                     ${isExtendedContextModel(s.model) ? `<span class="badge session-model-badge" title="${esc(s.model)}">1M</span>` : ''}
+                    ${s.conflicts ? `<span class="badge session-conflict" title="${s.conflicts} other active session${s.conflicts === 1 ? '' : 's'} in this working tree">conflict</span>` : ''}
+                    ${s.permission_mode === 'bypassPermissions' ? `<span class="badge session-permission-bypass" title="Permission checks bypassed">!P</span>` : ''}
+                    ${s.permission_mode === 'acceptEdits' ? `<span class="badge session-permission-edits" title="Edits accepted automatically">AE</span>` : ''}
+                    ${s.permission_mode === 'plan' ? `<span class="badge session-permission-plan" title="Plan mode: ends with a plan to approve">plan</span>` : ''}
+                    ${s.web_access ? `<span class="badge session-web-access" title="${s.web_access} web searches/fetches in recent activity">🌐${s.web_access}</span>` : ''}
+                    ${s.outdated ? `<span class="badge session-outdated" title="Claude Code ${esc(s.version)} is older than the configured minimum">v${esc(s.version)}</span>` : ''}
+                    ${modelSwitchBadge(s.model_switches)}
+                    ${hookBadge(s.hook)}
+                    ${s.api_errors ? `<span class="badge session-api-error" title="${esc(s.last_api_error || '')}">err ${s.api_errors}</span>` : ''}
                     <span class="session-context" title="${esc(s.model || '')}">
                         <span class="context-bar"><span class="context-fill ${ctxCls}" style="width:${Math.min(pct, 100)}%"></span></span>
                         <span>${pct > 0 ? Math.round(pct) + '%' : '-'}</span>
@@ -194,7 +207,10 @@
                     <span class="session-activity">${age}</span>
                     <a class="session-history-link" title="View project history">&#x29D6;</a>
                 </div>
-                ${s.last_message ? `<div class="session-bottom">${esc(s.last_message)}</div>` : ''}
+                ${s.pending_request
+                    ? `<div class="session-bottom session-pending"><span class="pending-label">Pending:</span> ${esc(s.pending_request)}</div>`
+                    : s.hook ? hookMessage(s.hook)
+                    : s.last_message ? `<div class="session-bottom">${esc(s.last_message)}</div>` : ''}
             </div>`;
         }).join('');
 
@@ -284,13 +300,14 @@
             sessions.forEach(s => {
                 const dur = formatDuration(s.duration);
                 const date = s.start_time ? dateGroup(s.start_time) + ' ' + new Date(s.start_time).toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' }) : '-';
+                const chain = s.chain > 1 ? ` <span class="history-chain" title="Resumed ${s.chain - 1} time(s)">&#x21BB;${s.chain}</span>` : '';
                 const promptLine = s.first_prompt ? `<div class="history-prompt">${esc(s.first_prompt)}</div>` : '';
                 html += `<div class="history-row" data-logfile="${esc(s.log_file || '')}">
                     <div class="history-row-main">
                         <span class="history-branch">${s.git_branch ? esc(s.git_branch) : '-'}</span>
                         <span class="history-date">${date}</span>
                         <span class="history-messages">${s.message_count || 0}</span>
-                        <span class="history-duration">${dur}</span>
+                        <span class="history-duration">${dur}${chain}</span>
                     </div>
                     ${promptLine}
                 </div>`;
@@ -509,15 +526,43 @@
 
         let html = `<div class="metrics-grid">
             <div class="metric-card"><div class="metric-label">Turns</div><div class="metric-value blue">${m.turn_count}</div></div>
+            ${m.turn_count > 0 ? `<div class="metric-card"><div class="metric-label">Avg Turn</div><div class="metric-value">${formatDuration(m.avg_turn_duration)}</div></div>
+            <div class="metric-card"><div class="metric-label">Longest Turn</div><div class="metric-value">${formatDuration(m.longest_turn_duration)}</div></div>` : ''}
             <div class="metric-card metric-clickable" data-action="show-user-prompts" title="Show user prompts in timeline"><div class="metric-label">User Prompts</div><div class="metric-value green">${m.user_prompt_count}</div></div>
             <div class="metric-card"><div class="metric-label">Tool Results</div><div class="metric-value">${m.tool_result_count}</div></div>
             <div class="metric-card"><div class="metric-label">Assistant Messages</div><div class="metric-value purple">${m.assistant_message_count}</div></div>
             <div class="metric-card"><div class="metric-label">Duration</div><div class="metric-value">${duration}</div></div>
+            ${m.version ? `<div class="metric-card"${m.outdated ? ' title="Older than the configured minimum version"' : ''}><div class="metric-label">Claude Code</div><div class="metric-value${m.outdated ? ' yellow' : ''}">${esc(m.version)}</div></div>` : ''}
             <div class="metric-card"><div class="metric-label">Total Tokens</div><div class="metric-value yellow">${fmtNum(totalTokens)}</div></div>
             <div class="metric-card"><div class="metric-label">Context Usage</div><div class="metric-value ${m.context_percent > 90 ? 'yellow' : 'green'}">${Math.round(m.context_percent)}%</div></div>
             ${m.compact_count > 0 ? `<div class="metric-card"><div class="metric-label">Compactions</div><div class="metric-value">${m.compact_count}</div></div>` : ''}
+            ${m.api_error_count > 0 ? `<div class="metric-card"><div class="metric-label">API Errors</div><div class="metric-value red">${m.api_error_count}</div></div>` : ''}
         </div>`;
 
+        if (m.last_api_error) {
+            const at = new Date(m.last_api_error_at).toLocaleString();
+            html += `<div class="api-error"><h3>Last API Error</h3><div class="api-error-message">${esc(m.last_api_error)}</div><div class="api-error-time">${esc(at)}</div></div>`;
+        }
+
+        // The latest context split by token type: how much of it is cached.
+        const cb = m.context_breakdown;
+        if (cb && cb.limit > 0 && cb.fresh + cb.cache_created + cb.cache_read > 0) {
+            const segs = [
+                { cls: 'context-seg-read', label: 'Cache read', value: cb.cache_read },
+                { cls: 'context-seg-created', label: 'Cache created', value: cb.cache_created },
+                { cls: 'context-seg-fresh', label: 'Fresh', value: cb.fresh },
+            ];
+            html += `<div class="context-breakdown"><h3>Context</h3><div class="context-breakdown-track">`;
+            segs.forEach(sg => {
+                html += `<span class="context-seg ${sg.cls}" style="width:${Math.min(sg.value / cb.limit * 100, 100)}%" title="${sg.label}: ${fmtNum(sg.value)}"></span>`;
+            });
+            html += `</div><div class="context-breakdown-legend">`;
+            segs.forEach(sg => {
+                html += `<span><span class="context-seg ${sg.cls}"></span>${sg.label} ${fmtNum(sg.value)}</span>`;
+            });
+            html += `<span>of ${fmtNum(cb.limit)}</span></div></div>`;
+        }
+
         html += `<div class="token-breakdown"><h3>Token Breakdown</h3>`;
         const bars = [
             { label: 'Input', value: m.total_input_tokens, color: 'var(--blue)' },
@@ -546,7 +591,30 @@
             html += '</div></div>';
         }
 
+        // Which profile, settings and CLAUDE.md files the session runs with.
+        const cfg = m.config || {};
+        if (cfg.config_dir) {
+            const files = list => (list || []).map(f => `<div>${esc(f)}</div>`).join('') || '<div class="session-config-none">none</div>';
+            html += `<div class="session-config"><h3>Configuration</h3>
+                <div class="session-config-row"><span class="session-config-label">Profile</span><div>${esc(cfg.config_dir)}</div></div>
+                <div class="session-config-row"><span class="session-config-label">Settings</span><div>${files(cfg.settings)}</div></div>
+                <div class="session-config-row"><span class="session-config-label">CLAUDE.md</span><div>${files(cfg.memory)}</div></div>
+            </div>`;
+        }
+
+        // A session waiting for approval shows what it wants to do first.
+        const live = currentSessions.find(s => s.log_file === currentLogFile);
+        if (live && live.pending_request) {
+            html = `<div class="pending-request"><h3>Waiting for approval</h3><pre>${esc(live.pending_request)}</pre></div>` + html;
+        }
+
+        // What a running session is executing right now.
+        if (live && live.ghost_pid && !live.host) {
+            html += `<div class="process-tree"><h3>Processes</h3><div class="process-tree-body"><div class="loading">Loading processes...</div></div></div>`;
+        }
+
         detailMetrics.innerHTML = html;
+        if (live && live.ghost_pid && !live.host) loadProcesses(live.ghost_pid);
 
         const userPromptsCard = detailMetrics.querySelector('[data-action="show-user-prompts"]');
         if (userPromptsCard) {
@@ -554,6 +622,30 @@
         }
     }
 
+    async function loadProcesses(pid) {
+        const body = detailMetrics.querySelector('.process-tree-body');
+        try {
+            const resp = await fetch(`/api/sessions/processes?pid=${pid}`);
+            if (!resp.ok) throw new Error(await resp.text());
+            const tree = await resp.json();
+            body.innerHTML = tree.length > 0
+                ? renderProcessNodes(tree)
+                : '<div class="empty-state">No child processes</div>';
+        } catch (err) {
+            body.innerHTML = '<div class="empty-state">Failed to load processes</div>';
+        }
+    }
+
+    function renderProcessNodes(nodes) {
+        let html = '<ul>';
+        nodes.forEach(n => {
+            html += `<li><span class="process-pid">${n.pid}</span> <span class="process-command">${esc(n.command)}</span>`;
+            if (n.children) html += renderProcessNodes(n.children);
+            html += '</li>';
+        });
+        return html + '</ul>';
+    }
+
     function showUserPromptsTimeline() {
         document.querySelectorAll('.detail-tab').forEach(t => {
             t.classList.toggle('active', t.dataset.detail === 'timeline');
@@ -777,6 +869,40 @@
         return d.innerHTML;
     }
 
+    // Badge for a session whose model changed mid-session, labelled with the
+    // latest switch; the tooltip lists them all.
+    function modelSwitchBadge(switches) {
+        if (!switches || switches.length === 0) return '';
+        const last = switches[switches.length - 1];
+        const title = switches.map(sw =>
+            `${new Date(sw.at).toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' })} ${sw.from} → ${sw.to}`).join('\n');
+        return `<span class="badge session-model-switch" title="${esc(title)}">${esc(shortModel(last.from))}→${esc(shortModel(last.to))}</span>`;
+    }
+
+    // Badge for a hook still running, or one that failed or blocked since
+    // the last prompt; mirrors the [hook] suffix of the terminal view.
+    function hookBadge(hook) {
+        if (!hook) return '';
+        const name = hook.name || hook.event || 'hook';
+        if (hook.running) {
+            return `<span class="badge session-hook-running" title="Running ${esc(name)}${hook.command ? ': ' + esc(hook.command) : ''}">hook</span>`;
+        }
+        return `<span class="badge session-hook-error" title="${esc(name)}: ${esc(hook.error || '')}">hook ${hook.blocked ? 'blocked' : 'failed'}</span>`;
+    }
+
+    function hookMessage(hook) {
+        const name = esc(hook.name || hook.event || 'hook');
+        const [cls, label, detail] = hook.running
+            ? ['session-hook-running', `Running hook ${name}:`, hook.command]
+            : ['session-hook-error', `Hook ${name} ${hook.blocked ? 'blocked' : 'failed'}:`, hook.error];
+        return `<div class="session-bottom"><span class="${cls}">${label}</span> ${esc(detail || '')}</div>`;
+    }
+
+    function shortModel(model) {
+        const m = /^claude-([a-z]+)-/.exec(model || '');
+        return m ? m[1] : (model || '').replace(/^claude-/, '');
+    }
+
     // Mirrors session.contextWindowForModel in Go: opus/sonnet from generation 4.6
     // onward use the 1M extended context window.
     function isExtendedContextModel(model) {
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/statsd"
+)
+
+// usageMetricsInterval is how often token usage is re-scanned for metrics;
+// it reads whole logs, so it runs far less often than session discovery.
+const usageMetricsInterval = time.Minute
+
+// metrics is the StatsD sink, set by setupStatsD when configured.
+var metrics *statsd.Client
+
+// addStatsDFlag registers --statsd for the long-running commands.
+func addStatsDFlag(fs *flag.FlagSet) *string {
+	return fs.String("statsd", "", "Send StatsD / DogStatsD metrics to `host:port` (overrides the statsd config)")
+}
+
+// setupStatsD connects the StatsD sink from --statsd or the config and, when
+// interval > 0, reports session metrics every interval for the life of the
+// process.
+func setupStatsD(cfg *config.Config, addr string, interval time.Duration) {
+	sc := cfg.StatsD
+	if addr != "" {
+		sc.Addr = addr
+	}
+	if sc.Addr == "" {
+		return
+	}
+	if sc.Prefix == "" {
+		sc.Prefix = "csm"
+	}
+
+	client, err := statsd.Dial(sc.Addr, sc.Prefix, sc.Tags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: statsd disabled: %v\n", err)
+		return
+	}
+	metrics = client
+	if interval > 0 {
+		go reportMetrics(interval)
+	}
+}
+
+// reportMetrics emits session gauges every interval and token usage every
+// usageMetricsInterval.
+func reportMetrics(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	tokens := make(map[string]int) // per-log token totals from the last scan
+	var lastUsage time.Time
+	for {
+		if sessions, err := session.Discover(); err == nil {
+			reportSessions(sessions)
+		}
+		if time.Since(lastUsage) >= usageMetricsInterval {
+			reportUsage(session.ComputeUsage(), tokens, !lastUsage.IsZero())
+			lastUsage = time.Now()
+		}
+		<-ticker.C
+	}
+}
+
+// reportSessions emits csm.sessions per status plus csm.ghosts.
+func reportSessions(sessions []session.Session) {
+	counts := map[session.Status]int{}
+	ghosts := 0
+	for _, s := range sessions {
+		counts[s.Status]++
+		if s.IsGhost {
+			ghosts++
+		}
+	}
+	// Send every status, zeros included, so gauges drop back down.
+	for _, st := range []session.Status{session.StatusWorking, session.StatusNeedsInput, session.StatusWaiting, session.StatusIdle, session.StatusInactive} {
+		metrics.Gauge("sessions", float64(counts[st]), "status:"+metricTag(string(st)))
+	}
+	metrics.Gauge("ghosts", float64(ghosts))
+}
+
+// reportUsage emits the rolling 5-hour token gauges and counts tokens
+// consumed since the previous scan, using prev to remember per-log totals.
+// The first scan (primed false) only fills prev, so pre-existing usage isn't
+// counted as a burst.
+func reportUsage(usage *session.UsageStats, prev map[string]int, primed bool) {
+	metrics.Gauge("tokens.window", float64(usage.InputTokens), "kind:input")
+	metrics.Gauge("tokens.window", float64(usage.OutputTokens), "kind:output")
+	metrics.Gauge("tokens.window", float64(usage.CacheTokens), "kind:cache")
+
+	var consumed int64
+	for _, su := range usage.Sessions {
+		if delta := su.TotalTokens - prev[su.LogFile]; delta > 0 && primed {
+			consumed += int64(delta)
+		}
+		prev[su.LogFile] = su.TotalTokens
+	}
+	if consumed > 0 {
+		metrics.Count("tokens.consumed", consumed)
+	}
+}
+
+// metricTag turns a status like "Needs Input" into "needs_input".
+func metricTag(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), " ", "_")
+}
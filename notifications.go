@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/notify"
+)
+
+// addNotifyFlag registers --notify for the commands that can alert.
+func addNotifyFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("notify", false, "Send desktop notifications (also enabled by notifications.enabled in the config)")
+}
+
+// setupNotifier returns a Notifier when notifications are enabled by flag or
+// config, or nil. An invalid notifications config is fatal so a typo in the
+// quiet hours doesn't silently wake you up at night.
+func setupNotifier(cfg *config.Config, enabled bool) *notify.Notifier {
+	if !enabled && !cfg.Notifications.Enabled {
+		return nil
+	}
+	n, err := notify.New(cfg.Notifications)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return n
+}
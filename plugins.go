@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/config"
+	"github.com/itk-dev/claude-sessions-monitor/internal/plugin"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+	"github.com/itk-dev/claude-sessions-monitor/internal/ui"
+)
+
+// pluginColumns holds the columns registered from the "plugin_columns"
+// config key.
+var pluginColumns []*plugin.Column
+
+// setupPlugins registers the configured plugin columns with the UI. It exits
+// on an invalid entry so a typo in the config isn't silently ignored.
+func setupPlugins(cfg *config.Config) {
+	for _, pc := range cfg.PluginColumns {
+		col, err := plugin.New(pc)
+		if err == nil {
+			err = ui.RegisterColumn(col.Name, col.Header, col.Width, col.Value)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		pluginColumns = append(pluginColumns, col)
+	}
+}
+
+// withPluginColumns appends the plugin columns not already named in names, so
+// configured plugins show up without also listing them under "columns".
+func withPluginColumns(names []string) []string {
+	for _, col := range pluginColumns {
+		if !slices.ContainsFunc(names, func(n string) bool {
+			return strings.EqualFold(strings.TrimSpace(n), col.Name)
+		}) {
+			names = append(names, col.Name)
+		}
+	}
+	return names
+}
+
+// primePluginColumns runs every plugin for every session and waits, so a
+// one-shot listing shows values instead of empty cells.
+func primePluginColumns(sessions []session.Session) {
+	var wg sync.WaitGroup
+	for _, col := range pluginColumns {
+		for _, s := range sessions {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				col.Refresh(s)
+			}()
+		}
+	}
+	wg.Wait()
+}
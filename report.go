@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/export"
+)
+
+// reportFlags are --format and --output of the commands whose report can be
+// exported: history, audit and models.
+type reportFlags struct {
+	format *string
+	output *string
+}
+
+// addReportFlags registers --format and --output. The default format,
+// "table", is the command's own terminal rendering.
+func addReportFlags(fs *flag.FlagSet) reportFlags {
+	return reportFlags{
+		format: fs.String("format", "table", "Output format: table, "+strings.Join(export.Formats, ", ")),
+		output: fs.String("output", "", "Write the report to this `file` instead of stdout; its extension (.csv, .json, .md) sets the format"),
+	}
+}
+
+// exporting checks the flags, exiting with a usage error when they don't
+// make sense, and reports whether the report is exported rather than shown
+// as a table.
+func (r reportFlags) exporting() bool {
+	if *r.format == "table" && *r.output != "" {
+		*r.format = strings.TrimPrefix(filepath.Ext(*r.output), ".")
+		if !export.Supported(*r.format) {
+			fmt.Fprintf(os.Stderr, "Error: --output needs --format %s, or a file ending in one of them\n", strings.Join(export.Formats, ", "))
+			os.Exit(2)
+		}
+	}
+	if *r.format != "table" && !export.Supported(*r.format) {
+		fmt.Fprintf(os.Stderr, "Error: --format must be table, %s\n", strings.Join(export.Formats, ", "))
+		os.Exit(2)
+	}
+	return *r.format != "table"
+}
+
+// write exports t in --format to --output.
+func (r reportFlags) write(t export.Table) {
+	w, err := export.Create(*r.output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := export.Write(w, *r.format, t); err != nil {
+		w.Close()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := w.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
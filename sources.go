@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/itk-dev/claude-sessions-monitor/internal/remote"
+	"github.com/itk-dev/claude-sessions-monitor/internal/session"
+)
+
+// remotePollInterval is how often the live view re-fetches remote hosts. SSH
+// round trips are far slower than a local scan, so this is decoupled from
+// the refresh interval.
+const remotePollInterval = 10 * time.Second
+
+// remotePoller is set by --remote; its sessions are merged into every
+// discoverSessions call.
+var remotePoller *remote.Poller
+
+// addRemoteFlag registers --remote for the commands that show sessions.
+func addRemoteFlag(fs *flag.FlagSet) *string {
+	return fs.String("remote", "", "Also show sessions from `user@host[,user@host2]` via SSH (requires csm on the remote PATH)")
+}
+
+// setupRemote creates the remote poller for a --remote value, if any.
+func setupRemote(spec string) {
+	if hosts := remote.ParseHosts(spec); len(hosts) > 0 {
+		remotePoller = remote.NewPoller(hosts)
+	}
+}
+
+// discoverSessions returns local sessions merged with the latest remote ones,
+// leaving out headless sessions unless --show-headless is set. A local
+// discovery error is only fatal when there is nothing remote to show.
+func discoverSessions() ([]session.Session, error) {
+	local, err := session.Discover()
+	if remotePoller == nil && showHeadless {
+		return local, err
+	}
+
+	// Copy: Discover's slice is shared through its result cache.
+	sessions := withoutHeadless(nil, local)
+	if remotePoller == nil {
+		return sessions, err
+	}
+	sessions = withoutHeadless(sessions, remotePoller.Sessions())
+	session.SortSessions(sessions)
+	return sessions, nil
+}
+
+// showHeadless is set by --show-headless. Sessions run by `claude -p` or the
+// Agent SDK (scripts, CI) are hidden otherwise.
+var showHeadless bool
+
+// addShowHeadlessFlag registers --show-headless for the commands that show
+// sessions.
+func addShowHeadlessFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&showHeadless, "show-headless", false, "Also show non-interactive sessions (claude -p, Agent SDK), tagged [headless]")
+}
+
+// withoutHeadless appends sessions to dst, skipping headless ones unless
+// --show-headless is set.
+func withoutHeadless(dst, sessions []session.Session) []session.Session {
+	for _, s := range sessions {
+		if showHeadless || !s.Headless {
+			dst = append(dst, s)
+		}
+	}
+	return dst
+}
+
+// refreshRemoteOnce fetches remote hosts synchronously for one-shot commands,
+// reporting unreachable hosts on stderr.
+func refreshRemoteOnce() {
+	if remotePoller == nil {
+		return
+	}
+	remotePoller.Refresh(context.Background())
+	for _, err := range remotePoller.Errors() {
+		fmt.Fprintf(os.Stderr, "Warning: remote %v\n", err)
+	}
+}
+
+// onlyNeedsInput is set by --only-needs-input: the list and live views then
+// show nothing but sessions waiting on the user.
+var onlyNeedsInput bool
+
+// addOnlyNeedsInputFlag registers --only-needs-input for the list and live
+// views.
+func addOnlyNeedsInputFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&onlyNeedsInput, "only-needs-input", false, "Only show sessions waiting for approval or input")
+}
+
+// activeWithin is set by --active-within: list output then leaves out
+// sessions whose last activity is longer ago. 0 keeps them all. The live
+// view ignores it.
+var activeWithin time.Duration
+
+// addActiveWithinFlag registers --active-within for `csm list` and the
+// legacy -l flag.
+func addActiveWithinFlag(fs *flag.FlagSet) {
+	fs.DurationVar(&activeWithin, "active-within", 0, "Only list sessions active within this long, e.g. 1h")
+}
+
+// filterSessions applies --only-needs-input. It returns a new slice, so the
+// result is safe to modify.
+func filterSessions(sessions []session.Session) []session.Session {
+	var out []session.Session
+	for _, s := range sessions {
+		if !onlyNeedsInput || (s.Status == session.StatusNeedsInput && !s.IsGhost) {
+			out = append(out, s)
+		}
+	}
+	return out
+}